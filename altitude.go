@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+const (
+	altitudeWindow  = 3 * time.Hour
+	altitudeSpacing = 30 * time.Second
+	altitudeRows    = 12
+)
+
+type altitudeSample struct {
+	at time.Time
+	km float64
+}
+
+func (m model) pushAltitude(km float64, at time.Time) []altitudeSample {
+	samples := m.altitudes
+	if n := len(samples); n > 0 && at.Sub(samples[n-1].at) < altitudeSpacing {
+		return samples
+	}
+	samples = append(samples, altitudeSample{at: at, km: km})
+	drop := 0
+	for drop < len(samples) && at.Sub(samples[drop].at) > altitudeWindow {
+		drop++
+	}
+	if drop > 0 {
+		samples = append([]altitudeSample(nil), samples[drop:]...)
+	}
+	return samples
+}
+
+func (m model) altitudeSeries(now time.Time, columns int) (predicted, measured []float64) {
+	factor, _ := m.units.distanceUnit()
+	step := altitudeWindow / time.Duration(columns)
+	start := now.Add(-altitudeWindow)
+	predicted = make([]float64, columns)
+	measured = make([]float64, columns)
+	counts := make([]int, columns)
+	for i := range predicted {
+		predicted[i] = math.NaN()
+		if m.sat == nil {
+			continue
+		}
+		if pos, err := m.sat.PositionAt(start.Add(time.Duration(i+1) * step)); err == nil {
+			predicted[i] = pos.AltitudeKm * factor
+		}
+	}
+	for _, s := range m.altitudes {
+		i := int(s.at.Sub(start) / step)
+		if i < 0 || i >= columns {
+			continue
+		}
+		measured[i] += s.km * factor
+		counts[i]++
+	}
+	for i, n := range counts {
+		if n == 0 {
+			measured[i] = math.NaN()
+		} else {
+			measured[i] /= float64(n)
+		}
+	}
+	return predicted, measured
+}
+
+func chartAxis(axis string, columns int, left, middle, right string) string {
+	row := []rune(strings.Repeat(" ", columns))
+	copy(row, []rune(left))
+	copy(row[max((columns-len([]rune(middle)))/2, 0):], []rune(middle))
+	copy(row[max(columns-len([]rune(right)), 0):], []rune(right))
+	return strings.Repeat(" ", len(axis)-columns) + string(row)
+}
+
+func (m model) altitudeView() string {
+	lines := []string{m.lang.text("altitude_view"), ""}
+
+	if m.sat == nil && len(m.altitudes) == 0 {
+		lines = append(lines, m.lang.text("loading_tle"))
+		return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+	}
+
+	now := clockNow()
+	columns := min(max(m.width-24, 40), 120)
+	predicted, measured := m.altitudeSeries(now, columns)
+	_, unit := m.units.distanceUnit()
+	chart, err := render.Chart([]render.Series{{Values: predicted, Glyph: '.'}, {Values: measured, Glyph: '*'}}, altitudeRows, "%.1f "+unit)
+	if err != nil {
+		lines = append(lines, err.Error())
+		return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+	}
+	lines = append(lines, strings.Split(chart, "\n")...)
+	lines = append(lines, chartAxis(lines[len(lines)-1], columns, "-3h", "", m.lang.text("now")))
+	lines = append(lines, "", ". "+m.lang.text("altitude_predicted")+"   * "+m.lang.text("altitude_measured"), "")
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, series := range [][]float64{predicted, measured} {
+		for _, v := range series {
+			if !math.IsNaN(v) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	factor, _ := m.units.distanceUnit()
+	fields := [][2]string{{m.lang.text("altitude_range"), fmt.Sprintf("%s – %s", m.units.formatDistance(low/factor), m.units.formatDistance(high/factor))}}
+	if m.hasAltitude {
+		fields = append([][2]string{{m.lang.text("altitude"), m.units.formatDistance(m.altitudeKm)}}, fields...)
+	}
+	lines = append(lines, alignFields(fields)...)
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
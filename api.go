@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	apiPrefix      = "/api/v1/"
+	apiMaxPassDays = 30
+)
+
+type apiPosition struct {
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	AltitudeKm  *float64  `json:"altitude_km,omitempty"`
+	VelocityKmh *float64  `json:"velocity_kmh,omitempty"`
+	Over        string    `json:"over,omitempty"`
+	Provider    string    `json:"provider,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type apiServer struct {
+	addr       string
+	grpcAddr   string
+	client     *http.Client
+	observer   *track.Observer
+	weights    passScoreWeights
+	tleRefresh time.Duration
+
+	mu      sync.Mutex
+	latest  *apiPosition
+	streams map[chan apiPosition]struct{}
+}
+
+func newAPIServer(cfg config, client *http.Client, weights passScoreWeights) *apiServer {
+	return &apiServer{
+		addr:       cfg.APIAddr,
+		grpcAddr:   cfg.GRPCAddr,
+		client:     client,
+		observer:   observerFromConfig(cfg),
+		weights:    weights,
+		tleRefresh: tleRefreshFromConfig(cfg),
+		streams:    map[chan apiPosition]struct{}{},
+	}
+}
+
+func newAPIPosition(pos track.Position, over string) apiPosition {
+	out := apiPosition{Lat: pos.Lat, Lon: pos.Lon, Over: over, Provider: pos.Provider, Timestamp: sampleTime(pos).UTC()}
+	if pos.HasAltitude {
+		out.AltitudeKm, out.VelocityKmh = &pos.AltitudeKm, &pos.VelocityKmh
+	}
+	return out
+}
+
+func (a *apiServer) observe(pos apiPosition) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.latest = &pos
+	for ch := range a.streams {
+		select {
+		case ch <- pos:
+		default:
+		}
+	}
+}
+
+func (a *apiServer) subscriber(e busEvent) tea.Cmd {
+	a.observe(newAPIPosition(e.pos, e.text))
+	return nil
+}
+
+func (a *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiPrefix+"position", a.getPosition)
+	mux.HandleFunc(apiPrefix+"positions/stream", a.streamPositions)
+	mux.HandleFunc(apiPrefix+"passes", a.predictPasses)
+	mux.HandleFunc(apiPrefix+"crew", a.listCrew)
+	return mux
+}
+
+func (a *apiServer) serveCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		server := &http.Server{Addr: a.addr, Handler: a.handler(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, done := context.WithTimeout(context.Background(), serveShutdownTimeout)
+			defer done()
+			server.Shutdown(shutdownCtx)
+		}()
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return errMsg{err: fmt.Errorf("api: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (a *apiServer) position() (apiPosition, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.latest == nil {
+		return apiPosition{}, false
+	}
+	return *a.latest, true
+}
+
+func (a *apiServer) getPosition(w http.ResponseWriter, r *http.Request) {
+	latest, ok := a.position()
+	if !ok {
+		writeAPIError(w, http.StatusServiceUnavailable, errors.New("no position yet"))
+		return
+	}
+	writeAPIJSON(w, latest)
+}
+
+func (a *apiServer) subscribe() (<-chan apiPosition, func()) {
+	ch := make(chan apiPosition, 1)
+	a.mu.Lock()
+	a.streams[ch] = struct{}{}
+	if a.latest != nil {
+		ch <- *a.latest
+	}
+	a.mu.Unlock()
+
+	return ch, func() {
+		a.mu.Lock()
+		delete(a.streams, ch)
+		a.mu.Unlock()
+	}
+}
+
+func (a *apiServer) streamPositions(w http.ResponseWriter, r *http.Request) {
+	a.stream(w, r, "application/x-ndjson", "", "")
+}
+
+func (a *apiServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	a.stream(w, r, "text/event-stream", "data: ", "\n")
+}
+
+func (a *apiServer) stream(w http.ResponseWriter, r *http.Request, contentType, prefix, suffix string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+		return
+	}
+
+	positions, unsubscribe := a.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case pos := <-positions:
+			line, err := json.Marshal(pos)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "%s%s\n%s", prefix, line, suffix); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (a *apiServer) predictPasses(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	observer, err := apiObserver(query.Get("lat"), query.Get("lon"), query.Get("alt_m"), a.observer)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	days, err := apiNumber(query.Get("days"), 3)
+	if err != nil {
+		days = -1
+	}
+	minElevation, err := apiNumber(query.Get("min_elevation"), 10)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("min_elevation: %w", err))
+		return
+	}
+	count, err := apiNumber(query.Get("count"), 0)
+	if err != nil {
+		count = -1
+	}
+	q, err := newPassQuery(observer, days, minElevation, query.Get("visible") == "true", count)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sat, err := a.satellite()
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err)
+		return
+	}
+	found, err := q.find(sat, time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]passJSON, len(found))
+	for i, pass := range found {
+		out[i] = newPassJSON(pass, nil, a.weights)
+	}
+	writeAPIJSON(w, out)
+}
+
+type passQuery struct {
+	observer     track.Observer
+	days         float64
+	minElevation float64
+	visible      bool
+	count        int
+}
+
+func newPassQuery(observer track.Observer, days, minElevation float64, visible bool, count float64) (passQuery, error) {
+	if days <= 0 || days > apiMaxPassDays {
+		return passQuery{}, fmt.Errorf("days must be between 0 and %d", apiMaxPassDays)
+	}
+	if count < 0 {
+		return passQuery{}, errors.New("count must not be negative")
+	}
+	return passQuery{observer: observer, days: days, minElevation: minElevation, visible: visible, count: int(count)}, nil
+}
+
+func (q passQuery) find(sat *track.Satellite, from time.Time) ([]track.Pass, error) {
+	found, err := track.FindPasses(sat, q.observer, from, from.Add(time.Duration(q.days*float64(24*time.Hour))), q.minElevation)
+	if err != nil {
+		return nil, err
+	}
+	if q.visible {
+		found = visiblePasses(found)
+	}
+	if q.count > 0 && len(found) > q.count {
+		found = found[:q.count]
+	}
+	return found, nil
+}
+
+func (a *apiServer) satellite() (*track.Satellite, error) {
+	set, err := loadTLE(a.client, track.ISSNoradID, a.tleRefresh)
+	if set.Line1 == "" {
+		return nil, err
+	}
+	return track.NewSatellite(set)
+}
+
+func (a *apiServer) listCrew(w http.ResponseWriter, r *http.Request) {
+	people, err := track.FetchCrew(a.client)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeAPIJSON(w, people)
+}
+
+func apiObserver(lat, lon, altM string, fallback *track.Observer) (track.Observer, error) {
+	alt, err := apiNumber(altM, 0)
+	if err != nil {
+		return track.Observer{}, fmt.Errorf("alt_m: %w", err)
+	}
+	if lat == "" && lon == "" {
+		return passObserver(nil, nil, alt, fallback)
+	}
+	latitude, latErr := strconv.ParseFloat(lat, 64)
+	longitude, lonErr := strconv.ParseFloat(lon, 64)
+	if latErr != nil || lonErr != nil {
+		return track.Observer{}, errObserverCoords
+	}
+	return passObserver(&latitude, &longitude, alt, fallback)
+}
+
+var errObserverCoords = errors.New("lat and lon must be given together, in degrees")
+
+func passObserver(lat, lon *float64, altM float64, fallback *track.Observer) (track.Observer, error) {
+	if lat == nil && lon == nil {
+		if fallback == nil {
+			return track.Observer{}, errors.New("lat and lon are required without an observer in the config")
+		}
+		return *fallback, nil
+	}
+	if lat == nil || lon == nil || *lat < -90 || *lat > 90 || *lon < -180 || *lon > 180 {
+		return track.Observer{}, errObserverCoords
+	}
+	return track.Observer{Lat: *lat, Lon: *lon, AltKm: altM / 1000}, nil
+}
+
+func apiNumber(value string, fallback float64) (float64, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func writeAPIJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
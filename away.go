@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	awayMinimum          = time.Minute
+	awayPassMinElevation = 10
+)
+
+type awayState struct {
+	since     time.Time
+	countries map[string]bool
+}
+
+type suspendMsg struct{}
+
+func (a awayState) active() bool {
+	return !a.since.IsZero()
+}
+
+func (m model) leave(now time.Time) model {
+	if !m.away.active() {
+		m.away = awayState{since: now, countries: map[string]bool{}}
+	}
+	return m.stopMapAnimation()
+}
+
+func (m model) observeAway(loc geo.Location) {
+	if !m.away.active() {
+		return
+	}
+	code := loc.CountryCode
+	if code == "" {
+		code = geo.CountryCode(loc.Name)
+	}
+	if code != "" {
+		m.away.countries[strings.ToUpper(code)] = true
+	}
+}
+
+func (m model) suspend() (model, tea.Cmd) {
+	if !suspendSupported {
+		return m, nil
+	}
+	return m.leave(clockNow()), tea.Suspend
+}
+
+func (m model) comeBack(now time.Time) (model, tea.Cmd) {
+	away := m.away
+	m.away = awayState{}
+	next, cmd := m.syncMapState()
+	if !away.active() || now.Sub(away.since) < awayMinimum {
+		return next, cmd
+	}
+
+	text := next.awaySummary(away, now)
+	next.events = appendLog(next.events, logEntry{at: now, text: text})
+	next, toastCmd := next.pushToast(text)
+	return next, tea.Batch(cmd, toastCmd)
+}
+
+func (m model) awaySummary(away awayState, now time.Time) string {
+	elapsed := now.Sub(away.since)
+	var parts []string
+	if m.sat != nil {
+		parts = append(parts, fmt.Sprintf(m.lang.text("away_orbits"), elapsed.Hours()/m.sat.Period().Hours()))
+	}
+	if len(away.countries) > 0 {
+		parts = append(parts, fmt.Sprintf(m.lang.text("away_countries"), len(away.countries)))
+	}
+	if m.sat != nil && m.observer != nil {
+		passes, err := track.FindPasses(m.sat, *m.observer, away.since, now, awayPassMinElevation)
+		if err == nil && len(passes) > 0 {
+			visible := len(visiblePasses(passes))
+			parts = append(parts, fmt.Sprintf(m.lang.text("away_passes"), len(passes), visible))
+		}
+	}
+	summary := fmt.Sprintf(m.lang.text("away_summary"), formatStayDuration(elapsed))
+	if len(parts) == 0 {
+		return summary
+	}
+	return summary + ": " + strings.Join(parts, " · ")
+}
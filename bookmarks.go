@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxBookmarkName = 24
+
+type bookmarkConfig struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+	AltM float64 `json:"alt_m"`
+}
+
+func (b bookmarkConfig) observer() track.Observer {
+	return track.Observer{Lat: b.Lat, Lon: b.Lon, AltKm: b.AltM / 1000}
+}
+
+func bookmarksFromConfig(cfg config) ([]bookmarkConfig, error) {
+	seen := map[string]bool{}
+	for i, b := range cfg.Bookmarks {
+		name := strings.TrimSpace(b.Name)
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("bookmarks[%d]: name is required", i)
+		case seen[strings.ToLower(name)]:
+			return nil, fmt.Errorf("bookmarks[%d]: duplicate name %q", i, name)
+		case b.Lat < -90 || b.Lat > 90:
+			return nil, fmt.Errorf("bookmarks[%d]: lat must be between -90 and 90", i)
+		case b.Lon < -180 || b.Lon > 180:
+			return nil, fmt.Errorf("bookmarks[%d]: lon must be between -180 and 180", i)
+		}
+		seen[strings.ToLower(name)] = true
+	}
+	return cfg.Bookmarks, nil
+}
+
+func (m model) activeBookmark() int {
+	if m.observer == nil {
+		return -1
+	}
+	for i, b := range m.bookmarks {
+		if b.Lat == m.observer.Lat && b.Lon == m.observer.Lon {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) cycleBookmark() (model, tea.Cmd) {
+	if len(m.bookmarks) == 0 {
+		m.notice = m.lang.text("no_bookmarks")
+		return m, nil
+	}
+	b := m.bookmarks[(m.activeBookmark()+1)%len(m.bookmarks)]
+	m, cmd := m.setObserver(func(o *track.Observer) { *o = b.observer() })
+	m.notice = fmt.Sprintf(m.lang.text("observer_at"), b.Name)
+	next, syncCmd := m.syncMapState()
+	return next, tea.Batch(cmd, syncCmd)
+}
+
+func (m model) saveBookmark(name string) (model, tea.Cmd, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return m, nil, errors.New("name is required")
+	case len([]rune(name)) > maxBookmarkName:
+		return m, nil, fmt.Errorf("name is longer than %d characters", maxBookmarkName)
+	case m.observer == nil:
+		return m, nil, errors.New("set the observer latitude and longitude first")
+	}
+
+	saved := bookmarkConfig{Name: name, Lat: m.observer.Lat, Lon: m.observer.Lon, AltM: m.observer.AltKm * 1000}
+	index := bookmarkIndex(m.bookmarks, name)
+	if index < 0 {
+		bookmarks := append(append([]bookmarkConfig(nil), m.bookmarks...), saved)
+		next, cmd := m.withBookmarks(bookmarks, len(bookmarks)-1)
+		return next, cmd, nil
+	}
+	m.dialog = newConfirmDialog(fmt.Sprintf(m.lang.text("confirm_replace_bookmark"), m.bookmarks[index].Name), func(m model) (model, tea.Cmd) {
+		bookmarks := append([]bookmarkConfig(nil), m.bookmarks...)
+		bookmarks[index] = saved
+		return m.withBookmarks(bookmarks, index)
+	})
+	return m, nil, nil
+}
+
+func (m model) renameBookmark(name string) (model, tea.Cmd, error) {
+	name = strings.TrimSpace(name)
+	if len(m.bookmarks) == 0 {
+		return m, nil, errors.New("no bookmark selected")
+	}
+	switch index := bookmarkIndex(m.bookmarks, name); {
+	case name == "":
+		return m, nil, errors.New("name is required")
+	case len([]rune(name)) > maxBookmarkName:
+		return m, nil, fmt.Errorf("name is longer than %d characters", maxBookmarkName)
+	case index >= 0 && index != m.bookmarkCursor:
+		return m, nil, fmt.Errorf("%q already exists", name)
+	}
+
+	bookmarks := append([]bookmarkConfig(nil), m.bookmarks...)
+	bookmarks[m.bookmarkCursor].Name = name
+	next, cmd := m.withBookmarks(bookmarks, m.bookmarkCursor)
+	return next, cmd, nil
+}
+
+func (m model) deleteBookmark() (model, tea.Cmd) {
+	if len(m.bookmarks) == 0 {
+		return m, nil
+	}
+	cursor := m.bookmarkCursor
+	m.dialog = newConfirmDialog(fmt.Sprintf(m.lang.text("confirm_delete_bookmark"), m.bookmarks[cursor].Name), func(m model) (model, tea.Cmd) {
+		bookmarks := append([]bookmarkConfig(nil), m.bookmarks[:cursor]...)
+		bookmarks = append(bookmarks, m.bookmarks[cursor+1:]...)
+		return m.withBookmarks(bookmarks, min(cursor, len(bookmarks)-1))
+	})
+	return m, nil
+}
+
+func (m model) withBookmarks(bookmarks []bookmarkConfig, cursor int) (model, tea.Cmd) {
+	m.bookmarks, m.bookmarkCursor = bookmarks, max(cursor, 0)
+	m.profilePasses = nil
+	next, cmd := m.syncMapState()
+	return next, tea.Batch(cmd, persistConfigCmd(m.configPath, "bookmarks", bookmarks))
+}
+
+func bookmarkIndex(bookmarks []bookmarkConfig, name string) int {
+	for i, b := range bookmarks {
+		if strings.EqualFold(b.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) bookmarkSetting() string {
+	if len(m.bookmarks) == 0 {
+		return "-"
+	}
+	b := m.bookmarks[m.bookmarkCursor]
+	value := fmt.Sprintf("%d/%d %s (%s, %s)", m.bookmarkCursor+1, len(m.bookmarks), b.Name, formatSettingNumber(b.Lat), formatSettingNumber(b.Lon))
+	if m.bookmarkCursor == m.activeBookmark() {
+		value += " *"
+	}
+	return value
+}
+
+func (m model) stepBookmark(step int) (model, tea.Cmd) {
+	if count := len(m.bookmarks); count > 0 {
+		m.bookmarkCursor = (m.bookmarkCursor + step + count) % count
+	}
+	return m, nil
+}
+
+func (m model) bookmarkGlyphs() []render.Glyph {
+	active := m.activeBookmark()
+	glyphs := make([]render.Glyph, len(m.bookmarks))
+	for i, b := range m.bookmarks {
+		color := m.palette.color("bright-cyan")
+		if i == active {
+			color = m.palette.color("bright-green")
+		}
+		glyphs[i] = render.Glyph{Lat: b.Lat, Lon: b.Lon, Char: '+', Color: color}
+	}
+	return glyphs
+}
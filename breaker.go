@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	breakerThreshold   = 3
+	breakerCooldown    = 15 * time.Second
+	breakerMaxCooldown = 5 * time.Minute
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breaker struct {
+	state    breakerState
+	failures int
+	openedAt time.Time
+	cooldown time.Duration
+	probing  bool
+}
+
+type breakerSet struct {
+	mu       sync.Mutex
+	services map[string]*breaker
+}
+
+type breakerOpenError struct {
+	service string
+	retry   time.Duration
+}
+
+type breakerStatus struct {
+	service string
+	state   breakerState
+	retry   time.Duration
+}
+
+func (e *breakerOpenError) Error() string {
+	return fmt.Sprintf("%s keeps failing, not retrying for %s", e.service, e.retry)
+}
+
+func newBreakerSet() *breakerSet {
+	return &breakerSet{services: map[string]*breaker{}}
+}
+
+func (s *breakerSet) allow(service string, now time.Time) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.services[service]
+	if b == nil || b.state == breakerClosed {
+		return nil
+	}
+	if b.state == breakerOpen {
+		if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return &breakerOpenError{service: service, retry: (wait + time.Second - 1).Truncate(time.Second)}
+		}
+		b.state = breakerHalfOpen
+	}
+	if b.probing {
+		return &breakerOpenError{service: service, retry: time.Second}
+	}
+	b.probing = true
+	return nil
+}
+
+func (s *breakerSet) report(service, outcome string, now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failed := breakerFailure(outcome)
+	b := s.services[service]
+	if b == nil {
+		if !failed {
+			return
+		}
+		b = &breaker{}
+		s.services[service] = b
+	}
+	b.probing = false
+
+	switch {
+	case outcome == "", outcome == "http_4xx":
+		*b = breaker{}
+	case !failed:
+		return
+	case b.state == breakerHalfOpen:
+		b.state, b.openedAt = breakerOpen, now
+		b.cooldown = min(b.cooldown*2, breakerMaxCooldown)
+	case b.state == breakerClosed:
+		b.failures++
+		if b.failures >= breakerThreshold {
+			b.state, b.openedAt, b.cooldown = breakerOpen, now, breakerCooldown
+		}
+	}
+}
+
+func (s *breakerSet) tripped(now time.Time) []breakerStatus {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []breakerStatus
+	for service, b := range s.services {
+		switch b.state {
+		case breakerOpen:
+			out = append(out, breakerStatus{service: service, state: b.state, retry: max(b.openedAt.Add(b.cooldown).Sub(now), 0)})
+		case breakerHalfOpen:
+			out = append(out, breakerStatus{service: service, state: b.state})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].service < out[j].service })
+	return out
+}
+
+func breakerFailure(outcome string) bool {
+	switch outcome {
+	case "timeout", "dns", "tls", "connection", "rate_limited", "http_5xx":
+		return true
+	}
+	return false
+}
+
+func (m model) breakerLine(now time.Time) string {
+	tripped := m.stats.breakers.tripped(now)
+	if len(tripped) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(tripped))
+	for i, status := range tripped {
+		if status.state == breakerHalfOpen {
+			parts[i] = fmt.Sprintf(m.lang.text("breaker_half_open"), status.service)
+			continue
+		}
+		parts[i] = fmt.Sprintf(m.lang.text("breaker_open"), status.service, (status.retry + time.Second - 1).Truncate(time.Second))
+	}
+	return strings.Join(parts, " · ")
+}
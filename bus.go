@@ -0,0 +1,120 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/rules"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type busTopic int
+
+const (
+	topicTelemetry busTopic = iota
+	topicPass
+	topicPassSoon
+	topicRegion
+	topicPosition
+	topicRule
+	topicOrbit
+	topicAnnounce
+	topicError
+	topicCountry
+	topicCount
+)
+
+var topicNames = [topicCount]string{"telemetry", "pass", "pass_soon", "region", "position", "rule", "orbit", "announce", "error", "country"}
+
+func (t busTopic) String() string {
+	return topicNames[t]
+}
+
+type busEvent struct {
+	topic busTopic
+	at    time.Time
+	text  string
+	pos   track.Position
+	pass  *track.Pass
+	hook  *hook
+	vars  rules.Vars
+	err   error
+}
+
+type subscriber func(busEvent) tea.Cmd
+
+type eventBus struct {
+	subs [topicCount][]subscriber
+}
+
+func newEventBus(cfg config) eventBus {
+	var bus eventBus
+	bus.subscribe(logSubscriber, topicTelemetry, topicPass, topicPassSoon, topicRegion, topicPosition, topicRule, topicOrbit, topicAnnounce, topicError, topicCountry)
+	if len(cfg.NotifyCommand) > 0 {
+		command := cfg.NotifyCommand
+		bus.subscribe(func(e busEvent) tea.Cmd {
+			return notifyCmd(command, "ISS", e.text)
+		}, topicRegion, topicPassSoon, topicOrbit, topicCountry)
+	}
+	bus.subscribe(func(e busEvent) tea.Cmd {
+		return hookCmd(*e.hook, e.vars)
+	}, topicRule)
+	return bus
+}
+
+func (b *eventBus) subscribe(fn subscriber, topics ...busTopic) {
+	for _, topic := range topics {
+		b.subs[topic] = append(b.subs[topic], fn)
+	}
+}
+
+func (b eventBus) deliver(e busEvent) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, fn := range b.subs[e.topic] {
+		if cmd := fn(e); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+func logSubscriber(e busEvent) tea.Cmd {
+	switch {
+	case e.err != nil:
+		slog.Warn("command failed", "err", e.err)
+	case e.topic == topicTelemetry:
+		slog.Debug("event", "topic", e.topic, "lat", e.pos.Lat, "lon", e.pos.Lon)
+	case e.pass != nil:
+		slog.Debug("event", "topic", e.topic, "start", e.pass.Start, "max_elevation", e.pass.MaxElevation)
+	default:
+		slog.Debug("event", "topic", e.topic, "text", e.text)
+	}
+	return nil
+}
+
+func (m model) publish(events ...busEvent) (model, []tea.Cmd) {
+	var cmds []tea.Cmd
+	for len(events) > 0 {
+		e := events[0]
+		events = events[1:]
+
+		switch e.topic {
+		case topicTelemetry:
+			var followUps []busEvent
+			m, followUps = m.runHooks(e.at)
+			events = append(events, followUps...)
+		case topicPassSoon, topicRegion, topicPosition, topicRule, topicOrbit, topicCountry:
+			m.events = appendLog(m.events, logEntry{at: e.at, text: e.text})
+			if e.topic == topicPassSoon || e.topic == topicCountry {
+				var cmd tea.Cmd
+				m, cmd = m.pushToast(e.text)
+				cmds = append(cmds, cmd)
+			}
+		case topicError:
+			m.lastErr = e.err.Error()
+		}
+		cmds = append(cmds, m.bus.deliver(e)...)
+	}
+	return m, cmds
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type castMsg struct {
+	path    string
+	started bool
+	err     error
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type castRecorder struct {
+	*os.File
+
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	events  *bufio.Writer
+	started time.Time
+}
+
+func newCastRecorder(out *os.File) *castRecorder {
+	return &castRecorder{File: out}
+}
+
+func (c *castRecorder) Write(p []byte) (int, error) {
+	n, err := c.File.Write(p)
+
+	c.mu.Lock()
+	if c.events != nil && n > 0 {
+		c.writeEvent("o", string(p[:n]))
+	}
+	c.mu.Unlock()
+
+	return n, err
+}
+
+func (c *castRecorder) writeEvent(kind string, data string) {
+	elapsed := time.Since(c.started).Seconds()
+	encoded, _ := json.Marshal([]any{elapsed, kind, data})
+	c.events.Write(encoded)
+	c.events.WriteByte('\n')
+}
+
+func (c *castRecorder) recording() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.path, c.events != nil
+}
+
+func (c *castRecorder) start(path string, width, height int, screen string, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.events != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: now.Unix(),
+		Title:     "iss",
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	c.path, c.file, c.started = path, file, now
+	c.events = bufio.NewWriter(file)
+	c.events.Write(header)
+	c.events.WriteByte('\n')
+
+	c.writeEvent("o", "\x1b[H\x1b[2J"+strings.ReplaceAll(screen, "\n", "\r\n"))
+	return nil
+}
+
+func (c *castRecorder) resize(width, height int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.events != nil {
+		c.writeEvent("r", fmt.Sprintf("%dx%d", width, height))
+	}
+}
+
+func (c *castRecorder) stop() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.events == nil {
+		return "", nil
+	}
+
+	err := c.events.Flush()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	path := c.path
+	c.events, c.file, c.path = nil, nil, ""
+
+	return path, err
+}
+
+func toggleCastCmd(c *castRecorder, width, height int, screen string, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if _, active := c.recording(); active {
+			path, err := c.stop()
+			if err != nil {
+				return castMsg{err: fmt.Errorf("cast recording: %w", err)}
+			}
+			return castMsg{path: path}
+		}
+
+		name := "iss-" + now.Format("20060102-150405") + ".cast"
+		path, err := filepath.Abs(name)
+		if err != nil {
+			path = name
+		}
+		if err := c.start(path, width, height, screen, now); err != nil {
+			return castMsg{err: fmt.Errorf("cast recording: %w", err)}
+		}
+		return castMsg{path: path, started: true}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+type charset int
+
+const (
+	charsetAuto charset = iota
+	charsetUnicode
+	charsetASCII
+	charsetNerd
+)
+
+var charsetNames = []string{"auto", "unicode", "ascii", "nerd"}
+
+var asciiGlyphs = strings.NewReplacer(
+	"·", "-", "°", "*", "’", "'", "→", ">", "←", "<", "↑", "^", "↓", "v",
+	"…", ".", "±", "~", "¿", "?", "–", "-",
+	"▁", "_", "▂", ".", "▃", ":", "▄", "-", "▅", "=", "▆", "+", "▇", "*", "█", "#",
+	"─", "-", "│", "|", "━", "=", "┃", "#", "✕", "X", "\uf197", "X",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "┏", "+", "┓", "+", "┗", "+", "┛", "+", "╭", "+", "╮", "+", "╰", "+", "╯", "+",
+)
+
+type glyphProfile struct {
+	border render.Border
+	strong render.Border
+	marker render.MarkerGlyphs
+	icons  map[string]string
+}
+
+var lightBorder = render.Border{Horizontal: "─", Vertical: "│", TopLeft: "┌", TopRight: "┐", BottomLeft: "└", BottomRight: "┘"}
+
+var heavyBorder = render.Border{Horizontal: "━", Vertical: "┃", TopLeft: "┏", TopRight: "┓", BottomLeft: "┗", BottomRight: "┛"}
+
+var glyphProfiles = map[charset]glyphProfile{
+	charsetASCII: {
+		strong: render.Border{Horizontal: "=", Vertical: "#"},
+	},
+	charsetUnicode: {
+		border: lightBorder,
+		strong: heavyBorder,
+		marker: render.MarkerGlyphs{Center: "✕", Horizontal: "─", Vertical: "│"},
+	},
+	charsetNerd: {
+		border: render.Border{Horizontal: "─", Vertical: "│", TopLeft: "╭", TopRight: "╮", BottomLeft: "╰", BottomRight: "╯"},
+		strong: heavyBorder,
+		marker: render.MarkerGlyphs{Center: "\uf197", Horizontal: "─", Vertical: "│"},
+		icons:  map[string]string{"clock": "\uf017", "location": "\uf0ac", "toast": "\uf0f3", "dialog": "\uf059"},
+	},
+}
+
+func parseCharset(value string) (charset, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return charsetAuto, nil
+	}
+
+	for i, name := range charsetNames {
+		if name == value {
+			return charset(i), nil
+		}
+	}
+
+	return charsetAuto, fmt.Errorf("unknown charset %q (want auto, unicode, ascii or nerd)", value)
+}
+
+func chosenCharset(opts globalOptions, cfg config) (charset, error) {
+	return parseCharset(cmp.Or(opts.charset, cfg.Charset))
+}
+
+func (c charset) String() string {
+	return charsetNames[c]
+}
+
+func (c charset) resolve(unicodeTerm bool) charset {
+	if c != charsetAuto {
+		return c
+	}
+	if unicodeTerm {
+		return charsetUnicode
+	}
+	return charsetASCII
+}
+
+func (m model) glyphs() glyphProfile {
+	return glyphProfiles[m.charset.resolve(m.unicodeTerm)]
+}
+
+func (m model) asciiOnly() bool {
+	return m.charset.resolve(m.unicodeTerm) == charsetASCII
+}
+
+func (m model) icon(name string) string {
+	if icon := m.glyphs().icons[name]; icon != "" {
+		return icon + " "
+	}
+	return ""
+}
+
+func (m model) box(lines []string) string {
+	return boxWith(lines, m.glyphs().border)
+}
+
+func (m model) mapStyle() render.Style {
+	style := m.style
+	style.Border = m.glyphs().border
+	style.Marker = m.glyphs().marker
+	return style
+}
+
+func (m model) applyCharset(screen string) string {
+	if !m.asciiOnly() {
+		return screen
+	}
+	return asciiGlyphs.Replace(screen)
+}
+
+type asciiWriter struct {
+	w io.Writer
+}
+
+func (a asciiWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, asciiGlyphs.Replace(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,558 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+type globalOptions struct {
+	lang       string
+	configPath string
+	utc        bool
+	color      string
+	charset    string
+	debug      bool
+	demo       bool
+}
+
+type tuiOptions struct {
+	tour      bool
+	record    string
+	recordFor time.Duration
+	attach    string
+	fresh     bool
+	inline    bool
+	height    int
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+	tui := &tuiOptions{}
+
+	root := &cobra.Command{
+		Use:           "iss",
+		Short:         "Track the International Space Station from your terminal",
+		Long:          "iss shows the live ISS position on an ASCII world map.\nRun without a subcommand to start the interactive TUI.",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		Args:          cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := newPalette(opts.color, false); err != nil {
+				return err
+			}
+			if _, err := parseCharset(opts.charset); err != nil {
+				return err
+			}
+			if cfg, err := loadConfig(opts.configPath); err == nil && capableTerminal() {
+				if glyphs, err := chosenCharset(*opts, cfg); err == nil && glyphs.resolve(unicodeConsole()) == charsetASCII {
+					cmd.Root().SetOut(asciiWriter{os.Stdout})
+				}
+			}
+			if opts.demo {
+				if err := startDemo(time.Now()); err != nil {
+					return err
+				}
+			}
+			return setupLogging(opts.debug)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTUI(cmd.Context(), *opts, *tui)
+		},
+	}
+
+	root.PersistentFlags().StringVar(&opts.lang, "lang", "", "language for labels and location names (e.g. de, fr-CA); defaults to $LANG")
+	root.PersistentFlags().StringVar(&opts.configPath, "config", "", "config file path (default $XDG_CONFIG_HOME/iss/config.json)")
+	root.PersistentFlags().StringVar(&opts.color, "color", "auto", "color output: always, never or auto (auto honours NO_COLOR and dumb terminals)")
+	root.PersistentFlags().StringVar(&opts.charset, "charset", "", "glyphs to draw with: auto, unicode, ascii or nerd (Nerd Font icons); overrides the charset setting")
+	root.PersistentFlags().BoolVar(&opts.debug, "debug", false, "write a debug log with API and frame timings to the data directory")
+	root.PersistentFlags().BoolVar(&opts.demo, "demo", false, "follow a synthetic ISS-like orbit computed locally, without any network access")
+	root.PersistentFlags().BoolVar(&opts.utc, "utc", false, "show times in UTC instead of the configured time zone")
+	addTUIFlags(root, tui)
+
+	root.AddCommand(
+		newTUICmd(opts),
+		newStatusCmd(opts),
+		newPositionCmd(opts),
+		newPassesCmd(opts),
+		newCrewCmd(opts),
+		newServeCmd(opts),
+		newDaemonCmd(opts),
+		newCtlCmd(),
+		newExportCmd(opts),
+		newWatchCmd(opts),
+		newTLECmd(opts),
+		newConjunctionCmd(opts),
+		newPhotoCmd(opts),
+		newTransitCmd(opts),
+		newHistoryCmd(opts),
+		newJournalCmd(opts),
+		newShareCmd(opts),
+		newPathsCmd(opts),
+		newDoctorCmd(opts),
+		newManCmd(),
+	)
+
+	return root
+}
+
+func newTUICmd(opts *globalOptions) *cobra.Command {
+	tui := &tuiOptions{}
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Start the interactive map (default)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTUI(cmd.Context(), *opts, *tui)
+		},
+	}
+	addTUIFlags(cmd, tui)
+
+	return cmd
+}
+
+func addTUIFlags(cmd *cobra.Command, tui *tuiOptions) {
+	cmd.Flags().BoolVar(&tui.tour, "tour", false, "start with the narrated tour ticker enabled")
+	cmd.Flags().StringVar(&tui.record, "record", "", "record the session to this animated GIF file")
+	cmd.Flags().DurationVar(&tui.recordFor, "duration", defaultRecordDuration, "how long to record with --record")
+	cmd.Flags().StringVar(&tui.attach, "attach", "", "take positions from the `socket` of a running iss daemon instead of fetching them")
+	cmd.Flags().Lookup("attach").NoOptDefVal = attachDefaultSocket
+	cmd.Flags().BoolVar(&tui.fresh, "fresh", false, "start without restoring the position, trail and view saved by the last session")
+	cmd.Flags().BoolVar(&tui.inline, "inline", false, "draw below the prompt in a fixed number of rows instead of taking over the screen")
+	cmd.Flags().IntVar(&tui.height, "height", 0, "rows to use with --inline (default 30)")
+}
+
+func newStatusCmd(opts *globalOptions) *cobra.Command {
+	status := statusOptions{maxAge: defaultStatusMaxAge}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the current ISS position and location once",
+		Long: "Print the current ISS position and location once.\n" +
+			"--format takes a Go template over .Country, .Lat, .Lon, .Latitude, .Longitude,\n" +
+			".Altitude, .Velocity, .AltitudeKm, .VelocityKmh, .Provider and .Time.\n" +
+			"Results are cached briefly so status bars can poll cheaply.",
+		Example: "  iss status --format '{{.Country}} {{printf \"%.1f\" .Lat}},{{printf \"%.1f\" .Lon}}'\n  iss status --waybar",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(cmd.Context(), *opts, cmd.OutOrStdout(), status)
+		},
+	}
+	cmd.Flags().StringVar(&status.format, "format", "", "Go template for the output line")
+	cmd.Flags().BoolVar(&status.waybar, "waybar", false, "print waybar custom-module JSON (text from --format)")
+	cmd.Flags().DurationVar(&status.maxAge, "max-age", status.maxAge, "reuse a cached position younger than this (0 disables)")
+
+	return cmd
+}
+
+func newPositionCmd(opts *globalOptions) *cobra.Command {
+	asJSON := false
+	cmd := &cobra.Command{
+		Use:   "position",
+		Short: "Print the raw ISS position from the live providers, without geocoding",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPosition(cmd.Context(), *opts, cmd.OutOrStdout(), asJSON)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print JSON")
+
+	return cmd
+}
+
+func newPassesCmd(opts *globalOptions) *cobra.Command {
+	passes := passesOptions{count: defaultPassCount, days: 3}
+	cmd := &cobra.Command{
+		Use:   "passes",
+		Short: "Predict upcoming ISS passes over an observer",
+		Long:  "Predict upcoming ISS passes over an observer by propagating the current Celestrak TLE with SGP4.\nTimes are shown in the local time zone. A pass is visible when the station\nis sunlit while the Sun is at least 6° below your horizon.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPasses(cmd, *opts, cmd.OutOrStdout(), passes)
+		},
+	}
+	addObserverFlags(cmd, &passes.observer)
+	cmd.Flags().StringVar(&passes.from, "from", "", "start of the search window, e.g. 2025-07-01 or 2025-07-01 21:00 (default now)")
+	cmd.Flags().IntVarP(&passes.count, "count", "n", passes.count, "maximum number of passes to list, 0 for all (all by default when --from or --days is set)")
+	cmd.Flags().IntVar(&passes.days, "days", passes.days, "length of the search window in days")
+	cmd.Flags().Float64Var(&passes.minElevation, "min-elevation", 10, "only list passes that climb at least this high, in degrees")
+	cmd.Flags().BoolVar(&passes.visible, "visible", false, "only list passes where the station is sunlit against a dark sky")
+	cmd.Flags().BoolVar(&passes.json, "json", false, "print JSON")
+	cmd.Flags().StringVar(&passes.ics, "ics", "", "write the passes to this iCalendar file (visible passes only unless --visible=false)")
+	cmd.Flags().DurationVar(&passes.alarm, "alarm", defaultPassAlarm, "reminder before each pass in the --ics file (0 for none)")
+	cmd.Flags().BoolVar(&passes.clouds, "clouds", false, "add the forecast cloud cover at each pass (Open-Meteo, up to 16 days ahead)")
+	cmd.Flags().BoolVar(&passes.compare, "compare", false, "show the next pass for the observer and every bookmark side by side")
+
+	return cmd
+}
+
+func newCrewCmd(opts *globalOptions) *cobra.Command {
+	asJSON := false
+	all := false
+	cmd := &cobra.Command{
+		Use:   "crew",
+		Short: "List the people currently aboard the ISS and count everyone in space",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCrew(cmd.Context(), cmd.OutOrStdout(), asJSON, all)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print JSON")
+	cmd.Flags().BoolVar(&all, "all", false, "include people on other spacecraft")
+
+	return cmd
+}
+
+func newServeCmd(opts *globalOptions) *cobra.Command {
+	addr := ":8080"
+	api := false
+	grpcAddr := ""
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Stream the live map to remote terminals over HTTP",
+		Long:  "Stream the live map to remote terminals over HTTP.\nView it with: curl -N http://host:8080/",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			colors, err := newPalette(opts.color, true)
+			if err != nil {
+				return err
+			}
+			m := newModel(cmd.Context(), *opts).withColors(colors.enabled)
+			m.charset = charsetUnicode
+			if grpcAddr != "" {
+				m.api.grpcAddr = grpcAddr
+			}
+			return runServer(addr, m, api)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", addr, "listen address")
+	cmd.Flags().BoolVar(&api, "api", false, "also serve the JSON API under /api/v1/")
+	cmd.Flags().StringVar(&grpcAddr, "grpc", "", "also serve the gRPC API on this address (default from config grpc_addr)")
+
+	return cmd
+}
+
+func newDaemonCmd(opts *globalOptions) *cobra.Command {
+	socket := ""
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Track headless and share positions with attached TUIs",
+		Long: "Run the tracker without a terminal: record history, send notifications, run hooks,\n" +
+			"scheduled jobs and the JSON API, and stream positions to TUIs started with --attach.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemon(cmd.Context(), *opts, socket)
+		},
+	}
+	cmd.Flags().StringVar(&socket, "socket", "", "unix socket path (default $XDG_RUNTIME_DIR/iss/daemon.sock)")
+
+	return cmd
+}
+
+func newCtlCmd() *cobra.Command {
+	socket := ""
+	cmd := &cobra.Command{
+		Use:   "ctl <command> [args]",
+		Short: "Control a running TUI or daemon",
+		Long: "Send a command to a running TUI or daemon over its control socket:\n" +
+			"  pause, resume            stop or restart position updates\n" +
+			"  set-interval <duration>  change the refresh interval, e.g. 10s\n" +
+			"  switch-provider <name>   try this position provider first\n" +
+			"  dump-state               print the current state as JSON",
+		Example:   "  iss ctl set-interval 30s\n  iss ctl dump-state | jq .position",
+		Args:      cobra.MinimumNArgs(1),
+		ValidArgs: ctlCommands,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCtl(cmd.Context(), cmd.OutOrStdout(), socket, args)
+		},
+	}
+	cmd.Flags().StringVar(&socket, "socket", "", "control socket path (default $XDG_RUNTIME_DIR/iss/ctl.sock)")
+
+	return cmd
+}
+
+func newExportCmd(opts *globalOptions) *cobra.Command {
+	width := 0
+	output := ""
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Render the current map and telemetry once as text",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				w = file
+			}
+
+			return runExport(cmd.Context(), *opts, w, width, output != "")
+		},
+	}
+	cmd.Flags().IntVar(&width, "width", width, "terminal width to lay out for (0 uses the default map width)")
+	cmd.Flags().StringVarP(&output, "output", "o", output, "write to this file instead of stdout")
+
+	return cmd
+}
+
+func newWatchCmd(opts *globalOptions) *cobra.Command {
+	watch := watchOptions{interval: telemetryInterval}
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print live updates without the TUI, for dumb terminals and pipes",
+		Long:  "Print live updates without the TUI.\nBy default a single status line is updated in place (or appended when not on a terminal);\nwith --map the whole map is redrawn using cursor movement.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWatch(cmd.Context(), *opts, cmd.OutOrStdout(), watch)
+		},
+	}
+	cmd.Flags().DurationVar(&watch.interval, "interval", watch.interval, "time between updates")
+	cmd.Flags().BoolVar(&watch.showMap, "map", false, "redraw the map and telemetry instead of a single line")
+	cmd.Flags().IntVar(&watch.width, "width", 0, "terminal width to lay out the map for")
+
+	return cmd
+}
+
+func newTLECmd(opts *globalOptions) *cobra.Command {
+	norad := track.ISSNoradID
+	cmd := &cobra.Command{
+		Use:   "tle",
+		Short: "Print the current two-line element set from Celestrak",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats := newProviderStats()
+			defer stats.save()
+
+			set, err := track.FetchTLE(newHTTPClient(cmd.Context(), stats), norad)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if set.Name != "" {
+				fmt.Fprintln(out, set.Name)
+			}
+			fmt.Fprintln(out, set.Line1)
+			fmt.Fprintln(out, set.Line2)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&norad, "norad", norad, "NORAD catalog number")
+
+	return cmd
+}
+
+func newDoctorCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the config, the providers, the orbital elements and the terminal",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), *opts, cmd.OutOrStdout())
+		},
+	}
+}
+
+func newPathsCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "paths",
+		Short: "Print where the config, cache and data files live",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writePaths(cmd.OutOrStdout(), opts.configPath)
+		},
+	}
+}
+
+func newHistoryCmd(opts *globalOptions) *cobra.Command {
+	hist := historyOptions{since: "24h"}
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Query positions recorded by the TUI",
+		Long:  "Query positions recorded by the TUI when \"history\": true is set in the config file.\n--since and --until take a duration back from now (24h, 7d) or a date.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(*opts, cmd.OutOrStdout(), hist)
+		},
+	}
+	cmd.Flags().StringVar(&hist.since, "since", hist.since, "start of the range, e.g. 90m, 24h, 7d or 2025-07-01")
+	cmd.Flags().StringVar(&hist.until, "until", "", "end of the range (default now)")
+	cmd.Flags().BoolVar(&hist.json, "json", false, "print one JSON object per sample")
+	cmd.Flags().BoolVar(&hist.countries, "countries", false, "summarize the time spent over each country and ocean instead")
+
+	return cmd
+}
+
+func newManCmd() *cobra.Command {
+	dir := ""
+	cmd := &cobra.Command{
+		Use:   "man",
+		Short: "Generate man pages",
+		Long:  "Print the iss(1) man page, or write one page per command into --dir.\nSOURCE_DATE_EPOCH is honoured for reproducible builds.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			root.DisableAutoGenTag = true
+			header := &doc.GenManHeader{Title: "ISS", Section: "1", Source: "iss", Manual: "User Commands"}
+
+			if dir == "" {
+				return doc.GenMan(root, header, cmd.OutOrStdout())
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			return doc.GenManTree(root, header, dir)
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "write a page for every command into this directory")
+
+	return cmd
+}
+
+func runPosition(ctx context.Context, opts globalOptions, w io.Writer, asJSON bool) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	units, err := parseUnits(cfg.Units)
+	if err != nil {
+		return err
+	}
+	providers, err := positionProviders(cfg)
+	if err != nil {
+		return err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+
+	client := newHTTPClient(ctx, stats)
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, client)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+
+	pos, err := track.FetchPosition(client, providers)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		out := map[string]any{
+			"lat":       pos.Lat,
+			"lon":       pos.Lon,
+			"timestamp": pos.Timestamp.UTC(),
+			"provider":  pos.Provider,
+		}
+		if pos.HasAltitude {
+			out["altitude_km"] = pos.AltitudeKm
+			out["velocity_kmh"] = pos.VelocityKmh
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	lang := resolveLanguage(opts.lang, cfg.Lang)
+	fields := [][2]string{
+		{lang.text("latitude"), formatLatitude(pos.Lat)},
+		{lang.text("longitude"), formatLongitude(pos.Lon)},
+	}
+	if pos.HasAltitude {
+		fields = append(fields,
+			[2]string{lang.text("altitude"), units.formatDistance(pos.AltitudeKm)},
+			[2]string{lang.text("velocity"), units.formatSpeed(pos.VelocityKmh)},
+		)
+	}
+	fields = append(fields, [2]string{"Time", times.format(pos.Timestamp, time.RFC3339)}, [2]string{"Source", pos.Provider})
+	for _, line := range alignFields(fields) {
+		fmt.Fprintln(w, line)
+	}
+
+	return nil
+}
+
+func runCrew(ctx context.Context, w io.Writer, asJSON bool, all bool) error {
+	stats := newProviderStats()
+	defer stats.save()
+
+	people, err := track.FetchCrew(newHTTPClient(ctx, stats))
+	if err != nil {
+		return err
+	}
+
+	crew := make([]track.CrewMember, 0, len(people))
+	for _, person := range people {
+		if all || strings.EqualFold(person.Craft, "ISS") {
+			crew = append(crew, person)
+		}
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(crew)
+	}
+
+	for _, person := range crew {
+		if all {
+			fmt.Fprintf(w, "%s (%s)\n", person.Name, person.Craft)
+		} else {
+			fmt.Fprintln(w, person.Name)
+		}
+	}
+
+	counts := track.CountByCraft(people)
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprintf("%d on %s", count.People, count.Craft)
+	}
+	fmt.Fprintf(w, "\n%d people in space: %s\n", len(people), strings.Join(parts, ", "))
+	return nil
+}
+
+func runExport(ctx context.Context, opts globalOptions, w io.Writer, width int, toFile bool) error {
+	m := newModel(ctx, opts)
+	if m.mapMask == nil {
+		return fmt.Errorf("%s", m.lastErr)
+	}
+
+	m.charset = charsetUnicode
+	m.width = width
+	if toFile {
+		colors, err := newPalette(opts.color, false)
+		if err != nil {
+			return err
+		}
+		m = m.withColors(colors.enabled)
+	}
+
+	switch msg := fetchTelemetryCmd(m.client, m.providers, m.geocodes, m.lang, m.location())().(type) {
+	case errMsg:
+		return msg.err
+	case telemetryMsg:
+		m = m.applyTelemetry(msg)
+	}
+	defer m.stats.save()
+
+	rendered, err := renderMap(m.rasters, m.rasterKey(), m.style, m.lat, m.lon, m.hasCoords, m.mapGlyphs(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(w, renderScreen(rendered, telemetryBox(m.telemetryLines()), m.width))
+	return err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/Kivayan/iss/pkg/weather"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	cloudsRefresh      = time.Hour
+	cloudForecastDays  = 3
+	cloudLayerStepDeg  = 20
+	cloudLayerMaxLat   = 60
+	cloudLayerCoverPct = 70
+)
+
+type cloudsMsg struct {
+	forecast *weather.Forecast
+	layer    []render.Glyph
+	err      error
+}
+
+type cloudsTickMsg struct{}
+
+func cloudsTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return cloudsTickMsg{}
+	})
+}
+
+func cloudLayerPoints() []weather.Point {
+	var points []weather.Point
+	for lat := -cloudLayerMaxLat; lat <= cloudLayerMaxLat; lat += cloudLayerStepDeg {
+		for lon := -180 + cloudLayerStepDeg/2; lon < 180; lon += cloudLayerStepDeg {
+			points = append(points, weather.Point{Lat: float64(lat), Lon: float64(lon)})
+		}
+	}
+	return points
+}
+
+func fetchCloudsCmd(client *http.Client, observer *track.Observer, layer bool, color string) tea.Cmd {
+	return func() tea.Msg {
+		var msg cloudsMsg
+		var errs []error
+		if observer != nil {
+			forecast, err := weather.CloudForecast(client, weather.Point{Lat: observer.Lat, Lon: observer.Lon}, cloudForecastDays)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				msg.forecast = &forecast
+			}
+		}
+		if layer {
+			points := cloudLayerPoints()
+			cover, err := weather.CurrentCloudCover(client, points)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			for i, pct := range cover {
+				if pct >= cloudLayerCoverPct {
+					msg.layer = append(msg.layer, render.Glyph{Lat: points[i].Lat, Lon: points[i].Lon, Char: '~', Color: color})
+				}
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			msg.err = fmt.Errorf("clouds: %w", err)
+		}
+		return msg
+	}
+}
+
+func (m model) cloudsEnabled() bool {
+	return (m.clouds && m.observer != nil) || m.cloudLayerOn
+}
+
+func (m model) cloudField(pass *track.Pass) ([2]string, bool) {
+	if m.cloudForecast == nil || pass == nil {
+		return [2]string{}, false
+	}
+	cover, ok := m.cloudForecast.At(pass.Max)
+	if !ok {
+		return [2]string{}, false
+	}
+	return [2]string{m.lang.text("cloud_cover"), fmt.Sprintf("%.0f%%", cover)}, true
+}
+
+func (m model) fetchCloudsCmd() tea.Cmd {
+	observer := m.observer
+	if !m.clouds {
+		observer = nil
+	}
+	return fetchCloudsCmd(m.client, observer, m.cloudLayerOn, m.palette.color("bright-white"))
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const collectionFileName = "collection.json"
+
+type collectedCountry struct {
+	Name  string    `json:"name"`
+	First time.Time `json:"first"`
+}
+
+type countryCollection struct {
+	mu        sync.Mutex
+	path      string
+	countries map[string]collectedCountry
+}
+
+func newCountryCollection(enabled bool) (*countryCollection, error) {
+	if !enabled {
+		return nil, nil
+	}
+	c := &countryCollection{countries: map[string]collectedCountry{}}
+	if demo != nil {
+		return c, nil
+	}
+
+	path, err := dataPath(collectionFileName)
+	if err != nil {
+		return c, err
+	}
+	c.path = path
+	countries, err := readCollection(path)
+	if err != nil {
+		return c, err
+	}
+	c.countries = countries
+	return c, nil
+}
+
+func readCollection(path string) (map[string]collectedCountry, error) {
+	data, err := readFileIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+	countries := map[string]collectedCountry{}
+	if len(data) == 0 {
+		return countries, nil
+	}
+	if err := json.Unmarshal(data, &countries); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return countries, nil
+}
+
+func (c *countryCollection) add(loc geo.Location, at time.Time) bool {
+	if c == nil {
+		return false
+	}
+	code := strings.ToUpper(loc.CountryCode)
+	if code == "" {
+		code = geo.CountryCode(loc.Name)
+	}
+	if code == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.countries[code]; ok {
+		return false
+	}
+	c.countries[code] = collectedCountry{Name: loc.Name, First: at.UTC()}
+	return true
+}
+
+func (c *countryCollection) progress() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for code := range c.countries {
+		if geo.Sovereign(code) {
+			count++
+		}
+	}
+	return count, geo.SovereignCount()
+}
+
+func (c *countryCollection) save() error {
+	if c == nil || c.path == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return withFileLock(c.path, func() error {
+		onDisk, err := readCollection(c.path)
+		if err != nil {
+			onDisk = map[string]collectedCountry{}
+		}
+		for code, country := range c.countries {
+			if seen, ok := onDisk[code]; !ok || country.First.Before(seen.First) {
+				onDisk[code] = country
+			}
+		}
+		data, err := json.MarshalIndent(onDisk, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(c.path, data, 0o644); err != nil {
+			return err
+		}
+		c.countries = onDisk
+		return nil
+	})
+}
+
+func saveCollectionCmd(c *countryCollection) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.save(); err != nil {
+			return errMsg{err: fmt.Errorf("collection: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (m model) collect(at time.Time) (model, []busEvent, tea.Cmd) {
+	if !m.collection.add(m.location(), at) {
+		return m, nil, nil
+	}
+	count, total := m.collection.progress()
+	text := fmt.Sprintf(m.lang.text("new_country"), m.issOver, count, total)
+	return m, []busEvent{{topic: topicCountry, at: at, text: text}}, saveCollectionCmd(m.collection)
+}
+
+func (m model) collectionView() string {
+	if m.collection == nil {
+		return "\n" + centerBlock(m.box([]string{m.lang.text("collection_off")}), m.width) + "\n"
+	}
+
+	count, total := m.collection.progress()
+	lines := []string{fmt.Sprintf(m.lang.text("collection"), count, total), ""}
+
+	m.collection.mu.Lock()
+	codes := make([]string, 0, len(m.collection.countries))
+	for code := range m.collection.countries {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := m.collection.countries[codes[i]], m.collection.countries[codes[j]]
+		return a.First.Before(b.First) || a.First.Equal(b.First) && codes[i] < codes[j]
+	})
+	table := [][]string{{m.lang.text("location"), "ISO", m.lang.text("first_seen")}}
+	for _, code := range codes {
+		country := m.collection.countries[code]
+		table = append(table, []string{country.Name, code, m.times.format(country.First, layoutStamp)})
+	}
+	m.collection.mu.Unlock()
+
+	if len(codes) == 0 {
+		lines = append(lines, "  "+m.lang.text("no_samples"))
+	} else {
+		for _, line := range formatTable(table, nil) {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type config struct {
+	MapDetail           string            `json:"map_detail"`
+	MemoryLimitMB       int               `json:"memory_limit_mb"`
+	Lang                string            `json:"lang"`
+	PlaceNames          string            `json:"place_names"`
+	LocationDisplay     string            `json:"location_display"`
+	CountryFlag         string            `json:"country_flag"`
+	Charset             string            `json:"charset"`
+	CellRedraw          *bool             `json:"cell_redraw"`
+	Tour                bool              `json:"tour"`
+	Units               string            `json:"units"`
+	Providers           []string          `json:"providers"`
+	ScreenshotFormat    string            `json:"screenshot_format"`
+	Observer            *observerConfig   `json:"observer"`
+	Regions             []regionConfig    `json:"regions"`
+	NotifyCommand       []string          `json:"notify_command"`
+	Highlight           *highlightConfig  `json:"highlight"`
+	PersistCountryStats bool              `json:"persist_country_stats"`
+	CountryCollection   *bool             `json:"country_collection"`
+	History             bool              `json:"history"`
+	Radio               []radioConfig     `json:"radio"`
+	Rotator             *rotatorConfig    `json:"rotator"`
+	Rig                 *rigConfig        `json:"rig"`
+	TLERefreshHours     float64           `json:"tle_refresh_hours"`
+	TLEMaxAgeDays       float64           `json:"tle_max_age_days"`
+	PositionCheck       bool              `json:"position_check"`
+	PositionCheckKm     float64           `json:"position_check_km"`
+	Video               *videoConfig      `json:"video"`
+	SunMoon             bool              `json:"sun_moon"`
+	Clouds              bool              `json:"clouds"`
+	CloudLayer          bool              `json:"cloud_layer"`
+	PassScore           *passScoreWeights `json:"pass_score"`
+	Timezone            string            `json:"timezone"`
+	RefreshSeconds      float64           `json:"refresh_seconds"`
+	Theme               string            `json:"theme"`
+	Screen              string            `json:"screen"`
+	InlineHeight        int               `json:"inline_height"`
+	GeocodeMinKm        *float64          `json:"geocode_min_km"`
+	Bookmarks           []bookmarkConfig  `json:"bookmarks"`
+	Tiangong            bool              `json:"tiangong"`
+	SpaceWeather        bool              `json:"space_weather"`
+	FlyoverWarnings     *lookalikeConfig  `json:"flyover_warnings"`
+	Panels              []panelConfig     `json:"panels"`
+	Hooks               []hookConfig      `json:"hooks"`
+	APIAddr             string            `json:"api_addr"`
+	GRPCAddr            string            `json:"grpc_addr"`
+	Notify              *notifyConfig     `json:"notify"`
+	Speech              *speechConfig     `json:"speech"`
+	Schedule            *scheduleConfig   `json:"schedule"`
+	HistoryRetainDays   float64           `json:"history_retention_days"`
+}
+
+type observerConfig struct {
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+	AltM float64 `json:"alt_m"`
+}
+
+type regionConfig struct {
+	Name      string        `json:"name"`
+	Countries []string      `json:"countries"`
+	Box       []float64     `json:"box"`
+	Circle    *circleConfig `json:"circle"`
+}
+
+type circleConfig struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	RadiusKm float64 `json:"radius_km"`
+}
+
+type highlightConfig struct {
+	Country  string  `json:"country"`
+	RadiusKm float64 `json:"radius_km"`
+	Color    string  `json:"color"`
+	Flash    bool    `json:"flash"`
+}
+
+type radioConfig struct {
+	Name        string  `json:"name"`
+	DownlinkMHz float64 `json:"downlink_mhz"`
+	UplinkMHz   float64 `json:"uplink_mhz"`
+}
+
+type rotatorConfig struct {
+	Addr         string  `json:"addr"`
+	MinElevation float64 `json:"min_elevation"`
+}
+
+type rigConfig struct {
+	Addr        string  `json:"addr"`
+	DownlinkMHz float64 `json:"downlink_mhz"`
+}
+
+type videoConfig struct {
+	URL    string   `json:"url"`
+	Player []string `json:"player"`
+}
+
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json"), nil
+}
+
+func loadConfig(path string) (config, error) {
+	if path == "" {
+		var err error
+		path, err = configPath()
+		if err != nil {
+			return config{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return config{}, nil
+		}
+		return config{}, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func updateConfig(path string, key string, value any) error {
+	if path == "" {
+		var err error
+		path, err = configPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return withFileLock(path, func() error {
+		data, err := readFileIfExists(path)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+
+		raw := map[string]json.RawMessage{}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &raw); err != nil {
+				return fmt.Errorf("parse config %s: %w", path, err)
+			}
+		}
+		raw[key] = encoded
+
+		out, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		return writeFileAtomic(path, append(out, '\n'), 0o644)
+	})
+}
+
+func persistConfigCmd(path string, key string, value any) tea.Cmd {
+	return func() tea.Msg {
+		if err := updateConfig(path, key, value); err != nil {
+			return errMsg{err: fmt.Errorf("save config: %w", err)}
+		}
+		return nil
+	}
+}
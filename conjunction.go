@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+type conjunctionOptions struct {
+	hours float64
+	json  bool
+}
+
+type conjunctionJSON struct {
+	Time             time.Time          `json:"time"`
+	DistanceKm       float64            `json:"distance_km"`
+	RelativeSpeedKmS float64            `json:"relative_speed_kms"`
+	Objects          [2]conjunctionSide `json:"objects"`
+}
+
+type conjunctionSide struct {
+	Name       string  `json:"name"`
+	NoradID    int     `json:"norad_id"`
+	Lat        float64 `json:"lat"`
+	Lon        float64 `json:"lon"`
+	AltitudeKm float64 `json:"altitude_km"`
+}
+
+func newConjunctionCmd(opts *globalOptions) *cobra.Command {
+	conj := conjunctionOptions{hours: 24}
+	cmd := &cobra.Command{
+		Use:   "conjunction <norad> <norad>",
+		Short: "Find the closest approach of two tracked objects",
+		Long:  "Find when two objects, given by NORAD catalog number, come closest to each other\nover the next --hours, by propagating both Celestrak TLEs with SGP4.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConjunction(cmd, *opts, cmd.OutOrStdout(), args, conj)
+		},
+	}
+	cmd.Flags().Float64Var(&conj.hours, "hours", conj.hours, "length of the search window in hours")
+	cmd.Flags().BoolVar(&conj.json, "json", false, "print JSON")
+
+	return cmd
+}
+
+func runConjunction(cmd *cobra.Command, opts globalOptions, w io.Writer, args []string, conj conjunctionOptions) error {
+	var ids [2]int
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("%q is not a NORAD catalog number", arg)
+		}
+		ids[i] = id
+	}
+	if ids[0] == ids[1] {
+		return fmt.Errorf("both objects are NORAD %d", ids[0])
+	}
+	if conj.hours <= 0 {
+		return fmt.Errorf("--hours must be positive, got %g", conj.hours)
+	}
+
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+	client := newHTTPClient(cmd.Context(), stats)
+
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, client)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", warning)
+	}
+
+	var sats [2]*track.Satellite
+	for i, id := range ids {
+		set, err := loadTLE(client, id, tleRefreshFromConfig(cfg))
+		if set.Line1 == "" {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+		}
+		if sats[i], err = track.NewSatellite(set); err != nil {
+			return fmt.Errorf("NORAD %d: %w", id, err)
+		}
+		if set.Name == "" {
+			sats[i].Name = "NORAD " + strconv.Itoa(id)
+		}
+	}
+
+	from := time.Now()
+	found, err := track.ClosestApproach(sats[0], sats[1], from, from.Add(time.Duration(conj.hours*float64(time.Hour))))
+	if err != nil {
+		return err
+	}
+
+	if conj.json {
+		out := conjunctionJSON{Time: found.Time.UTC(), DistanceKm: found.DistanceKm, RelativeSpeedKmS: found.RelativeSpeedKmS}
+		for i, pos := range []track.Position{found.PositionA, found.PositionB} {
+			out.Objects[i] = conjunctionSide{Name: sats[i].Name, NoradID: ids[i], Lat: pos.Lat, Lon: pos.Lon, AltitudeKm: pos.AltitudeKm}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	fmt.Fprintf(w, "%s and %s are closest at %s %s\n", sats[0].Name, sats[1].Name, times.format(found.Time, layoutFull), times.zone(found.Time))
+	fmt.Fprintf(w, "%.1f km apart, passing at %.2f km/s\n\n", found.DistanceKm, found.RelativeSpeedKmS)
+	rows := [][]string{{"Object", "NORAD", "Lat", "Lon", "Altitude"}}
+	for i, pos := range []track.Position{found.PositionA, found.PositionB} {
+		rows = append(rows, []string{sats[i].Name, strconv.Itoa(ids[i]), formatLatitude(pos.Lat), formatLongitude(pos.Lon), fmt.Sprintf("%.0f km", pos.AltitudeKm)})
+	}
+	for _, line := range formatTable(rows, nil) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import "os"
+
+func enableVirtualTerminal() bool {
+	return false
+}
+
+func restoreConsole() {}
+
+func unicodeConsole() bool {
+	return true
+}
+
+func consoleDetail() string {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(key); locale != "" {
+			return "locale " + locale
+		}
+	}
+	return "locale "
+}
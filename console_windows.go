@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const codePageUTF8 = 65001
+
+var consoleModes = map[windows.Handle]uint32{}
+
+func enableVirtualTerminal() bool {
+	enabled := false
+	for _, handle := range []windows.Handle{windows.Stdout, windows.Stderr} {
+		var mode uint32
+		if windows.GetConsoleMode(handle, &mode) != nil {
+			continue
+		}
+		if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING == 0 {
+			if windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) != nil {
+				continue
+			}
+			consoleModes[handle] = mode
+		}
+		if handle == windows.Stdout {
+			enabled = true
+		}
+	}
+	return enabled
+}
+
+func restoreConsole() {
+	for handle, mode := range consoleModes {
+		windows.SetConsoleMode(handle, mode)
+	}
+}
+
+func unicodeConsole() bool {
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != "" || utf8Locale() {
+		return true
+	}
+	cp, err := windows.GetConsoleOutputCP()
+	return err != nil || cp == codePageUTF8
+}
+
+func consoleDetail() string {
+	cp, err := windows.GetConsoleOutputCP()
+	if err != nil {
+		return "not a console"
+	}
+	detail := fmt.Sprintf("code page %d", cp)
+	if os.Getenv("WT_SESSION") != "" {
+		detail += ", Windows Terminal"
+	}
+	return detail
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/hamlib"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	controlInterval    = time.Second
+	rotatorDeadbandDeg = 1.0
+	rigDeadbandHz      = 10.0
+	rotatorLead        = 2 * time.Minute
+)
+
+type controlTickMsg struct{}
+
+type controller struct {
+	mu           sync.Mutex
+	rotator      *hamlib.Conn
+	rig          *hamlib.Conn
+	minElevation float64
+	downlinkHz   float64
+	aimed        bool
+	lastAz       float64
+	lastEl       float64
+	lastHz       float64
+}
+
+func newController(cfg config) (*controller, error) {
+	if cfg.Rotator == nil && cfg.Rig == nil {
+		return nil, nil
+	}
+	if cfg.Observer == nil {
+		return nil, errors.New("rotator and rig control need an observer location")
+	}
+
+	c := &controller{}
+	if rc := cfg.Rotator; rc != nil {
+		if rc.Addr == "" {
+			return nil, errors.New("rotator: addr is required")
+		}
+		c.rotator = hamlib.Dial(rc.Addr)
+		c.minElevation = rc.MinElevation
+	}
+	if rc := cfg.Rig; rc != nil {
+		if rc.Addr == "" || rc.DownlinkMHz <= 0 {
+			return nil, errors.New("rig: addr and downlink_mhz are required")
+		}
+		c.rig = hamlib.Dial(rc.Addr)
+		c.downlinkHz = rc.DownlinkMHz * 1e6
+	}
+	return c, nil
+}
+
+func controlTick() tea.Cmd {
+	return tea.Tick(controlInterval, func(time.Time) tea.Msg {
+		return controlTickMsg{}
+	})
+}
+
+func controlCmd(c *controller, look track.Look, pass *track.Pass, now time.Time) tea.Cmd {
+	var next track.Pass
+	if pass != nil {
+		next = *pass
+	}
+	return func() tea.Msg {
+		if err := c.update(look, next, now); err != nil {
+			return errMsg{err: err}
+		}
+		return nil
+	}
+}
+
+func (c *controller) update(look track.Look, pass track.Pass, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	if c.rotator != nil {
+		az, el, ok := look.Azimuth, look.Elevation, look.Elevation >= c.minElevation
+		if !ok && pass.Start.After(now) && pass.Start.Sub(now) <= rotatorLead {
+			az, el, ok = pass.StartAzimuth, 0, true
+		}
+		if ok && (!c.aimed || angleGap(az, c.lastAz) >= rotatorDeadbandDeg || math.Abs(el-c.lastEl) >= rotatorDeadbandDeg) {
+			if err := c.rotator.SetPosition(az, el); err != nil {
+				errs = append(errs, fmt.Errorf("rotator: %w", err))
+			} else {
+				c.aimed, c.lastAz, c.lastEl = true, az, el
+			}
+		}
+	}
+
+	if c.rig != nil && look.Elevation >= 0 {
+		hz := track.DopplerDownlink(c.downlinkHz, look.RangeRateKmS)
+		if math.Abs(hz-c.lastHz) >= rigDeadbandHz {
+			if err := c.rig.SetFrequency(hz); err != nil {
+				errs = append(errs, fmt.Errorf("rig: %w", err))
+			} else {
+				c.lastHz = hz
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *controller) close() {
+	if c == nil {
+		return
+	}
+	if c.rotator != nil {
+		c.rotator.Close()
+	}
+	if c.rig != nil {
+		c.rig.Close()
+	}
+}
+
+func angleGap(a, b float64) float64 {
+	return math.Abs(math.Mod(a-b+540, 360) - 180)
+}
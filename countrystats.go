@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+const countryStatsMaxGap = 30 * time.Second
+
+type countrySort int
+
+const (
+	sortByTime countrySort = iota
+	sortByName
+)
+
+type countryRecord struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+type countryRow struct {
+	name     string
+	duration time.Duration
+	share    float64
+}
+
+type countryStats struct {
+	mu      sync.Mutex
+	path    string
+	session map[string]*countryRecord
+	history map[string]*countryRecord
+	unsaved map[string]*countryRecord
+	lastKey string
+	lastAt  time.Time
+}
+
+func newCountryStats(persist bool) *countryStats {
+	stats := &countryStats{
+		session: map[string]*countryRecord{},
+		history: map[string]*countryRecord{},
+		unsaved: map[string]*countryRecord{},
+	}
+	if !persist {
+		return stats
+	}
+
+	path, err := dataPath("country-stats.json")
+	if err != nil {
+		return stats
+	}
+	stats.path = path
+
+	withFileLock(stats.path, func() error {
+		history, err := readCountryRecords(stats.path)
+		if err == nil {
+			stats.history = history
+		}
+		return nil
+	})
+
+	return stats
+}
+
+func (o countrySort) next() countrySort {
+	if o == sortByTime {
+		return sortByName
+	}
+	return sortByTime
+}
+
+func countryKey(loc geo.Location) string {
+	if loc.CountryCode != "" {
+		return "country:" + loc.CountryCode
+	}
+	return "water:" + strings.ToLower(loc.Name)
+}
+
+func (s *countryStats) observe(loc geo.Location, at time.Time) {
+	if s == nil || loc.Name == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if gap := at.Sub(s.lastAt); s.lastKey != "" && gap > 0 && gap <= countryStatsMaxGap {
+		for _, records := range []map[string]*countryRecord{s.session, s.history, s.unsaved} {
+			rec := records[s.lastKey]
+			if rec == nil {
+				rec = &countryRecord{}
+				records[s.lastKey] = rec
+			}
+			rec.Seconds += gap.Seconds()
+		}
+	}
+
+	key := countryKey(loc)
+	for _, records := range []map[string]*countryRecord{s.session, s.history, s.unsaved} {
+		if rec := records[key]; rec != nil {
+			rec.Name = loc.Name
+		} else {
+			records[key] = &countryRecord{Name: loc.Name}
+		}
+	}
+	s.lastKey, s.lastAt = key, at
+}
+
+func (s *countryStats) persistent() bool {
+	return s != nil && s.path != ""
+}
+
+func (s *countryStats) rows(all bool, order countrySort) []countryRow {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := s.session
+	if all {
+		records = s.history
+	}
+
+	total := 0.0
+	for _, rec := range records {
+		total += rec.Seconds
+	}
+
+	rows := make([]countryRow, 0, len(records))
+	for _, rec := range records {
+		if rec.Seconds <= 0 {
+			continue
+		}
+		rows = append(rows, countryRow{
+			name:     rec.Name,
+			duration: time.Duration(rec.Seconds * float64(time.Second)),
+			share:    rec.Seconds / total * 100,
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if order == sortByName || rows[i].duration == rows[j].duration {
+			return strings.ToLower(rows[i].name) < strings.ToLower(rows[j].name)
+		}
+		return rows[i].duration > rows[j].duration
+	})
+	return rows
+}
+
+func (s *countryStats) save() error {
+	if !s.persistent() {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.unsaved) == 0 {
+		return nil
+	}
+
+	return withFileLock(s.path, func() error {
+		onDisk, err := readCountryRecords(s.path)
+		if err != nil {
+			onDisk = map[string]*countryRecord{}
+		}
+
+		for key, delta := range s.unsaved {
+			rec := onDisk[key]
+			if rec == nil {
+				rec = &countryRecord{}
+				onDisk[key] = rec
+			}
+			rec.Name = delta.Name
+			rec.Seconds += delta.Seconds
+		}
+
+		data, err := json.Marshal(onDisk)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(s.path, data, 0o644); err != nil {
+			return err
+		}
+
+		s.unsaved = map[string]*countryRecord{}
+		return nil
+	})
+}
+
+func readCountryRecords(path string) (map[string]*countryRecord, error) {
+	data, err := readFileIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := map[string]*countryRecord{}
+	if len(data) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse country stats: %w", err)
+	}
+
+	return records, nil
+}
+
+func countryStatsTable(lang language, title string, rows []countryRow) []string {
+	lines := []string{title}
+	if len(rows) == 0 {
+		return append(lines, "  "+lang.text("no_samples"))
+	}
+
+	table := [][]string{{lang.text("location"), lang.text("time"), "%"}}
+	for _, row := range rows {
+		table = append(table, []string{row.name, formatStayDuration(row.duration), fmt.Sprintf("%.1f", row.share)})
+	}
+	for _, line := range formatTable(table, map[int]bool{1: true, 2: true}) {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+func formatStayDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
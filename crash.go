@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var secretKeyHints = []string{"token", "secret", "password", "passwd", "key", "auth", "webhook", "url"}
+
+type crashReporter struct {
+	mu         sync.Mutex
+	program    *tea.Program
+	configPath string
+	once       sync.Once
+}
+
+var crashes = &crashReporter{}
+
+func (c *crashReporter) attach(p *tea.Program, configPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.program, c.configPath = p, configPath
+}
+
+func (c *crashReporter) recover() {
+	if r := recover(); r != nil {
+		c.crash(r, debug.Stack())
+	}
+}
+
+func (c *crashReporter) crash(value any, stack []byte) {
+	c.once.Do(func() {
+		c.mu.Lock()
+		program, cfgPath := c.program, c.configPath
+		c.mu.Unlock()
+		if program != nil {
+			program.ReleaseTerminal()
+		}
+
+		fmt.Fprintf(os.Stderr, "iss crashed: %v\n", value)
+		dir, err := writeCrashBundle(value, stack, cfgPath, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not write a crash report (%v):\n\n%s", err, stack)
+		} else {
+			fmt.Fprintf(os.Stderr, "A crash report was written to %s\n", dir)
+		}
+		os.Exit(2)
+	})
+}
+
+func (c *crashReporter) guard(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer c.recover()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = c.guard(batch[i])
+			}
+		}
+		return msg
+	}
+}
+
+type guardedModel struct {
+	model
+}
+
+func (g guardedModel) Init() tea.Cmd {
+	return crashes.guard(g.model.Init())
+}
+
+func (g guardedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := g.model.Update(msg)
+	return guardedModel{next.(model)}, crashes.guard(cmd)
+}
+
+func writeCrashBundle(value any, stack []byte, cfgPath string, now time.Time) (string, error) {
+	root, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, "crashes", now.UTC().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "panic: %v\n\n", value)
+	fmt.Fprintf(&report, "time:    %s\n", now.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&report, "version: %s\n", buildVersion())
+	fmt.Fprintf(&report, "go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&report, "args:    %s\n\n", strings.Join(os.Args[1:], " "))
+	report.Write(stack)
+
+	files := map[string][]byte{
+		"panic.txt": []byte(report.String()),
+		"log.txt":   []byte(strings.Join(recentLog.last(recentLogLines), "\n") + "\n"),
+	}
+	if snapshot, err := scrubbedConfig(cfgPath); err == nil && snapshot != nil {
+		files["config.json"] = snapshot
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "unknown"
+	}
+	return info.Main.Version
+}
+
+func scrubbedConfig(path string) ([]byte, error) {
+	if path == "" {
+		var err error
+		if path, err = configPath(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := readFileIfExists(path)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(scrubSecrets(raw), "", "  ")
+}
+
+func scrubSecrets(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if looksSecret(key) {
+				v[key] = "[redacted]"
+				continue
+			}
+			v[key] = scrubSecrets(inner)
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = scrubSecrets(inner)
+		}
+	case string:
+		if strings.Contains(v, "://") && strings.Contains(v, "@") {
+			return "[redacted]"
+		}
+	}
+	return value
+}
+
+func looksSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, hint := range secretKeyHints {
+		if strings.Contains(key, hint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	ctlSocketName = "ctl.sock"
+	ctlTimeout    = 5 * time.Second
+)
+
+type ctlRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+type ctlResponse struct {
+	OK     bool   `json:"ok"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ctlMsg struct {
+	mode  string
+	req   ctlRequest
+	reply chan<- ctlResponse
+}
+
+type ctlState struct {
+	Mode        string       `json:"mode"`
+	Version     string       `json:"version"`
+	Started     time.Time    `json:"started"`
+	Paused      bool         `json:"paused"`
+	Interval    string       `json:"interval"`
+	Providers   []string     `json:"providers"`
+	Attached    bool         `json:"attached"`
+	History     bool         `json:"history"`
+	Position    *apiPosition `json:"position,omitempty"`
+	CountryCode string       `json:"country_code,omitempty"`
+	NextPass    *passJSON    `json:"next_pass,omitempty"`
+	Jobs        []string     `json:"jobs,omitempty"`
+	LastError   string       `json:"last_error,omitempty"`
+}
+
+var ctlCommands = []string{"pause", "resume", "set-interval", "switch-provider", "dump-state"}
+
+func ctlSocketPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dir, err := appDir("XDG_RUNTIME_DIR", os.UserCacheDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ctlSocketName), nil
+}
+
+func serveCtl(ctx context.Context, p *tea.Program, mode string) func() {
+	path, err := ctlSocketPath("")
+	if err != nil {
+		return func() {}
+	}
+	ln, err := listenUnix(path)
+	if err != nil {
+		slog.Debug("control socket unavailable", "err", err)
+		return func() {}
+	}
+	go func() {
+		defer crashes.recover()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleCtl(ctx, p, mode, conn)
+		}
+	}()
+	return func() {
+		ln.Close()
+		os.Remove(path)
+	}
+}
+
+func handleCtl(ctx context.Context, p *tea.Program, mode string, conn net.Conn) {
+	defer crashes.recover()
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ctlTimeout))
+
+	var req ctlRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(ctlResponse{Error: fmt.Sprintf("bad request: %v", err)})
+		return
+	}
+
+	reply := make(chan ctlResponse, 1)
+	p.Send(ctlMsg{mode: mode, req: req, reply: reply})
+	select {
+	case resp := <-reply:
+		json.NewEncoder(conn).Encode(resp)
+	case <-ctx.Done():
+	case <-time.After(ctlTimeout):
+		json.NewEncoder(conn).Encode(ctlResponse{Error: "timed out waiting for the instance"})
+	}
+}
+
+func (m model) handleCtl(msg ctlMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	result, err := func() (any, error) {
+		args := msg.req.Args
+		switch msg.req.Command {
+		case "pause", "resume":
+			if len(args) != 0 {
+				return nil, fmt.Errorf("%s takes no arguments", msg.req.Command)
+			}
+			if m.paused() != (msg.req.Command == "pause") {
+				m, cmd = m.togglePause()
+			}
+			return nil, nil
+		case "set-interval":
+			if len(args) != 1 {
+				return nil, errors.New("usage: set-interval <duration>")
+			}
+			interval, err := time.ParseDuration(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if interval < telemetryMinGap {
+				return nil, fmt.Errorf("interval must be at least %v", telemetryMinGap)
+			}
+			m.interval = interval
+			return nil, nil
+		case "switch-provider":
+			if len(args) != 1 {
+				return nil, errors.New("usage: switch-provider <name>")
+			}
+			providers, err := track.ResolveProviders(args)
+			if err != nil {
+				return nil, err
+			}
+			for _, provider := range m.providers {
+				if provider.Name() != providers[0].Name() {
+					providers = append(providers, provider)
+				}
+			}
+			m.providers = providers
+			return nil, nil
+		case "dump-state":
+			return m.ctlState(msg.mode), nil
+		default:
+			return nil, fmt.Errorf("unknown command %q (want %s)", msg.req.Command, strings.Join(ctlCommands, ", "))
+		}
+	}()
+
+	resp := ctlResponse{OK: err == nil, Result: result}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	slog.Info("control command", "command", msg.req.Command, "args", msg.req.Args, "err", err)
+	msg.reply <- resp
+	return m, cmd
+}
+
+func (m model) ctlState(mode string) ctlState {
+	state := ctlState{
+		Mode:        mode,
+		Version:     buildVersion(),
+		Started:     m.started,
+		Paused:      m.paused(),
+		Interval:    m.interval.String(),
+		Attached:    m.daemon != nil,
+		History:     m.history != nil,
+		CountryCode: m.countryCode,
+		LastError:   m.lastErr,
+	}
+	for _, provider := range m.providers {
+		state.Providers = append(state.Providers, provider.Name())
+	}
+	if m.hasCoords {
+		pos := apiPosition{Lat: m.lat, Lon: m.lon, Over: m.issOver, Timestamp: m.motion.at}
+		if m.hasAltitude {
+			pos.AltitudeKm, pos.VelocityKmh = &m.altitudeKm, &m.velocityKmh
+		}
+		state.Position = &pos
+	}
+	if m.skyPass != nil {
+		pass := newPassJSON(*m.skyPass, nil, m.passWeights)
+		state.NextPass = &pass
+	}
+	for _, job := range m.jobs {
+		state.Jobs = append(state.Jobs, job.name+": "+job.when.String())
+	}
+	return state
+}
+
+func runCtl(ctx context.Context, w io.Writer, socket string, args []string) error {
+	path, err := ctlSocketPath(socket)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, ctlTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(dialCtx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("no running instance on %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * ctlTimeout))
+
+	if err := json.NewEncoder(conn).Encode(ctlRequest{Command: args[0], Args: args[1:]}); err != nil {
+		return err
+	}
+	var resp struct {
+		OK     bool            `json:"ok"`
+		Result json.RawMessage `json:"result"`
+		Error  string          `json:"error"`
+	}
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return fmt.Errorf("reading reply: %w", err)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	if len(resp.Result) == 0 {
+		fmt.Fprintln(w, "ok")
+		return nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, resp.Result, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err = w.Write(out.Bytes())
+	return err
+}
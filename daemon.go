@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	attachDefaultSocket = "default"
+	daemonSocketName    = "daemon.sock"
+	daemonDialTimeout   = 2 * time.Second
+	daemonMaxFrame      = 64 << 10
+)
+
+type daemonFrame struct {
+	Position    *track.Position `json:"position,omitempty"`
+	Location    string          `json:"location,omitempty"`
+	CountryCode string          `json:"country_code,omitempty"`
+	Located     bool            `json:"located"`
+}
+
+type daemonHub struct {
+	mu      sync.Mutex
+	latest  *daemonFrame
+	clients map[chan daemonFrame]struct{}
+}
+
+type daemonModel struct {
+	guardedModel
+	hub *daemonHub
+}
+
+type daemonClient struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+type daemonMsg struct {
+	msg tea.Msg
+}
+
+type daemonLostMsg struct {
+	err error
+}
+
+func defaultDaemonSocket() (string, error) {
+	dir, err := appDir("XDG_RUNTIME_DIR", os.UserCacheDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, daemonSocketName), nil
+}
+
+func resolveDaemonSocket(path string) (string, error) {
+	if path != "" && path != attachDefaultSocket {
+		return path, nil
+	}
+	return defaultDaemonSocket()
+}
+
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", path, daemonDialTimeout); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another instance is already listening on %s", path)
+	}
+	os.Remove(path)
+	return net.Listen("unix", path)
+}
+
+func runDaemon(ctx context.Context, opts globalOptions, socket string) error {
+	path, err := resolveDaemonSocket(socket)
+	if err != nil {
+		return err
+	}
+	m := newModel(ctx, opts)
+	if m.mapMask == nil {
+		return errors.New(m.lastErr)
+	}
+	if m.lastErr != "" {
+		return errors.New(m.lastErr)
+	}
+	m.mapMask = nil
+
+	ln, err := listenUnix(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	hub := &daemonHub{clients: map[chan daemonFrame]struct{}{}}
+	go hub.serve(ctx, ln)
+
+	fmt.Fprintf(os.Stderr, "iss daemon listening on %s (attach with: iss --attach=%s)\n", path, path)
+	slog.Info("daemon started", "socket", path)
+	p := tea.NewProgram(daemonModel{guardedModel{m}, hub}, tea.WithoutRenderer(), tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithContext(ctx))
+	closeCtl := serveCtl(ctx, p, "daemon")
+	defer closeCtl()
+	_, err = func() (tea.Model, error) {
+		defer crashes.recover()
+		return p.Run()
+	}()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		err = nil
+	}
+	ln.Close()
+	slog.Info("daemon stopped", "err", err)
+	m.stats.save()
+	m.countries.save()
+	m.control.close()
+	if historyErr := m.history.flush(); historyErr != nil && err == nil {
+		err = fmt.Errorf("history: %w", historyErr)
+	}
+	return err
+}
+
+func (d daemonModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := d.guardedModel.Update(msg)
+	g := next.(guardedModel)
+	if done, ok := msg.(telemetryDoneMsg); ok {
+		msg = done.msg
+	}
+	switch msg := msg.(type) {
+	case telemetryMsg:
+		pos := msg.pos
+		d.hub.publish(daemonFrame{Position: &pos, Location: msg.country, CountryCode: msg.countryCode, Located: msg.located})
+	case locationMsg:
+		if msg.loc.Name != "" {
+			d.hub.publish(daemonFrame{Location: msg.loc.Name, CountryCode: msg.loc.CountryCode, Located: true})
+		}
+	}
+	return daemonModel{g, d.hub}, cmd
+}
+
+func (h *daemonHub) publish(frame daemonFrame) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if frame.Position != nil {
+		h.latest = &frame
+	} else if h.latest != nil {
+		latest := *h.latest
+		latest.Location, latest.CountryCode, latest.Located = frame.Location, frame.CountryCode, true
+		h.latest = &latest
+	}
+	for ch := range h.clients {
+		select {
+		case ch <- frame:
+		default:
+		}
+	}
+}
+
+func (h *daemonHub) subscribe() (<-chan daemonFrame, func()) {
+	ch := make(chan daemonFrame, 4)
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	if h.latest != nil {
+		ch <- *h.latest
+	}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}
+}
+
+func (h *daemonHub) serve(ctx context.Context, ln net.Listener) {
+	defer crashes.recover()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.stream(ctx, conn)
+	}
+}
+
+func (h *daemonHub) stream(ctx context.Context, conn net.Conn) {
+	defer crashes.recover()
+	defer conn.Close()
+
+	frames, unsubscribe := h.subscribe()
+	defer unsubscribe()
+	slog.Debug("daemon client attached")
+
+	encoder := json.NewEncoder(conn)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case frame := <-frames:
+			if err := encoder.Encode(frame); err != nil {
+				slog.Debug("daemon client detached", "err", err)
+				return
+			}
+		}
+	}
+}
+
+func dialDaemon(path string) (*daemonClient, error) {
+	conn, err := net.DialTimeout("unix", path, daemonDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("no daemon on %s (start one with: iss daemon): %w", path, err)
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), daemonMaxFrame)
+	return &daemonClient{conn: conn, scanner: scanner}, nil
+}
+
+func (c *daemonClient) next() tea.Cmd {
+	return func() tea.Msg {
+		if !c.scanner.Scan() {
+			err := c.scanner.Err()
+			if err == nil {
+				err = io.EOF
+			}
+			return daemonLostMsg{err: err}
+		}
+		var frame daemonFrame
+		if err := json.Unmarshal(c.scanner.Bytes(), &frame); err != nil {
+			return daemonLostMsg{err: fmt.Errorf("bad frame: %w", err)}
+		}
+		if frame.Position == nil {
+			return daemonMsg{locationMsg{loc: geo.Location{Name: frame.Location, CountryCode: frame.CountryCode}}}
+		}
+		return daemonMsg{telemetryMsg{country: frame.Location, countryCode: frame.CountryCode, located: frame.Located, pos: *frame.Position}}
+	}
+}
+
+func (c *daemonClient) close() {
+	if c != nil {
+		c.conn.Close()
+	}
+}
+
+func (m model) attachTo(client *daemonClient) model {
+	m.daemon = client
+	m.history = nil
+	m.hooks, m.hooksFired = nil, nil
+	m.jobs = nil
+	m.digest = nil
+	m.passLead = 0
+	m.speaker = nil
+	m.api.addr, m.api.grpcAddr = "", ""
+	m.bus = newEventBus(config{})
+	return m
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	demoSpan = 24 * time.Hour
+	demoStep = 10 * time.Minute
+)
+
+var demoSpeeds = []float64{1, 10, 60}
+
+var errOffline = errors.New("network access is disabled in demo mode")
+
+type demoSession struct {
+	tle      track.TLE
+	provider *track.Synthetic
+	clock    *simClock
+}
+
+var demo *demoSession
+
+func startDemo(now time.Time) error {
+	tle := track.SyntheticTLE(now)
+	provider, err := track.NewSynthetic(tle)
+	if err != nil {
+		return fmt.Errorf("demo: %w", err)
+	}
+	clock := &simClock{start: now, anchor: now, at: now, speed: 1}
+	provider.Now = clock.now
+	demo = &demoSession{tle: tle, provider: provider, clock: clock}
+	return nil
+}
+
+func clockNow() time.Time {
+	if demo != nil {
+		return demo.clock.now()
+	}
+	return time.Now()
+}
+
+type simClock struct {
+	mu     sync.Mutex
+	start  time.Time
+	anchor time.Time
+	at     time.Time
+	speed  float64
+	frozen bool
+}
+
+func (c *simClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nowAt(time.Now())
+}
+
+func (c *simClock) nowAt(real time.Time) time.Time {
+	if c.frozen {
+		return c.at
+	}
+	return c.at.Add(time.Duration(float64(real.Sub(c.anchor)) * c.speed))
+}
+
+func (c *simClock) rebase(update func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	real := time.Now()
+	c.at, c.anchor = c.nowAt(real), real
+	update()
+}
+
+func (c *simClock) setSpeed(speed float64) {
+	c.rebase(func() { c.speed = speed })
+}
+
+func (c *simClock) seek(d time.Duration) {
+	c.rebase(func() {
+		c.at = c.at.Add(d)
+		if c.at.Before(c.start) {
+			c.at = c.start
+		}
+		if end := c.start.Add(demoSpan); c.at.After(end) {
+			c.at = end
+		}
+	})
+}
+
+func (c *simClock) freeze(frozen bool) {
+	c.rebase(func() { c.frozen = frozen })
+}
+
+func (c *simClock) state() (speed float64, elapsed time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.speed, c.nowAt(time.Now()).Sub(c.start)
+}
+
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%s: %w", req.URL.Host, errOffline)
+}
+
+func positionProviders(cfg config) ([]track.Provider, error) {
+	if demo != nil {
+		return []track.Provider{demo.provider}, nil
+	}
+	return track.ResolveProviders(cfg.Providers)
+}
+
+func demoConfig(cfg config) config {
+	if demo != nil {
+		cfg.History = false
+		cfg.PersistCountryStats = false
+		cfg.Rotator = nil
+		cfg.Rig = nil
+		cfg.Tiangong = false
+		cfg.FlyoverWarnings = nil
+	}
+	return cfg
+}
+
+func (m model) demoKey(key string) (model, tea.Cmd, bool) {
+	if demo == nil {
+		return m, nil, false
+	}
+
+	speed, _ := demo.clock.state()
+	index := 0
+	for i, s := range demoSpeeds {
+		if s == speed {
+			index = i
+		}
+	}
+	orbit := demoStep
+	if m.sat != nil {
+		orbit = m.sat.Period()
+	}
+
+	switch key {
+	case ">":
+		demo.clock.setSpeed(demoSpeeds[min(index+1, len(demoSpeeds)-1)])
+		return m, nil, true
+	case "<":
+		demo.clock.setSpeed(demoSpeeds[max(index-1, 0)])
+		return m, nil, true
+	case "[":
+		demo.clock.seek(-demoStep)
+	case "]":
+		demo.clock.seek(demoStep)
+	case "{":
+		demo.clock.seek(-orbit)
+	case "}":
+		demo.clock.seek(orbit)
+	default:
+		return m, nil, false
+	}
+
+	m.skyPass = nil
+	m = m.refreshSkyPass(clockNow())
+	if m.paused() {
+		m.pausedAt = clockNow()
+	}
+	if m.fetches.inFlight {
+		return m, nil, true
+	}
+	next, cmd := m.requestTelemetry(time.Now())
+	return next, cmd, true
+}
+
+func (m model) demoBar() string {
+	speed, elapsed := demo.clock.state()
+	speeds := make([]string, len(demoSpeeds))
+	for i, s := range demoSpeeds {
+		speeds[i] = fmt.Sprintf("%gx", s)
+		if s == speed {
+			speeds[i] = "[" + speeds[i] + "]"
+		}
+	}
+
+	const width = 32
+	filled := int(float64(width) * min(max(elapsed.Seconds()/demoSpan.Seconds(), 0), 1))
+	bar := strings.Repeat("=", filled) + "o" + strings.Repeat("-", width-filled)
+
+	line := fmt.Sprintf("%s |%s| +%s · %s", strings.Join(speeds, " "), bar, formatStayDuration(elapsed), m.lang.text("demo_help"))
+	if m.width > 0 {
+		line = ansi.Truncate(line, m.width, "")
+	}
+	return line
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	mapascii "github.com/Kivayan/map-ascii"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	memCheckInterval = 10 * time.Second
+	lowMemoryTotal   = 1 << 30
+	midMemoryTotal   = 2 << 30
+	memBudgetDivisor = 8
+)
+
+type mapDetail struct {
+	name        string
+	supersample int
+	maxWidth    int
+	maskScale   int
+}
+
+var mapDetailTiers = []mapDetail{
+	{name: "low", supersample: 1, maxWidth: 80, maskScale: 4},
+	{name: "medium", supersample: 2, maxWidth: 100, maskScale: 2},
+	{name: "high", supersample: mapSupersample, maxWidth: maxMapWidth, maskScale: 1},
+}
+
+type memCheckMsg time.Time
+
+func mapDetailByName(name string) (mapDetail, bool) {
+	for _, tier := range mapDetailTiers {
+		if tier.name == name {
+			return tier, true
+		}
+	}
+
+	return mapDetail{}, false
+}
+
+func lowerMapDetail(current mapDetail) (mapDetail, bool) {
+	for i, tier := range mapDetailTiers {
+		if tier.name == current.name && i > 0 {
+			return mapDetailTiers[i-1], true
+		}
+	}
+
+	return current, false
+}
+
+func resolveMapDetail(cfg config) (mapDetail, bool, uint64, error) {
+	total := systemMemoryTotal()
+
+	limit := uint64(cfg.MemoryLimitMB) << 20
+	if limit == 0 && total > 0 {
+		limit = total / memBudgetDivisor
+	}
+
+	name := strings.ToLower(strings.TrimSpace(cfg.MapDetail))
+	if name != "" && name != "auto" {
+		tier, ok := mapDetailByName(name)
+		if !ok {
+			return mapDetailTiers[len(mapDetailTiers)-1], true, limit, fmt.Errorf("unknown map_detail %q (want auto, low, medium or high)", cfg.MapDetail)
+		}
+		return tier, false, limit, nil
+	}
+
+	switch {
+	case total == 0:
+		return mapDetailTiers[2], true, limit, nil
+	case total < lowMemoryTotal:
+		return mapDetailTiers[0], true, limit, nil
+	case total < midMemoryTotal:
+		return mapDetailTiers[1], true, limit, nil
+	default:
+		return mapDetailTiers[2], true, limit, nil
+	}
+}
+
+func systemMemoryTotal() uint64 {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb << 10
+	}
+
+	return 0
+}
+
+func processMemoryUsage() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse + stats.StackInuse
+}
+
+func memCheckTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return memCheckMsg(t)
+	})
+}
+
+func downsampleMask(mask *mapascii.LandMask, factor int) *mapascii.LandMask {
+	if mask == nil || factor <= 1 {
+		return mask
+	}
+
+	width := mask.Width / factor
+	height := mask.Height / factor
+	if width < 2 || height < 2 {
+		return mask
+	}
+
+	data := make([]float64, width*height)
+	cells := float64(factor * factor)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum := 0.0
+			for dy := 0; dy < factor; dy++ {
+				row := (y*factor + dy) * mask.Width
+				for dx := 0; dx < factor; dx++ {
+					sum += mask.Data[row+x*factor+dx]
+				}
+			}
+			data[y*width+x] = sum / cells
+		}
+	}
+
+	return &mapascii.LandMask{Width: width, Height: height, Data: data}
+}
+
+func releaseMemory() {
+	debug.FreeOSMemory()
+}
+
+func formatMapDetail(detail mapDetail, auto bool, usage uint64) string {
+	mode := "fixed"
+	if auto {
+		mode = "auto"
+	}
+
+	return fmt.Sprintf("%s (%s, %d MB)", detail.name, mode, usage>>20)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type dialogKind int
+
+const (
+	dialogConfirm dialogKind = iota
+	dialogInput
+	dialogSelect
+)
+
+type dialog struct {
+	active  bool
+	kind    dialogKind
+	title   string
+	detail  string
+	options []string
+	cursor  int
+	input   string
+	focus   int
+	err     string
+	submit  func(m model, d dialog) (model, tea.Cmd, error)
+}
+
+func newConfirmDialog(title string, confirm func(m model) (model, tea.Cmd)) dialog {
+	return dialog{active: true, kind: dialogConfirm, title: title, focus: 1, submit: func(m model, d dialog) (model, tea.Cmd, error) {
+		next, cmd := confirm(m)
+		return next, cmd, nil
+	}}
+}
+
+func newInputDialog(title, input string, submit func(m model, input string) (model, tea.Cmd, error)) dialog {
+	return dialog{active: true, kind: dialogInput, title: title, input: input, submit: func(m model, d dialog) (model, tea.Cmd, error) {
+		return submit(m, strings.TrimSpace(d.input))
+	}}
+}
+
+func newSelectDialog(title string, options []string, choose func(m model, index int) (model, tea.Cmd)) dialog {
+	return dialog{active: true, kind: dialogSelect, title: title, options: options, submit: func(m model, d dialog) (model, tea.Cmd, error) {
+		next, cmd := choose(m, d.cursor)
+		return next, cmd, nil
+	}}
+}
+
+func (d *dialog) buttons() []string {
+	if d.kind == dialogConfirm {
+		return []string{"dialog_yes", "dialog_no"}
+	}
+	return []string{"dialog_ok", "dialog_cancel"}
+}
+
+func (d *dialog) focusCount() int {
+	if d.kind == dialogConfirm {
+		return len(d.buttons())
+	}
+	return len(d.buttons()) + 1
+}
+
+func (d *dialog) focused() string {
+	if d.kind != dialogConfirm {
+		if d.focus == 0 {
+			return "body"
+		}
+		return d.buttons()[d.focus-1]
+	}
+	return d.buttons()[d.focus]
+}
+
+func (m model) dialogKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	d := &m.dialog
+	key := msg.String()
+	switch key {
+	case "esc":
+		m.dialog = dialog{}
+		return m, nil
+	case "tab", "shift+tab":
+		step := 1
+		if key == "shift+tab" {
+			step = d.focusCount() - 1
+		}
+		d.focus = (d.focus + step) % d.focusCount()
+		return m, nil
+	case "enter":
+		switch d.focused() {
+		case "dialog_no", "dialog_cancel":
+			m.dialog = dialog{}
+			return m, nil
+		}
+		return m.submitDialog()
+	}
+
+	switch d.kind {
+	case dialogConfirm:
+		switch key {
+		case "left", "right", "h", "l":
+			d.focus = (d.focus + 1) % d.focusCount()
+		case "y":
+			return m.submitDialog()
+		case "n":
+			m.dialog = dialog{}
+		}
+	case dialogInput:
+		if d.focused() != "body" {
+			return m, nil
+		}
+		d.err = ""
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if runes := []rune(d.input); len(runes) > 0 {
+				d.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyCtrlU:
+			d.input = ""
+		case tea.KeySpace:
+			d.input += " "
+		case tea.KeyRunes:
+			d.input += string(msg.Runes)
+		}
+	case dialogSelect:
+		switch key {
+		case "up", "k":
+			d.cursor = (d.cursor + len(d.options) - 1) % len(d.options)
+		case "down", "j":
+			d.cursor = (d.cursor + 1) % len(d.options)
+		default:
+			if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(d.options) {
+				d.cursor = n - 1
+				return m.submitDialog()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) submitDialog() (model, tea.Cmd) {
+	d := m.dialog
+	m.dialog = dialog{}
+	next, cmd, err := d.submit(m, d)
+	if err != nil {
+		d.err = err.Error()
+		m.dialog = d
+		return m, nil
+	}
+	return next, cmd
+}
+
+func (m model) dialogLines() []string {
+	d := &m.dialog
+	width := max(m.width-10, 20)
+	lines := []string{ansi.Truncate(m.icon("dialog")+d.title, width, "…")}
+	if d.detail != "" {
+		lines = append(lines, ansi.Truncate(d.detail, width, "…"))
+	}
+	lines = append(lines, "")
+
+	switch d.kind {
+	case dialogInput:
+		field := "[" + d.input + "_]"
+		if d.focused() != "body" {
+			field = " " + d.input + " "
+		}
+		lines = append(lines, ansi.TruncateLeft(field, max(ansi.StringWidth(field)-width, 0), "…"), "")
+	case dialogSelect:
+		for i, option := range d.options {
+			marker := "  "
+			if i == d.cursor {
+				marker = "> "
+				if d.focused() != "body" {
+					marker = "* "
+				}
+			}
+			lines = append(lines, ansi.Truncate(marker+strconv.Itoa(i+1)+" "+option, width, "…"))
+		}
+		lines = append(lines, "")
+	}
+
+	buttons := make([]string, 0, len(d.buttons()))
+	for _, button := range d.buttons() {
+		label := m.lang.text(button)
+		if button == d.focused() {
+			label = "[" + label + "]"
+		} else {
+			label = " " + label + " "
+		}
+		buttons = append(buttons, label)
+	}
+	lines = append(lines, strings.Join(buttons, "  "))
+	if d.err != "" {
+		lines = append(lines, "", ansi.Truncate(d.err, width, "…"))
+	}
+	help := map[dialogKind]string{dialogConfirm: "dialog_confirm_help", dialogInput: "prompt_help", dialogSelect: "choose_help"}[d.kind]
+	return append(lines, "", m.lang.text(help))
+}
+
+func (m model) overlayDialog(screen string) string {
+	if !m.dialog.active {
+		return screen
+	}
+	box := strings.Split(m.box(m.dialogLines()), "\n")
+	lines := strings.Split(screen, "\n")
+	top := max((len(lines)-len(box))/2, 0)
+	for len(lines) < top+len(box) {
+		lines = append(lines, "")
+	}
+	col := max((m.width-ansi.StringWidth(box[0]))/2, 0)
+	for i, row := range box {
+		lines[top+i] = overlayLine(lines[top+i], row, col)
+	}
+	return strings.Join(lines, "\n")
+}
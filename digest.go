@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/notify"
+	"github.com/Kivayan/iss/pkg/schedule"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultDigestSchedule = "0 7 * * *"
+	digestWindow          = 24 * time.Hour
+	digestMinElevation    = 10
+)
+
+type emailConfig struct {
+	Addr     string   `json:"addr"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Schedule string   `json:"schedule"`
+}
+
+type passDigest struct {
+	email    notify.Email
+	schedule *schedule.Schedule
+}
+
+func digestFromConfig(cfg config) (*passDigest, error) {
+	if cfg.Notify == nil || cfg.Notify.Email == nil {
+		return nil, nil
+	}
+	ec := cfg.Notify.Email
+	if cfg.Observer == nil {
+		return nil, errors.New("email digest needs an observer")
+	}
+	if !strings.Contains(ec.Addr, ":") {
+		return nil, errors.New("email addr must be host:port")
+	}
+	if _, err := mail.ParseAddress(ec.From); err != nil {
+		return nil, fmt.Errorf("email from: %w", err)
+	}
+	if len(ec.To) == 0 {
+		return nil, errors.New("email needs at least one address in to")
+	}
+	for _, to := range ec.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("email to: %w", err)
+		}
+	}
+	expr := ec.Schedule
+	if expr == "" {
+		expr = defaultDigestSchedule
+	}
+	when, err := schedule.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("email %w", err)
+	}
+	return &passDigest{
+		email:    notify.Email{Addr: ec.Addr, Username: ec.Username, Password: ec.Password, From: ec.From, To: ec.To},
+		schedule: when,
+	}, nil
+}
+
+func (d *passDigest) sendCmd(client *http.Client, observer track.Observer, times timeDisplay, weights passScoreWeights, tleRefresh time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		if err := d.send(client, observer, times, weights, tleRefresh, time.Now()); err != nil {
+			return errMsg{err: fmt.Errorf("email digest: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (d *passDigest) send(client *http.Client, observer track.Observer, times timeDisplay, weights passScoreWeights, tleRefresh time.Duration, now time.Time) error {
+	set, err := loadTLE(client, track.ISSNoradID, tleRefresh)
+	if set.Line1 == "" {
+		return err
+	}
+	sat, err := track.NewSatellite(set)
+	if err != nil {
+		return err
+	}
+	found, err := track.FindPasses(sat, observer, now, now.Add(digestWindow), digestMinElevation)
+	if err != nil {
+		return err
+	}
+	found = visiblePasses(found)
+	if len(found) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Visible ISS passes over %s, %s in the next 24 hours:\n\n", formatLatitude(observer.Lat), formatLongitude(observer.Lon))
+	for _, line := range passTable(found, nil, weights, times) {
+		text.WriteString(line + "\n")
+	}
+	text.WriteString("\nThe attached calendar has a reminder 10 minutes before each pass.\n")
+
+	var ics bytes.Buffer
+	if err := writePassesICS(&ics, found, observer, nil, times, defaultPassAlarm, now); err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("ISS passes for %s: %d visible", times.format(now, layoutDay), len(found))
+	return d.email.Send(subject, text.String(), notify.Attachment{
+		Name:        "iss-passes.ics",
+		ContentType: "text/calendar; method=PUBLISH; charset=utf-8",
+		Data:        ics.Bytes(),
+	})
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+)
+
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "FAIL"
+)
+
+type doctorCheck struct {
+	status string
+	name   string
+	detail string
+}
+
+func runDoctor(ctx context.Context, opts globalOptions, w io.Writer) error {
+	stats := newProviderStats()
+	defer stats.save()
+	client := newHTTPClient(ctx, stats)
+
+	cfg, checks := doctorConfig(opts)
+	checks = append(checks, doctorProviders(client, cfg)...)
+	checks = append(checks, doctorTLE(client, cfg, time.Now()))
+	checks = append(checks, doctorTerminal(opts, cfg)...)
+
+	rows := make([][]string, 0, len(checks))
+	failed := 0
+	for _, check := range checks {
+		rows = append(rows, []string{"[" + check.status + "]", check.name, check.detail})
+		if check.status == doctorFail {
+			failed++
+		}
+	}
+	for _, line := range formatTable(rows, nil) {
+		fmt.Fprintln(w, line)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+func doctorConfig(opts globalOptions) (config, []doctorCheck) {
+	path := opts.configPath
+	if path == "" {
+		path, _ = configPath()
+	}
+
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return config{}, []doctorCheck{{doctorFail, "config", err.Error()}}
+	}
+	if _, statErr := os.Stat(path); statErr != nil {
+		return cfg, []doctorCheck{{doctorOK, "config", path + " not found, using defaults"}}
+	}
+
+	validators := []func(config) error{
+		func(cfg config) error { _, _, _, err := resolveMapDetail(cfg); return err },
+		func(cfg config) error { _, err := parseUnits(cfg.Units); return err },
+		func(cfg config) error { _, err := parseLocationDisplay(cfg.LocationDisplay); return err },
+		func(cfg config) error { _, err := parseCountryFlag(cfg.CountryFlag); return err },
+		func(cfg config) error { _, err := parseCharset(cfg.Charset); return err },
+		func(cfg config) error { _, err := inlineRowsFromConfig(cfg); return err },
+		func(cfg config) error { _, err := normalizeScreenshotFormat(cfg.ScreenshotFormat); return err },
+		func(cfg config) error { _, err := track.ResolveProviders(cfg.Providers); return err },
+		func(cfg config) error { _, err := regionsFromConfig(cfg); return err },
+		func(cfg config) error { _, err := highlightFromConfig(cfg); return err },
+		func(cfg config) error { _, err := parseTheme(cfg.Theme); return err },
+		func(cfg config) error { _, err := refreshIntervalFromConfig(cfg); return err },
+		func(cfg config) error { _, _, err := timeDisplayFromConfig(cfg, false); return err },
+		func(cfg config) error { _, err := passScoreWeightsFromConfig(cfg); return err },
+		func(cfg config) error { _, err := geocodeMinKmFromConfig(cfg); return err },
+		func(cfg config) error { _, err := placeNamesFromConfig(cfg); return err },
+		func(cfg config) error { _, err := bookmarksFromConfig(cfg); return err },
+		func(cfg config) error { _, err := lookalikesFromConfig(cfg); return err },
+		func(cfg config) error { _, err := panelsFromConfig(cfg); return err },
+		func(cfg config) error { _, err := hooksFromConfig(cfg); return err },
+		func(cfg config) error { _, err := notifySettingsFromConfig(cfg); return err },
+		func(cfg config) error { _, err := speechFromConfig(cfg, resolveLanguage("", cfg.Lang)); return err },
+		func(cfg config) error { _, err := digestFromConfig(cfg); return err },
+		func(cfg config) error { _, err := schedulesFromConfig(cfg); return err },
+		func(cfg config) error {
+			control, err := newController(cfg)
+			control.close()
+			return err
+		},
+	}
+	var problems []string
+	for _, validate := range validators {
+		if err := validate(cfg); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return cfg, []doctorCheck{{doctorFail, "config", path + ": " + strings.Join(problems, "; ")}}
+	}
+	return cfg, []doctorCheck{{doctorOK, "config", path}}
+}
+
+func doctorProviders(client *http.Client, cfg config) []doctorCheck {
+	providers, err := positionProviders(cfg)
+	if err != nil {
+		providers, _ = track.ResolveProviders(nil)
+	}
+
+	checks := make([]doctorCheck, 0, len(providers))
+	for _, provider := range providers {
+		start := time.Now()
+		pos, err := provider.Fetch(client)
+		took := time.Since(start)
+		name := "provider " + provider.Name()
+		if err != nil {
+			checks = append(checks, doctorCheck{doctorFail, name, err.Error()})
+			continue
+		}
+		checks = append(checks, doctorCheck{doctorOK, name, fmt.Sprintf("%s, at %s %s", formatLatency(took), formatLatitude(pos.Lat), formatLongitude(pos.Lon))})
+	}
+	return checks
+}
+
+func doctorTLE(client *http.Client, cfg config, now time.Time) doctorCheck {
+	set, err := loadTLE(client, track.ISSNoradID, tleRefreshFromConfig(cfg))
+	if set.Line1 == "" {
+		return doctorCheck{doctorFail, "tle", err.Error()}
+	}
+	sat, satErr := track.NewSatellite(set)
+	if satErr != nil {
+		return doctorCheck{doctorFail, "tle", satErr.Error()}
+	}
+
+	age := now.Sub(sat.Epoch)
+	detail := fmt.Sprintf("epoch %s, %.1f days old", sat.Epoch.UTC().Format(layoutStamp), age.Hours()/24)
+	switch {
+	case age > tleMaxAgeFromConfig(cfg):
+		return doctorCheck{doctorWarn, "tle", detail + ", predictions are drifting"}
+	case err != nil:
+		return doctorCheck{doctorWarn, "tle", detail + ", " + err.Error()}
+	}
+	return doctorCheck{doctorOK, "tle", detail}
+}
+
+func doctorTerminal(opts globalOptions, cfg config) []doctorCheck {
+	term := os.Getenv("TERM")
+	terminal := doctorCheck{doctorOK, "terminal", fmt.Sprintf("TERM=%s", term)}
+	if virtualConsole {
+		terminal.detail += ", console escape sequences enabled"
+	}
+	if !capableTerminal() {
+		terminal.status = doctorWarn
+		terminal.detail += ", not an interactive terminal: use iss watch or iss export"
+	}
+
+	colors, _ := newPalette(opts.color, capableTerminal())
+	color := doctorCheck{doctorOK, "color", "enabled (--color " + opts.color + ")"}
+	if !colors.enabled {
+		color.detail = "disabled (--color " + opts.color + ")"
+		if os.Getenv("NO_COLOR") != "" {
+			color.detail += ", NO_COLOR is set"
+		}
+	}
+
+	glyphs, _ := chosenCharset(opts, cfg)
+	unicode := doctorCheck{doctorOK, "unicode", consoleDetail() + ", charset " + glyphs.String()}
+	switch {
+	case glyphs.resolve(unicodeConsole()) == charsetASCII:
+		unicode.detail += ": drawing ASCII only"
+		if runtime.GOOS == "windows" && glyphs == charsetAuto {
+			unicode.detail += " (chcp 65001 or Windows Terminal for °, → and …)"
+		}
+	case runtime.GOOS != "windows" && !utf8Locale():
+		unicode.status = doctorWarn
+		unicode.detail = consoleDetail() + ` is not UTF-8: °, → and … may be drawn with the wrong width ("charset": "ascii" avoids them)`
+	}
+
+	flags := doctorCheck{doctorOK, "flags", "country_flag auto draws flag emoji"}
+	if !emojiFlagTerminal() || glyphs.resolve(unicodeConsole()) == charsetASCII {
+		flags.detail = "country_flag auto shows ISO codes: this terminal may not draw flag emoji two cells wide"
+	}
+
+	return []doctorCheck{terminal, color, unicode, flags}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	etaHorizon  = 24 * time.Hour
+	etaWithinKm = 500
+	etaGrace    = time.Minute
+	etaLabelMax = 18
+)
+
+type etaPin struct {
+	place    geo.Place
+	from     time.Time
+	approach track.Approach
+	known    bool
+}
+
+func (m model) pinETA(place geo.Place) model {
+	m.eta = &etaPin{place: place}
+	return m.refreshETA(clockNow())
+}
+
+func (m model) refreshETA(now time.Time) model {
+	if m.eta == nil || m.sat == nil {
+		return m
+	}
+	if m.eta.known && !now.Before(m.eta.from) && now.Before(m.eta.approach.Time.Add(etaGrace)) {
+		return m
+	}
+
+	pin := *m.eta
+	approach, err := track.NextApproach(m.sat, pin.place.Lat, pin.place.Lon, now, now.Add(etaHorizon), etaWithinKm)
+	if err != nil {
+		m.lastErr = err.Error()
+		return m
+	}
+	pin.from, pin.approach, pin.known = now, approach, true
+	m.eta = &pin
+	return m
+}
+
+func (m model) etaField(now time.Time) ([2]string, bool) {
+	if m.eta == nil {
+		return [2]string{}, false
+	}
+
+	label := ansi.Truncate(m.eta.place.Name, etaLabelMax, "…")
+	if !m.eta.known {
+		return [2]string{label, m.lang.text("resolving")}, true
+	}
+
+	distance := m.units.formatDistance(m.eta.approach.DistanceKm)
+	remaining := m.eta.approach.Time.Sub(now)
+	if remaining <= 0 {
+		return [2]string{label, fmt.Sprintf(m.lang.text("eta_now"), distance)}, true
+	}
+	when := m.times.format(m.eta.approach.Time, layoutShort)
+	return [2]string{label, fmt.Sprintf(m.lang.text("eta_in"), formatStayDuration(remaining), when, distance)}, true
+}
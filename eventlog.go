@@ -0,0 +1,29 @@
+package main
+
+import "time"
+
+const maxLogEntries = 5
+
+type logEntry struct {
+	at   time.Time
+	text string
+}
+
+func appendLog(log []logEntry, entries ...logEntry) []logEntry {
+	log = append(log, entries...)
+	if len(log) > maxLogEntries {
+		log = append([]logEntry(nil), log[len(log)-maxLogEntries:]...)
+	}
+	return log
+}
+
+func eventLogLines(lang language, times timeDisplay, log []logEntry) []string {
+	lines := []string{lang.text("events")}
+	if len(log) == 0 {
+		return append(lines, lang.text("no_events"))
+	}
+	for i := len(log) - 1; i >= 0; i-- {
+		lines = append(lines, times.format(log[i].at, layoutClock)+"  "+log[i].text)
+	}
+	return lines
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const lockHelperEnv = "ISS_FILELOCK_HELPER"
+
+func incrementUnderLock(t testing.TB, path string, holders *atomic.Int32) {
+	err := withFileLock(path, func() error {
+		if holders != nil {
+			if n := holders.Add(1); n != 1 {
+				t.Errorf("%d holders inside the lock", n)
+			}
+			defer holders.Add(-1)
+		}
+		data, err := readFileIfExists(path)
+		if err != nil {
+			return err
+		}
+		n := 0
+		if len(data) > 0 {
+			if n, err = strconv.Atoi(string(data)); err != nil {
+				return err
+			}
+		}
+		return writeFileAtomic(path, []byte(strconv.Itoa(n+1)), 0o600)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func readCounter(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestWithFileLockGoroutines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	const workers, rounds = 8, 50
+
+	var holders atomic.Int32
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				incrementUnderLock(t, path, &holders)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := readCounter(t, path); got != workers*rounds {
+		t.Fatalf("counter = %d, want %d", got, workers*rounds)
+	}
+}
+
+func TestWithFileLockProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns processes")
+	}
+	path := filepath.Join(t.TempDir(), "counter")
+	const workers, rounds = 4, 50
+
+	cmds := make([]*exec.Cmd, workers)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestFileLockHelper$")
+		cmd.Env = append(os.Environ(), lockHelperEnv+"="+path, fmt.Sprintf("ISS_FILELOCK_ROUNDS=%d", rounds))
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds[i] = cmd
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("helper: %v", err)
+		}
+	}
+
+	if got := readCounter(t, path); got != workers*rounds {
+		t.Fatalf("counter = %d, want %d", got, workers*rounds)
+	}
+}
+
+func TestFileLockHelper(t *testing.T) {
+	path := os.Getenv(lockHelperEnv)
+	if path == "" {
+		t.Skip("only runs as a helper process")
+	}
+	rounds, err := strconv.Atoi(os.Getenv("ISS_FILELOCK_ROUNDS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range rounds {
+		incrementUnderLock(t, path, nil)
+	}
+}
+
+func TestWithFileLockReleasedAfterError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	want := errors.New("boom")
+	if err := withFileLock(path, func() error { return want }); err != want {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+	if err := withFileLock(path, func() error { return nil }); err != nil {
+		t.Fatalf("lock not released: %v", err)
+	}
+}
+
+func TestSaveStateWaitsForLock(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path, err := dataPath(stateFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := make(chan error, 1)
+	err = withFileLock(path, func() error {
+		go func() { saved <- saveState(stateSnapshot{Location: "Pacific Ocean"}) }()
+		time.Sleep(100 * time.Millisecond)
+		if _, err := os.Stat(path); err == nil {
+			return errors.New("state written while another process held the lock")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-saved; err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := loadState()
+	if !ok || s.Location != "Pacific Ocean" {
+		t.Fatalf("loadState() = %+v, %v", s, ok)
+	}
+}
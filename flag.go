@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+type countryFlag int
+
+const (
+	flagOff countryFlag = iota
+	flagAuto
+	flagCode
+	flagEmoji
+)
+
+var countryFlagNames = []string{"off", "auto", "code", "emoji"}
+
+var emojiFlagPrograms = []string{"iTerm.app", "Apple_Terminal", "WezTerm", "ghostty", "vscode", "Hyper", "Tabby"}
+
+func parseCountryFlag(value string) (countryFlag, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return flagOff, nil
+	}
+
+	for i, name := range countryFlagNames {
+		if name == value {
+			return countryFlag(i), nil
+		}
+	}
+
+	return flagOff, fmt.Errorf("unknown country_flag %q (want off, auto, code or emoji)", value)
+}
+
+func (f countryFlag) String() string {
+	return countryFlagNames[f]
+}
+
+func utf8Locale() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := strings.ToUpper(os.Getenv(key)); locale != "" {
+			return strings.Contains(locale, "UTF-8") || strings.Contains(locale, "UTF8")
+		}
+	}
+	return false
+}
+
+func emojiFlagTerminal() bool {
+	if runtime.GOOS == "windows" || !utf8Locale() {
+		return false
+	}
+	term := os.Getenv("TERM")
+	if term == "linux" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") || os.Getenv("TMUX") != "" {
+		return false
+	}
+	if strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	if os.Getenv("VTE_VERSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	program := os.Getenv("TERM_PROGRAM")
+	for _, name := range emojiFlagPrograms {
+		if strings.EqualFold(program, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m model) flaggedCountry() string {
+	style := m.flagStyle
+	if style == flagAuto {
+		style = flagCode
+		if m.flagEmoji && !m.asciiOnly() {
+			style = flagEmoji
+		}
+	}
+	code := m.countryCode
+	if code == "" {
+		code = geo.CountryCode(m.issOver)
+	}
+	switch {
+	case style == flagOff || code == "":
+		return m.issOver
+	case style == flagEmoji:
+		return geo.Flag(code) + " " + m.issOver
+	}
+	return m.issOver + " (" + strings.ToUpper(code) + ")"
+}
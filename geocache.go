@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+const (
+	geocodeCacheCell       = 0.25
+	geocodeCacheTTL        = 30 * 24 * time.Hour
+	geocodeCacheMaxEntries = 5000
+	defaultGeocodeMinKm    = 50
+)
+
+type geocodeEntry struct {
+	Name    string    `json:"name"`
+	Code    string    `json:"code,omitempty"`
+	Water   bool      `json:"water,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+type geocodeCache struct {
+	path      string
+	mu        sync.Mutex
+	entries   map[string]geocodeEntry
+	minMoveKm float64
+	last      geocodeFix
+	land      *mapascii.LandMask
+}
+
+type geocodeFix struct {
+	lat, lon float64
+	lang     string
+	loc      geo.Location
+}
+
+func geocodeMinKmFromConfig(cfg config) (float64, error) {
+	if cfg.GeocodeMinKm == nil {
+		return defaultGeocodeMinKm, nil
+	}
+	if *cfg.GeocodeMinKm < 0 {
+		return defaultGeocodeMinKm, fmt.Errorf("geocode_min_km must not be negative")
+	}
+	return *cfg.GeocodeMinKm, nil
+}
+
+func placeNamesFromConfig(cfg config) (geo.Naming, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.PlaceNames)) {
+	case "", "localized":
+		return geo.Localized, nil
+	case "local":
+		return geo.Local, nil
+	default:
+		return geo.Localized, fmt.Errorf("unknown place_names %q (want localized or local)", cfg.PlaceNames)
+	}
+}
+
+func newGeocodeCache(minMoveKm float64) *geocodeCache {
+	cache := &geocodeCache{entries: map[string]geocodeEntry{}, minMoveKm: minMoveKm}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return cache
+	}
+	cache.path = filepath.Join(dir, "geocode.json")
+
+	withFileLock(cache.path, func() error {
+		entries, err := readGeocodeEntries(cache.path)
+		if err == nil {
+			cache.entries = entries
+		}
+		return nil
+	})
+
+	return cache
+}
+
+func geocodeCacheKey(lat, lon float64, lang string) string {
+	snap := func(v float64) float64 {
+		return math.Floor(v/geocodeCacheCell) * geocodeCacheCell
+	}
+
+	return fmt.Sprintf("%s:%.2f,%.2f", lang, snap(lat), snap(lon))
+}
+
+func (c *geocodeCache) lookup(lat, lon float64, lang string) (geo.Location, bool) {
+	if c == nil {
+		return geo.Location{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[geocodeCacheKey(lat, lon, lang)]
+	if !ok || time.Since(entry.Updated) > geocodeCacheTTL || (entry.Code == "" && !entry.Water) {
+		return geo.Location{}, false
+	}
+
+	loc := geo.Location{Name: entry.Name, CountryCode: entry.Code}
+	c.last = geocodeFix{lat: lat, lon: lon, lang: lang, loc: loc}
+	return loc, true
+}
+
+func (c *geocodeCache) near(lat, lon float64, lang string) (geo.Location, bool) {
+	if c == nil {
+		return geo.Location{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last.lang != lang || c.last.loc.Name == "" || geo.HaversineKm(lat, lon, c.last.lat, c.last.lon) >= c.minMoveKm {
+		return geo.Location{}, false
+	}
+	return c.last.loc, true
+}
+
+func (c *geocodeCache) store(lat, lon float64, lang string, loc geo.Location) error {
+	if c == nil {
+		return nil
+	}
+
+	key := geocodeCacheKey(lat, lon, lang)
+	entry := geocodeEntry{Name: loc.Name, Code: loc.CountryCode, Water: loc.CountryCode == "", Updated: time.Now().UTC()}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = geocodeFix{lat: lat, lon: lon, lang: lang, loc: loc}
+	c.entries[key] = entry
+	if c.path == "" {
+		return nil
+	}
+
+	return withFileLock(c.path, func() error {
+		onDisk, err := readGeocodeEntries(c.path)
+		if err != nil {
+			onDisk = map[string]geocodeEntry{}
+		}
+
+		for k, v := range onDisk {
+			if current, ok := c.entries[k]; !ok || v.Updated.After(current.Updated) {
+				c.entries[k] = v
+			}
+		}
+		c.entries[key] = entry
+		pruneGeocodeEntries(c.entries)
+
+		data, err := json.Marshal(c.entries)
+		if err != nil {
+			return err
+		}
+
+		return writeFileAtomic(c.path, data, 0o644)
+	})
+}
+
+func readGeocodeEntries(path string) (map[string]geocodeEntry, error) {
+	data, err := readFileIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]geocodeEntry{}
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse geocode cache: %w", err)
+	}
+
+	return entries, nil
+}
+
+func pruneGeocodeEntries(entries map[string]geocodeEntry) {
+	for k, v := range entries {
+		if time.Since(v.Updated) > geocodeCacheTTL {
+			delete(entries, k)
+		}
+	}
+
+	if len(entries) <= geocodeCacheMaxEntries {
+		return
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].Updated.Before(entries[keys[j]].Updated)
+	})
+	for _, k := range keys[:len(keys)-geocodeCacheMaxEntries] {
+		delete(entries, k)
+	}
+}
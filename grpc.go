@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/issrpc"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type trackerService struct {
+	issrpc.UnimplementedTrackerServer
+	api *apiServer
+}
+
+func (a *apiServer) grpcServer() *grpc.Server {
+	server := grpc.NewServer()
+	issrpc.RegisterTrackerServer(server, &trackerService{api: a})
+	return server
+}
+
+func (a *apiServer) serveGRPCCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		if err := a.serveGRPC(ctx, a.grpcAddr); err != nil {
+			return errMsg{err: fmt.Errorf("grpc: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (a *apiServer) serveGRPC(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := a.grpcServer()
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(serveShutdownTimeout):
+			server.Stop()
+		}
+	}()
+	if err := server.Serve(listener); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func (s *trackerService) GetPosition(context.Context, *issrpc.GetPositionRequest) (*issrpc.Position, error) {
+	latest, ok := s.api.position()
+	if !ok {
+		return nil, status.Error(codes.Unavailable, "no position yet")
+	}
+	return grpcPosition(latest), nil
+}
+
+func (s *trackerService) StreamPositions(_ *issrpc.StreamPositionsRequest, stream grpc.ServerStreamingServer[issrpc.Position]) error {
+	positions, unsubscribe := s.api.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case pos := <-positions:
+			if err := stream.Send(grpcPosition(pos)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *trackerService) PredictPasses(_ context.Context, req *issrpc.PredictPassesRequest) (*issrpc.PredictPassesResponse, error) {
+	observer, err := passObserver(req.Lat, req.Lon, req.AltM, s.api.observer)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	days := req.Days
+	if days == 0 {
+		days = 3
+	}
+	minElevation := 10.0
+	if req.MinElevation != nil {
+		minElevation = *req.MinElevation
+	}
+	q, err := newPassQuery(observer, days, minElevation, req.VisibleOnly, float64(req.Count))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sat, err := s.api.satellite()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	found, err := q.find(sat, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	out := &issrpc.PredictPassesResponse{Passes: make([]*issrpc.Pass, len(found))}
+	for i, pass := range found {
+		out.Passes[i] = grpcPass(pass, s.api.weights)
+	}
+	return out, nil
+}
+
+func (s *trackerService) ListCrew(context.Context, *issrpc.ListCrewRequest) (*issrpc.ListCrewResponse, error) {
+	people, err := track.FetchCrew(s.api.client)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	out := &issrpc.ListCrewResponse{People: make([]*issrpc.CrewMember, len(people))}
+	for i, person := range people {
+		out.People[i] = &issrpc.CrewMember{Name: person.Name, Craft: person.Craft}
+	}
+	return out, nil
+}
+
+func grpcPosition(pos apiPosition) *issrpc.Position {
+	return &issrpc.Position{
+		Lat:         pos.Lat,
+		Lon:         pos.Lon,
+		AltitudeKm:  pos.AltitudeKm,
+		VelocityKmh: pos.VelocityKmh,
+		Over:        pos.Over,
+		Provider:    pos.Provider,
+		Timestamp:   timestamppb.New(pos.Timestamp),
+	}
+}
+
+func grpcPass(pass track.Pass, weights passScoreWeights) *issrpc.Pass {
+	return &issrpc.Pass{
+		Start:        timestamppb.New(pass.Start),
+		Max:          timestamppb.New(pass.Max),
+		End:          timestamppb.New(pass.End),
+		StartAzimuth: pass.StartAzimuth,
+		MaxAzimuth:   pass.MaxAzimuth,
+		EndAzimuth:   pass.EndAzimuth,
+		MaxElevation: pass.MaxElevation,
+		SunElevation: pass.SunElevation,
+		Visible:      pass.Visible,
+		Magnitude:    passMagnitude(pass),
+		Score:        int32(passScore(pass, nil, weights)),
+	}
+}
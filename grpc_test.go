@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/issrpc"
+	"github.com/Kivayan/iss/pkg/track"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dialTracker(t *testing.T, api *apiServer) issrpc.TrackerClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := api.grpcServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return issrpc.NewTrackerClient(conn)
+}
+
+func testAPIServer() *apiServer {
+	return newAPIServer(config{}, nil, passScoreWeights{})
+}
+
+func TestGRPCGetPosition(t *testing.T) {
+	api := testAPIServer()
+	client := dialTracker(t, api)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.GetPosition(ctx, &issrpc.GetPositionRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("before the first position: err = %v, want Unavailable", err)
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	api.observe(newAPIPosition(track.Position{Lat: 12.5, Lon: -45, Timestamp: at, Provider: "wheretheiss", HasAltitude: true, AltitudeKm: 420}, "Atlantic Ocean"))
+
+	pos, err := client.GetPosition(ctx, &issrpc.GetPositionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Lat != 12.5 || pos.Lon != -45 || pos.Over != "Atlantic Ocean" || pos.Provider != "wheretheiss" || pos.GetAltitudeKm() != 420 {
+		t.Errorf("position = %v", pos)
+	}
+	if !pos.Timestamp.AsTime().Equal(at) {
+		t.Errorf("timestamp = %v, want %v", pos.Timestamp.AsTime(), at)
+	}
+}
+
+func TestGRPCStreamPositions(t *testing.T) {
+	api := testAPIServer()
+	client := dialTracker(t, api)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	api.observe(newAPIPosition(track.Position{Lat: 1, Lon: 2}, "first"))
+	stream, err := client.StreamPositions(ctx, &issrpc.StreamPositionsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Over != "first" {
+		t.Errorf("first streamed position = %v, want the latest one", first)
+	}
+
+	go func() {
+		for ctx.Err() == nil {
+			api.observe(newAPIPosition(track.Position{Lat: 3, Lon: 4}, "second"))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	for {
+		next, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Over == "second" {
+			break
+		}
+	}
+}
+
+func TestGRPCPredictPassesInvalid(t *testing.T) {
+	client := dialTracker(t, testAPIServer())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lat, lon := 51.5, 200.0
+	for name, req := range map[string]*issrpc.PredictPassesRequest{
+		"no observer":    {},
+		"only lat":       {Lat: &lat},
+		"lon too large":  {Lat: &lat, Lon: &lon},
+		"too many days":  {Lat: &lat, Lon: new(float64), Days: 31},
+		"negative count": {Lat: &lat, Lon: new(float64), Count: -1},
+	} {
+		if _, err := client.PredictPasses(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: err = %v, want InvalidArgument", name, err)
+		}
+	}
+}
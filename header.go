@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const headerInterval = time.Second
+
+type headerTickMsg struct{}
+
+func headerTick() tea.Cmd {
+	return tea.Tick(headerInterval, func(time.Time) tea.Msg {
+		return headerTickMsg{}
+	})
+}
+
+func (m model) headerLine(now time.Time) string {
+	var parts []string
+	if m.paused() {
+		parts = append(parts, "["+m.lang.text("paused")+"]")
+	}
+	if demo != nil {
+		parts = append(parts, "["+m.lang.text("demo")+"]")
+	}
+	parts = append(parts, m.icon("clock")+now.UTC().Format(layoutClock)+" UTC")
+	if zone := m.times.zone(now); zone != "UTC" {
+		parts = append(parts, m.times.format(now, layoutClock)+" "+zone)
+	}
+	if m.sat != nil {
+		orbit := m.sat.OrbitNumber(now)
+		local := m.times.in(now)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		today := orbit - m.sat.OrbitNumber(midnight) + 1
+		parts = append(parts, fmt.Sprintf(m.lang.text("orbit_today"), orbit, today))
+	}
+	parts = append(parts, m.lang.text("uptime")+" "+formatStayDuration(now.Sub(m.started)))
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+const maxHeadingGap = 5 * time.Minute
+
+type motion struct {
+	lat, lon   float64
+	at         time.Time
+	known      bool
+	heading    float64
+	groundKmh  float64
+	dLat, dLon float64
+}
+
+func (mo motion) observe(lat, lon float64, at time.Time) motion {
+	dist := geo.HaversineKm(mo.lat, mo.lon, lat, lon)
+	if mo.known && dist == 0 {
+		return mo
+	}
+
+	next := motion{lat: lat, lon: lon, at: at, known: true}
+	if dt := at.Sub(mo.at); !mo.known || dt <= 0 || dt > maxHeadingGap {
+		return next
+	}
+
+	next.heading = geo.InitialBearing(mo.lat, mo.lon, lat, lon)
+	next.groundKmh = dist / at.Sub(mo.at).Hours()
+	next.dLat = lat - mo.lat
+	next.dLon = math.Mod(lon-mo.lon+540, 360) - 180
+	return next
+}
+
+func (mo motion) moving() bool {
+	return mo.groundKmh > 0
+}
+
+func (m model) headingFields() [][2]string {
+	if !m.motion.moving() {
+		return nil
+	}
+	return [][2]string{
+		{m.lang.text("heading"), fmt.Sprintf("%.0f° %s", m.motion.heading, compassPoint(m.motion.heading))},
+		{m.lang.text("ground_speed"), m.units.formatSpeed(m.motion.groundKmh)},
+	}
+}
+
+func (m model) headingGlyph() (render.Glyph, bool) {
+	if !m.hasCoords || !m.motion.moving() {
+		return render.Glyph{}, false
+	}
+
+	x, y := m.motion.dLon, -m.motion.dLat/mapCharAspect
+	length := math.Hypot(x, y)
+	if length == 0 {
+		return render.Glyph{}, false
+	}
+	x, y = x/length, y/length
+
+	const arrows = `>/^\</v\`
+	sector := int(math.Round(math.Atan2(-y, x)/(math.Pi/4))+8) % 8
+	return render.Glyph{
+		Lat:   m.lat,
+		Lon:   m.lon,
+		DX:    int(math.Round(x * (markerArmX + 1))),
+		DY:    int(math.Round(y * (markerArmY + 1))),
+		Char:  arrows[sector],
+		Color: m.palette.color(m.palette.theme.markerColor),
+	}, true
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/history"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const historyFlushEvery = 12
+
+type historyRecorder struct {
+	mu      sync.Mutex
+	path    string
+	pending []history.Sample
+}
+
+type historyCountriesMsg struct {
+	stats *countryStats
+	err   error
+}
+
+type historyOptions struct {
+	since     string
+	until     string
+	json      bool
+	countries bool
+}
+
+func historyPath() (string, error) {
+	return dataPath("history.db")
+}
+
+func newHistoryRecorder(enabled bool) *historyRecorder {
+	if !enabled {
+		return nil
+	}
+	path, err := historyPath()
+	if err != nil {
+		return nil
+	}
+	return &historyRecorder{path: path}
+}
+
+func historySample(pos track.Position, loc geo.Location, at time.Time) history.Sample {
+	return history.Sample{
+		Time:        at,
+		Lat:         pos.Lat,
+		Lon:         pos.Lon,
+		AltitudeKm:  pos.AltitudeKm,
+		VelocityKmh: pos.VelocityKmh,
+		HasAltitude: pos.HasAltitude,
+		Provider:    pos.Provider,
+		Location:    loc.Name,
+		CountryCode: loc.CountryCode,
+	}
+}
+
+func (r *historyRecorder) add(sample history.Sample) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = append(r.pending, sample)
+	return len(r.pending) >= historyFlushEvery
+}
+
+func (r *historyRecorder) flush() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	store, err := history.Open(r.path, false)
+	if err != nil {
+		return err
+	}
+	if err := store.Append(r.pending...); err != nil {
+		store.Close()
+		return err
+	}
+	if err := store.Close(); err != nil {
+		return err
+	}
+
+	r.pending = nil
+	return nil
+}
+
+func flushHistoryCmd(r *historyRecorder) tea.Cmd {
+	return func() tea.Msg {
+		if err := r.flush(); err != nil {
+			return errMsg{err: fmt.Errorf("history: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (r *historyRecorder) prune(before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	store, err := history.Open(r.path, false)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := store.Prune(before)
+	if closeErr := store.Close(); err == nil {
+		err = closeErr
+	}
+	return removed, err
+}
+
+func pruneHistoryCmd(r *historyRecorder, retention time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		removed, err := r.prune(time.Now().Add(-retention))
+		if err != nil {
+			return errMsg{err: fmt.Errorf("history: %w", err)}
+		}
+		slog.Info("pruned history", "samples", removed)
+		return nil
+	}
+}
+
+func loadHistoryCountriesCmd(r *historyRecorder) tea.Cmd {
+	return func() tea.Msg {
+		if err := r.flush(); err != nil {
+			return historyCountriesMsg{err: fmt.Errorf("history: %w", err)}
+		}
+
+		store, err := history.Open(r.path, true)
+		if err != nil {
+			return historyCountriesMsg{err: fmt.Errorf("history: %w", err)}
+		}
+		defer store.Close()
+
+		stats := newCountryStats(false)
+		err = store.Range(time.Time{}, time.Time{}, func(sample history.Sample) error {
+			stats.observe(geo.Location{Name: sample.Location, CountryCode: sample.CountryCode}, sample.Time)
+			return nil
+		})
+		if err != nil {
+			return historyCountriesMsg{err: fmt.Errorf("history: %w", err)}
+		}
+		return historyCountriesMsg{stats: stats}
+	}
+}
+
+func parseHistoryTime(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return now.AddDate(0, 0, -days), nil
+	}
+	for _, layout := range passStartLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q: use a duration such as 24h or 7d, YYYY-MM-DD or RFC 3339", value)
+}
+
+func runHistory(opts globalOptions, w io.Writer, hist historyOptions) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	times, _, err := timeDisplayFromConfig(cfg, opts.utc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := parseHistoryTime(hist.since, now, times.location())
+	if err != nil {
+		return fmt.Errorf("--since %w", err)
+	}
+	until, err := parseHistoryTime(hist.until, now, times.location())
+	if err != nil {
+		return fmt.Errorf("--until %w", err)
+	}
+
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	store, err := history.Open(path, true)
+	if errors.Is(err, fs.ErrNotExist) {
+		if !cfg.History {
+			return errors.New("no history recorded yet: set \"history\": true in the config file")
+		}
+		return errors.New("no history recorded yet")
+	}
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if hist.countries {
+		stats := newCountryStats(false)
+		err := store.Range(since, until, func(sample history.Sample) error {
+			stats.observe(geo.Location{Name: sample.Location, CountryCode: sample.CountryCode}, sample.Time)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		lang := resolveLanguage(opts.lang, cfg.Lang)
+		for _, line := range countryStatsTable(lang, lang.text("all_sessions"), stats.rows(false, sortByTime)) {
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+
+	if hist.json {
+		encoder := json.NewEncoder(w)
+		return store.Range(since, until, func(sample history.Sample) error {
+			return encoder.Encode(sample)
+		})
+	}
+
+	units, err := parseUnits(cfg.Units)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"Time", "Lat", "Lon", "Altitude", "Location", "Provider"}}
+	err = store.Range(since, until, func(sample history.Sample) error {
+		altitude := "-"
+		if sample.HasAltitude {
+			altitude = units.formatDistance(sample.AltitudeKm)
+		}
+		rows = append(rows, []string{
+			times.format(sample.Time, layoutFull),
+			fmt.Sprintf("%.4f", sample.Lat),
+			fmt.Sprintf("%.4f", sample.Lon),
+			altitude,
+			strings.TrimSpace(sample.Location),
+			sample.Provider,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 1 {
+		fmt.Fprintln(w, "No samples in that range.")
+		return nil
+	}
+
+	for _, line := range formatTable(rows, map[int]bool{1: true, 2: true, 3: true}) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/rules"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const hookNightSunElevation = -6
+
+var hookNames = []string{
+	"lat", "lon", "altitude_km", "velocity_kmh", "over", "country",
+	"elevation", "azimuth", "range_km", "sunlit", "night", "visible", "magnitude",
+	"minutes_to_pass", "pass_max_elevation", "pass_visible", "people_in_space",
+}
+
+type hookConfig struct {
+	When string   `json:"when"`
+	Run  []string `json:"run"`
+}
+
+type hook struct {
+	rule *rules.Rule
+	run  []string
+}
+
+func hooksFromConfig(cfg config) ([]hook, error) {
+	hooks := make([]hook, 0, len(cfg.Hooks))
+	for i, hc := range cfg.Hooks {
+		if len(hc.Run) == 0 || hc.Run[0] == "" {
+			return nil, fmt.Errorf("hooks[%d]: run needs a command", i)
+		}
+		rule, err := rules.Parse(hc.When, hookNames)
+		if err != nil {
+			return nil, fmt.Errorf("hooks[%d]: %q: %w", i, hc.When, err)
+		}
+		hooks = append(hooks, hook{rule: rule, run: hc.Run})
+	}
+	return hooks, nil
+}
+
+func (m model) hookVars(now time.Time) rules.Vars {
+	vars := rules.Vars{}
+	if m.hasCoords {
+		vars["lat"], vars["lon"], vars["over"] = m.lat, m.lon, m.issOver
+		vars["country"] = m.countryCode
+		if m.hasAltitude {
+			vars["altitude_km"], vars["velocity_kmh"] = m.altitudeKm, m.velocityKmh
+		}
+	}
+	if m.sat != nil {
+		if sunlit, err := m.sat.Sunlit(now); err == nil {
+			vars["sunlit"] = sunlit
+		}
+	}
+	if m.observer != nil {
+		night := m.observer.SunElevation(now) < hookNightSunElevation
+		vars["night"] = night
+		if look, ok := m.currentLook(now); ok {
+			vars["elevation"], vars["azimuth"], vars["range_km"] = look.Elevation, look.Azimuth, look.RangeKm
+			if sunlit, ok := vars["sunlit"].(bool); ok {
+				vars["visible"] = look.Elevation > 0 && sunlit && night
+			}
+			if magnitude, ok, err := m.observer.Magnitude(m.sat, now, track.ISSStandardMagnitude); err == nil && ok {
+				vars["magnitude"] = magnitude
+			}
+		}
+	}
+	if m.skyPass != nil && m.skyPass.End.After(now) {
+		vars["minutes_to_pass"] = max(m.skyPass.Start.Sub(now).Minutes(), 0)
+		vars["pass_max_elevation"] = m.skyPass.MaxElevation
+		vars["pass_visible"] = m.skyPass.Visible
+	}
+	if m.crew != nil {
+		total := 0
+		for _, count := range m.crew {
+			total += count.People
+		}
+		vars["people_in_space"] = float64(total)
+	}
+	return vars
+}
+
+func (m model) runHooks(now time.Time) (model, []busEvent) {
+	if len(m.hooks) == 0 {
+		return m, nil
+	}
+	vars := m.hookVars(now)
+	armed := append([]bool(nil), m.hooksFired...)
+	var events []busEvent
+	for i := range m.hooks {
+		h := &m.hooks[i]
+		holds, err := h.rule.Eval(vars)
+		if err != nil && !errors.Is(err, rules.ErrUnknown) {
+			events = append(events, busEvent{topic: topicError, at: now, err: fmt.Errorf("hook %q: %w", h.rule, err)})
+		}
+		if holds && !armed[i] {
+			events = append(events, busEvent{topic: topicRule, at: now, text: m.lang.text("hook_fired") + ": " + h.rule.String(), hook: h, vars: vars})
+		}
+		armed[i] = holds
+	}
+	m.hooksFired = armed
+	return m, events
+}
+
+func hookCmd(h hook, vars rules.Vars) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, h.run[0], h.run[1:]...)
+		cmd.Env = append(os.Environ(), hookEnv(h, vars)...)
+		if err := cmd.Run(); err != nil {
+			return errMsg{err: fmt.Errorf("hook %q: %w", h.rule, err)}
+		}
+		return nil
+	}
+}
+
+func hookEnv(h hook, vars rules.Vars) []string {
+	env := []string{"ISS_RULE=" + h.rule.String()}
+	for name, value := range vars {
+		if f, ok := value.(float64); ok {
+			value = fmt.Sprintf("%.4f", f)
+		}
+		env = append(env, fmt.Sprintf("ISS_%s=%v", strings.ToUpper(name), value))
+	}
+	sort.Strings(env[1:])
+	return env
+}
@@ -0,0 +1,1720 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+const defaultLanguage = "en"
+
+type language struct {
+	tag   string
+	base  string
+	names geo.Naming
+}
+
+var catalogs = map[string]map[string]string{
+	"en": {
+		"iss_over":        "ISS over",
+		"latitude":        "Latitude",
+		"longitude":       "Longitude",
+		"coords":          "Coords",
+		"resolving":       "Resolving...",
+		"detail":          "Detail",
+		"ocean":           "Ocean",
+		"map_unavailable": "Map unavailable.",
+		"diagnostics":     "Diagnostics (d to return)",
+		"this_session":    "This session",
+		"all_sessions":    "All sessions",
+		"last_error":      "Last error",
+		"no_requests":     "no requests yet",
+
+		"tour":     "Tour",
+		"now_over": "Now over",
+
+		"altitude": "Altitude",
+		"velocity": "Velocity",
+
+		"saved": "Saved:",
+
+		"recording": "REC (R to stop):",
+
+		"events":    "Events",
+		"entered":   "Entered",
+		"left":      "Left",
+		"no_events": "no events yet",
+
+		"countries":  "Time over countries (c to return, o to sort)",
+		"location":   "Location",
+		"time":       "Time",
+		"no_samples": "no samples yet",
+		"by_time":    "by time",
+		"by_name":    "by name",
+
+		"sky_view":    "Sky view (p to return)",
+		"no_observer": "Set \"observer\" in the config file to use the sky view.",
+		"loading_tle": "Loading orbital elements...",
+		"azimuth":     "Azimuth",
+		"elevation":   "Elevation",
+		"range":       "Range",
+		"next_pass":   "Next pass",
+		"this_pass":   "This pass",
+
+		"radio": "Radio",
+
+		"tle_stale": "Orbital elements are stale",
+
+		"position_off": "%s is %s off the predicted position",
+		"position_ok":  "%s agrees with the predicted position again",
+
+		"vehicles":    "Docked vehicles (v to return)",
+		"no_vehicles": "no vehicles docked",
+		"vehicle":     "Vehicle",
+		"port":        "Port",
+		"docked":      "Docked",
+		"updated":     "Updated",
+
+		"video_opened":  "Opened the ISS live video in your browser",
+		"video_playing": "Playing the ISS live video (l to stop)",
+
+		"magnitude": "Magnitude",
+		"eclipsed":  "in Earth shadow",
+
+		"cloud_cover": "Cloud cover",
+
+		"orbit_today": "Orbit %d (#%d today)",
+		"uptime":      "Up",
+
+		"paused": "PAUSED",
+
+		"refreshing":   "Refreshing…",
+		"refresh_wait": "Next refresh possible in %s",
+
+		"settings":            "Settings (, to return)",
+		"settings_help":       "↑/↓ select · enter edit · ←/→ change · esc back",
+		"setting_refresh":     "Refresh interval (s)",
+		"setting_theme":       "Theme",
+		"setting_units":       "Units",
+		"setting_lat":         "Observer latitude",
+		"setting_lon":         "Observer longitude",
+		"setting_alt":         "Observer altitude (m)",
+		"setting_sun_moon":    "Sun and moon",
+		"setting_cloud_layer": "Cloud layer",
+		"on":                  "on",
+		"off":                 "off",
+
+		"log_view": "Log (L to return)",
+		"no_log":   "nothing logged yet",
+
+		"demo":          "DEMO",
+		"demo_location": "Demo orbit",
+
+		"demo_help": "</> speed · [/] ±10 min · {/} ±1 orbit",
+
+		"breaker_open":      "%s keeps failing, paused for %s",
+		"breaker_half_open": "%s: trying again",
+
+		"heading":      "Heading",
+		"ground_speed": "Ground speed",
+
+		"eta_prompt":      "ETA to place",
+		"prompt_help":     "enter confirm · esc cancel",
+		"place_not_found": "no place found for %q",
+		"eta_in":          "in %s (%s), %s",
+		"eta_now":         "now, %s",
+
+		"search_prompt": "Go to place",
+		"choose_place":  "Which place?",
+		"choose_help":   "↑/↓ select · enter or 1-5 choose · esc cancel",
+
+		"setting_bookmark":     "Bookmark",
+		"setting_bookmark_add": "Save observer as bookmark",
+		"bookmark_help":        "←/→ pick · enter rename · x delete",
+		"bookmark_add_help":    "enter a name; an existing name is moved to the observer",
+		"no_bookmarks":         "No bookmarks yet, add one in settings (,)",
+		"observer_at":          "Observer: %s",
+
+		"profiles_view": "Next pass per observer",
+		"no_profiles":   "No observer or bookmarks set.",
+		"profile":       "Profile",
+		"no_pass":       "none",
+		"profiles_help": "First pass above %d° in the next %d h · P back",
+
+		"people_in_space": "People in space",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong alt",
+
+		"lookalikes":        "Bright satellites near the ISS this pass:",
+		"lookalikes_more":   "and %d more",
+		"lookalike_warning": "%d bright satellites cross near the ISS track on the %s pass, see p",
+
+		"hook_fired": "Rule matched",
+
+		"pass_alert": "Visible pass in %d min: rises %s in the %s, up to %.0f°",
+
+		"daemon_lost": "daemon connection lost (%v), fetching positions directly",
+
+		"startup_title":    "Starting up",
+		"startup_config":   "Configuration",
+		"startup_map":      "World map",
+		"startup_tle":      "Orbital elements",
+		"startup_position": "ISS position",
+		"startup_retrying": "(retrying)",
+
+		"last_30_minutes": "Last 30 minutes",
+
+		"altitude_view":      "Altitude, last 3 hours (a to return)",
+		"altitude_predicted": "predicted from orbital elements",
+		"altitude_measured":  "reported by the position API",
+		"altitude_range":     "Range",
+		"now":                "now",
+
+		"latitude_view":  "Ground track latitude, last and next orbit (g to return)",
+		"heading_north":  "heading north",
+		"heading_south":  "heading south",
+		"orbital_period": "Orbital period",
+
+		"orbit_raised":  "Orbit raised ~%s on %s",
+		"orbit_lowered": "Orbit lowered ~%s on %s",
+
+		"space_weather": "Space weather",
+		"kp_quiet":      "quiet",
+		"kp_active":     "active, aurora at high latitudes",
+		"storm_g1":      "G1 storm, aurora at mid latitudes",
+		"storm_g2":      "G2 storm, aurora at mid latitudes",
+		"storm_g3":      "G3 storm, aurora far from the poles",
+		"storm_g4":      "G4 storm, aurora far from the poles",
+		"storm_g5":      "G5 storm, aurora far from the poles",
+		"flux_low":      "low, little drag",
+		"flux_moderate": "moderate drag",
+		"flux_high":     "high, faster orbital decay",
+
+		"nadir_view":       "Below the station now (n to return)",
+		"nadir_local_time": "Local solar time",
+		"nadir_day":        "day",
+		"nadir_twilight":   "twilight",
+		"nadir_night":      "night",
+		"nadir_city":       "Nearest city",
+		"nadir_city_from":  "%s %s of %s",
+		"nadir_station":    "Station",
+		"nadir_sunlit":     "in sunlight",
+		"nadir_sunrise":    "Next sunrise",
+		"nadir_sunset":     "Next sunset",
+		"nadir_per_day":    "Sunrises per day",
+
+		"speak_n":        "in the north",
+		"speak_ne":       "in the northeast",
+		"speak_e":        "in the east",
+		"speak_se":       "in the southeast",
+		"speak_s":        "in the south",
+		"speak_sw":       "in the southwest",
+		"speak_w":        "in the west",
+		"speak_nw":       "in the northwest",
+		"speak_soon":     "ISS rising %s in %d minutes",
+		"speak_soon_one": "ISS rising %s in one minute",
+		"speak_rise":     "ISS rising now %s, up to %.0f degrees",
+		"speak_peak":     "ISS at its highest, %.0f degrees %s",
+
+		"journal_prompt":  "Log sighting, rating 1-5 then notes",
+		"journal_no_pass": "No pass over your observer location in the last 6 hours to log",
+		"journal_saved":   "Sighting saved (%d in the journal)",
+
+		"offshore": "%s (off the coast of %s)",
+
+		"iss_near":         "ISS near",
+		"setting_location": "Location",
+
+		"setting_flag": "Country flag",
+
+		"new_country":    "New country: %s (%d/%d)",
+		"collection":     "Countries collected: %d/%d (C to return)",
+		"collection_off": "The country collection is off (country_collection in the config; C to return)",
+		"first_seen":     "First seen",
+
+		"provider_switched": "Positions now from %s",
+
+		"dialog_yes":               "Yes",
+		"dialog_no":                "No",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Cancel",
+		"dialog_confirm_help":      "y/n · ←/→ or tab move · enter choose · esc cancel",
+		"confirm_delete_bookmark":  "Delete bookmark %q?",
+		"confirm_replace_bookmark": "Bookmark %q exists. Move it to the current observer location?",
+		"observer_dialog":          "Observer: latitude, longitude, altitude in metres (optional)",
+
+		"away_summary":   "While you were away (%s)",
+		"away_orbits":    "%.1f orbits",
+		"away_countries": "crossed %d countries",
+		"away_passes":    "%d passes over you, %d visible",
+
+		"setting_charset": "Characters",
+	},
+	"de": {
+		"iss_over":        "ISS über",
+		"latitude":        "Breite",
+		"longitude":       "Länge",
+		"coords":          "Koordinaten",
+		"resolving":       "Wird ermittelt...",
+		"detail":          "Detail",
+		"ocean":           "Ozean",
+		"map_unavailable": "Karte nicht verfügbar.",
+		"diagnostics":     "Diagnose (d zum Zurückkehren)",
+		"this_session":    "Diese Sitzung",
+		"all_sessions":    "Alle Sitzungen",
+		"last_error":      "Letzter Fehler",
+		"no_requests":     "noch keine Anfragen",
+
+		"tour":     "Tour",
+		"now_over": "Jetzt über",
+
+		"altitude": "Höhe",
+		"velocity": "Geschwindigkeit",
+
+		"saved": "Gespeichert:",
+
+		"recording": "REC (R zum Beenden):",
+
+		"events":    "Ereignisse",
+		"entered":   "Betreten",
+		"left":      "Verlassen",
+		"no_events": "noch keine Ereignisse",
+
+		"countries":  "Zeit über Ländern (c zum Zurückkehren, o zum Sortieren)",
+		"location":   "Ort",
+		"time":       "Zeit",
+		"no_samples": "noch keine Messwerte",
+		"by_time":    "nach Zeit",
+		"by_name":    "nach Name",
+
+		"sky_view":    "Himmelsansicht (p zum Zurückkehren)",
+		"no_observer": "Setze \"observer\" in der Konfigurationsdatei, um die Himmelsansicht zu nutzen.",
+		"loading_tle": "Bahnelemente werden geladen...",
+		"azimuth":     "Azimut",
+		"elevation":   "Elevation",
+		"range":       "Entfernung",
+		"next_pass":   "Nächster Überflug",
+		"this_pass":   "Dieser Überflug",
+
+		"radio": "Funk",
+
+		"tle_stale": "Bahnelemente sind veraltet",
+
+		"position_off": "%s weicht %s von der berechneten Position ab",
+		"position_ok":  "%s stimmt wieder mit der berechneten Position überein",
+
+		"vehicles":    "Angedockte Raumschiffe (v zum Zurückkehren)",
+		"no_vehicles": "keine Raumschiffe angedockt",
+		"vehicle":     "Raumschiff",
+		"port":        "Andockstelle",
+		"docked":      "Angedockt",
+		"updated":     "Aktualisiert",
+
+		"video_opened":  "ISS-Livevideo im Browser geöffnet",
+		"video_playing": "ISS-Livevideo läuft (l zum Beenden)",
+
+		"magnitude": "Helligkeit",
+		"eclipsed":  "im Erdschatten",
+
+		"cloud_cover": "Bewölkung",
+
+		"orbit_today": "Umlauf %d (Nr. %d heute)",
+		"uptime":      "Laufzeit",
+
+		"paused": "PAUSIERT",
+
+		"refreshing":   "Aktualisiere…",
+		"refresh_wait": "Nächste Aktualisierung in %s möglich",
+
+		"settings":            "Einstellungen (, zum Zurückkehren)",
+		"settings_help":       "↑/↓ wählen · Enter bearbeiten · ←/→ ändern · Esc zurück",
+		"setting_refresh":     "Aktualisierungsintervall (s)",
+		"setting_theme":       "Farbschema",
+		"setting_units":       "Einheiten",
+		"setting_lat":         "Beobachter Breite",
+		"setting_lon":         "Beobachter Länge",
+		"setting_alt":         "Beobachter Höhe (m)",
+		"setting_sun_moon":    "Sonne und Mond",
+		"setting_cloud_layer": "Wolkenebene",
+		"on":                  "an",
+		"off":                 "aus",
+
+		"log_view": "Protokoll (L zum Zurückkehren)",
+		"no_log":   "noch nichts protokolliert",
+
+		"demo":          "DEMO",
+		"demo_location": "Demo-Umlaufbahn",
+
+		"demo_help": "</> Tempo · [/] ±10 min · {/} ±1 Umlauf",
+
+		"breaker_open":      "%s schlägt wiederholt fehl, Pause für %s",
+		"breaker_half_open": "%s: neuer Versuch",
+
+		"heading":      "Kurs",
+		"ground_speed": "Grundgeschw.",
+
+		"eta_prompt":      "Ankunft über Ort",
+		"prompt_help":     "Enter bestätigen · Esc abbrechen",
+		"place_not_found": "kein Ort gefunden für %q",
+		"eta_in":          "in %s (%s), %s",
+		"eta_now":         "jetzt, %s",
+
+		"search_prompt": "Gehe zu Ort",
+		"choose_place":  "Welcher Ort?",
+		"choose_help":   "↑/↓ wählen · Enter oder 1-5 übernehmen · Esc abbrechen",
+
+		"setting_bookmark":     "Lesezeichen",
+		"setting_bookmark_add": "Beobachter als Lesezeichen speichern",
+		"bookmark_help":        "←/→ wählen · Enter umbenennen · x löschen",
+		"bookmark_add_help":    "Namen eingeben; ein vorhandener Name wird auf den Beobachter verschoben",
+		"no_bookmarks":         "Noch keine Lesezeichen, in den Einstellungen (,) anlegen",
+		"observer_at":          "Beobachter: %s",
+
+		"profiles_view": "Nächster Überflug je Beobachter",
+		"no_profiles":   "Kein Beobachter und keine Lesezeichen gesetzt.",
+		"profile":       "Profil",
+		"no_pass":       "keiner",
+		"profiles_help": "Erster Überflug über %d° in den nächsten %d h · P zurück",
+
+		"people_in_space": "Menschen im All",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong Höhe",
+
+		"lookalikes":        "Helle Satelliten nahe der ISS bei diesem Überflug:",
+		"lookalikes_more":   "und %d weitere",
+		"lookalike_warning": "%d helle Satelliten kreuzen die ISS-Bahn beim Überflug um %s, siehe p",
+
+		"hook_fired": "Regel erfüllt",
+
+		"pass_alert": "Sichtbarer Überflug in %d min: Aufgang %s im %s, bis %.0f°",
+
+		"daemon_lost": "Verbindung zum Daemon verloren (%v), Positionen werden direkt abgerufen",
+
+		"startup_title":    "Wird gestartet",
+		"startup_config":   "Konfiguration",
+		"startup_map":      "Weltkarte",
+		"startup_tle":      "Bahnelemente",
+		"startup_position": "ISS-Position",
+		"startup_retrying": "(neuer Versuch)",
+
+		"last_30_minutes": "Letzte 30 Minuten",
+
+		"altitude_view":      "Höhe, letzte 3 Stunden (a zum Zurückkehren)",
+		"altitude_predicted": "aus den Bahnelementen berechnet",
+		"altitude_measured":  "von der Positions-API gemeldet",
+		"altitude_range":     "Bereich",
+		"now":                "jetzt",
+
+		"latitude_view":  "Breite der Bodenspur, letzter und nächster Umlauf (g zum Zurückkehren)",
+		"heading_north":  "nach Norden",
+		"heading_south":  "nach Süden",
+		"orbital_period": "Umlaufzeit",
+
+		"orbit_raised":  "Bahn um ~%s angehoben am %s",
+		"orbit_lowered": "Bahn um ~%s abgesenkt am %s",
+
+		"space_weather": "Weltraumwetter",
+		"kp_quiet":      "ruhig",
+		"kp_active":     "aktiv, Polarlichter in hohen Breiten",
+		"storm_g1":      "G1-Sturm, Polarlichter in mittleren Breiten",
+		"storm_g2":      "G2-Sturm, Polarlichter in mittleren Breiten",
+		"storm_g3":      "G3-Sturm, Polarlichter weit von den Polen",
+		"storm_g4":      "G4-Sturm, Polarlichter weit von den Polen",
+		"storm_g5":      "G5-Sturm, Polarlichter weit von den Polen",
+		"flux_low":      "niedrig, wenig Luftwiderstand",
+		"flux_moderate": "mäßiger Luftwiderstand",
+		"flux_high":     "hoch, schnellerer Bahnabfall",
+
+		"nadir_view":       "Gerade unter der Station (n zum Zurückkehren)",
+		"nadir_local_time": "Wahre Ortszeit",
+		"nadir_day":        "Tag",
+		"nadir_twilight":   "Dämmerung",
+		"nadir_night":      "Nacht",
+		"nadir_city":       "Nächste Stadt",
+		"nadir_city_from":  "%s %s von %s",
+		"nadir_station":    "Station",
+		"nadir_sunlit":     "im Sonnenlicht",
+		"nadir_sunrise":    "Nächster Sonnenaufgang",
+		"nadir_sunset":     "Nächster Sonnenuntergang",
+		"nadir_per_day":    "Sonnenaufgänge pro Tag",
+
+		"speak_n":        "im Norden",
+		"speak_ne":       "im Nordosten",
+		"speak_e":        "im Osten",
+		"speak_se":       "im Südosten",
+		"speak_s":        "im Süden",
+		"speak_sw":       "im Südwesten",
+		"speak_w":        "im Westen",
+		"speak_nw":       "im Nordwesten",
+		"speak_soon":     "Die ISS geht in %[2]d Minuten %[1]s auf",
+		"speak_soon_one": "Die ISS geht in einer Minute %s auf",
+		"speak_rise":     "Die ISS geht jetzt %s auf, bis %.0f Grad",
+		"speak_peak":     "Die ISS steht am höchsten, %.0f Grad %s",
+
+		"journal_prompt":  "Sichtung notieren, Bewertung 1-5 und Notizen",
+		"journal_no_pass": "Kein Überflug über deinem Standort in den letzten 6 Stunden zum Notieren",
+		"journal_saved":   "Sichtung gespeichert (%d im Journal)",
+
+		"offshore": "%s (vor der Küste von %s)",
+
+		"iss_near":         "ISS nahe",
+		"setting_location": "Ortsangabe",
+
+		"setting_flag": "Landesflagge",
+
+		"new_country":    "Neues Land: %s (%d/%d)",
+		"collection":     "Gesammelte Länder: %d/%d (C zum Zurückkehren)",
+		"collection_off": "Die Ländersammlung ist aus (country_collection in der Konfiguration; C zum Zurückkehren)",
+		"first_seen":     "Zuerst gesehen",
+
+		"provider_switched": "Positionen jetzt von %s",
+
+		"dialog_yes":               "Ja",
+		"dialog_no":                "Nein",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Abbrechen",
+		"dialog_confirm_help":      "y/n · ←/→ oder Tab wechseln · Enter wählen · Esc abbrechen",
+		"confirm_delete_bookmark":  "Lesezeichen %q löschen?",
+		"confirm_replace_bookmark": "Lesezeichen %q existiert. An den aktuellen Beobachterstandort verschieben?",
+		"observer_dialog":          "Beobachter: Breite, Länge, Höhe in Metern (optional)",
+
+		"away_summary":   "Während du weg warst (%s)",
+		"away_orbits":    "%.1f Umläufe",
+		"away_countries": "%d Länder überflogen",
+		"away_passes":    "%d Überflüge bei dir, %d sichtbar",
+
+		"setting_charset": "Zeichensatz",
+	},
+	"fr": {
+		"iss_over":        "ISS au-dessus de",
+		"latitude":        "Latitude",
+		"longitude":       "Longitude",
+		"coords":          "Coordonnées",
+		"resolving":       "Recherche...",
+		"detail":          "Détail",
+		"ocean":           "Océan",
+		"map_unavailable": "Carte indisponible.",
+		"diagnostics":     "Diagnostic (d pour revenir)",
+		"this_session":    "Cette session",
+		"all_sessions":    "Toutes les sessions",
+		"last_error":      "Dernière erreur",
+		"no_requests":     "aucune requête",
+
+		"tour":     "Visite",
+		"now_over": "Maintenant au-dessus de",
+
+		"altitude": "Altitude",
+		"velocity": "Vitesse",
+
+		"saved": "Enregistré :",
+
+		"recording": "REC (R pour arrêter) :",
+
+		"events":    "Événements",
+		"entered":   "Entrée dans",
+		"left":      "Sortie de",
+		"no_events": "aucun événement",
+
+		"countries":  "Temps au-dessus des pays (c pour revenir, o pour trier)",
+		"location":   "Lieu",
+		"time":       "Durée",
+		"no_samples": "aucune mesure",
+		"by_time":    "par durée",
+		"by_name":    "par nom",
+
+		"sky_view":    "Vue du ciel (p pour revenir)",
+		"no_observer": "Définissez \"observer\" dans le fichier de configuration pour la vue du ciel.",
+		"loading_tle": "Chargement des éléments orbitaux...",
+		"azimuth":     "Azimut",
+		"elevation":   "Élévation",
+		"range":       "Distance",
+		"next_pass":   "Prochain passage",
+		"this_pass":   "Passage en cours",
+
+		"radio": "Radio",
+
+		"tle_stale": "Éléments orbitaux périmés",
+
+		"position_off": "%s est à %s de la position calculée",
+		"position_ok":  "%s concorde de nouveau avec la position calculée",
+
+		"vehicles":    "Véhicules amarrés (v pour revenir)",
+		"no_vehicles": "aucun véhicule amarré",
+		"vehicle":     "Véhicule",
+		"port":        "Port",
+		"docked":      "Amarré",
+		"updated":     "Mis à jour",
+
+		"video_opened":  "Vidéo en direct de l’ISS ouverte dans le navigateur",
+		"video_playing": "Lecture de la vidéo en direct de l’ISS (l pour arrêter)",
+
+		"magnitude": "Magnitude",
+		"eclipsed":  "dans l’ombre de la Terre",
+
+		"cloud_cover": "Couverture nuageuse",
+
+		"orbit_today": "Orbite %d (n° %d aujourd’hui)",
+		"uptime":      "Actif depuis",
+
+		"paused": "EN PAUSE",
+
+		"refreshing":   "Actualisation…",
+		"refresh_wait": "Prochaine actualisation possible dans %s",
+
+		"settings":            "Réglages (, pour revenir)",
+		"settings_help":       "↑/↓ choisir · Entrée modifier · ←/→ changer · Échap retour",
+		"setting_refresh":     "Intervalle d’actualisation (s)",
+		"setting_theme":       "Thème",
+		"setting_units":       "Unités",
+		"setting_lat":         "Latitude de l’observateur",
+		"setting_lon":         "Longitude de l’observateur",
+		"setting_alt":         "Altitude de l’observateur (m)",
+		"setting_sun_moon":    "Soleil et lune",
+		"setting_cloud_layer": "Couche nuageuse",
+		"on":                  "activé",
+		"off":                 "désactivé",
+
+		"log_view": "Journal (L pour revenir)",
+		"no_log":   "rien de journalisé",
+
+		"demo":          "DÉMO",
+		"demo_location": "Orbite de démo",
+
+		"demo_help": "</> vitesse · [/] ±10 min · {/} ±1 orbite",
+
+		"breaker_open":      "%s échoue sans cesse, en pause pour %s",
+		"breaker_half_open": "%s : nouvel essai",
+
+		"heading":      "Cap",
+		"ground_speed": "Vitesse sol",
+
+		"eta_prompt":      "Passage au-dessus de",
+		"prompt_help":     "Entrée valider · Échap annuler",
+		"place_not_found": "aucun lieu trouvé pour %q",
+		"eta_in":          "dans %s (%s), %s",
+		"eta_now":         "maintenant, %s",
+
+		"search_prompt": "Aller au lieu",
+		"choose_place":  "Quel lieu ?",
+		"choose_help":   "↑/↓ choisir · Entrée ou 1-5 valider · Échap annuler",
+
+		"setting_bookmark":     "Favori",
+		"setting_bookmark_add": "Enregistrer l’observateur en favori",
+		"bookmark_help":        "←/→ choisir · Entrée renommer · x supprimer",
+		"bookmark_add_help":    "saisir un nom ; un nom existant est déplacé sur l’observateur",
+		"no_bookmarks":         "Aucun favori, ajoutez-en dans les réglages (,)",
+		"observer_at":          "Observateur : %s",
+
+		"profiles_view": "Prochain passage par observateur",
+		"no_profiles":   "Aucun observateur ni favori défini.",
+		"profile":       "Profil",
+		"no_pass":       "aucun",
+		"profiles_help": "Premier passage au-dessus de %d° dans les %d h · P retour",
+
+		"people_in_space": "Personnes dans l’espace",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong alt.",
+
+		"lookalikes":        "Satellites brillants près de l’ISS pendant ce passage :",
+		"lookalikes_more":   "et %d de plus",
+		"lookalike_warning": "%d satellites brillants croisent la trace de l’ISS au passage de %s, voir p",
+
+		"hook_fired": "Règle remplie",
+
+		"pass_alert": "Passage visible dans %d min : lever à %s au %s, jusqu’à %.0f°",
+
+		"daemon_lost": "connexion au démon perdue (%v), récupération directe des positions",
+
+		"startup_title":    "Démarrage",
+		"startup_config":   "Configuration",
+		"startup_map":      "Carte du monde",
+		"startup_tle":      "Éléments orbitaux",
+		"startup_position": "Position de l’ISS",
+		"startup_retrying": "(nouvel essai)",
+
+		"last_30_minutes": "30 dernières minutes",
+
+		"altitude_view":      "Altitude, 3 dernières heures (a pour revenir)",
+		"altitude_predicted": "calculée à partir des éléments orbitaux",
+		"altitude_measured":  "fournie par l’API de position",
+		"altitude_range":     "Plage",
+		"now":                "maintenant",
+
+		"latitude_view":  "Latitude de la trace au sol, orbite passée et suivante (g pour revenir)",
+		"heading_north":  "vers le nord",
+		"heading_south":  "vers le sud",
+		"orbital_period": "Période orbitale",
+
+		"orbit_raised":  "Orbite relevée de ~%s le %s",
+		"orbit_lowered": "Orbite abaissée de ~%s le %s",
+
+		"space_weather": "Météo spatiale",
+		"kp_quiet":      "calme",
+		"kp_active":     "actif, aurores aux hautes latitudes",
+		"storm_g1":      "tempête G1, aurores aux latitudes moyennes",
+		"storm_g2":      "tempête G2, aurores aux latitudes moyennes",
+		"storm_g3":      "tempête G3, aurores loin des pôles",
+		"storm_g4":      "tempête G4, aurores loin des pôles",
+		"storm_g5":      "tempête G5, aurores loin des pôles",
+		"flux_low":      "faible, peu de freinage",
+		"flux_moderate": "freinage modéré",
+		"flux_high":     "élevé, décroissance orbitale plus rapide",
+
+		"nadir_view":       "Sous la station maintenant (n pour revenir)",
+		"nadir_local_time": "Heure solaire locale",
+		"nadir_day":        "jour",
+		"nadir_twilight":   "crépuscule",
+		"nadir_night":      "nuit",
+		"nadir_city":       "Ville la plus proche",
+		"nadir_city_from":  "%s %s de %s",
+		"nadir_station":    "Station",
+		"nadir_sunlit":     "au soleil",
+		"nadir_sunrise":    "Prochain lever du soleil",
+		"nadir_sunset":     "Prochain coucher du soleil",
+		"nadir_per_day":    "Levers de soleil par jour",
+
+		"speak_n":        "au nord",
+		"speak_ne":       "au nord-est",
+		"speak_e":        "à l’est",
+		"speak_se":       "au sud-est",
+		"speak_s":        "au sud",
+		"speak_sw":       "au sud-ouest",
+		"speak_w":        "à l’ouest",
+		"speak_nw":       "au nord-ouest",
+		"speak_soon":     "L’ISS se lève %s dans %d minutes",
+		"speak_soon_one": "L’ISS se lève %s dans une minute",
+		"speak_rise":     "L’ISS se lève maintenant %s, jusqu’à %.0f degrés",
+		"speak_peak":     "L’ISS est au plus haut, %.0f degrés %s",
+
+		"journal_prompt":  "Noter l’observation, note de 1 à 5 puis remarques",
+		"journal_no_pass": "Aucun passage au-dessus de votre position ces 6 dernières heures",
+		"journal_saved":   "Observation enregistrée (%d dans le journal)",
+
+		"offshore": "%s (au large de : %s)",
+
+		"iss_near":         "ISS près de",
+		"setting_location": "Position",
+
+		"setting_flag": "Drapeau du pays",
+
+		"new_country":    "Nouveau pays : %s (%d/%d)",
+		"collection":     "Pays collectionnés : %d/%d (C pour revenir)",
+		"collection_off": "La collection de pays est désactivée (country_collection dans la configuration ; C pour revenir)",
+		"first_seen":     "Vu la première fois",
+
+		"provider_switched": "Positions désormais de %s",
+
+		"dialog_yes":               "Oui",
+		"dialog_no":                "Non",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Annuler",
+		"dialog_confirm_help":      "y/n · ←/→ ou tab déplacer · entrée choisir · échap annuler",
+		"confirm_delete_bookmark":  "Supprimer le favori %q ?",
+		"confirm_replace_bookmark": "Le favori %q existe. Le déplacer vers la position actuelle de l’observateur ?",
+		"observer_dialog":          "Observateur : latitude, longitude, altitude en mètres (facultative)",
+
+		"away_summary":   "Pendant votre absence (%s)",
+		"away_orbits":    "%.1f orbites",
+		"away_countries": "%d pays survolés",
+		"away_passes":    "%d passages au-dessus de vous, %d visibles",
+
+		"setting_charset": "Jeu de caractères",
+	},
+	"es": {
+		"iss_over":        "ISS sobre",
+		"latitude":        "Latitud",
+		"longitude":       "Longitud",
+		"coords":          "Coordenadas",
+		"resolving":       "Resolviendo...",
+		"detail":          "Detalle",
+		"ocean":           "Océano",
+		"map_unavailable": "Mapa no disponible.",
+		"diagnostics":     "Diagnóstico (d para volver)",
+		"this_session":    "Esta sesión",
+		"all_sessions":    "Todas las sesiones",
+		"last_error":      "Último error",
+		"no_requests":     "sin solicitudes aún",
+
+		"tour":     "Recorrido",
+		"now_over": "Ahora sobre",
+
+		"altitude": "Altitud",
+		"velocity": "Velocidad",
+
+		"saved": "Guardado:",
+
+		"recording": "REC (R para detener):",
+
+		"events":    "Eventos",
+		"entered":   "Entró en",
+		"left":      "Salió de",
+		"no_events": "sin eventos todavía",
+
+		"countries":  "Tiempo sobre países (c para volver, o para ordenar)",
+		"location":   "Lugar",
+		"time":       "Tiempo",
+		"no_samples": "sin muestras todavía",
+		"by_time":    "por tiempo",
+		"by_name":    "por nombre",
+
+		"sky_view":    "Vista del cielo (p para volver)",
+		"no_observer": "Define \"observer\" en el archivo de configuración para usar la vista del cielo.",
+		"loading_tle": "Cargando elementos orbitales...",
+		"azimuth":     "Acimut",
+		"elevation":   "Elevación",
+		"range":       "Distancia",
+		"next_pass":   "Próximo paso",
+		"this_pass":   "Paso actual",
+
+		"radio": "Radio",
+
+		"tle_stale": "Elementos orbitales desactualizados",
+
+		"position_off": "%s está a %s de la posición calculada",
+		"position_ok":  "%s vuelve a coincidir con la posición calculada",
+
+		"vehicles":    "Naves acopladas (v para volver)",
+		"no_vehicles": "ninguna nave acoplada",
+		"vehicle":     "Nave",
+		"port":        "Puerto",
+		"docked":      "Acoplada",
+		"updated":     "Actualizado",
+
+		"video_opened":  "Vídeo en directo de la ISS abierto en el navegador",
+		"video_playing": "Reproduciendo el vídeo en directo de la ISS (l para detener)",
+
+		"magnitude": "Magnitud",
+		"eclipsed":  "en la sombra de la Tierra",
+
+		"cloud_cover": "Nubosidad",
+
+		"orbit_today": "Órbita %d (n.º %d hoy)",
+		"uptime":      "Activo",
+
+		"paused": "EN PAUSA",
+
+		"refreshing":   "Actualizando…",
+		"refresh_wait": "Próxima actualización posible en %s",
+
+		"settings":            "Ajustes (, para volver)",
+		"settings_help":       "↑/↓ elegir · Intro editar · ←/→ cambiar · Esc volver",
+		"setting_refresh":     "Intervalo de actualización (s)",
+		"setting_theme":       "Tema",
+		"setting_units":       "Unidades",
+		"setting_lat":         "Latitud del observador",
+		"setting_lon":         "Longitud del observador",
+		"setting_alt":         "Altitud del observador (m)",
+		"setting_sun_moon":    "Sol y luna",
+		"setting_cloud_layer": "Capa de nubes",
+		"on":                  "activado",
+		"off":                 "desactivado",
+
+		"log_view": "Registro (L para volver)",
+		"no_log":   "nada registrado todavía",
+
+		"demo":          "DEMO",
+		"demo_location": "Órbita de demostración",
+
+		"demo_help": "</> velocidad · [/] ±10 min · {/} ±1 órbita",
+
+		"breaker_open":      "%s falla repetidamente, en pausa durante %s",
+		"breaker_half_open": "%s: reintentando",
+
+		"heading":      "Rumbo",
+		"ground_speed": "Vel. suelo",
+
+		"eta_prompt":      "Paso sobre el lugar",
+		"prompt_help":     "Intro confirmar · Esc cancelar",
+		"place_not_found": "ningún lugar encontrado para %q",
+		"eta_in":          "en %s (%s), %s",
+		"eta_now":         "ahora, %s",
+
+		"search_prompt": "Ir al lugar",
+		"choose_place":  "¿Qué lugar?",
+		"choose_help":   "↑/↓ elegir · Intro o 1-5 aceptar · Esc cancelar",
+
+		"setting_bookmark":     "Marcador",
+		"setting_bookmark_add": "Guardar observador como marcador",
+		"bookmark_help":        "←/→ elegir · Intro renombrar · x borrar",
+		"bookmark_add_help":    "escribe un nombre; un nombre existente se mueve al observador",
+		"no_bookmarks":         "Aún no hay marcadores, añade uno en ajustes (,)",
+		"observer_at":          "Observador: %s",
+
+		"profiles_view": "Próximo paso por observador",
+		"no_profiles":   "No hay observador ni marcadores.",
+		"profile":       "Perfil",
+		"no_pass":       "ninguno",
+		"profiles_help": "Primer paso sobre %d° en las próximas %d h · P volver",
+
+		"people_in_space": "Personas en el espacio",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong alt.",
+
+		"lookalikes":        "Satélites brillantes cerca de la ISS en este paso:",
+		"lookalikes_more":   "y %d más",
+		"lookalike_warning": "%d satélites brillantes cruzan cerca de la ISS en el paso de las %s, ver p",
+
+		"hook_fired": "Regla cumplida",
+
+		"pass_alert": "Paso visible en %d min: sale a las %s por el %s, hasta %.0f°",
+
+		"daemon_lost": "conexión con el daemon perdida (%v), obteniendo posiciones directamente",
+
+		"startup_title":    "Iniciando",
+		"startup_config":   "Configuración",
+		"startup_map":      "Mapa mundial",
+		"startup_tle":      "Elementos orbitales",
+		"startup_position": "Posición de la ISS",
+		"startup_retrying": "(reintentando)",
+
+		"last_30_minutes": "Últimos 30 minutos",
+
+		"altitude_view":      "Altitud, últimas 3 horas (a para volver)",
+		"altitude_predicted": "calculada con los elementos orbitales",
+		"altitude_measured":  "informada por la API de posición",
+		"altitude_range":     "Rango",
+		"now":                "ahora",
+
+		"latitude_view":  "Latitud de la traza, órbita anterior y siguiente (g para volver)",
+		"heading_north":  "hacia el norte",
+		"heading_south":  "hacia el sur",
+		"orbital_period": "Período orbital",
+
+		"orbit_raised":  "Órbita elevada ~%s el %s",
+		"orbit_lowered": "Órbita descendida ~%s el %s",
+
+		"space_weather": "Clima espacial",
+		"kp_quiet":      "tranquilo",
+		"kp_active":     "activo, auroras en latitudes altas",
+		"storm_g1":      "tormenta G1, auroras en latitudes medias",
+		"storm_g2":      "tormenta G2, auroras en latitudes medias",
+		"storm_g3":      "tormenta G3, auroras lejos de los polos",
+		"storm_g4":      "tormenta G4, auroras lejos de los polos",
+		"storm_g5":      "tormenta G5, auroras lejos de los polos",
+		"flux_low":      "bajo, poco arrastre",
+		"flux_moderate": "arrastre moderado",
+		"flux_high":     "alto, decaimiento orbital más rápido",
+
+		"nadir_view":       "Bajo la estación ahora (n para volver)",
+		"nadir_local_time": "Hora solar local",
+		"nadir_day":        "día",
+		"nadir_twilight":   "crepúsculo",
+		"nadir_night":      "noche",
+		"nadir_city":       "Ciudad más cercana",
+		"nadir_city_from":  "%s %s de %s",
+		"nadir_station":    "Estación",
+		"nadir_sunlit":     "al sol",
+		"nadir_sunrise":    "Próximo amanecer",
+		"nadir_sunset":     "Próximo atardecer",
+		"nadir_per_day":    "Amaneceres por día",
+
+		"speak_n":        "por el norte",
+		"speak_ne":       "por el noreste",
+		"speak_e":        "por el este",
+		"speak_se":       "por el sureste",
+		"speak_s":        "por el sur",
+		"speak_sw":       "por el suroeste",
+		"speak_w":        "por el oeste",
+		"speak_nw":       "por el noroeste",
+		"speak_soon":     "La ISS sale %s en %d minutos",
+		"speak_soon_one": "La ISS sale %s en un minuto",
+		"speak_rise":     "La ISS sale ahora %s, hasta %.0f grados",
+		"speak_peak":     "La ISS en su punto más alto, %.0f grados %s",
+
+		"journal_prompt":  "Registrar avistamiento, nota 1-5 y comentarios",
+		"journal_no_pass": "Ningún paso sobre tu ubicación en las últimas 6 horas para registrar",
+		"journal_saved":   "Avistamiento guardado (%d en el diario)",
+
+		"offshore": "%s (frente a la costa de %s)",
+
+		"iss_near":         "ISS cerca de",
+		"setting_location": "Ubicación",
+
+		"setting_flag": "Bandera del país",
+
+		"new_country":    "Nuevo país: %s (%d/%d)",
+		"collection":     "Países coleccionados: %d/%d (C para volver)",
+		"collection_off": "La colección de países está desactivada (country_collection en la configuración; C para volver)",
+		"first_seen":     "Visto por primera vez",
+
+		"provider_switched": "Posiciones ahora de %s",
+
+		"dialog_yes":               "Sí",
+		"dialog_no":                "No",
+		"dialog_ok":                "Aceptar",
+		"dialog_cancel":            "Cancelar",
+		"dialog_confirm_help":      "y/n · ←/→ o tab mover · intro elegir · esc cancelar",
+		"confirm_delete_bookmark":  "¿Eliminar el marcador %q?",
+		"confirm_replace_bookmark": "El marcador %q ya existe. ¿Moverlo a la ubicación actual del observador?",
+		"observer_dialog":          "Observador: latitud, longitud, altitud en metros (opcional)",
+
+		"away_summary":   "Mientras no estabas (%s)",
+		"away_orbits":    "%.1f órbitas",
+		"away_countries": "%d países sobrevolados",
+		"away_passes":    "%d pasos sobre ti, %d visibles",
+
+		"setting_charset": "Juego de caracteres",
+	},
+	"it": {
+		"iss_over":        "ISS sopra",
+		"latitude":        "Latitudine",
+		"longitude":       "Longitudine",
+		"coords":          "Coordinate",
+		"resolving":       "Ricerca...",
+		"detail":          "Dettaglio",
+		"ocean":           "Oceano",
+		"map_unavailable": "Mappa non disponibile.",
+		"diagnostics":     "Diagnostica (d per tornare)",
+		"this_session":    "Questa sessione",
+		"all_sessions":    "Tutte le sessioni",
+		"last_error":      "Ultimo errore",
+		"no_requests":     "nessuna richiesta",
+
+		"tour":     "Tour",
+		"now_over": "Ora sopra",
+
+		"altitude": "Altitudine",
+		"velocity": "Velocità",
+
+		"saved": "Salvato:",
+
+		"recording": "REC (R per fermare):",
+
+		"events":    "Eventi",
+		"entered":   "Entrata in",
+		"left":      "Uscita da",
+		"no_events": "nessun evento",
+
+		"countries":  "Tempo sopra i paesi (c per tornare, o per ordinare)",
+		"location":   "Luogo",
+		"time":       "Tempo",
+		"no_samples": "nessun campione",
+		"by_time":    "per tempo",
+		"by_name":    "per nome",
+
+		"sky_view":    "Vista del cielo (p per tornare)",
+		"no_observer": "Imposta \"observer\" nel file di configurazione per usare la vista del cielo.",
+		"loading_tle": "Caricamento degli elementi orbitali...",
+		"azimuth":     "Azimut",
+		"elevation":   "Elevazione",
+		"range":       "Distanza",
+		"next_pass":   "Prossimo passaggio",
+		"this_pass":   "Passaggio in corso",
+
+		"radio": "Radio",
+
+		"tle_stale": "Elementi orbitali obsoleti",
+
+		"position_off": "%s è a %s dalla posizione calcolata",
+		"position_ok":  "%s torna a coincidere con la posizione calcolata",
+
+		"vehicles":    "Veicoli attraccati (v per tornare)",
+		"no_vehicles": "nessun veicolo attraccato",
+		"vehicle":     "Veicolo",
+		"port":        "Porta",
+		"docked":      "Attraccato",
+		"updated":     "Aggiornato",
+
+		"video_opened":  "Video in diretta della ISS aperto nel browser",
+		"video_playing": "Riproduzione del video in diretta della ISS (l per fermare)",
+
+		"magnitude": "Magnitudine",
+		"eclipsed":  "nell’ombra della Terra",
+
+		"cloud_cover": "Copertura nuvolosa",
+
+		"orbit_today": "Orbita %d (n. %d oggi)",
+		"uptime":      "Attivo da",
+
+		"paused": "IN PAUSA",
+
+		"refreshing":   "Aggiornamento…",
+		"refresh_wait": "Prossimo aggiornamento possibile tra %s",
+
+		"settings":            "Impostazioni (, per tornare)",
+		"settings_help":       "↑/↓ scegli · Invio modifica · ←/→ cambia · Esc indietro",
+		"setting_refresh":     "Intervallo di aggiornamento (s)",
+		"setting_theme":       "Tema",
+		"setting_units":       "Unità",
+		"setting_lat":         "Latitudine dell’osservatore",
+		"setting_lon":         "Longitudine dell’osservatore",
+		"setting_alt":         "Altitudine dell’osservatore (m)",
+		"setting_sun_moon":    "Sole e luna",
+		"setting_cloud_layer": "Strato di nuvole",
+		"on":                  "attivo",
+		"off":                 "disattivo",
+
+		"log_view": "Registro (L per tornare)",
+		"no_log":   "ancora nessuna voce",
+
+		"demo":          "DEMO",
+		"demo_location": "Orbita dimostrativa",
+
+		"demo_help": "</> velocità · [/] ±10 min · {/} ±1 orbita",
+
+		"breaker_open":      "%s continua a fallire, in pausa per %s",
+		"breaker_half_open": "%s: nuovo tentativo",
+
+		"heading":      "Rotta",
+		"ground_speed": "Vel. al suolo",
+
+		"eta_prompt":      "Passaggio sopra il luogo",
+		"prompt_help":     "Invio conferma · Esc annulla",
+		"place_not_found": "nessun luogo trovato per %q",
+		"eta_in":          "tra %s (%s), %s",
+		"eta_now":         "ora, %s",
+
+		"search_prompt": "Vai al luogo",
+		"choose_place":  "Quale luogo?",
+		"choose_help":   "↑/↓ scegli · Invio o 1-5 conferma · Esc annulla",
+
+		"setting_bookmark":     "Segnalibro",
+		"setting_bookmark_add": "Salva osservatore come segnalibro",
+		"bookmark_help":        "←/→ scegli · Invio rinomina · x elimina",
+		"bookmark_add_help":    "inserisci un nome; un nome esistente viene spostato sull’osservatore",
+		"no_bookmarks":         "Nessun segnalibro, aggiungine uno nelle impostazioni (,)",
+		"observer_at":          "Osservatore: %s",
+
+		"profiles_view": "Prossimo passaggio per osservatore",
+		"no_profiles":   "Nessun osservatore o segnalibro impostato.",
+		"profile":       "Profilo",
+		"no_pass":       "nessuno",
+		"profiles_help": "Primo passaggio sopra %d° nelle prossime %d h · P indietro",
+
+		"people_in_space": "Persone nello spazio",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong quota",
+
+		"lookalikes":        "Satelliti luminosi vicino alla ISS in questo passaggio:",
+		"lookalikes_more":   "e altri %d",
+		"lookalike_warning": "%d satelliti luminosi incrociano la traccia della ISS nel passaggio delle %s, vedi p",
+
+		"hook_fired": "Regola soddisfatta",
+
+		"pass_alert": "Passaggio visibile tra %d min: sorge alle %s a %s, fino a %.0f°",
+
+		"daemon_lost": "connessione al daemon persa (%v), posizioni recuperate direttamente",
+
+		"startup_title":    "Avvio in corso",
+		"startup_config":   "Configurazione",
+		"startup_map":      "Mappa del mondo",
+		"startup_tle":      "Elementi orbitali",
+		"startup_position": "Posizione della ISS",
+		"startup_retrying": "(nuovo tentativo)",
+
+		"last_30_minutes": "Ultimi 30 minuti",
+
+		"altitude_view":      "Altitudine, ultime 3 ore (a per tornare)",
+		"altitude_predicted": "calcolata dagli elementi orbitali",
+		"altitude_measured":  "fornita dall’API di posizione",
+		"altitude_range":     "Intervallo",
+		"now":                "ora",
+
+		"latitude_view":  "Latitudine della traccia, orbita precedente e successiva (g per tornare)",
+		"heading_north":  "verso nord",
+		"heading_south":  "verso sud",
+		"orbital_period": "Periodo orbitale",
+
+		"orbit_raised":  "Orbita alzata di ~%s il %s",
+		"orbit_lowered": "Orbita abbassata di ~%s il %s",
+
+		"space_weather": "Meteo spaziale",
+		"kp_quiet":      "calmo",
+		"kp_active":     "attivo, aurore alle alte latitudini",
+		"storm_g1":      "tempesta G1, aurore alle medie latitudini",
+		"storm_g2":      "tempesta G2, aurore alle medie latitudini",
+		"storm_g3":      "tempesta G3, aurore lontano dai poli",
+		"storm_g4":      "tempesta G4, aurore lontano dai poli",
+		"storm_g5":      "tempesta G5, aurore lontano dai poli",
+		"flux_low":      "basso, poca resistenza",
+		"flux_moderate": "resistenza moderata",
+		"flux_high":     "alto, decadimento orbitale più rapido",
+
+		"nadir_view":       "Sotto la stazione ora (n per tornare)",
+		"nadir_local_time": "Ora solare locale",
+		"nadir_day":        "giorno",
+		"nadir_twilight":   "crepuscolo",
+		"nadir_night":      "notte",
+		"nadir_city":       "Città più vicina",
+		"nadir_city_from":  "%s %s da %s",
+		"nadir_station":    "Stazione",
+		"nadir_sunlit":     "al sole",
+		"nadir_sunrise":    "Prossima alba",
+		"nadir_sunset":     "Prossimo tramonto",
+		"nadir_per_day":    "Albe al giorno",
+
+		"speak_n":        "a nord",
+		"speak_ne":       "a nord-est",
+		"speak_e":        "a est",
+		"speak_se":       "a sud-est",
+		"speak_s":        "a sud",
+		"speak_sw":       "a sud-ovest",
+		"speak_w":        "a ovest",
+		"speak_nw":       "a nord-ovest",
+		"speak_soon":     "La ISS sorge %s tra %d minuti",
+		"speak_soon_one": "La ISS sorge %s tra un minuto",
+		"speak_rise":     "La ISS sorge ora %s, fino a %.0f gradi",
+		"speak_peak":     "La ISS è al punto più alto, %.0f gradi %s",
+
+		"journal_prompt":  "Registra avvistamento, voto 1-5 e note",
+		"journal_no_pass": "Nessun passaggio sulla tua posizione nelle ultime 6 ore da registrare",
+		"journal_saved":   "Avvistamento salvato (%d nel diario)",
+
+		"offshore": "%s (al largo di: %s)",
+
+		"iss_near":         "ISS vicino a",
+		"setting_location": "Posizione",
+
+		"setting_flag": "Bandiera del paese",
+
+		"new_country":    "Nuovo paese: %s (%d/%d)",
+		"collection":     "Paesi collezionati: %d/%d (C per tornare)",
+		"collection_off": "La collezione di paesi è disattivata (country_collection nella configurazione; C per tornare)",
+		"first_seen":     "Visto la prima volta",
+
+		"provider_switched": "Posizioni ora da %s",
+
+		"dialog_yes":               "Sì",
+		"dialog_no":                "No",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Annulla",
+		"dialog_confirm_help":      "y/n · ←/→ o tab sposta · invio scegli · esc annulla",
+		"confirm_delete_bookmark":  "Eliminare il segnalibro %q?",
+		"confirm_replace_bookmark": "Il segnalibro %q esiste. Spostarlo sulla posizione attuale dell’osservatore?",
+		"observer_dialog":          "Osservatore: latitudine, longitudine, altitudine in metri (facoltativa)",
+
+		"away_summary":   "Mentre eri via (%s)",
+		"away_orbits":    "%.1f orbite",
+		"away_countries": "%d paesi sorvolati",
+		"away_passes":    "%d passaggi sopra di te, %d visibili",
+
+		"setting_charset": "Set di caratteri",
+	},
+	"pl": {
+		"iss_over":        "ISS nad",
+		"latitude":        "Szerokość",
+		"longitude":       "Długość",
+		"coords":          "Współrzędne",
+		"resolving":       "Ustalanie...",
+		"detail":          "Szczegóły",
+		"ocean":           "Ocean",
+		"map_unavailable": "Mapa niedostępna.",
+		"diagnostics":     "Diagnostyka (d, aby wrócić)",
+		"this_session":    "Ta sesja",
+		"all_sessions":    "Wszystkie sesje",
+		"last_error":      "Ostatni błąd",
+		"no_requests":     "brak zapytań",
+
+		"tour":     "Wycieczka",
+		"now_over": "Teraz nad",
+
+		"altitude": "Wysokość",
+		"velocity": "Prędkość",
+
+		"saved": "Zapisano:",
+
+		"recording": "REC (r, aby zatrzymać):",
+
+		"events":    "Zdarzenia",
+		"entered":   "Wejście w",
+		"left":      "Wyjście z",
+		"no_events": "brak zdarzeń",
+
+		"countries":  "Czas nad krajami (c, aby wrócić, o, aby sortować)",
+		"location":   "Miejsce",
+		"time":       "Czas",
+		"no_samples": "brak próbek",
+		"by_time":    "wg czasu",
+		"by_name":    "wg nazwy",
+
+		"sky_view":    "Widok nieba (p, aby wrócić)",
+		"no_observer": "Ustaw \"observer\" w pliku konfiguracyjnym, aby użyć widoku nieba.",
+		"loading_tle": "Wczytywanie elementów orbitalnych...",
+		"azimuth":     "Azymut",
+		"elevation":   "Wysokość",
+		"range":       "Odległość",
+		"next_pass":   "Następny przelot",
+		"this_pass":   "Bieżący przelot",
+
+		"radio": "Radio",
+
+		"tle_stale": "Elementy orbitalne są nieaktualne",
+
+		"position_off": "%s odbiega o %s od obliczonej pozycji",
+		"position_ok":  "%s znów zgadza się z obliczoną pozycją",
+
+		"vehicles":    "Zadokowane statki (v, aby wrócić)",
+		"no_vehicles": "brak zadokowanych statków",
+		"vehicle":     "Statek",
+		"port":        "Port",
+		"docked":      "Zadokowany",
+		"updated":     "Zaktualizowano",
+
+		"video_opened":  "Otwarto transmisję na żywo z ISS w przeglądarce",
+		"video_playing": "Odtwarzanie transmisji na żywo z ISS (l, aby zatrzymać)",
+
+		"magnitude": "Jasność",
+		"eclipsed":  "w cieniu Ziemi",
+
+		"cloud_cover": "Zachmurzenie",
+
+		"orbit_today": "Orbita %d (nr %d dzisiaj)",
+		"uptime":      "Działa",
+
+		"paused": "WSTRZYMANO",
+
+		"refreshing":   "Odświeżanie…",
+		"refresh_wait": "Kolejne odświeżenie możliwe za %s",
+
+		"settings":            "Ustawienia (, aby wrócić)",
+		"settings_help":       "↑/↓ wybór · Enter edycja · ←/→ zmiana · Esc powrót",
+		"setting_refresh":     "Interwał odświeżania (s)",
+		"setting_theme":       "Motyw",
+		"setting_units":       "Jednostki",
+		"setting_lat":         "Szerokość obserwatora",
+		"setting_lon":         "Długość obserwatora",
+		"setting_alt":         "Wysokość obserwatora (m)",
+		"setting_sun_moon":    "Słońce i księżyc",
+		"setting_cloud_layer": "Warstwa chmur",
+		"on":                  "wł.",
+		"off":                 "wył.",
+
+		"log_view": "Dziennik (L, aby wrócić)",
+		"no_log":   "brak wpisów",
+
+		"demo":          "DEMO",
+		"demo_location": "Orbita demonstracyjna",
+
+		"demo_help": "</> tempo · [/] ±10 min · {/} ±1 orbita",
+
+		"breaker_open":      "%s ciągle zawodzi, wstrzymano na %s",
+		"breaker_half_open": "%s: ponowna próba",
+
+		"heading":      "Kurs",
+		"ground_speed": "Pręd. nad ziemią",
+
+		"eta_prompt":      "Przelot nad miejscem",
+		"prompt_help":     "Enter zatwierdź · Esc anuluj",
+		"place_not_found": "nie znaleziono miejsca dla %q",
+		"eta_in":          "za %s (%s), %s",
+		"eta_now":         "teraz, %s",
+
+		"search_prompt": "Przejdź do miejsca",
+		"choose_place":  "Które miejsce?",
+		"choose_help":   "↑/↓ wybierz · Enter lub 1-5 zatwierdź · Esc anuluj",
+
+		"setting_bookmark":     "Zakładka",
+		"setting_bookmark_add": "Zapisz obserwatora jako zakładkę",
+		"bookmark_help":        "←/→ wybierz · Enter zmień nazwę · x usuń",
+		"bookmark_add_help":    "wpisz nazwę; istniejąca nazwa zostanie przeniesiona do obserwatora",
+		"no_bookmarks":         "Brak zakładek, dodaj je w ustawieniach (,)",
+		"observer_at":          "Obserwator: %s",
+
+		"profiles_view": "Następny przelot dla każdego obserwatora",
+		"no_profiles":   "Brak obserwatora i zakładek.",
+		"profile":       "Profil",
+		"no_pass":       "brak",
+		"profiles_help": "Pierwszy przelot powyżej %d° w ciągu %d h · P wróć",
+
+		"people_in_space": "Ludzie w kosmosie",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong wys.",
+
+		"lookalikes":        "Jasne satelity w pobliżu ISS podczas tego przelotu:",
+		"lookalikes_more":   "i %d więcej",
+		"lookalike_warning": "%d jasnych satelitów przecina tor ISS podczas przelotu o %s, zobacz p",
+
+		"hook_fired": "Reguła spełniona",
+
+		"pass_alert": "Widoczny przelot za %d min: wschód %s na %s, do %.0f°",
+
+		"daemon_lost": "utracono połączenie z demonem (%v), pozycje pobierane bezpośrednio",
+
+		"startup_title":    "Uruchamianie",
+		"startup_config":   "Konfiguracja",
+		"startup_map":      "Mapa świata",
+		"startup_tle":      "Elementy orbitalne",
+		"startup_position": "Pozycja ISS",
+		"startup_retrying": "(ponawianie)",
+
+		"last_30_minutes": "Ostatnie 30 minut",
+
+		"altitude_view":      "Wysokość, ostatnie 3 godziny (a, aby wrócić)",
+		"altitude_predicted": "obliczona z elementów orbitalnych",
+		"altitude_measured":  "podana przez API pozycji",
+		"altitude_range":     "Zakres",
+		"now":                "teraz",
+
+		"latitude_view":  "Szerokość śladu naziemnego, poprzednia i następna orbita (g, aby wrócić)",
+		"heading_north":  "na północ",
+		"heading_south":  "na południe",
+		"orbital_period": "Okres orbitalny",
+
+		"orbit_raised":  "Orbita podniesiona o ~%s dnia %s",
+		"orbit_lowered": "Orbita obniżona o ~%s dnia %s",
+
+		"space_weather": "Pogoda kosmiczna",
+		"kp_quiet":      "spokojnie",
+		"kp_active":     "aktywnie, zorze na dużych szerokościach",
+		"storm_g1":      "burza G1, zorze na średnich szerokościach",
+		"storm_g2":      "burza G2, zorze na średnich szerokościach",
+		"storm_g3":      "burza G3, zorze daleko od biegunów",
+		"storm_g4":      "burza G4, zorze daleko od biegunów",
+		"storm_g5":      "burza G5, zorze daleko od biegunów",
+		"flux_low":      "niski, słaby opór",
+		"flux_moderate": "umiarkowany opór",
+		"flux_high":     "wysoki, szybsze obniżanie orbity",
+
+		"nadir_view":       "Teraz pod stacją (n, aby wrócić)",
+		"nadir_local_time": "Lokalny czas słoneczny",
+		"nadir_day":        "dzień",
+		"nadir_twilight":   "zmierzch",
+		"nadir_night":      "noc",
+		"nadir_city":       "Najbliższe miasto",
+		"nadir_city_from":  "%s %s od %s",
+		"nadir_station":    "Stacja",
+		"nadir_sunlit":     "w słońcu",
+		"nadir_sunrise":    "Następny wschód słońca",
+		"nadir_sunset":     "Następny zachód słońca",
+		"nadir_per_day":    "Wschody słońca na dobę",
+
+		"speak_n":        "na północy",
+		"speak_ne":       "na północnym wschodzie",
+		"speak_e":        "na wschodzie",
+		"speak_se":       "na południowym wschodzie",
+		"speak_s":        "na południu",
+		"speak_sw":       "na południowym zachodzie",
+		"speak_w":        "na zachodzie",
+		"speak_nw":       "na północnym zachodzie",
+		"speak_soon":     "ISS wschodzi %s za %d min",
+		"speak_soon_one": "ISS wschodzi %s za minutę",
+		"speak_rise":     "ISS wschodzi teraz %s, do %.0f stopni",
+		"speak_peak":     "ISS najwyżej, %.0f stopni %s",
+
+		"journal_prompt":  "Zapisz obserwację, ocena 1-5 i notatki",
+		"journal_no_pass": "Brak przelotu nad twoją lokalizacją w ostatnich 6 godzinach",
+		"journal_saved":   "Obserwacja zapisana (%d w dzienniku)",
+
+		"offshore": "%s (u wybrzeży: %s)",
+
+		"iss_near":         "ISS w pobliżu",
+		"setting_location": "Położenie",
+
+		"setting_flag": "Flaga kraju",
+
+		"new_country":    "Nowy kraj: %s (%d/%d)",
+		"collection":     "Zebrane kraje: %d/%d (C, aby wrócić)",
+		"collection_off": "Kolekcja krajów jest wyłączona (country_collection w konfiguracji; C, aby wrócić)",
+		"first_seen":     "Pierwszy raz",
+
+		"provider_switched": "Pozycje teraz z %s",
+
+		"dialog_yes":               "Tak",
+		"dialog_no":                "Nie",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Anuluj",
+		"dialog_confirm_help":      "y/n · ←/→ lub tab przejdź · enter wybierz · esc anuluj",
+		"confirm_delete_bookmark":  "Usunąć zakładkę %q?",
+		"confirm_replace_bookmark": "Zakładka %q istnieje. Przenieść ją do obecnego położenia obserwatora?",
+		"observer_dialog":          "Obserwator: szerokość, długość, wysokość w metrach (opcjonalnie)",
+
+		"away_summary":   "Podczas twojej nieobecności (%s)",
+		"away_orbits":    "%.1f okrążeń",
+		"away_countries": "%d krajów w przelocie",
+		"away_passes":    "%d przelotów nad tobą, %d widocznych",
+
+		"setting_charset": "Zestaw znaków",
+	},
+	"pt": {
+		"iss_over":        "ISS sobre",
+		"latitude":        "Latitude",
+		"longitude":       "Longitude",
+		"coords":          "Coordenadas",
+		"resolving":       "A determinar...",
+		"detail":          "Detalhe",
+		"ocean":           "Oceano",
+		"map_unavailable": "Mapa indisponível.",
+		"diagnostics":     "Diagnóstico (d para voltar)",
+		"this_session":    "Esta sessão",
+		"all_sessions":    "Todas as sessões",
+		"last_error":      "Último erro",
+		"no_requests":     "ainda sem pedidos",
+
+		"tour":     "Visita",
+		"now_over": "Agora sobre",
+
+		"altitude": "Altitude",
+		"velocity": "Velocidade",
+
+		"saved": "Guardado:",
+
+		"recording": "REC (R para parar):",
+
+		"events":    "Eventos",
+		"entered":   "Entrou em",
+		"left":      "Saiu de",
+		"no_events": "nenhum evento ainda",
+
+		"countries":  "Tempo sobre países (c para voltar, o para ordenar)",
+		"location":   "Local",
+		"time":       "Tempo",
+		"no_samples": "nenhuma amostra ainda",
+		"by_time":    "por tempo",
+		"by_name":    "por nome",
+
+		"sky_view":    "Vista do céu (p para voltar)",
+		"no_observer": "Defina \"observer\" no arquivo de configuração para usar a vista do céu.",
+		"loading_tle": "Carregando elementos orbitais...",
+		"azimuth":     "Azimute",
+		"elevation":   "Elevação",
+		"range":       "Distância",
+		"next_pass":   "Próxima passagem",
+		"this_pass":   "Passagem atual",
+
+		"radio": "Rádio",
+
+		"tle_stale": "Elementos orbitais desatualizados",
+
+		"position_off": "%s está a %s da posição calculada",
+		"position_ok":  "%s volta a coincidir com a posição calculada",
+
+		"vehicles":    "Naves acopladas (v para voltar)",
+		"no_vehicles": "nenhuma nave acoplada",
+		"vehicle":     "Nave",
+		"port":        "Porta",
+		"docked":      "Acoplada",
+		"updated":     "Atualizado",
+
+		"video_opened":  "Vídeo ao vivo da ISS aberto no navegador",
+		"video_playing": "Reproduzindo o vídeo ao vivo da ISS (l para parar)",
+
+		"magnitude": "Magnitude",
+		"eclipsed":  "na sombra da Terra",
+
+		"cloud_cover": "Nebulosidade",
+
+		"orbit_today": "Órbita %d (n.º %d hoje)",
+		"uptime":      "Ativo há",
+
+		"paused": "PAUSADO",
+
+		"refreshing":   "Atualizando…",
+		"refresh_wait": "Próxima atualização possível em %s",
+
+		"settings":            "Definições (, para voltar)",
+		"settings_help":       "↑/↓ escolher · Enter editar · ←/→ mudar · Esc voltar",
+		"setting_refresh":     "Intervalo de atualização (s)",
+		"setting_theme":       "Tema",
+		"setting_units":       "Unidades",
+		"setting_lat":         "Latitude do observador",
+		"setting_lon":         "Longitude do observador",
+		"setting_alt":         "Altitude do observador (m)",
+		"setting_sun_moon":    "Sol e lua",
+		"setting_cloud_layer": "Camada de nuvens",
+		"on":                  "ligado",
+		"off":                 "desligado",
+
+		"log_view": "Registo (L para voltar)",
+		"no_log":   "nada registado ainda",
+
+		"demo":          "DEMO",
+		"demo_location": "Órbita de demonstração",
+
+		"demo_help": "</> velocidade · [/] ±10 min · {/} ±1 órbita",
+
+		"breaker_open":      "%s continua a falhar, em pausa por %s",
+		"breaker_half_open": "%s: a tentar de novo",
+
+		"heading":      "Rumo",
+		"ground_speed": "Vel. solo",
+
+		"eta_prompt":      "Passagem sobre o local",
+		"prompt_help":     "Enter confirmar · Esc cancelar",
+		"place_not_found": "nenhum local encontrado para %q",
+		"eta_in":          "em %s (%s), %s",
+		"eta_now":         "agora, %s",
+
+		"search_prompt": "Ir para o local",
+		"choose_place":  "Qual local?",
+		"choose_help":   "↑/↓ escolher · Enter ou 1-5 confirmar · Esc cancelar",
+
+		"setting_bookmark":     "Favorito",
+		"setting_bookmark_add": "Guardar observador como favorito",
+		"bookmark_help":        "←/→ escolher · Enter renomear · x apagar",
+		"bookmark_add_help":    "escreve um nome; um nome existente passa para o observador",
+		"no_bookmarks":         "Ainda sem favoritos, adiciona um nas definições (,)",
+		"observer_at":          "Observador: %s",
+
+		"profiles_view": "Próxima passagem por observador",
+		"no_profiles":   "Sem observador nem favoritos definidos.",
+		"profile":       "Perfil",
+		"no_pass":       "nenhuma",
+		"profiles_help": "Primeira passagem acima de %d° nas próximas %d h · P voltar",
+
+		"people_in_space": "Pessoas no espaço",
+		"tiangong":        "Tiangong",
+		"tiangong_alt":    "Tiangong alt.",
+
+		"lookalikes":        "Satélites brilhantes perto da ISS nesta passagem:",
+		"lookalikes_more":   "e mais %d",
+		"lookalike_warning": "%d satélites brilhantes cruzam perto da ISS na passagem das %s, ver p",
+
+		"hook_fired": "Regra cumprida",
+
+		"pass_alert": "Passagem visível em %d min: nasce às %s a %s, até %.0f°",
+
+		"daemon_lost": "ligação ao daemon perdida (%v), a obter posições diretamente",
+
+		"startup_title":    "A iniciar",
+		"startup_config":   "Configuração",
+		"startup_map":      "Mapa-múndi",
+		"startup_tle":      "Elementos orbitais",
+		"startup_position": "Posição da ISS",
+		"startup_retrying": "(a tentar de novo)",
+
+		"last_30_minutes": "Últimos 30 minutos",
+
+		"altitude_view":      "Altitude, últimas 3 horas (a para voltar)",
+		"altitude_predicted": "calculada a partir dos elementos orbitais",
+		"altitude_measured":  "indicada pela API de posição",
+		"altitude_range":     "Intervalo",
+		"now":                "agora",
+
+		"latitude_view":  "Latitude do traçado, órbita anterior e seguinte (g para voltar)",
+		"heading_north":  "para norte",
+		"heading_south":  "para sul",
+		"orbital_period": "Período orbital",
+
+		"orbit_raised":  "Órbita elevada ~%s em %s",
+		"orbit_lowered": "Órbita baixada ~%s em %s",
+
+		"space_weather": "Meteorologia espacial",
+		"kp_quiet":      "calmo",
+		"kp_active":     "ativo, auroras em latitudes altas",
+		"storm_g1":      "tempestade G1, auroras em latitudes médias",
+		"storm_g2":      "tempestade G2, auroras em latitudes médias",
+		"storm_g3":      "tempestade G3, auroras longe dos polos",
+		"storm_g4":      "tempestade G4, auroras longe dos polos",
+		"storm_g5":      "tempestade G5, auroras longe dos polos",
+		"flux_low":      "baixo, pouco arrasto",
+		"flux_moderate": "arrasto moderado",
+		"flux_high":     "alto, decaimento orbital mais rápido",
+
+		"nadir_view":       "Abaixo da estação agora (n para voltar)",
+		"nadir_local_time": "Hora solar local",
+		"nadir_day":        "dia",
+		"nadir_twilight":   "crepúsculo",
+		"nadir_night":      "noite",
+		"nadir_city":       "Cidade mais próxima",
+		"nadir_city_from":  "%s %s de %s",
+		"nadir_station":    "Estação",
+		"nadir_sunlit":     "ao sol",
+		"nadir_sunrise":    "Próximo nascer do sol",
+		"nadir_sunset":     "Próximo pôr do sol",
+		"nadir_per_day":    "Nasceres do sol por dia",
+
+		"speak_n":        "a norte",
+		"speak_ne":       "a nordeste",
+		"speak_e":        "a leste",
+		"speak_se":       "a sudeste",
+		"speak_s":        "a sul",
+		"speak_sw":       "a sudoeste",
+		"speak_w":        "a oeste",
+		"speak_nw":       "a noroeste",
+		"speak_soon":     "A ISS nasce %s em %d minutos",
+		"speak_soon_one": "A ISS nasce %s em um minuto",
+		"speak_rise":     "A ISS nasce agora %s, até %.0f graus",
+		"speak_peak":     "A ISS no ponto mais alto, %.0f graus %s",
+
+		"journal_prompt":  "Registar avistamento, nota 1-5 e notas",
+		"journal_no_pass": "Nenhuma passagem sobre a tua localização nas últimas 6 horas para registar",
+		"journal_saved":   "Avistamento guardado (%d no diário)",
+
+		"offshore": "%s (ao largo de: %s)",
+
+		"iss_near":         "ISS perto de",
+		"setting_location": "Localização",
+
+		"setting_flag": "Bandeira do país",
+
+		"new_country":    "Novo país: %s (%d/%d)",
+		"collection":     "Países colecionados: %d/%d (C para voltar)",
+		"collection_off": "A coleção de países está desativada (country_collection na configuração; C para voltar)",
+		"first_seen":     "Visto pela primeira vez",
+
+		"provider_switched": "Posições agora de %s",
+
+		"dialog_yes":               "Sim",
+		"dialog_no":                "Não",
+		"dialog_ok":                "OK",
+		"dialog_cancel":            "Cancelar",
+		"dialog_confirm_help":      "y/n · ←/→ ou tab mover · enter escolher · esc cancelar",
+		"confirm_delete_bookmark":  "Apagar o favorito %q?",
+		"confirm_replace_bookmark": "O favorito %q existe. Movê-lo para a localização atual do observador?",
+		"observer_dialog":          "Observador: latitude, longitude, altitude em metros (opcional)",
+
+		"away_summary":   "Enquanto estava fora (%s)",
+		"away_orbits":    "%.1f órbitas",
+		"away_countries": "%d países sobrevoados",
+		"away_passes":    "%d passagens sobre si, %d visíveis",
+
+		"setting_charset": "Conjunto de caracteres",
+	},
+}
+
+func resolveLanguage(flagValue, configValue string) language {
+	for _, candidate := range []string{flagValue, configValue, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if lang, ok := parseLanguage(candidate); ok {
+			return lang
+		}
+	}
+
+	return language{tag: defaultLanguage, base: defaultLanguage}
+}
+
+func parseLanguage(value string) (language, bool) {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.ReplaceAll(value, "_", "-")
+	if value == "" || value == "C" || value == "POSIX" {
+		return language{}, false
+	}
+
+	base := strings.ToLower(strings.SplitN(value, "-", 2)[0])
+	if len(base) < 2 || len(base) > 3 {
+		return language{}, false
+	}
+
+	return language{tag: value, base: base}, true
+}
+
+func (l language) text(key string) string {
+	if catalog, ok := catalogs[l.base]; ok {
+		if value, ok := catalog[key]; ok {
+			return value
+		}
+	}
+
+	return catalogs[defaultLanguage][key]
+}
+
+func (l language) geocodeKey() string {
+	if l.names == geo.Local {
+		return l.tag + "/local"
+	}
+	return l.tag
+}
+
+func (l language) acceptLanguage() string {
+	if l.tag == "" || l.base == defaultLanguage {
+		return defaultLanguage
+	}
+	if l.tag == l.base {
+		return l.tag + "," + defaultLanguage
+	}
+
+	return l.tag + "," + l.base + "," + defaultLanguage
+}
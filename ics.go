@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/Kivayan/iss/pkg/weather"
+)
+
+const (
+	icsTimeLayout    = "20060102T150405Z"
+	icsLineLimit     = 75
+	defaultPassAlarm = 10 * time.Minute
+)
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func writePassesICS(w io.Writer, passes []track.Pass, observer track.Observer, clouds *weather.Forecast, times timeDisplay, alarm time.Duration, now time.Time) error {
+	var buf bytes.Buffer
+	line := func(format string, args ...any) {
+		writeICSLine(&buf, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Kivayan//iss//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:ISS passes")
+
+	for _, pass := range passes {
+		summary := fmt.Sprintf("ISS pass, max %.0f° %s", pass.MaxElevation, compassPoint(pass.MaxAzimuth))
+		description := fmt.Sprintf("Rise %s %s (%.0f°)\nMax %s %.0f° %s\nSet %s %s (%.0f°)\nDuration %s",
+			times.format(pass.Start, layoutClock), compassPoint(pass.StartAzimuth), pass.StartAzimuth,
+			times.format(pass.Max, layoutClock), pass.MaxElevation, compassPoint(pass.MaxAzimuth),
+			times.format(pass.End, layoutClock), compassPoint(pass.EndAzimuth), pass.EndAzimuth,
+			pass.Duration().Round(time.Second))
+		if pass.Visible {
+			description += fmt.Sprintf("\nBrightest magnitude %.1f", pass.Magnitude)
+		}
+		if cover := passCloudCover(clouds, pass); cover != nil {
+			description += fmt.Sprintf("\nCloud cover %.0f%%", *cover)
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:iss-%s-%.3f-%.3f@iss", pass.Start.UTC().Format(icsTimeLayout), observer.Lat, observer.Lon)
+		line("DTSTAMP:%s", now.UTC().Format(icsTimeLayout))
+		line("DTSTART:%s", pass.Start.UTC().Format(icsTimeLayout))
+		line("DTEND:%s", pass.End.UTC().Format(icsTimeLayout))
+		line("SUMMARY:%s", icsEscaper.Replace(summary))
+		line("DESCRIPTION:%s", icsEscaper.Replace(description))
+		line("GEO:%.6f;%.6f", observer.Lat, observer.Lon)
+		line("TRANSP:TRANSPARENT")
+		if alarm > 0 {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:%s", icsEscaper.Replace(summary))
+			line("TRIGGER:-PT%dM", int(alarm.Round(time.Minute)/time.Minute))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeICSLine(buf *bytes.Buffer, content string) {
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		buf.WriteString(content[:cut])
+		buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = icsLineLimit - 1
+	}
+	buf.WriteString(content)
+	buf.WriteString("\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
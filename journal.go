@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+const (
+	journalFileName = "journal.json"
+	journalLookback = 6 * time.Hour
+	maxRating       = 5
+)
+
+type journalEntry struct {
+	Logged       time.Time `json:"logged"`
+	PassStart    time.Time `json:"pass_start"`
+	PassMax      time.Time `json:"pass_max"`
+	PassEnd      time.Time `json:"pass_end"`
+	StartAzimuth float64   `json:"start_azimuth"`
+	MaxElevation float64   `json:"max_elevation"`
+	EndAzimuth   float64   `json:"end_azimuth"`
+	Magnitude    *float64  `json:"magnitude,omitempty"`
+	Lat          float64   `json:"lat"`
+	Lon          float64   `json:"lon"`
+	Rating       int       `json:"rating,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+}
+
+type journalSavedMsg struct {
+	count int
+	err   error
+}
+
+type journalOptions struct {
+	csv      bool
+	markdown bool
+	json     bool
+	output   string
+}
+
+func (m model) recentPass(now time.Time) (track.Pass, bool) {
+	if m.sat == nil || m.observer == nil {
+		return track.Pass{}, false
+	}
+	passes, err := track.FindPasses(m.sat, *m.observer, now.Add(-journalLookback), now, 0)
+	if err != nil || len(passes) == 0 {
+		return track.Pass{}, false
+	}
+	return passes[len(passes)-1], true
+}
+
+func (m model) startJournal() model {
+	m.view = viewMap
+	pass, ok := m.recentPass(clockNow())
+	if !ok {
+		m.notice = m.lang.text("journal_no_pass")
+		return m
+	}
+	m.journalPass = &pass
+	m.prompt = newTextPrompt("journal_prompt")
+	m.prompt.detail = fmt.Sprintf("%s %s, %.0f°", m.times.format(pass.Start, layoutShort), compassPoint(pass.StartAzimuth), pass.MaxElevation)
+	return m
+}
+
+func (m model) submitJournal() (model, tea.Cmd) {
+	pass := m.journalPass
+	m.journalPass = nil
+	if pass == nil {
+		return m, nil
+	}
+	rating, notes := parseSighting(m.prompt.input)
+	entry := journalEntry{
+		Logged:       clockNow(),
+		PassStart:    pass.Start,
+		PassMax:      pass.Max,
+		PassEnd:      pass.End,
+		StartAzimuth: pass.StartAzimuth,
+		MaxElevation: pass.MaxElevation,
+		EndAzimuth:   pass.EndAzimuth,
+		Magnitude:    passMagnitude(*pass),
+		Lat:          m.observer.Lat,
+		Lon:          m.observer.Lon,
+		Rating:       rating,
+		Notes:        notes,
+	}
+	return m, func() tea.Msg {
+		count, err := appendJournal(entry)
+		return journalSavedMsg{count: count, err: err}
+	}
+}
+
+func parseSighting(input string) (int, string) {
+	input = strings.TrimSpace(input)
+	first, rest, _ := strings.Cut(input, " ")
+	rating, err := strconv.Atoi(first)
+	if err != nil || rating < 1 || rating > maxRating {
+		return 0, input
+	}
+	return rating, strings.TrimSpace(rest)
+}
+
+func journalPath() (string, error) {
+	return dataPath(journalFileName)
+}
+
+func loadJournal(path string) ([]journalEntry, error) {
+	data, err := readFileIfExists(path)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var entries []journalEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func appendJournal(entry journalEntry) (int, error) {
+	path, err := journalPath()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = withFileLock(path, func() error {
+		entries, err := loadJournal(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		count = len(entries)
+		return writeFileAtomic(path, data, 0o644)
+	})
+	return count, err
+}
+
+func newJournalCmd(opts *globalOptions) *cobra.Command {
+	var journal journalOptions
+	cmd := &cobra.Command{
+		Use:   "journal",
+		Short: "List or export the sightings logged with j in the TUI",
+		Long:  "List the sightings logged with j in the TUI, each with the pass it belongs to, the\nrating and the notes. --csv and --markdown export them for a spreadsheet or a notebook.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runJournal(*opts, cmd.OutOrStdout(), journal)
+		},
+	}
+	cmd.Flags().BoolVar(&journal.csv, "csv", false, "print CSV")
+	cmd.Flags().BoolVar(&journal.markdown, "markdown", false, "print a Markdown table")
+	cmd.Flags().BoolVar(&journal.json, "json", false, "print JSON")
+	cmd.Flags().StringVarP(&journal.output, "output", "o", "", "write to this file instead of standard output")
+	cmd.MarkFlagsMutuallyExclusive("csv", "markdown", "json")
+
+	return cmd
+}
+
+func runJournal(opts globalOptions, w io.Writer, journal journalOptions) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	times, _, err := timeDisplayFromConfig(cfg, opts.utc)
+	if err != nil {
+		return err
+	}
+	path, err := journalPath()
+	if err != nil {
+		return err
+	}
+	entries, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && !journal.json {
+		return errors.New("no sightings logged yet: press j in the TUI during or after a pass")
+	}
+
+	var buf bytes.Buffer
+	switch {
+	case journal.json:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []journalEntry{}
+		}
+		err = encoder.Encode(entries)
+	case journal.csv:
+		err = writeJournalCSV(&buf, entries)
+	case journal.markdown:
+		writeJournalMarkdown(&buf, entries, times)
+	default:
+		for _, line := range formatTable(journalRows(entries, times), nil) {
+			fmt.Fprintln(&buf, line)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if journal.output != "" {
+		if err := writeFileAtomic(journal.output, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Wrote %d sightings to %s\n", len(entries), journal.output)
+		return nil
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func journalRows(entries []journalEntry, times timeDisplay) [][]string {
+	date := "Date"
+	if len(entries) > 0 {
+		date += " (" + times.zone(entries[0].PassStart) + ")"
+	}
+	rows := [][]string{{date, "Rise", "Az", "Max", "Elev", "Set", "Az", "Mag", "Rating", "Notes"}}
+	for _, e := range entries {
+		rows = append(rows, []string{
+			times.format(e.PassStart, layoutDay),
+			times.format(e.PassStart, layoutClock),
+			compassPoint(e.StartAzimuth),
+			times.format(e.PassMax, layoutClock),
+			fmt.Sprintf("%.0f°", e.MaxElevation),
+			times.format(e.PassEnd, layoutClock),
+			compassPoint(e.EndAzimuth),
+			formatMagnitude(e.Magnitude),
+			formatRating(e.Rating),
+			e.Notes,
+		})
+	}
+	return rows
+}
+
+func formatRating(rating int) string {
+	if rating == 0 {
+		return "-"
+	}
+	return strings.Repeat("*", rating) + strings.Repeat(".", maxRating-rating)
+}
+
+func writeJournalCSV(w io.Writer, entries []journalEntry) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"pass_start", "pass_max", "pass_end", "start_azimuth", "max_elevation", "end_azimuth", "magnitude", "lat", "lon", "rating", "notes", "logged"})
+	for _, e := range entries {
+		magnitude, rating := "", ""
+		if e.Magnitude != nil {
+			magnitude = strconv.FormatFloat(*e.Magnitude, 'f', 1, 64)
+		}
+		if e.Rating > 0 {
+			rating = strconv.Itoa(e.Rating)
+		}
+		out.Write([]string{
+			e.PassStart.UTC().Format(time.RFC3339),
+			e.PassMax.UTC().Format(time.RFC3339),
+			e.PassEnd.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(e.StartAzimuth, 'f', 0, 64),
+			strconv.FormatFloat(e.MaxElevation, 'f', 0, 64),
+			strconv.FormatFloat(e.EndAzimuth, 'f', 0, 64),
+			magnitude,
+			strconv.FormatFloat(e.Lat, 'f', 4, 64),
+			strconv.FormatFloat(e.Lon, 'f', 4, 64),
+			rating,
+			e.Notes,
+			e.Logged.UTC().Format(time.RFC3339),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func writeJournalMarkdown(w io.Writer, entries []journalEntry, times timeDisplay) {
+	rows := journalRows(entries, times)
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = escape.Replace(cell)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		if i == 0 {
+			fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(row)))
+		}
+	}
+}
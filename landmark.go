@@ -0,0 +1,59 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+type locationDisplay int
+
+const (
+	displayCountry locationDisplay = iota
+	displayLandmark
+)
+
+var locationDisplayNames = []string{"country", "landmark"}
+
+//go:embed data/landmarks.json
+var landmarksJSON []byte
+
+func parseLocationDisplay(value string) (locationDisplay, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return displayCountry, nil
+	}
+
+	for i, name := range locationDisplayNames {
+		if name == value {
+			return locationDisplay(i), nil
+		}
+	}
+
+	return displayCountry, fmt.Errorf("unknown location_display %q (want country or landmark)", value)
+}
+
+func (d locationDisplay) String() string {
+	return locationDisplayNames[d]
+}
+
+func loadLandmarks(cities []city) ([]city, error) {
+	var landmarks []city
+	if err := json.Unmarshal(landmarksJSON, &landmarks); err != nil {
+		return nil, fmt.Errorf("parse landmarks: %w", err)
+	}
+	return append(append([]city{}, cities...), landmarks...), nil
+}
+
+func (m model) locationLine() string {
+	if m.locDisplay == displayLandmark && m.hasCoords {
+		if c, km, ok := nearestCity(m.landmarks, m.lat, m.lon); ok {
+			bearing := compassPoint(geo.InitialBearing(c.Lat, c.Lon, m.lat, m.lon))
+			return m.icon("location") + m.lang.text("iss_near") + ": " + fmt.Sprintf(m.lang.text("nadir_city_from"), m.units.formatDistance(km), bearing, c.Name)
+		}
+	}
+	return m.icon("location") + m.lang.text("iss_over") + ": " + m.flaggedCountry()
+}
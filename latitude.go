@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+const latitudeRows = 13
+
+func (m model) latitudeSeries(now time.Time, columns int) ([]float64, int) {
+	period := m.sat.Period()
+	step := 2 * period / time.Duration(columns-1)
+	start := now.Add(-period)
+	values := make([]float64, columns)
+	for i := range values {
+		values[i] = math.NaN()
+		if pos, err := m.sat.PositionAt(start.Add(time.Duration(i) * step)); err == nil {
+			values[i] = pos.Lat
+		}
+	}
+	return values, (columns - 1) / 2
+}
+
+func (m model) latitudeView() string {
+	lines := []string{m.lang.text("latitude_view"), ""}
+
+	if m.sat == nil {
+		lines = append(lines, m.lang.text("loading_tle"))
+		return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+	}
+
+	now := clockNow()
+	columns := min(max(m.width-24, 41), 121) | 1
+	values, cursor := m.latitudeSeries(now, columns)
+	chart, err := render.Chart([]render.Series{{Values: values, Glyph: '*'}}, latitudeRows, "%+.0f°", cursor)
+	if err != nil {
+		lines = append(lines, err.Error())
+		return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+	}
+	lines = append(lines, strings.Split(chart, "\n")...)
+	minutes := m.sat.Period().Minutes()
+	lines = append(lines, chartAxis(lines[len(lines)-1], columns, fmt.Sprintf("-%.0f min", minutes), m.lang.text("now"), fmt.Sprintf("+%.0f min", minutes)), "")
+
+	lat := values[cursor]
+	direction := m.lang.text("heading_north")
+	if values[cursor+1] < lat {
+		direction = m.lang.text("heading_south")
+	}
+	fields := [][2]string{
+		{m.lang.text("latitude"), formatLatitude(lat) + ", " + direction},
+		{m.lang.text("orbital_period"), fmt.Sprintf("%.1f min", minutes)},
+	}
+	lines = append(lines, alignFields(fields)...)
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	logFileName    = "iss.log"
+	logMaxBytes    = 5 << 20
+	recentLogLines = 200
+)
+
+type logTail struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+var recentLog = &logTail{}
+
+type logFile struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var debugLog = &logFile{}
+
+func (t *logTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		t.lines = append(t.lines, line)
+	}
+	if extra := len(t.lines) - recentLogLines; extra > 0 {
+		t.lines = append(t.lines[:0:0], t.lines[extra:]...)
+	}
+	return len(p), nil
+}
+
+func (t *logTail) last(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	start := max(len(t.lines)-n, 0)
+	return append([]string(nil), t.lines[start:]...)
+}
+
+func logPath() (string, error) {
+	return dataPath(logFileName)
+}
+
+func openLogFile(rotate bool) (*os.File, error) {
+	path, err := logPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && (rotate || info.Size() > logMaxBytes) {
+		os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	f.Chmod(0o600)
+	return f, nil
+}
+
+func (l *logFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return len(p), nil
+	}
+	return l.file.Write(p)
+}
+
+func (l *logFile) rotate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	l.file.Close()
+	file, err := openLogFile(true)
+	l.file = file
+	return err
+}
+
+func rotateLogCmd() tea.Cmd {
+	return func() tea.Msg {
+		if err := debugLog.rotate(); err != nil {
+			return errMsg{err: fmt.Errorf("log rotation: %w", err)}
+		}
+		return nil
+	}
+}
+
+func setupLogging(debug bool) error {
+	level := slog.LevelInfo
+	var out io.Writer = recentLog
+	if debug {
+		level = slog.LevelDebug
+		file, err := openLogFile(false)
+		if err != nil {
+			return err
+		}
+		debugLog.file = file
+		out = io.MultiWriter(debugLog, recentLog)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: level})))
+	return nil
+}
+
+func (m model) logView() string {
+	lines := []string{m.lang.text("log_view"), ""}
+	tail := recentLog.last(max(m.height-8, 10))
+	if len(tail) == 0 {
+		tail = []string{m.lang.text("no_log")}
+	}
+	width := max(m.width-6, 20)
+	for _, line := range tail {
+		lines = append(lines, ansi.Truncate(line, width, "…"))
+	}
+	if m.debug {
+		if path, err := logPath(); err == nil {
+			lines = append(lines, "", path)
+		}
+	}
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
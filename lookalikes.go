@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultLookalikeGroup = "visual"
+	defaultLookalikeDeg   = 3
+	maxLookalikeDeg       = 30
+	lookalikeStep         = 10 * time.Second
+	maxLookalikeLines     = 4
+)
+
+var tleGroupName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+type lookalikeConfig struct {
+	Group     string  `json:"group"`
+	WithinDeg float64 `json:"within_deg"`
+}
+
+type lookalikeSettings struct {
+	group     string
+	withinDeg float64
+}
+
+type lookalikesMsg struct {
+	pass  time.Time
+	found []track.Lookalike
+	err   error
+}
+
+func lookalikesFromConfig(cfg config) (*lookalikeSettings, error) {
+	lc := cfg.FlyoverWarnings
+	if lc == nil {
+		return nil, nil
+	}
+	settings := &lookalikeSettings{group: defaultLookalikeGroup, withinDeg: defaultLookalikeDeg}
+	if lc.Group != "" {
+		if !tleGroupName.MatchString(lc.Group) {
+			return nil, fmt.Errorf("flyover_warnings: group %q is not a Celestrak group name", lc.Group)
+		}
+		settings.group = lc.Group
+	}
+	if lc.WithinDeg != 0 {
+		if lc.WithinDeg < 0 || lc.WithinDeg > maxLookalikeDeg {
+			return nil, fmt.Errorf("flyover_warnings: within_deg must be between 0 and %d", maxLookalikeDeg)
+		}
+		settings.withinDeg = lc.WithinDeg
+	}
+	return settings, nil
+}
+
+func lookalikesCmd(client *http.Client, sat *track.Satellite, observer track.Observer, pass track.Pass, settings lookalikeSettings, refresh time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		sets, err := loadTLEGroup(client, settings.group, refresh)
+		if len(sets) == 0 {
+			return lookalikesMsg{pass: pass.Start, err: fmt.Errorf("flyover warnings: %w", err)}
+		}
+
+		others := make([]*track.Satellite, 0, len(sets))
+		for _, set := range sets {
+			if other, err := track.NewSatellite(set); err == nil {
+				others = append(others, other)
+			}
+		}
+		found := track.FindLookalikes(sat, others, observer, pass.Start, pass.End, lookalikeStep, settings.withinDeg)
+		return lookalikesMsg{pass: pass.Start, found: found, err: err}
+	}
+}
+
+func (m model) checkLookalikes(now time.Time) (model, tea.Cmd) {
+	if m.flyover == nil || m.observer == nil || m.sat == nil || m.lookalikeBusy {
+		return m, nil
+	}
+	m = m.refreshSkyPass(now)
+	if m.skyPass == nil || m.skyPass.Start.Equal(m.lookalikePass) {
+		return m, nil
+	}
+	m.lookalikeBusy = true
+	m.lookalikePass = m.skyPass.Start
+	m.lookalikes = nil
+	return m, lookalikesCmd(m.client, m.sat, *m.observer, *m.skyPass, *m.flyover, m.tleRefresh)
+}
+
+func (m model) applyLookalikes(msg lookalikesMsg) model {
+	m.lookalikeBusy = false
+	if msg.err != nil {
+		m.lastErr = msg.err.Error()
+	}
+	if msg.pass.Equal(m.lookalikePass) {
+		m.lookalikes = msg.found
+	}
+	return m
+}
+
+func (m model) lookalikeLine(now time.Time) string {
+	if len(m.lookalikes) == 0 || m.skyPass == nil || !m.skyPass.Start.Equal(m.lookalikePass) || !m.skyPass.End.After(now) {
+		return ""
+	}
+	return fmt.Sprintf(m.lang.text("lookalike_warning"), len(m.lookalikes), m.times.format(m.skyPass.Start, layoutShort))
+}
+
+func (m model) lookalikeLines() []string {
+	if len(m.lookalikes) == 0 || m.skyPass == nil || !m.skyPass.Start.Equal(m.lookalikePass) {
+		return nil
+	}
+	lines := []string{m.lang.text("lookalikes")}
+	for i, l := range m.lookalikes {
+		if i == maxLookalikeLines {
+			lines = append(lines, fmt.Sprintf("  "+m.lang.text("lookalikes_more"), len(m.lookalikes)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s  %.1f°, %s %.0f°", m.times.format(l.Time, layoutClock), l.Name, l.SeparationDeg, m.lang.text("elevation"), l.Elevation))
+	}
+	return lines
+}
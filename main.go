@@ -2,16 +2,25 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
-	"net/url"
 	"os"
-	"strconv"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/geofence"
+	"github.com/Kivayan/iss/pkg/quality"
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/speech"
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/Kivayan/iss/pkg/weather"
 	mapascii "github.com/Kivayan/map-ascii"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
@@ -19,9 +28,7 @@ import (
 
 const (
 	telemetryInterval = 5 * time.Second
-	issURL            = "http://api.open-notify.org/iss-now.json"
-	nominatimURL      = "https://nominatim.openstreetmap.org/reverse"
-	userAgent         = "iss-tui/1.2 (+https://github.com/kivayan/iss)"
+	resizeDebounce    = 150 * time.Millisecond
 	defaultMapWidth   = 60
 	minMapWidth       = 30
 	maxMapWidth       = 120
@@ -34,11 +41,36 @@ const (
 
 type telemetryTickMsg time.Time
 
+type resizeSettledMsg uint64
+
+type viewMode int
+
+const (
+	viewMap viewMode = iota
+	viewDiagnostics
+	viewCountries
+	viewSky
+	viewVehicles
+	viewSettings
+	viewLog
+	viewProfiles
+	viewAltitude
+	viewLatitude
+	viewNadir
+	viewCollection
+)
+
 type telemetryMsg struct {
-	country string
-	lat     float64
-	lon     float64
-	err     error
+	country     string
+	countryCode string
+	located     bool
+	pos         track.Position
+	err         error
+}
+
+type locationMsg struct {
+	loc geo.Location
+	err error
 }
 
 type errMsg struct {
@@ -57,51 +89,402 @@ type mapFrameClosedMsg struct {
 
 type model struct {
 	issOver        string
+	countryCode    string
 	lat            float64
 	lon            float64
 	hasCoords      bool
+	altitudeKm     float64
+	velocityKmh    float64
+	hasAltitude    bool
+	units          unitSystem
+	shotFormat     string
+	notice         string
+	recorder       *gifRecorder
+	cast           *castRecorder
+	configPath     string
+	providers      []track.Provider
+	fence          *geofence.Fence
+	events         []logEntry
+	quality        *quality.Monitor
+	vehicles       []track.DockedVehicle
+	vehiclesAt     time.Time
+	vehiclesLoaded bool
+	video          *videoPlayer
+	sunMoon        bool
+	clouds         bool
+	cloudLayerOn   bool
+	cloudsPolling  bool
+	cloudForecast  *weather.Forecast
+	cloudLayer     []render.Glyph
+	times          timeDisplay
+	tzLookup       bool
+	bus            eventBus
+	highlight      highlightState
 	lastErr        string
+	started        time.Time
+	pausedAt       time.Time
+	held           []tea.Msg
+	fetches        fetchCoordinator
+	interval       time.Duration
+	settings       settingsForm
+	debug          bool
+	ctx            context.Context
+	palette        palette
 	width          int
 	height         int
 	client         *http.Client
+	lang           language
+	annotations    []annotation
+	cities         []city
+	landmarks      []city
+	locDisplay     locationDisplay
+	flagStyle      countryFlag
+	flagEmoji      bool
+	charset        charset
+	unicodeTerm    bool
+	cellRedraw     bool
+	tour           tourState
+	stats          *providerStats
+	countries      *countryStats
+	collection     *countryCollection
+	toasts         []toast
+	away           awayState
+	inlineRows     int
+	provider       string
+	countrySort    countrySort
+	history        *historyRecorder
+	observer       *track.Observer
+	sat            *track.Satellite
+	skyPass        *track.Pass
+	tleRefresh     time.Duration
+	tleMaxAge      time.Duration
+	radios         []radioConfig
+	bookmarks      []bookmarkConfig
+	bookmarkCursor int
+	profilePasses  []profilePass
+	tiangongOn     bool
+	tiangong       *track.Satellite
+	spaceWxOn      bool
+	spaceWx        *weather.SpaceWeather
+	crew           []track.CraftCount
+	flyover        *lookalikeSettings
+	lookalikes     []track.Lookalike
+	lookalikePass  time.Time
+	lookalikeBusy  bool
+	panels         []panelSource
+	panelData      []*panelResponse
+	hooks          []hook
+	hooksFired     []bool
+	api            *apiServer
+	passLead       time.Duration
+	speaker        speech.Speaker
+	announcer      announcer
+	passAlerted    time.Time
+	digest         *passDigest
+	jobs           []scheduledJob
+	tleCron        bool
+	daemon         *daemonClient
+	passWeights    passScoreWeights
+	control        *controller
+	historyTotals  *countryStats
+	view           viewMode
+	geocodes       *geocodeCache
+	geocoding      bool
+	located        bool
+	motion         motion
+	trail          []trailPoint
+	altitudes      []altitudeSample
+	startup        startupState
+	prompt         textPrompt
+	journalPass    *track.Pass
+	dialog         dialog
+	eta            *etaPin
+	searchPin      *geo.Place
+	mapView        render.Viewport
 	mapMask        *mapascii.LandMask
 	mapASCII       string
+	rasters        *render.Cache
+	style          render.Style
 	mapFrameCh     chan mapFrameMsg
 	cancelMapAnim  context.CancelFunc
 	currentAnimRun uint64
+	resizeSeq      uint64
+	detail         mapDetail
+	detailAuto     bool
+	memLimit       uint64
+	memUsage       uint64
 }
 
-type issPositionResponse struct {
-	Message     string `json:"message"`
-	ISSPosition struct {
-		Latitude  string `json:"latitude"`
-		Longitude string `json:"longitude"`
-	} `json:"iss_position"`
+func main() {
+	defer crashes.recover()
+	virtualConsole = enableVirtualTerminal()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := newRootCmd().ExecuteContext(ctx)
+	stop()
+	restoreConsole()
+	if err != nil {
+		os.Exit(1)
+	}
 }
 
-type nominatimResponse struct {
-	Error       string `json:"error"`
-	Name        string `json:"name"`
-	DisplayName string `json:"display_name"`
-	Category    string `json:"category"`
-	Type        string `json:"type"`
-	Addresstype string `json:"addresstype"`
-	Address     struct {
-		Country string `json:"country"`
-	} `json:"address"`
+func runTUI(ctx context.Context, opts globalOptions, tui tuiOptions) error {
+	m := newModel(ctx, opts)
+	if saved, ok := loadState(); ok && demo == nil && !tui.fresh {
+		m = m.restore(saved, time.Now())
+	}
+	if tui.attach != "" {
+		path, err := resolveDaemonSocket(tui.attach)
+		if err != nil {
+			return err
+		}
+		client, err := dialDaemon(path)
+		if err != nil {
+			return err
+		}
+		m = m.attachTo(client)
+		defer func() { m.daemon.close() }()
+	}
+	m.tour.enabled = m.tour.enabled || tui.tour
+	if tui.record != "" {
+		m.recorder = newGIFRecorder(tui.record, tui.recordFor, time.Now())
+	}
+
+	m.cast = newCastRecorder(os.Stdout)
+
+	slog.Info("tui started", "lang", m.lang.tag, "providers", len(m.providers), "interval", m.interval)
+	if tui.inline || tui.height != 0 {
+		rows, err := inlineRows(tui.height)
+		if err != nil {
+			return err
+		}
+		m.inlineRows = rows
+	}
+
+	var output io.Writer = m.cast
+	if m.cellRedraw {
+		output = newRedrawWriter(m.cast)
+	}
+	p := tea.NewProgram(guardedModel{m}, append(m.programOptions(), tea.WithOutput(output), tea.WithoutCatchPanics(), tea.WithContext(ctx), tea.WithReportFocus())...)
+	forwardSuspend(ctx, p)
+	crashes.attach(p, opts.configPath)
+	closeCtl := serveCtl(ctx, p, "tui")
+	defer closeCtl()
+	final, err := func() (tea.Model, error) {
+		defer crashes.recover()
+		return p.Run()
+	}()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		err = nil
+	}
+	slog.Info("tui stopped", "err", err)
+	if g, ok := final.(guardedModel); ok && demo == nil {
+		if stateErr := saveState(g.model.snapshot(time.Now())); stateErr != nil {
+			slog.Warn("saving state failed", "err", stateErr)
+		}
+	}
+	m.stats.save()
+	m.countries.save()
+	m.control.close()
+	m.video.stop()
+	if historyErr := m.history.flush(); historyErr != nil && err == nil {
+		err = fmt.Errorf("history: %w", historyErr)
+	}
+	if _, castErr := m.cast.stop(); castErr != nil && err == nil {
+		err = castErr
+	}
+	if err != nil {
+		return fmt.Errorf("application error: %w", err)
+	}
+
+	if m.recorder != nil {
+		if err := m.recorder.finish(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func main() {
-	mask, maskErr := mapascii.LoadEmbeddedDefaultLandMask()
+func newModel(ctx context.Context, opts globalOptions) model {
 	initialErr := ""
-	if maskErr != nil {
+	cfg, cfgErr := loadConfig(opts.configPath)
+	if cfgErr != nil {
+		initialErr = fmt.Sprintf("config error: %v", cfgErr)
+	}
+	cfg = demoConfig(cfg)
+
+	detail, detailAuto, memLimit, detailErr := resolveMapDetail(cfg)
+	if detailErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", detailErr)
+	}
+
+	mask, maskErr := mapascii.LoadEmbeddedDefaultLandMask()
+	if maskErr != nil && initialErr == "" {
 		initialErr = fmt.Sprintf("map mask load error: %v", maskErr)
 	}
+	if mask != nil && detail.maskScale > 1 {
+		mask = downsampleMask(mask, detail.maskScale)
+		releaseMemory()
+	}
 
-	mapASCII := "Map unavailable."
+	lang := resolveLanguage(opts.lang, cfg.Lang)
+	names, namesErr := placeNamesFromConfig(cfg)
+	if namesErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", namesErr)
+	}
+	lang.names = names
+	units, unitsErr := parseUnits(cfg.Units)
+	if unitsErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", unitsErr)
+	}
+	shotFormat, shotErr := normalizeScreenshotFormat(cfg.ScreenshotFormat)
+	if shotErr != nil {
+		if initialErr == "" {
+			initialErr = fmt.Sprintf("config error: %v", shotErr)
+		}
+		shotFormat = defaultScreenshotFormat
+	}
+	providers, providersErr := positionProviders(cfg)
+	if providersErr != nil {
+		if initialErr == "" {
+			initialErr = fmt.Sprintf("config error: %v", providersErr)
+		}
+		providers, _ = track.ResolveProviders(nil)
+	}
+	var fence *geofence.Fence
+	regions, regionsErr := regionsFromConfig(cfg)
+	if regionsErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", regionsErr)
+	}
+	if len(regions) > 0 {
+		fence = geofence.New(regions)
+	}
+	highlight, highlightErr := highlightFromConfig(cfg)
+	if highlightErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", highlightErr)
+	}
+	var monitor *quality.Monitor
+	if cfg.PositionCheck {
+		monitor = quality.NewMonitor(cfg.PositionCheckKm)
+	}
+	times, tzLookup, timesErr := timeDisplayFromConfig(cfg, opts.utc)
+	if timesErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", timesErr)
+	}
+	colors, colorsErr := newPalette(opts.color, capableTerminal())
+	if colorsErr != nil && initialErr == "" {
+		initialErr = colorsErr.Error()
+	}
+	themeChoice, themeErr := parseTheme(cfg.Theme)
+	if themeErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", themeErr)
+	}
+	colors.theme = themeChoice
+	interval, intervalErr := refreshIntervalFromConfig(cfg)
+	if intervalErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", intervalErr)
+	}
+	if demo != nil {
+		interval = time.Second
+	}
+	control, controlErr := newController(cfg)
+	if controlErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", controlErr)
+	}
+	geocodeMinKm, geocodeErr := geocodeMinKmFromConfig(cfg)
+	if geocodeErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", geocodeErr)
+	}
+	geocodes := newGeocodeCache(geocodeMinKm)
+	geocodes.land = mask
+	bookmarks, bookmarksErr := bookmarksFromConfig(cfg)
+	if bookmarksErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", bookmarksErr)
+	}
+	passWeights, passWeightsErr := passScoreWeightsFromConfig(cfg)
+	if passWeightsErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", passWeightsErr)
+	}
+	lookalikes, lookalikesErr := lookalikesFromConfig(cfg)
+	if lookalikesErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", lookalikesErr)
+	}
+	panels, panelsErr := panelsFromConfig(cfg)
+	if panelsErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", panelsErr)
+	}
+	hooks, hooksErr := hooksFromConfig(cfg)
+	if hooksErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", hooksErr)
+	}
+	notifications, notifyErr := notifySettingsFromConfig(cfg)
+	if notifyErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", notifyErr)
+	}
+	spoken, speechErr := speechFromConfig(cfg, lang)
+	if speechErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", speechErr)
+	}
+	digest, digestErr := digestFromConfig(cfg)
+	if digestErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", digestErr)
+	}
+	jobSchedules, schedulesErr := schedulesFromConfig(cfg)
+	if schedulesErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", schedulesErr)
+	}
+	annotations, annotationsErr := loadAnnotations()
+	if annotationsErr != nil && initialErr == "" {
+		initialErr = annotationsErr.Error()
+	}
+	cities, citiesErr := loadCities()
+	if citiesErr != nil && initialErr == "" {
+		initialErr = citiesErr.Error()
+	}
+	landmarks, landmarksErr := loadLandmarks(cities)
+	if landmarksErr != nil && initialErr == "" {
+		initialErr = landmarksErr.Error()
+	}
+	locDisplay, locDisplayErr := parseLocationDisplay(cfg.LocationDisplay)
+	if locDisplayErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", locDisplayErr)
+	}
+	collection, collectionErr := newCountryCollection(cfg.CountryCollection == nil || *cfg.CountryCollection)
+	if collectionErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("collection: %v", collectionErr)
+	}
+	inlineRows, inlineErr := inlineRowsFromConfig(cfg)
+	if inlineErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", inlineErr)
+	}
+	flagStyle, flagStyleErr := parseCountryFlag(cfg.CountryFlag)
+	if flagStyleErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", flagStyleErr)
+	}
+	glyphs, glyphsErr := chosenCharset(opts, cfg)
+	if glyphsErr != nil && initialErr == "" {
+		initialErr = fmt.Sprintf("config error: %v", glyphsErr)
+	}
+
+	configErr := cfgErr
+	if configErr == nil && strings.HasPrefix(initialErr, "config error: ") {
+		configErr = errors.New(strings.TrimPrefix(initialErr, "config error: "))
+	}
+	mapErr := maskErr
+
+	rasters := render.NewCache()
+	style := colors.mapStyle()
+	mapASCII := lang.text("map_unavailable")
 	if mask != nil {
-		rendered, err := renderMap(mask, defaultMapWidth, 0, 0, false)
+		key := render.Key{
+			Mask:        mask,
+			Size:        mapWidthForTerm(0, detail.maxWidth),
+			Supersample: detail.supersample,
+			CharAspect:  mapCharAspect,
+		}
+		rendered, err := renderMap(rasters, key, style, 0, 0, false, nil)
 		if err != nil {
+			mapErr = err
 			if initialErr == "" {
 				initialErr = fmt.Sprintf("map render error: %v", err)
 			}
@@ -110,55 +493,431 @@ func main() {
 		}
 	}
 
+	stats := newProviderStats()
+	client := newHTTPClient(ctx, stats)
+	api := newAPIServer(cfg, client, passWeights)
+	bus := newEventBus(cfg)
+	bus.subscribe(api.subscriber, topicTelemetry)
+	if len(notifications.notifiers) > 0 {
+		bus.subscribe(notifierSubscriber(notifications, newNotifierClient(ctx, stats), mask, observerFromConfig(cfg)), topicRegion, topicPassSoon, topicOrbit)
+	}
+	if spoken.speaker != nil {
+		bus.subscribe(speechSubscriber(spoken.speaker), topicAnnounce, topicRegion, topicOrbit)
+	}
+
 	m := model{
-		issOver:  "Resolving...",
-		mapMask:  mask,
-		mapASCII: mapASCII,
-		lastErr:  initialErr,
-		client: &http.Client{
-			Timeout: 8 * time.Second,
-		},
-	}
-
-	p := tea.NewProgram(m)
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "application error: %v\n", err)
-		os.Exit(1)
+		issOver:      lang.text("resolving"),
+		lang:         lang,
+		units:        units,
+		shotFormat:   shotFormat,
+		providers:    providers,
+		fence:        fence,
+		quality:      monitor,
+		video:        newVideoPlayer(cfg),
+		sunMoon:      cfg.SunMoon,
+		clouds:       cfg.Clouds,
+		cloudLayerOn: cfg.CloudLayer,
+		times:        times,
+		tzLookup:     tzLookup,
+		bus:          bus,
+		api:          api,
+		passLead:     notifications.passLead,
+		speaker:      spoken.speaker,
+		announcer:    announcer{lead: spoken.lead},
+		digest:       digest,
+		tleCron:      jobSchedules.tleRefresh != nil,
+		highlight:    highlight,
+		countries:    newCountryStats(cfg.PersistCountryStats),
+		collection:   collection,
+		history:      newHistoryRecorder(cfg.History),
+		observer:     observerFromConfig(cfg),
+		tleRefresh:   tleRefreshFromConfig(cfg),
+		tleMaxAge:    tleMaxAgeFromConfig(cfg),
+		radios:       cfg.Radio,
+		bookmarks:    bookmarks,
+		passWeights:  passWeights,
+		tiangongOn:   cfg.Tiangong,
+		spaceWxOn:    cfg.SpaceWeather,
+		flyover:      lookalikes,
+		panels:       panels,
+		panelData:    make([]*panelResponse, len(panels)),
+		hooks:        hooks,
+		hooksFired:   make([]bool, len(hooks)),
+		control:      control,
+		configPath:   opts.configPath,
+		annotations:  annotations,
+		cities:       cities,
+		landmarks:    landmarks,
+		locDisplay:   locDisplay,
+		flagStyle:    flagStyle,
+		inlineRows:   inlineRows,
+		flagEmoji:    emojiFlagTerminal(),
+		charset:      glyphs,
+		unicodeTerm:  unicodeConsole(),
+		cellRedraw:   cfg.CellRedraw == nil || *cfg.CellRedraw,
+		startup:      newStartup(configErr, mapErr),
+		tour:         tourState{enabled: cfg.Tour},
+		mapMask:      mask,
+		mapASCII:     mapASCII,
+		rasters:      rasters,
+		style:        style,
+		palette:      colors,
+		lastErr:      initialErr,
+		started:      time.Now(),
+		debug:        opts.debug,
+		interval:     interval,
+		detail:       detail,
+		detailAuto:   detailAuto,
+		memLimit:     memLimit,
+		memUsage:     processMemoryUsage(),
+		geocodes:     geocodes,
+		stats:        stats,
+		ctx:          ctx,
+		client:       client,
+	}
+	m.cloudsPolling = m.cloudsEnabled()
+	m.jobs = m.scheduledJobs(jobSchedules)
+	return m
+}
+
+func newHTTPClient(ctx context.Context, stats *providerStats) *http.Client {
+	if demo != nil {
+		return &http.Client{Transport: offlineTransport{}}
+	}
+	return &http.Client{
+		Timeout:   8 * time.Second,
+		Transport: newInstrumentedTransport(ctx, stats, false),
 	}
 }
 
+func newNotifierClient(ctx context.Context, stats *providerStats) *http.Client {
+	client := newHTTPClient(ctx, stats)
+	if demo == nil {
+		client.Transport = newInstrumentedTransport(ctx, stats, true)
+	}
+	return client
+}
+
 func (m model) Init() tea.Cmd {
-	return telemetryTick(0)
+	telemetry := telemetryTick(0)
+	if m.daemon != nil {
+		telemetry = m.daemon.next()
+	}
+	cmds := []tea.Cmd{telemetry, memCheckTick(memCheckInterval), headerTick(), sunMoonTick(), fetchSatelliteCmd(m.client, m.tleRefresh)}
+	if m.control != nil {
+		cmds = append(cmds, controlTick())
+	}
+	if m.cloudsPolling {
+		cmds = append(cmds, m.fetchCloudsCmd())
+	}
+	if m.tzLookup && m.observer != nil {
+		cmds = append(cmds, lookupTimezoneCmd(m.client, m.observer.Lat, m.observer.Lon))
+	}
+	if m.recorder != nil {
+		cmds = append(cmds, recordTick(recordInterval))
+	}
+	if m.view == viewCountries && m.history != nil {
+		cmds = append(cmds, loadHistoryCountriesCmd(m.history))
+	}
+	if m.tiangongOn {
+		cmds = append(cmds, fetchStationCmd(m.client, track.TiangongNoradID, m.tleRefresh))
+	}
+	if demo == nil {
+		cmds = append(cmds, fetchCrewCmd(m.client))
+	}
+	if m.spaceWxOn && demo == nil {
+		cmds = append(cmds, fetchSpaceWeatherCmd(m.client))
+	}
+	cmds = append(cmds, m.startPanels()...)
+	if m.api.addr != "" {
+		cmds = append(cmds, m.api.serveCmd(m.ctx))
+	}
+	if m.api.grpcAddr != "" {
+		cmds = append(cmds, m.api.serveGRPCCmd(m.ctx))
+	}
+	cmds = append(cmds, m.startJobs()...)
+	cmds = append(cmds, m.startup.begin()...)
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.paused() && heldWhilePaused(msg) {
+		m.held = append(m.held, msg)
+		return m, nil
+	}
+	if !m.startup.done {
+		m.startup = m.startup.observe(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.dialog.active && msg.Type != tea.KeyCtrlC {
+			return m.dialogKey(msg)
+		}
+		if m.prompt.active && msg.Type != tea.KeyCtrlC {
+			if !m.prompt.key(msg) {
+				return m, nil
+			}
+			return m.submitPrompt()
+		}
+		if m.view == viewSettings {
+			if next, cmd, handled := m.settingsKey(msg); handled {
+				return next, cmd
+			}
+		}
+		if next, cmd, handled := m.demoKey(msg.String()); handled {
+			return next, cmd
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m = m.stopMapAnimation()
 			return m, tea.Quit
+		case "ctrl+z":
+			return m.suspend()
+		case "t":
+			m.tour.enabled = !m.tour.enabled
+			return m, nil
+		case "u":
+			m.units = m.units.next()
+			return m, persistConfigCmd(m.configPath, "units", m.units.String())
+		case "r":
+			return m.manualRefresh(time.Now())
+		case "R":
+			if m.cast == nil {
+				return m, nil
+			}
+			return m, toggleCastCmd(m.cast, m.width, m.height, m.View(), time.Now())
+		case "s":
+			return m, saveScreenshotCmd(m.View(), m.shotFormat, time.Now())
+		case "d":
+			if m.view == viewDiagnostics {
+				m.view = viewMap
+			} else {
+				m.view = viewDiagnostics
+			}
+			return m, nil
+		case "c":
+			if m.view == viewCountries {
+				m.view = viewMap
+				return m, nil
+			}
+			m.view = viewCountries
+			if m.history != nil {
+				return m, loadHistoryCountriesCmd(m.history)
+			}
+			return m, nil
+		case "p":
+			if m.view == viewSky {
+				m.view = viewMap
+			} else {
+				m.view = viewSky
+				m = m.refreshSkyPass(clockNow())
+			}
+			return m, nil
+		case "l":
+			return m, toggleVideoCmd(m.video)
+		case "z":
+			m.times = m.times.toggleUTC()
+			return m, nil
+		case " ":
+			return m.togglePause()
+		case "a":
+			if m.view == viewAltitude {
+				m.view = viewMap
+			} else {
+				m.view = viewAltitude
+			}
+			return m, nil
+		case "g":
+			if m.view == viewLatitude {
+				m.view = viewMap
+			} else {
+				m.view = viewLatitude
+			}
+			return m, nil
+		case "n":
+			if m.view == viewNadir {
+				m.view = viewMap
+			} else {
+				m.view = viewNadir
+			}
+			return m, nil
+		case "O":
+			return m.editObserver(), nil
+		case "C":
+			if m.view == viewCollection {
+				m.view = viewMap
+			} else {
+				m.view = viewCollection
+			}
+			return m, nil
+		case "L":
+			if m.view == viewLog {
+				m.view = viewMap
+			} else {
+				m.view = viewLog
+			}
+			return m, nil
+		case ",":
+			if m.view == viewSettings {
+				m.view = viewMap
+			} else {
+				m.view = viewSettings
+				m.settings = newSettingsForm()
+			}
+			return m, nil
+		case "v":
+			if m.view == viewVehicles {
+				m.view = viewMap
+				return m, nil
+			}
+			m.view = viewVehicles
+			if !m.vehiclesLoaded {
+				m.vehiclesLoaded = true
+				return m, fetchVehiclesCmd(m.client)
+			}
+			return m, nil
+		case "e":
+			m.view = viewMap
+			m.prompt = newTextPrompt("eta_prompt")
+			return m, nil
+		case "b":
+			return m.cycleBookmark()
+		case "P":
+			if m.view == viewProfiles {
+				m.view = viewMap
+			} else {
+				m.view = viewProfiles
+				m = m.refreshProfilePasses(clockNow())
+			}
+			return m, nil
+		case "/":
+			m.view = viewMap
+			m.prompt = newTextPrompt("search_prompt")
+			return m, nil
+		case "j":
+			return m.startJournal(), nil
+		case "esc":
+			if m.view == viewMap && (m.searchPin != nil || !m.mapView.World()) {
+				return m.jumpTo(nil)
+			}
+			return m, nil
+		case "o":
+			if m.view == viewCountries {
+				m.countrySort = m.countrySort.next()
+			}
+			return m, nil
 		}
 
 	case tea.WindowSizeMsg:
+		initial := m.width == 0
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.inlineRows > 0 {
+			m.height = min(msg.Height, m.inlineRows)
+		}
+		if m.cast != nil {
+			m.cast.resize(msg.Width, msg.Height)
+		}
+		if initial {
+			return m.syncMapState()
+		}
+		m.resizeSeq++
+		return m, resizeSettleCmd(m.resizeSeq)
+
+	case resizeSettledMsg:
+		if uint64(msg) != m.resizeSeq {
+			return m, nil
+		}
 		return m.syncMapState()
 
+	case readyMsg:
+		m.startup = m.startup.mark(msg)
+		return m, nil
+
+	case startupTickMsg:
+		if m.startup.done {
+			return m, nil
+		}
+		m.startup.frame++
+		return m, startupTick()
+
 	case telemetryTickMsg:
-		return m, tea.Batch(telemetryTick(telemetryInterval), fetchTelemetryCmd(m.client, m.issOver))
+		now := time.Now()
+		if !m.fetches.ready(now) {
+			return m, telemetryTick(m.interval)
+		}
+		next, fetch := m.requestTelemetry(now)
+		return next, tea.Batch(telemetryTick(m.interval), fetch)
 
-	case telemetryMsg:
-		m.issOver = msg.country
-		m.lat = msg.lat
-		m.lon = msg.lon
-		m.hasCoords = true
+	case telemetryDoneMsg:
+		m.fetches.inFlight = false
+		if m.notice == m.lang.text("refreshing") {
+			m.notice = ""
+		}
+		return m.Update(msg.msg)
+
+	case locationMsg:
+		m.geocoding = false
 		if msg.err != nil {
 			m.lastErr = msg.err.Error()
-		} else {
-			m.lastErr = ""
 		}
-		return m.syncMapState()
+		if msg.loc.Name == "" {
+			return m, nil
+		}
+		return m.moveTo(msg.loc), nil
+
+	case telemetryMsg:
+		switched := m.provider != "" && msg.pos.Provider != "" && msg.pos.Provider != m.provider
+		m = m.applyTelemetry(msg)
+		var toasted tea.Cmd
+		if switched {
+			m, toasted = m.pushToast(fmt.Sprintf(m.lang.text("provider_switched"), msg.pos.Provider))
+		}
+		var locate tea.Cmd
+		if !msg.located && !m.geocoding && m.daemon == nil {
+			m.geocoding = true
+			locate = geocodeCmd(m.client, m.geocodes, m.lang, m.lat, m.lon)
+		}
+		m.tour = m.tour.advance(m.annotations, m.lat, m.lon)
+		previousPass := m.skyPass
+		m = m.refreshSkyPass(clockNow())
+		var events []busEvent
+		var cmds []tea.Cmd
+		if m.skyPass != nil && (previousPass == nil || !previousPass.Start.Equal(m.skyPass.Start)) {
+			events = append(events, busEvent{topic: topicPass, at: clockNow(), pass: m.skyPass})
+		}
+		if m.hasCoords && m.located {
+			m.countries.observe(m.location(), sampleTime(msg.pos))
+			m.observeAway(m.location())
+			var collected []busEvent
+			var save tea.Cmd
+			m, collected, save = m.collect(sampleTime(msg.pos))
+			events = append(events, collected...)
+			cmds = append(cmds, save)
+			if m.history.add(historySample(msg.pos, m.location(), sampleTime(msg.pos))) {
+				cmds = append(cmds, flushHistoryCmd(m.history))
+			}
+		}
+		if m.hasCoords {
+			sample := geofence.Sample{Lat: m.lat, Lon: m.lon, CountryCode: m.countryCode, Time: sampleTime(msg.pos)}
+			m.highlight = m.highlight.update(sample)
+			if entry, ok := m.checkPosition(msg.pos); ok {
+				events = append(events, busEvent{topic: topicPosition, at: entry.at, text: entry.text})
+			}
+			for _, event := range m.fence.Update(sample) {
+				events = append(events, busEvent{topic: topicRegion, at: event.Time, text: regionEventText(m.lang, event), pos: msg.pos})
+			}
+		}
+		var alerts []busEvent
+		m, alerts = m.checkPassAlert(clockNow(), msg.pos)
+		events = append(events, alerts...)
+		m, alerts = m.checkAnnouncements(clockNow(), msg.pos)
+		events = append(events, alerts...)
+		events = append(events, busEvent{topic: topicTelemetry, at: clockNow(), text: m.issOver, pos: msg.pos})
+		next, published := m.publish(events...)
+		next, cmd := next.syncMapState()
+		return next, tea.Batch(append(append(cmds, published...), cmd, locate, toasted)...)
 
 	case mapFrameMsg:
 		if msg.runID != m.currentAnimRun {
@@ -179,39 +938,481 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cancelMapAnim = nil
 		return m, nil
 
+	case memCheckMsg:
+		m.memUsage = processMemoryUsage()
+		if !m.detailAuto || m.memLimit == 0 || m.memUsage <= m.memLimit {
+			return m, memCheckTick(memCheckInterval)
+		}
+
+		lower, ok := lowerMapDetail(m.detail)
+		if !ok {
+			return m, memCheckTick(memCheckInterval)
+		}
+
+		m.mapMask = downsampleMask(m.mapMask, lower.maskScale/m.detail.maskScale)
+		m.detail = lower
+		next, cmd := m.syncMapState()
+		releaseMemory()
+		next.memUsage = processMemoryUsage()
+		return next, tea.Batch(cmd, memCheckTick(memCheckInterval))
+
+	case recordTickMsg:
+		now := time.Now()
+		if m.recorder.due(now) {
+			return m, finishRecordingCmd(m.recorder, now)
+		}
+		m.recorder.capture(m.View(), now)
+		return m, recordTick(recordInterval)
+
+	case journalSavedMsg:
+		if msg.err != nil {
+			m.lastErr = fmt.Sprintf("journal: %v", msg.err)
+			return m, nil
+		}
+		return m.pushToast(fmt.Sprintf(m.lang.text("journal_saved"), msg.count))
+
+	case castMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			return m, nil
+		}
+		if !msg.started {
+			return m.pushToast(m.lang.text("saved") + " " + msg.path)
+		}
+		return m, nil
+
+	case timezoneMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			return m, nil
+		}
+		m.times.loc = msg.loc
+		return m, nil
+
+	case panelTickMsg:
+		if msg.index >= len(m.panels) {
+			return m, nil
+		}
+		return m, runPanelCmd(msg.index, m.panels[msg.index], m.panelRequest(clockNow()))
+
+	case panelMsg:
+		return m.applyPanel(msg)
+
+	case cloudsTickMsg:
+		if !m.cloudsEnabled() {
+			m.cloudsPolling = false
+			return m, nil
+		}
+		return m, m.fetchCloudsCmd()
+
+	case cloudsMsg:
+		next := cloudsRefresh
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			next = cloudsRefresh / 4
+		}
+		if msg.forecast != nil {
+			m.cloudForecast = msg.forecast
+		}
+		if !m.cloudLayerOn || (msg.err != nil && msg.layer == nil) {
+			return m, cloudsTick(next)
+		}
+		m.cloudLayer = msg.layer
+		updated, cmd := m.syncMapState()
+		return updated, tea.Batch(cmd, cloudsTick(next))
+
+	case sunMoonTickMsg:
+		if !m.sunMoon {
+			return m, sunMoonTick()
+		}
+		next, cmd := m.syncMapState()
+		return next, tea.Batch(cmd, sunMoonTick())
+
+	case videoMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+		} else if msg.opened {
+			m.notice = m.lang.text("video_opened")
+		}
+		return m, nil
+
+	case controlTickMsg:
+		now := time.Now()
+		look, ok := m.currentLook(now)
+		if !ok {
+			return m, controlTick()
+		}
+		m = m.refreshSkyPass(now)
+		return m, tea.Batch(controlTick(), controlCmd(m.control, look, m.skyPass, now))
+
+	case jobMsg:
+		return m, m.runJob(msg)
+
+	case ctlMsg:
+		return m.handleCtl(msg)
+
+	case daemonMsg:
+		if m.paused() {
+			return m, m.daemon.next()
+		}
+		next, cmd := m.Update(msg.msg)
+		return next, tea.Batch(cmd, m.daemon.next())
+
+	case daemonLostMsg:
+		m.daemon.close()
+		m.daemon = nil
+		m.lastErr = fmt.Sprintf(m.lang.text("daemon_lost"), msg.err)
+		return m, telemetryTick(0)
+
+	case tleRefreshMsg:
+		refresh := m.tleRefresh
+		if m.tleCron {
+			refresh = 0
+		}
+		if m.tiangongOn {
+			return m, tea.Batch(fetchSatelliteCmd(m.client, refresh), fetchStationCmd(m.client, track.TiangongNoradID, refresh))
+		}
+		return m, fetchSatelliteCmd(m.client, refresh)
+
+	case stationMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+		}
+		if msg.sat != nil {
+			m.tiangong = msg.sat
+		}
+		return m, nil
+
+	case crewRefreshMsg:
+		return m, fetchCrewCmd(m.client)
+
+	case crewMsg:
+		return m.applyCrew(msg)
+
+	case spaceWeatherRefreshMsg:
+		return m, fetchSpaceWeatherCmd(m.client)
+
+	case spaceWeatherMsg:
+		return m.applySpaceWeather(msg)
+
+	case satelliteMsg:
+		next := m.tleRefresh
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			next = min(next, tleRetryInterval)
+		}
+		tick := tleRefreshTick(next)
+		if m.tleCron && msg.err == nil {
+			tick = nil
+		}
+		if msg.sat == nil {
+			return m, tick
+		}
+		var events []busEvent
+		if msg.previous != nil {
+			if event, ok := m.orbitChangeEvent(msg.previous, msg.sat); ok {
+				events = append(events, event)
+			}
+		}
+		m.sat = msg.sat
+		m.skyPass = nil
+		m.profilePasses = nil
+		if m.eta != nil {
+			m.eta = &etaPin{place: m.eta.place}
+		}
+		updated, cmds := m.refreshSkyPass(clockNow()).refreshETA(clockNow()).publish(events...)
+		return updated, tea.Batch(append(cmds, tick)...)
+
+	case headerTickMsg:
+		m = m.refreshETA(clockNow())
+		if m.view == viewProfiles {
+			m = m.refreshProfilePasses(clockNow())
+		}
+		m, cmd := m.checkLookalikes(clockNow())
+		return m, tea.Batch(cmd, headerTick())
+
+	case lookalikesMsg:
+		return m.applyLookalikes(msg), nil
+
+	case placeSearchMsg:
+		return m.applyPlaceSearch(msg)
+
+	case vehiclesRefreshMsg:
+		return m, fetchVehiclesCmd(m.client)
+
+	case vehiclesMsg:
+		next := vehiclesRefresh
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			next = vehiclesRetry
+		}
+		if !msg.fetchedAt.IsZero() {
+			m.vehicles, m.vehiclesAt = msg.vehicles, msg.fetchedAt
+			if msg.err == nil {
+				next = max(msg.fetchedAt.Add(vehiclesRefresh).Sub(time.Now()), time.Minute)
+			}
+		}
+		return m, vehiclesRefreshTick(next)
+
+	case historyCountriesMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			return m, nil
+		}
+		m.historyTotals = msg.stats
+		return m, nil
+
+	case fileSavedMsg:
+		if msg.err != nil {
+			m.lastErr = msg.err.Error()
+			return m, nil
+		}
+		return m.pushToast(m.lang.text("saved") + " " + msg.path)
+
+	case tea.BlurMsg:
+		return m.leave(clockNow()), nil
+
+	case tea.FocusMsg:
+		return m.comeBack(clockNow())
+
+	case suspendMsg:
+		return m.suspend()
+
+	case tea.ResumeMsg:
+		next, cmd := m.comeBack(clockNow())
+		return next, tea.Batch(tea.ClearScreen, tea.WindowSize(), cmd)
+
+	case toastExpiredMsg:
+		m.toasts = liveToasts(m.toasts, time.Now())
+		return m, nil
+
 	case errMsg:
-		m.lastErr = msg.err.Error()
+		next, cmds := m.publish(busEvent{topic: topicError, at: clockNow(), err: msg.err})
+		return next, tea.Batch(cmds...)
 	}
 
 	return m, nil
 }
 
+func (m model) applyTelemetry(msg telemetryMsg) model {
+	m.notice = ""
+	m.lat = msg.pos.Lat
+	m.lon = msg.pos.Lon
+	m.hasCoords = true
+	m.altitudeKm = msg.pos.AltitudeKm
+	m.velocityKmh = msg.pos.VelocityKmh
+	m.hasAltitude = msg.pos.HasAltitude
+	if msg.pos.Provider != "" {
+		m.provider = msg.pos.Provider
+	}
+	m.motion = m.motion.observe(msg.pos.Lat, msg.pos.Lon, sampleTime(msg.pos))
+	m.trail = m.pushTrail(msg.pos.Lat, msg.pos.Lon, sampleTime(msg.pos))
+	if msg.pos.HasAltitude {
+		m.altitudes = m.pushAltitude(msg.pos.AltitudeKm, sampleTime(msg.pos))
+	}
+	if msg.err != nil {
+		m.lastErr = msg.err.Error()
+	} else {
+		m.lastErr = ""
+	}
+	if msg.located {
+		m = m.moveTo(geo.Location{Name: msg.country, CountryCode: msg.countryCode})
+	}
+	return m
+}
+
+func (m model) moveTo(loc geo.Location) model {
+	previous := m.issOver
+	m.issOver, m.countryCode = loc.Name, loc.CountryCode
+	m.located = m.located || loc.Name != ""
+	if m.hasCoords && loc.Name != previous && previous != m.lang.text("resolving") {
+		m.tour = m.tour.push(m.lang.text("now_over") + " " + loc.Name)
+	}
+	return m
+}
+
+func sampleTime(pos track.Position) time.Time {
+	if pos.Timestamp.IsZero() {
+		return clockNow()
+	}
+	return pos.Timestamp
+}
+
+func (m model) checkPosition(pos track.Position) (logEntry, bool) {
+	if m.quality == nil || m.sat == nil {
+		return logEntry{}, false
+	}
+	check, err := quality.Compare(m.sat, pos, sampleTime(pos))
+	if err != nil {
+		return logEntry{}, false
+	}
+	event, ok := m.quality.Observe(check)
+	if !ok {
+		return logEntry{}, false
+	}
+	if event.Kind == quality.Recovered {
+		return logEntry{at: check.Time, text: fmt.Sprintf(m.lang.text("position_ok"), check.Provider)}, true
+	}
+	return logEntry{at: check.Time, text: fmt.Sprintf(m.lang.text("position_off"), check.Provider, m.units.formatDistance(check.OffsetKm))}, true
+}
+
+func (m model) location() geo.Location {
+	return geo.Location{Name: m.issOver, CountryCode: m.countryCode}
+}
+
 func (m model) View() string {
-	telemetryLines := []string{"ISS over: " + m.issOver}
+	return m.applyCharset(m.fitInline(m.overlayToasts(m.overlayDialog(m.compose()), time.Now())))
+}
+
+func (m model) compose() string {
+	switch m.view {
+	case viewDiagnostics:
+		return m.diagnosticsView()
+	case viewCountries:
+		return m.countriesView()
+	case viewSky:
+		return m.skyView()
+	case viewVehicles:
+		return m.vehiclesView()
+	case viewSettings:
+		return m.settingsView()
+	case viewLog:
+		return m.logView()
+	case viewProfiles:
+		return m.profilesView()
+	case viewAltitude:
+		return m.altitudeView()
+	case viewLatitude:
+		return m.latitudeView()
+	case viewNadir:
+		return m.nadirView()
+	case viewCollection:
+		return m.collectionView()
+	}
+	if !m.startup.done {
+		return m.startupView()
+	}
+	screen := centerBlock(m.headerLine(m.displayTime()), m.width)
+	if demo != nil {
+		screen += centerBlock(m.demoBar(), m.width)
+	}
+	screen += renderScreen(m.mapASCII, m.highlight.apply(m.telemetryLines(), m.glyphs(), time.Now(), m.palette), m.width)
+	if m.fence != nil || len(m.events) > 0 {
+		screen += centerBlock(m.box(eventLogLines(m.lang, m.times, m.events)), m.width) + "\n"
+	}
+	if line := m.tour.line(m.lang.text("tour"), m.width); line != "" {
+		screen += centerBlock(line, m.width) + "\n"
+	}
+	if m.cast != nil {
+		if path, active := m.cast.recording(); active {
+			screen += centerBlock(m.lang.text("recording")+" "+filepath.Base(path), m.width) + "\n"
+		}
+	}
+	if m.video.running() {
+		screen += centerBlock(m.lang.text("video_playing"), m.width) + "\n"
+	}
+	if line := m.breakerLine(time.Now()); line != "" {
+		screen += centerBlock(line, m.width) + "\n"
+	}
+	if line := m.tleStaleLine(clockNow()); line != "" {
+		screen += centerBlock(line, m.width) + "\n"
+	}
+	if line := m.lookalikeLine(clockNow()); line != "" {
+		screen += centerBlock(line, m.width) + "\n"
+	}
+	if m.notice != "" {
+		screen += centerBlock(m.notice, m.width) + "\n"
+	}
+	if line := m.promptLine(); line != "" {
+		screen += centerBlock(line, m.width) + "\n"
+	}
+	return screen
+}
+
+func (m model) diagnosticsView() string {
+	lines := []string{m.lang.text("diagnostics"), ""}
+	lines = append(lines, providerStatsTable(m.lang.text("this_session"), m.lang.text("no_requests"), m.stats.summaries(false))...)
+	lines = append(lines, "")
+	lines = append(lines, providerStatsTable(m.lang.text("all_sessions"), m.lang.text("no_requests"), m.stats.summaries(true))...)
+	lines = append(lines, "")
+	lines = append(lines, providerTimelineTable(m.lang.text("last_30_minutes"), m.lang.text("no_requests"), m.stats.timelines(time.Now()))...)
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
+
+func (m model) countriesView() string {
+	order := m.lang.text("by_time")
+	if m.countrySort == sortByName {
+		order = m.lang.text("by_name")
+	}
+
+	lines := []string{m.lang.text("countries") + " · " + order, ""}
+	lines = append(lines, countryStatsTable(m.lang, m.lang.text("this_session"), m.countries.rows(false, m.countrySort))...)
+	switch {
+	case m.historyTotals != nil:
+		lines = append(lines, "")
+		lines = append(lines, countryStatsTable(m.lang, m.lang.text("all_sessions"), m.historyTotals.rows(false, m.countrySort))...)
+	case m.countries.persistent():
+		lines = append(lines, "")
+		lines = append(lines, countryStatsTable(m.lang, m.lang.text("all_sessions"), m.countries.rows(true, m.countrySort))...)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
+
+func (m model) telemetryLines() []string {
+	telemetryLines := []string{m.locationLine()}
+	fields := [][2]string{}
 	if m.hasCoords {
-		telemetryLines = append(telemetryLines, "Latitude:  "+formatLatitude(m.lat))
-		telemetryLines = append(telemetryLines, "Longitude: "+formatLongitude(m.lon))
+		fields = append(fields, [2]string{m.lang.text("latitude"), formatLatitude(m.lat)})
+		fields = append(fields, [2]string{m.lang.text("longitude"), formatLongitude(m.lon)})
+		if m.hasAltitude {
+			fields = append(fields, [2]string{m.lang.text("altitude"), m.units.formatDistance(m.altitudeKm)})
+			fields = append(fields, [2]string{m.lang.text("velocity"), m.units.formatSpeed(m.velocityKmh)})
+		}
+		fields = append(fields, m.headingFields()...)
 	} else {
-		telemetryLines = append(telemetryLines, "Coords: Resolving...")
+		telemetryLines = append(telemetryLines, m.lang.text("coords")+": "+m.lang.text("resolving"))
 	}
-	mapView := centerBlock(m.mapASCII, m.width)
-	telemetry := centerBlock(telemetryBox(telemetryLines), m.width)
+	fields = append(fields, m.tiangongFields(clockNow())...)
+	if field, ok := m.crewField(); ok {
+		fields = append(fields, field)
+	}
+	fields = append(fields, [2]string{m.lang.text("detail"), formatMapDetail(m.detail, m.detailAuto, m.memUsage)})
+	if look, ok := m.currentLook(clockNow()); ok && look.Elevation > 0 {
+		fields = append(fields, [2]string{m.lang.text("elevation"), fmt.Sprintf("%.1f° %s", look.Elevation, compassPoint(look.Azimuth))})
+		fields = append(fields, m.magnitudeField(clockNow()))
+		fields = append(fields, dopplerFields(m.lang, m.radios, look)...)
+	}
+	if field, ok := m.etaField(clockNow()); ok {
+		fields = append(fields, field)
+	}
+	telemetryLines = append(telemetryLines, alignFields(fields)...)
+	telemetryLines = append(telemetryLines, m.spaceWeatherLines()...)
+	return append(telemetryLines, m.panelLines()...)
+}
+
+func renderScreen(mapASCII string, telemetry string, width int) string {
+	mapView := centerBlock(mapASCII, width)
+	telemetry = centerBlock(telemetry, width)
 	return "\n" + mapView + "\n\n" + telemetry + "\n"
 }
 
 func (m model) syncMapState() (model, tea.Cmd) {
-	if m.mapMask == nil {
+	if m.mapMask == nil || m.paused() {
 		return m, nil
 	}
 
-	if m.hasCoords {
+	if m.hasCoords && !m.away.active() {
 		return m.startMapAnimation()
 	}
 
 	m = m.stopMapAnimation()
 
-	size := mapWidthForTerm(m.width)
-	rendered, err := renderMap(m.mapMask, size, m.lat, m.lon, m.hasCoords)
+	rendered, err := renderMap(m.rasters, m.rasterKey(), m.mapStyle(), m.lat, m.lon, m.hasCoords, m.mapGlyphs(clockNow()))
 	if err != nil {
 		m.lastErr = err.Error()
 		return m, nil
@@ -237,62 +1438,55 @@ func (m model) stopMapAnimation() model {
 }
 
 func (m model) startMapAnimation() (model, tea.Cmd) {
-	size := mapWidthForTerm(m.width)
-	marker := &mapascii.Marker{
-		Lon:    m.lon,
-		Lat:    m.lat,
-		Center: 'X',
-		ArmX:   markerArmX,
-		ArmY:   markerArmY,
-	}
-	renderOptions := &mapascii.RenderOptions{
-		VerticalMarginRows: mapMarginRows,
-		Frame:              true,
-		ColorMode:          "auto",
-		MapColor:           "green",
-		MarkerColor:        "blue",
-	}
-	animOptions := &mapascii.AnimationOptions{
-		FPS:   mapascii.DefaultAnimationFPS,
-		Style: mapascii.AnimationStyleBlink,
-	}
+	marker := issMarker(m.lat, m.lon)
 
 	m = m.cancelMapAnimation()
 	m.currentAnimRun++
 	runID := m.currentAnimRun
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(m.ctx)
 	frameCh := make(chan mapFrameMsg, 1)
 	m.cancelMapAnim = cancel
 	m.mapFrameCh = frameCh
 
-	go streamMapAnimation(ctx, runID, frameCh, m.mapMask, size, marker, renderOptions, animOptions)
+	go streamMapAnimation(ctx, runID, frameCh, m.rasters, m.rasterKey(), marker, m.mapGlyphs(clockNow()), m.mapStyle())
 
 	return m, waitForMapFrame(frameCh, runID)
 }
 
+func (m model) rasterKey() render.Key {
+	return render.Key{
+		Mask:        m.mapMask,
+		Size:        mapWidthForTerm(m.width, m.mapWidthLimit()),
+		Supersample: m.detail.supersample,
+		CharAspect:  mapCharAspect,
+		View:        m.mapView,
+	}
+}
+
 func streamMapAnimation(
 	ctx context.Context,
 	runID uint64,
 	frameCh chan<- mapFrameMsg,
-	mask *mapascii.LandMask,
-	size int,
+	rasters *render.Cache,
+	key render.Key,
 	marker *mapascii.Marker,
-	renderOptions *mapascii.RenderOptions,
-	animOptions *mapascii.AnimationOptions,
+	glyphs []render.Glyph,
+	style render.Style,
 ) {
+	defer crashes.recover()
 	defer close(frameCh)
 
-	emit := func(frame mapascii.Frame) error {
+	emit := func(frame string) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case frameCh <- mapFrameMsg{runID: runID, frame: frame.Text}:
+		case frameCh <- mapFrameMsg{runID: runID, frame: frame}:
 			return nil
 		}
 	}
 
-	err := mapascii.StreamWorldASCIIAnimation(ctx, mask, size, mapSupersample, mapCharAspect, marker, renderOptions, animOptions, emit)
+	err := animateMap(ctx, rasters, key, marker, glyphs, style, emit)
 	if err != nil && !errors.Is(err, context.Canceled) {
 		select {
 		case <-ctx.Done():
@@ -301,6 +1495,49 @@ func streamMapAnimation(
 	}
 }
 
+func animateMap(
+	ctx context.Context,
+	rasters *render.Cache,
+	key render.Key,
+	marker *mapascii.Marker,
+	glyphs []render.Glyph,
+	style render.Style,
+	emit func(string) error,
+) error {
+	start := time.Now()
+	base, err := rasters.Get(key)
+	if err != nil {
+		return err
+	}
+	slog.Debug("map raster", "width", key.Size, "took", time.Since(start))
+
+	ticker := time.NewTicker(time.Second / mapascii.DefaultAnimationFPS)
+	defer ticker.Stop()
+
+	for frameIdx := 0; ; frameIdx++ {
+		frameMarker := marker
+		if frameIdx%2 == 1 {
+			frameMarker = nil
+		}
+
+		start := time.Now()
+		frame, err := render.Compose(base, frameMarker, style, glyphs...)
+		if err != nil {
+			return err
+		}
+		slog.Debug("map frame", "frame", frameIdx, "compose", time.Since(start))
+		if err := emit(frame); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 func waitForMapFrame(frameCh <-chan mapFrameMsg, runID uint64) tea.Cmd {
 	if frameCh == nil {
 		return nil
@@ -315,7 +1552,7 @@ func waitForMapFrame(frameCh <-chan mapFrameMsg, runID uint64) tea.Cmd {
 	}
 }
 
-func mapWidthForTerm(termWidth int) int {
+func mapWidthForTerm(termWidth int, maxWidth int) int {
 	if termWidth <= 0 {
 		return defaultMapWidth
 	}
@@ -324,34 +1561,41 @@ func mapWidthForTerm(termWidth int) int {
 	if width < minMapWidth {
 		return minMapWidth
 	}
-	if width > maxMapWidth {
-		return maxMapWidth
+	if width > maxWidth {
+		return maxWidth
 	}
 
 	return width
 }
 
-func renderMap(mask *mapascii.LandMask, size int, lat, lon float64, hasCoords bool) (string, error) {
+func issMarker(lat, lon float64) *mapascii.Marker {
+	return &mapascii.Marker{
+		Lon:    lon,
+		Lat:    lat,
+		Center: 'X',
+		ArmX:   markerArmX,
+		ArmY:   markerArmY,
+	}
+}
+
+func renderMap(rasters *render.Cache, key render.Key, style render.Style, lat, lon float64, hasCoords bool, glyphs []render.Glyph) (string, error) {
+	base, err := rasters.Get(key)
+	if err != nil {
+		return "", err
+	}
+
 	var marker *mapascii.Marker
 	if hasCoords {
-		marker = &mapascii.Marker{
-			Lon:    lon,
-			Lat:    lat,
-			Center: 'X',
-			ArmX:   markerArmX,
-			ArmY:   markerArmY,
-		}
+		marker = issMarker(lat, lon)
 	}
 
-	options := &mapascii.RenderOptions{
-		VerticalMarginRows: mapMarginRows,
-		Frame:              true,
-		ColorMode:          "auto",
-		MapColor:           "green",
-		MarkerColor:        "blue",
-	}
+	return render.Compose(base, marker, style, glyphs...)
+}
 
-	return mapascii.RenderWorldASCIIWithOptions(mask, size, mapSupersample, mapCharAspect, marker, options)
+func resizeSettleCmd(seq uint64) tea.Cmd {
+	return tea.Tick(resizeDebounce, func(time.Time) tea.Msg {
+		return resizeSettledMsg(seq)
+	})
 }
 
 func telemetryTick(d time.Duration) tea.Cmd {
@@ -360,193 +1604,119 @@ func telemetryTick(d time.Duration) tea.Cmd {
 	})
 }
 
-func fetchTelemetryCmd(client *http.Client, currentCountry string) tea.Cmd {
+func fetchPositionCmd(client *http.Client, providers []track.Provider, geocodes *geocodeCache, lang language) tea.Cmd {
 	return func() tea.Msg {
-		lat, lon, err := fetchISSPosition(client)
+		pos, err := track.FetchPosition(client, providers)
 		if err != nil {
 			return errMsg{err: err}
 		}
 
-		country, err := reverseGeocodeCountry(client, lat, lon)
-		if err != nil {
-			return telemetryMsg{
-				country: currentCountry,
-				lat:     lat,
-				lon:     lon,
-				err:     err,
-			}
+		if loc, ok := geocodes.lookup(pos.Lat, pos.Lon, lang.geocodeKey()); ok {
+			return telemetryMsg{country: loc.Name, countryCode: loc.CountryCode, located: true, pos: pos}
 		}
-
-		return telemetryMsg{
-			country: country,
-			lat:     lat,
-			lon:     lon,
+		if loc, ok := geocodes.near(pos.Lat, pos.Lon, lang.geocodeKey()); ok {
+			return telemetryMsg{country: loc.Name, countryCode: loc.CountryCode, located: true, pos: pos}
 		}
+		return telemetryMsg{pos: pos}
 	}
 }
 
-func fetchISSPosition(client *http.Client) (float64, float64, error) {
-	req, err := http.NewRequest(http.MethodGet, issURL, nil)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	req.Header.Set("User-Agent", userAgent)
-	resp, err := client.Do(req)
-	if err != nil {
-		return 0, 0, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("iss api status: %s", resp.Status)
-	}
-
-	var payload issPositionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return 0, 0, err
-	}
-
-	if !strings.EqualFold(payload.Message, "success") {
-		return 0, 0, fmt.Errorf("open-notify message: %q", payload.Message)
-	}
-
-	lat, err := strconv.ParseFloat(payload.ISSPosition.Latitude, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid latitude %q: %w", payload.ISSPosition.Latitude, err)
-	}
-
-	lon, err := strconv.ParseFloat(payload.ISSPosition.Longitude, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid longitude %q: %w", payload.ISSPosition.Longitude, err)
+func geocodeCmd(client *http.Client, geocodes *geocodeCache, lang language, lat, lon float64) tea.Cmd {
+	return func() tea.Msg {
+		loc, err := resolveLocation(client, geocodes, lang, lat, lon)
+		return locationMsg{loc: loc, err: err}
 	}
-
-	return lat, lon, nil
 }
 
-func reverseGeocodeCountry(client *http.Client, lat, lon float64) (string, error) {
-	payload, err := reverseGeocode(client, lat, lon, 3)
-	if err != nil {
-		return "", err
-	}
-
-	if strings.EqualFold(payload.Error, "Unable to geocode") {
-		deepPayload, deepErr := reverseGeocode(client, lat, lon, 2)
-		if deepErr != nil {
-			return "Ocean", nil
+func fetchTelemetryCmd(client *http.Client, providers []track.Provider, geocodes *geocodeCache, lang language, current geo.Location) tea.Cmd {
+	fetch := fetchPositionCmd(client, providers, geocodes, lang)
+	return func() tea.Msg {
+		result := fetch()
+		msg, ok := result.(telemetryMsg)
+		if !ok || msg.located {
+			return result
 		}
 
-		if name := oceanOrWaterName(deepPayload); name != "" {
-			return name, nil
+		loc, err := resolveLocation(client, geocodes, lang, msg.pos.Lat, msg.pos.Lon)
+		if loc.Name == "" {
+			loc = current
 		}
-
-		return "Ocean", nil
-	}
-
-	if country := strings.TrimSpace(payload.Address.Country); country != "" {
-		return country, nil
-	}
-
-	if name := oceanOrWaterName(payload); name != "" {
-		return name, nil
-	}
-
-	deepPayload, err := reverseGeocode(client, lat, lon, 2)
-	if err != nil {
-		return "Ocean", nil
-	}
-
-	if name := oceanOrWaterName(deepPayload); name != "" {
-		return name, nil
+		msg.country, msg.countryCode, msg.located, msg.err = loc.Name, loc.CountryCode, true, err
+		return msg
 	}
-
-	return "Ocean", nil
 }
 
-func reverseGeocode(client *http.Client, lat, lon float64, zoom int) (nominatimResponse, error) {
-	q := url.Values{}
-	q.Set("format", "jsonv2")
-	q.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
-	q.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
-	q.Set("zoom", strconv.Itoa(zoom))
-	q.Set("addressdetails", "1")
-	q.Set("accept-language", "en")
-
-	u, err := url.Parse(nominatimURL)
-	if err != nil {
-		return nominatimResponse{}, err
+func resolveLocation(client *http.Client, geocodes *geocodeCache, lang language, lat, lon float64) (geo.Location, error) {
+	loc, err := reverseGeocodeCountry(client, lat, lon, lang)
+	if errors.Is(err, errOffline) {
+		return geo.Location{Name: lang.text("demo_location")}, nil
 	}
-	u.RawQuery = q.Encode()
-
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nominatimResponse{}, err
-	}
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept-Language", "en")
-
-	resp, err := client.Do(req)
 	if err != nil {
-		return nominatimResponse{}, err
+		return geo.Location{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nominatimResponse{}, fmt.Errorf("nominatim status: %s", resp.Status)
+	if geocodes != nil {
+		if loc, err = describeOffshore(client, geocodes.land, lang, lat, lon, loc); err != nil {
+			return loc, err
+		}
 	}
 
-	var payload nominatimResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nominatimResponse{}, err
+	if err := geocodes.store(lat, lon, lang.geocodeKey(), loc); err != nil {
+		return loc, fmt.Errorf("geocode cache: %w", err)
 	}
-
-	return payload, nil
+	return loc, nil
 }
 
-func oceanOrWaterName(payload nominatimResponse) string {
-	name := strings.TrimSpace(payload.Name)
-	if name == "" {
-		name = strings.TrimSpace(strings.Split(payload.DisplayName, ",")[0])
+func reverseGeocodeCountry(client *http.Client, lat, lon float64, lang language) (geo.Location, error) {
+	loc, err := geo.Locate(client, lat, lon, lang.acceptLanguage(), lang.names)
+	if err != nil {
+		return geo.Location{}, err
 	}
-
-	if name == "" {
-		return ""
+	if loc.Name == "" {
+		loc.Name = lang.text("ocean")
 	}
 
-	typeValue := strings.ToLower(strings.TrimSpace(payload.Type))
-	category := strings.ToLower(strings.TrimSpace(payload.Category))
-	addresstype := strings.ToLower(strings.TrimSpace(payload.Addresstype))
-	loweredName := strings.ToLower(name)
+	return loc, nil
+}
 
-	if addresstype == "ocean" || typeValue == "ocean" || typeValue == "sea" || typeValue == "bay" || typeValue == "strait" || category == "natural" {
-		return name
+func alignFields(fields [][2]string) []string {
+	labelWidth := 0
+	for _, field := range fields {
+		if w := ansi.StringWidth(field[0]); w > labelWidth {
+			labelWidth = w
+		}
 	}
 
-	if strings.Contains(loweredName, "ocean") || strings.Contains(loweredName, "sea") || strings.Contains(loweredName, "gulf") || strings.Contains(loweredName, "strait") || strings.Contains(loweredName, "bay") {
-		return name
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		padding := strings.Repeat(" ", labelWidth-ansi.StringWidth(field[0]))
+		lines = append(lines, field[0]+": "+padding+field[1])
 	}
 
-	return ""
+	return lines
 }
 
 func telemetryBox(lines []string) string {
+	return boxWith(lines, render.Border{})
+}
+
+func boxWith(lines []string, border render.Border) string {
 	contentWidth := 0
 	for _, line := range lines {
-		if w := len([]rune(line)); w > contentWidth {
+		if w := ansi.StringWidth(line); w > contentWidth {
 			contentWidth = w
 		}
 	}
 
 	width := contentWidth + 2
-	border := "+" + strings.Repeat("-", width) + "+"
+	side := border.Side()
 
 	rendered := make([]string, 0, len(lines)+2)
-	rendered = append(rendered, border)
+	rendered = append(rendered, border.Top(width))
 	for _, line := range lines {
-		padding := strings.Repeat(" ", contentWidth-len([]rune(line)))
-		rendered = append(rendered, "| "+line+padding+" |")
+		padding := strings.Repeat(" ", contentWidth-ansi.StringWidth(line))
+		rendered = append(rendered, side+" "+line+padding+" "+side)
 	}
-	rendered = append(rendered, border)
+	rendered = append(rendered, border.Bottom(width))
 
 	return strings.Join(rendered, "\n")
 }
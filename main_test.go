@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+)
+
+func testModel(t *testing.T) model {
+	t.Helper()
+	for _, name := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME"} {
+		t.Setenv(name, t.TempDir())
+	}
+	return newModel(context.Background(), globalOptions{})
+}
+
+func TestTelemetryWaitsForLocation(t *testing.T) {
+	m := testModel(t)
+	m.countries = newCountryStats(false)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	telemetry := func(m model, at time.Time) model {
+		next, _ := m.Update(telemetryMsg{pos: track.Position{Lat: 10, Lon: -30, Timestamp: at}})
+		return next.(model)
+	}
+
+	m = telemetry(m, start)
+	m = telemetry(m, start.Add(5*time.Second))
+	if rows := m.countries.rows(true, sortByTime); len(rows) != 0 {
+		t.Fatalf("recorded %v before the first geocode result", rows)
+	}
+
+	next, _ := m.Update(locationMsg{loc: geo.Location{Name: "Atlantic Ocean"}})
+	m = next.(model)
+	m = telemetry(m, start.Add(10*time.Second))
+	m = telemetry(m, start.Add(15*time.Second))
+	rows := m.countries.rows(true, sortByTime)
+	if len(rows) != 1 || rows[0].name != "Atlantic Ocean" {
+		t.Fatalf("rows = %v, want only Atlantic Ocean", rows)
+	}
+}
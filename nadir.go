@@ -0,0 +1,130 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+)
+
+const (
+	sunriseElevation  = -0.833
+	terminatorStep    = 20 * time.Second
+	terminatorPrecise = time.Second
+)
+
+//go:embed data/cities.json
+var citiesJSON []byte
+
+type city struct {
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+}
+
+func loadCities() ([]city, error) {
+	var cities []city
+	if err := json.Unmarshal(citiesJSON, &cities); err != nil {
+		return nil, fmt.Errorf("parse cities: %w", err)
+	}
+	return cities, nil
+}
+
+func nearestCity(cities []city, lat, lon float64) (city, float64, bool) {
+	best, bestKm := city{}, math.Inf(1)
+	for _, c := range cities {
+		if km := geo.HaversineKm(c.Lat, c.Lon, lat, lon); km < bestKm {
+			best, bestKm = c, km
+		}
+	}
+	return best, bestKm, len(cities) > 0
+}
+
+func solarTime(lon float64, now time.Time) time.Time {
+	return now.UTC().Add(time.Duration(lon / 15 * float64(time.Hour)))
+}
+
+func nextTerminator(sat *track.Satellite, now time.Time) (time.Time, bool, error) {
+	sunlit, err := sat.Sunlit(now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	before := now
+	for after := now.Add(terminatorStep); after.Sub(now) <= sat.Period(); after = after.Add(terminatorStep) {
+		lit, err := sat.Sunlit(after)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if lit == sunlit {
+			before = after
+			continue
+		}
+		for after.Sub(before) > terminatorPrecise {
+			mid := before.Add(after.Sub(before) / 2)
+			if lit, err = sat.Sunlit(mid); err != nil {
+				return time.Time{}, false, err
+			}
+			if lit == sunlit {
+				before = mid
+			} else {
+				after = mid
+			}
+		}
+		return after, !sunlit, nil
+	}
+	return time.Time{}, false, fmt.Errorf("no sunrise or sunset within one orbit")
+}
+
+func (m model) nadirView() string {
+	lines := []string{m.lang.text("nadir_view"), ""}
+
+	if !m.hasCoords {
+		lines = append(lines, m.lang.text("resolving"))
+		return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+	}
+
+	now := clockNow()
+	sky := m.lang.text("nadir_night")
+	switch elevation := (track.Observer{Lat: m.lat, Lon: m.lon}).SunElevation(now); {
+	case elevation > sunriseElevation:
+		sky = m.lang.text("nadir_day")
+	case elevation > track.TwilightElevation:
+		sky = m.lang.text("nadir_twilight")
+	}
+	fields := [][2]string{
+		{m.lang.text("iss_over"), m.issOver},
+		{m.lang.text("latitude"), formatLatitude(m.lat)},
+		{m.lang.text("longitude"), formatLongitude(m.lon)},
+		{m.lang.text("nadir_local_time"), solarTime(m.lon, now).Format("15:04") + " · " + sky},
+	}
+	if c, km, ok := nearestCity(m.cities, m.lat, m.lon); ok {
+		bearing := compassPoint(geo.InitialBearing(c.Lat, c.Lon, m.lat, m.lon))
+		fields = append(fields, [2]string{m.lang.text("nadir_city"), fmt.Sprintf(m.lang.text("nadir_city_from"), m.units.formatDistance(km), bearing, c.Name)})
+	}
+
+	if m.sat != nil {
+		light := m.lang.text("eclipsed")
+		if sunlit, err := m.sat.Sunlit(now); err == nil && sunlit {
+			light = m.lang.text("nadir_sunlit")
+		}
+		fields = append(fields, [2]string{m.lang.text("nadir_station"), light})
+		if at, sunrise, err := nextTerminator(m.sat, now); err == nil {
+			label := m.lang.text("nadir_sunset")
+			if sunrise {
+				label = m.lang.text("nadir_sunrise")
+			}
+			fields = append(fields, [2]string{label, fmt.Sprintf("%s (%s)", formatStayDuration(at.Sub(now)), m.times.format(at, layoutClock))})
+		}
+		fields = append(fields, [2]string{m.lang.text("nadir_per_day"), fmt.Sprintf("%.1f", float64(24*time.Hour)/float64(m.sat.Period()))})
+	}
+	lines = append(lines, alignFields(fields)...)
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/notify"
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	mapascii "github.com/Kivayan/map-ascii"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	notifyMapWidth = 480
+	maxPassMinutes = 24 * 60
+)
+
+type notifyConfig struct {
+	PassMinutes float64         `json:"pass_minutes"`
+	Map         *bool           `json:"map"`
+	Discord     *webhookConfig  `json:"discord"`
+	Slack       *webhookConfig  `json:"slack"`
+	Telegram    *telegramConfig `json:"telegram"`
+	Ntfy        *ntfyConfig     `json:"ntfy"`
+	Pushover    *pushoverConfig `json:"pushover"`
+	Email       *emailConfig    `json:"email"`
+}
+
+type webhookConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+type telegramConfig struct {
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"`
+}
+
+type ntfyConfig struct {
+	Server string `json:"server"`
+	Topic  string `json:"topic"`
+	Token  string `json:"token"`
+}
+
+type pushoverConfig struct {
+	Token string `json:"token"`
+	User  string `json:"user"`
+}
+
+type notifySettings struct {
+	notifiers []notify.Notifier
+	passLead  time.Duration
+	withMap   bool
+}
+
+func notifySettingsFromConfig(cfg config) (notifySettings, error) {
+	nc := cfg.Notify
+	if nc == nil {
+		return notifySettings{}, nil
+	}
+	if nc.PassMinutes < 0 || nc.PassMinutes > maxPassMinutes {
+		return notifySettings{}, fmt.Errorf("notify: pass_minutes must be between 0 and %d", maxPassMinutes)
+	}
+	settings := notifySettings{passLead: time.Duration(nc.PassMinutes * float64(time.Minute)), withMap: nc.Map == nil || *nc.Map}
+
+	if nc.Discord != nil {
+		if err := checkURL(nc.Discord.WebhookURL); err != nil {
+			return notifySettings{}, fmt.Errorf("notify: discord: webhook_url %w", err)
+		}
+		settings.notifiers = append(settings.notifiers, notify.Discord{WebhookURL: nc.Discord.WebhookURL})
+	}
+	if nc.Slack != nil {
+		if err := checkURL(nc.Slack.WebhookURL); err != nil {
+			return notifySettings{}, fmt.Errorf("notify: slack: webhook_url %w", err)
+		}
+		settings.notifiers = append(settings.notifiers, notify.Slack{WebhookURL: nc.Slack.WebhookURL})
+	}
+	if nc.Telegram != nil {
+		if nc.Telegram.BotToken == "" || nc.Telegram.ChatID == "" {
+			return notifySettings{}, errors.New("notify: telegram needs bot_token and chat_id")
+		}
+		settings.notifiers = append(settings.notifiers, notify.Telegram{BotToken: nc.Telegram.BotToken, ChatID: nc.Telegram.ChatID})
+	}
+	if nc.Ntfy != nil {
+		if nc.Ntfy.Topic == "" || strings.Contains(nc.Ntfy.Topic, "/") {
+			return notifySettings{}, errors.New("notify: ntfy needs a topic name without slashes")
+		}
+		if nc.Ntfy.Server != "" {
+			if err := checkURL(nc.Ntfy.Server); err != nil {
+				return notifySettings{}, fmt.Errorf("notify: ntfy: server %w", err)
+			}
+		}
+		settings.notifiers = append(settings.notifiers, notify.Ntfy{Server: nc.Ntfy.Server, Topic: nc.Ntfy.Topic, Token: nc.Ntfy.Token})
+	}
+	if nc.Pushover != nil {
+		if nc.Pushover.Token == "" || nc.Pushover.User == "" {
+			return notifySettings{}, errors.New("notify: pushover needs token and user")
+		}
+		settings.notifiers = append(settings.notifiers, notify.Pushover{Token: nc.Pushover.Token, User: nc.Pushover.User})
+	}
+	return settings, nil
+}
+
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("must be an http(s) URL")
+	}
+	return nil
+}
+
+func notifierSubscriber(settings notifySettings, client *http.Client, mask *mapascii.LandMask, observer *track.Observer) subscriber {
+	return func(e busEvent) tea.Cmd {
+		title := "ISS"
+		switch e.topic {
+		case topicPassSoon:
+			title = "ISS pass"
+		case topicOrbit:
+			title = "ISS orbit"
+		}
+		return func() tea.Msg {
+			msg := notify.Message{Title: title, Text: e.text}
+			if settings.withMap && mask != nil {
+				msg.Image = notifyMap(mask, e.pos, observer)
+			}
+			var errs []error
+			for _, n := range settings.notifiers {
+				if err := n.Send(client, msg); err != nil {
+					errs = append(errs, fmt.Errorf("notify %s: %w", n.Name(), err))
+				}
+			}
+			if len(errs) > 0 {
+				return errMsg{err: errors.Join(errs...)}
+			}
+			return nil
+		}
+	}
+}
+
+func notifyMap(mask *mapascii.LandMask, pos track.Position, observer *track.Observer) []byte {
+	var points []render.Point
+	if observer != nil {
+		points = append(points, render.Point{Lat: observer.Lat, Lon: observer.Lon, Radius: 2, Color: color.RGBA{R: 0x88, G: 0xc0, B: 0xd0, A: 0xff}})
+	}
+	points = append(points, render.Point{Lat: pos.Lat, Lon: pos.Lon, Radius: 4, Color: color.RGBA{R: 0xeb, G: 0xcb, B: 0x8b, A: 0xff}})
+
+	img, err := render.Image(mask, notifyMapWidth, render.Viewport{}, points...)
+	if err != nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (m model) checkPassAlert(now time.Time, pos track.Position) (model, []busEvent) {
+	pass := m.skyPass
+	if m.passLead <= 0 || pass == nil || !pass.Visible || !pass.Start.After(now) || pass.Start.Sub(now) > m.passLead || pass.Start.Equal(m.passAlerted) {
+		return m, nil
+	}
+	m.passAlerted = pass.Start
+	minutes := int(pass.Start.Sub(now).Round(time.Minute).Minutes())
+	text := fmt.Sprintf(m.lang.text("pass_alert"), minutes, m.times.format(pass.Start, layoutShort), compassPoint(pass.StartAzimuth), pass.MaxElevation)
+	return m, []busEvent{{topic: topicPassSoon, at: now, text: text, pos: pos, pass: pass}}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/render"
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+const (
+	offshoreMaxKm  = 370
+	offshoreStepKm = 10
+	landThreshold  = 0.5
+)
+
+func nearestCoast(mask *mapascii.LandMask, lat, lon float64) (float64, float64, bool) {
+	for km := float64(offshoreStepKm); km <= offshoreMaxKm; km += offshoreStepKm {
+		steps := int(math.Ceil(2 * math.Pi * km / offshoreStepKm))
+		for i := 0; i < steps; i++ {
+			landLat, landLon := geo.Destination(lat, lon, 360*float64(i)/float64(steps), km)
+			if render.Land(mask, landLat, landLon) >= landThreshold {
+				return landLat, landLon, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func describeOffshore(client *http.Client, mask *mapascii.LandMask, lang language, lat, lon float64, sea geo.Location) (geo.Location, error) {
+	if mask == nil || sea.Name == "" || sea.CountryCode != "" {
+		return sea, nil
+	}
+	coastLat, coastLon, ok := nearestCoast(mask, lat, lon)
+	if !ok {
+		return sea, nil
+	}
+	country, err := geo.Locate(client, coastLat, coastLon, lang.acceptLanguage(), lang.names)
+	if err != nil {
+		return sea, err
+	}
+	if country.CountryCode != "" {
+		sea.Name = fmt.Sprintf(lang.text("offshore"), sea.Name, country.Name)
+	}
+	return sea, nil
+}
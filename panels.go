@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	defaultPanelInterval = time.Minute
+	minPanelInterval     = time.Second
+	panelTimeout         = 10 * time.Second
+	maxPanelRows         = 8
+	maxPanelOutput       = 64 << 10
+)
+
+type panelConfig struct {
+	Name            string   `json:"name"`
+	Command         []string `json:"command"`
+	IntervalSeconds float64  `json:"interval_seconds"`
+}
+
+type panelSource struct {
+	name     string
+	command  []string
+	interval time.Duration
+}
+
+type panelRequest struct {
+	Time     time.Time       `json:"time"`
+	ISS      *panelPosition  `json:"iss"`
+	Observer *observerConfig `json:"observer"`
+}
+
+type panelPosition struct {
+	Lat         float64 `json:"lat"`
+	Lon         float64 `json:"lon"`
+	AltitudeKm  float64 `json:"altitude_km,omitempty"`
+	VelocityKmh float64 `json:"velocity_kmh,omitempty"`
+	Over        string  `json:"over"`
+}
+
+type panelResponse struct {
+	Title string     `json:"title"`
+	Rows  []panelRow `json:"rows"`
+	Error string     `json:"error"`
+}
+
+type panelRow struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type panelMsg struct {
+	index int
+	panel panelResponse
+	err   error
+}
+
+type panelTickMsg struct {
+	index int
+}
+
+func panelsFromConfig(cfg config) ([]panelSource, error) {
+	sources := make([]panelSource, 0, len(cfg.Panels))
+	seen := map[string]bool{}
+	for _, pc := range cfg.Panels {
+		name := strings.TrimSpace(pc.Name)
+		switch {
+		case name == "":
+			return nil, errors.New("panels: every panel needs a name")
+		case seen[name]:
+			return nil, fmt.Errorf("panels: %q is listed twice", name)
+		case len(pc.Command) == 0 || pc.Command[0] == "":
+			return nil, fmt.Errorf("panels: %q has no command", name)
+		case pc.IntervalSeconds < 0:
+			return nil, fmt.Errorf("panels: %q interval_seconds must not be negative", name)
+		}
+		seen[name] = true
+
+		interval := defaultPanelInterval
+		if pc.IntervalSeconds > 0 {
+			interval = max(time.Duration(pc.IntervalSeconds*float64(time.Second)), minPanelInterval)
+		}
+		sources = append(sources, panelSource{name: name, command: pc.Command, interval: interval})
+	}
+	return sources, nil
+}
+
+func panelTick(index int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return panelTickMsg{index: index}
+	})
+}
+
+func runPanelCmd(index int, source panelSource, request panelRequest) tea.Cmd {
+	return func() tea.Msg {
+		panel, err := runPanel(source, request)
+		if err != nil {
+			err = fmt.Errorf("panel %s: %w", source.name, err)
+		}
+		return panelMsg{index: index, panel: panel, err: err}
+	}
+}
+
+func runPanel(source panelSource, request panelRequest) (panelResponse, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return panelResponse{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), panelTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, source.command[0], source.command[1:]...)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return panelResponse{}, fmt.Errorf("no answer in %s", panelTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return panelResponse{}, fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return panelResponse{}, err
+	}
+	if stdout.Len() > maxPanelOutput {
+		return panelResponse{}, fmt.Errorf("output is larger than %d KiB", maxPanelOutput>>10)
+	}
+
+	var panel panelResponse
+	if err := json.Unmarshal(stdout.Bytes(), &panel); err != nil {
+		return panelResponse{}, fmt.Errorf("decode output: %w", err)
+	}
+	if panel.Error != "" {
+		return panelResponse{}, errors.New(panel.Error)
+	}
+	if len(panel.Rows) > maxPanelRows {
+		panel.Rows = panel.Rows[:maxPanelRows]
+	}
+	return panel, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func panelText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, ansi.Strip(s))
+}
+
+func (m model) panelRequest(now time.Time) panelRequest {
+	request := panelRequest{Time: now.UTC()}
+	if m.hasCoords {
+		request.ISS = &panelPosition{Lat: m.lat, Lon: m.lon, Over: m.issOver}
+		if m.hasAltitude {
+			request.ISS.AltitudeKm = m.altitudeKm
+			request.ISS.VelocityKmh = m.velocityKmh
+		}
+	}
+	if m.observer != nil {
+		request.Observer = &observerConfig{Lat: m.observer.Lat, Lon: m.observer.Lon, AltM: m.observer.AltKm * 1000}
+	}
+	return request
+}
+
+func (m model) startPanels() []tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.panels))
+	for i := range m.panels {
+		cmds[i] = panelTick(i, 0)
+	}
+	return cmds
+}
+
+func (m model) applyPanel(msg panelMsg) (model, tea.Cmd) {
+	if msg.index >= len(m.panels) {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.lastErr = msg.err.Error()
+	} else {
+		data := append([]*panelResponse(nil), m.panelData...)
+		data[msg.index] = &msg.panel
+		m.panelData = data
+	}
+	return m, panelTick(msg.index, m.panels[msg.index].interval)
+}
+
+func (m model) panelLines() []string {
+	var lines []string
+	for i, panel := range m.panelData {
+		if panel == nil || len(panel.Rows) == 0 {
+			continue
+		}
+		title := panelText(panel.Title)
+		if title == "" {
+			title = m.panels[i].name
+		}
+		fields := make([][2]string, len(panel.Rows))
+		for j, row := range panel.Rows {
+			fields[j] = [2]string{panelText(row.Label), panelText(row.Value)}
+		}
+		lines = append(lines, "", title)
+		lines = append(lines, alignFields(fields)...)
+	}
+	return lines
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/Kivayan/iss/pkg/weather"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultPassCount = 5
+	tleAccuracyDays  = 14
+)
+
+var passStartLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+type observerFlags struct {
+	lat  float64
+	lon  float64
+	altM float64
+}
+
+type passesOptions struct {
+	observer     observerFlags
+	from         string
+	count        int
+	days         int
+	minElevation float64
+	visible      bool
+	json         bool
+	ics          string
+	alarm        time.Duration
+	clouds       bool
+	compare      bool
+}
+
+type passJSON struct {
+	Start        time.Time `json:"start"`
+	Max          time.Time `json:"max"`
+	End          time.Time `json:"end"`
+	DurationSec  float64   `json:"duration_s"`
+	StartAzimuth float64   `json:"start_azimuth"`
+	MaxAzimuth   float64   `json:"max_azimuth"`
+	EndAzimuth   float64   `json:"end_azimuth"`
+	MaxElevation float64   `json:"max_elevation"`
+	SunElevation float64   `json:"sun_elevation"`
+	Visible      bool      `json:"visible"`
+	Magnitude    *float64  `json:"magnitude,omitempty"`
+	CloudCover   *float64  `json:"cloud_cover,omitempty"`
+	Score        int       `json:"score"`
+}
+
+func addObserverFlags(cmd *cobra.Command, flags *observerFlags) {
+	cmd.Flags().Float64Var(&flags.lat, "lat", 0, "observer latitude in degrees (default from config observer.lat)")
+	cmd.Flags().Float64Var(&flags.lon, "lon", 0, "observer longitude in degrees (default from config observer.lon)")
+	cmd.Flags().Float64Var(&flags.altM, "alt", 0, "observer altitude in metres (default from config observer.alt_m)")
+}
+
+func resolveObserver(cmd *cobra.Command, flags observerFlags, cfg config) (track.Observer, error) {
+	observer := track.Observer{}
+	known := false
+	if cfg.Observer != nil {
+		observer = track.Observer{Lat: cfg.Observer.Lat, Lon: cfg.Observer.Lon, AltKm: cfg.Observer.AltM / 1000}
+		known = true
+	}
+
+	latSet, lonSet := cmd.Flags().Changed("lat"), cmd.Flags().Changed("lon")
+	if latSet != lonSet && !known {
+		return track.Observer{}, errors.New("--lat and --lon must be given together")
+	}
+	if latSet {
+		observer.Lat = flags.lat
+	}
+	if lonSet {
+		observer.Lon = flags.lon
+	}
+	if cmd.Flags().Changed("alt") {
+		observer.AltKm = flags.altM / 1000
+	}
+
+	if !known && !latSet {
+		return track.Observer{}, errors.New("no observer location: pass --lat and --lon or set observer in the config file")
+	}
+	if math.Abs(observer.Lat) > 90 || math.Abs(observer.Lon) > 180 {
+		return track.Observer{}, fmt.Errorf("observer %.4f, %.4f is out of range", observer.Lat, observer.Lon)
+	}
+
+	return observer, nil
+}
+
+func runPasses(cmd *cobra.Command, opts globalOptions, w io.Writer, passes passesOptions) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err := bookmarksFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	observer, err := resolveObserver(cmd, passes.observer, cfg)
+	if err != nil && !(passes.compare && len(bookmarks) > 0) {
+		return err
+	}
+	var profiles []observerProfile
+	if passes.compare {
+		if passes.ics != "" || passes.clouds {
+			return errors.New("--compare cannot be combined with --ics or --clouds")
+		}
+		var current *track.Observer
+		if err == nil {
+			current = &observer
+		}
+		profiles = observerProfiles(current, bookmarks)
+	}
+	weights, err := passScoreWeightsFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, newHTTPClient(cmd.Context(), stats))
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", warning)
+	}
+
+	from := time.Now()
+	if passes.from != "" {
+		if from, err = parsePassStart(passes.from, times.location()); err != nil {
+			return err
+		}
+	}
+	if passes.days <= 0 {
+		return fmt.Errorf("--days must be positive, got %d", passes.days)
+	}
+	to := from.AddDate(0, 0, passes.days)
+	if passes.alarm < 0 {
+		return fmt.Errorf("--alarm must not be negative, got %s", passes.alarm)
+	}
+	visibleOnly := passes.visible || (passes.ics != "" && !cmd.Flags().Changed("visible"))
+
+	set, err := loadTLE(newHTTPClient(cmd.Context(), stats), track.ISSNoradID, tleRefreshFromConfig(cfg))
+	if set.Line1 == "" {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	sat, err := track.NewSatellite(set)
+	if err != nil {
+		return err
+	}
+
+	if drift := max(epochDistance(sat.Epoch, from), epochDistance(sat.Epoch, to)); drift > tleAccuracyDays*24*time.Hour {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: the TLE epoch is %.0f days from the search window; predicted times will drift\n", drift.Hours()/24)
+	}
+
+	if passes.compare {
+		found, err := nextProfilePasses(sat, profiles, from, to, passes.minElevation, passes.visible)
+		if err != nil {
+			return err
+		}
+		return writeProfilePasses(w, found, weights, times, passes.json)
+	}
+
+	found, err := track.FindPasses(sat, observer, from, to, passes.minElevation)
+	if err != nil {
+		return err
+	}
+	if visibleOnly {
+		found = visiblePasses(found)
+	}
+
+	count := passes.count
+	if !cmd.Flags().Changed("count") && (cmd.Flags().Changed("from") || cmd.Flags().Changed("days")) {
+		count = 0
+	}
+	if count > 0 && len(found) > count {
+		found = found[:count]
+	}
+
+	var clouds *weather.Forecast
+	if passes.clouds && len(found) > 0 {
+		days := int(math.Ceil(time.Until(found[len(found)-1].End).Hours()/24)) + 1
+		forecast, err := weather.CloudForecast(newHTTPClient(cmd.Context(), stats), weather.Point{Lat: observer.Lat, Lon: observer.Lon}, days)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: cloud forecast: %v\n", err)
+		} else {
+			clouds = &forecast
+		}
+	}
+
+	if passes.ics != "" {
+		var buf bytes.Buffer
+		if err := writePassesICS(&buf, found, observer, clouds, times, passes.alarm, time.Now()); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(passes.ics, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Wrote %d passes to %s\n", len(found), passes.ics)
+		return nil
+	}
+
+	if passes.json {
+		out := make([]passJSON, 0, len(found))
+		for _, pass := range found {
+			out = append(out, newPassJSON(pass, clouds, weights))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	if len(found) == 0 {
+		kind := "passes"
+		if visibleOnly {
+			kind = "visible passes"
+		}
+		fmt.Fprintf(w, "No %s above %.0f° between %s and %s.\n", kind, passes.minElevation, times.format(from, layoutStamp), times.format(to, layoutStamp))
+		return nil
+	}
+
+	for _, line := range passTable(found, clouds, weights, times) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+func newPassJSON(pass track.Pass, clouds *weather.Forecast, weights passScoreWeights) passJSON {
+	return passJSON{
+		Start:        pass.Start,
+		Max:          pass.Max,
+		End:          pass.End,
+		DurationSec:  pass.Duration().Seconds(),
+		StartAzimuth: pass.StartAzimuth,
+		MaxAzimuth:   pass.MaxAzimuth,
+		EndAzimuth:   pass.EndAzimuth,
+		MaxElevation: pass.MaxElevation,
+		SunElevation: pass.SunElevation,
+		Visible:      pass.Visible,
+		Magnitude:    passMagnitude(pass),
+		CloudCover:   passCloudCover(clouds, pass),
+		Score:        passScore(pass, clouds, weights),
+	}
+}
+
+func passTable(passes []track.Pass, clouds *weather.Forecast, weights passScoreWeights, times timeDisplay) []string {
+	date := "Date"
+	if len(passes) > 0 {
+		date += " (" + times.zone(passes[0].Start) + ")"
+	}
+	header := []string{date, "Rise", "Az", "Max", "Elev", "Set", "Az", "Duration", "Visible", "Mag"}
+	if clouds != nil {
+		header = append(header, "Cloud")
+	}
+	header = append(header, "Score")
+	rows := [][]string{header}
+	for _, pass := range passes {
+		row := []string{
+			times.format(pass.Start, layoutDay),
+			times.format(pass.Start, layoutClock),
+			compassPoint(pass.StartAzimuth),
+			times.format(pass.Max, layoutClock),
+			fmt.Sprintf("%.0f°", pass.MaxElevation),
+			times.format(pass.End, layoutClock),
+			compassPoint(pass.EndAzimuth),
+			pass.Duration().Round(time.Second).String(),
+			passSky(pass),
+			formatMagnitude(passMagnitude(pass)),
+		}
+		if clouds != nil {
+			row = append(row, formatCloudCover(passCloudCover(clouds, pass)))
+		}
+		row = append(row, strconv.Itoa(passScore(pass, clouds, weights)))
+		rows = append(rows, row)
+	}
+
+	return formatTable(rows, nil)
+}
+
+func passSky(pass track.Pass) string {
+	switch {
+	case pass.Visible:
+		return "yes"
+	case pass.SunElevation >= track.TwilightElevation:
+		return "daylight"
+	default:
+		return "shadow"
+	}
+}
+
+func passMagnitude(pass track.Pass) *float64 {
+	if !pass.Visible {
+		return nil
+	}
+	magnitude := math.Round(pass.Magnitude*10) / 10
+	return &magnitude
+}
+
+func formatMagnitude(magnitude *float64) string {
+	if magnitude == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *magnitude)
+}
+
+func passCloudCover(clouds *weather.Forecast, pass track.Pass) *float64 {
+	if clouds == nil {
+		return nil
+	}
+	cover, ok := clouds.At(pass.Max)
+	if !ok {
+		return nil
+	}
+	return &cover
+}
+
+func formatCloudCover(cover *float64) string {
+	if cover == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *cover)
+}
+
+func visiblePasses(passes []track.Pass) []track.Pass {
+	visible := passes[:0]
+	for _, pass := range passes {
+		if pass.Visible {
+			visible = append(visible, pass)
+		}
+	}
+	return visible
+}
+
+func parsePassStart(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range passStartLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("--from %q: use YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339", value)
+}
+
+func epochDistance(epoch, t time.Time) time.Duration {
+	d := t.Sub(epoch)
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func compassPoint(azimuth float64) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	index := int(math.Round(azimuth/22.5)) % len(points)
+	return points[index]
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/Kivayan/iss/pkg/weather"
+)
+
+type passScoreWeights struct {
+	Elevation  float64 `json:"elevation"`
+	Brightness float64 `json:"brightness"`
+	Darkness   float64 `json:"darkness"`
+	Clouds     float64 `json:"clouds"`
+}
+
+var defaultPassScoreWeights = passScoreWeights{Elevation: 0.35, Brightness: 0.25, Darkness: 0.2, Clouds: 0.2}
+
+func passScoreWeightsFromConfig(cfg config) (passScoreWeights, error) {
+	if cfg.PassScore == nil {
+		return defaultPassScoreWeights, nil
+	}
+	w := *cfg.PassScore
+	if w.Elevation < 0 || w.Brightness < 0 || w.Darkness < 0 || w.Clouds < 0 {
+		return defaultPassScoreWeights, errors.New("pass_score weights must not be negative")
+	}
+	if w.Elevation+w.Brightness+w.Darkness+w.Clouds == 0 {
+		return defaultPassScoreWeights, errors.New("pass_score weights must not all be zero")
+	}
+	return w, nil
+}
+
+func passScore(pass track.Pass, clouds *weather.Forecast, w passScoreWeights) int {
+	elevation := clamp01(pass.MaxElevation / 90)
+	brightness := 0.0
+	if pass.Visible {
+		brightness = clamp01(-pass.Magnitude / 4)
+	}
+	darkness := clamp01((track.TwilightElevation - pass.SunElevation) / 12)
+
+	sum := w.Elevation*elevation + w.Brightness*brightness + w.Darkness*darkness
+	total := w.Elevation + w.Brightness + w.Darkness
+	if cover := passCloudCover(clouds, pass); cover != nil {
+		sum += w.Clouds * clamp01(1-*cover/100)
+		total += w.Clouds
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(100 * sum / total))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
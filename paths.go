@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const appDirName = "iss"
+
+func appDir(xdgVar string, fallback func() (string, error)) (string, error) {
+	if dir := os.Getenv(xdgVar); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDirName), nil
+	}
+	dir, err := fallback()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appDirName), nil
+}
+
+func configDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", os.UserConfigDir)
+}
+
+func cacheDir() (string, error) {
+	dir, err := appDir("XDG_CACHE_HOME", os.UserCacheDir)
+	if err != nil || runtime.GOOS != "windows" || filepath.IsAbs(os.Getenv("XDG_CACHE_HOME")) {
+		return dir, err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
+func dataDir() (string, error) {
+	return appDir("XDG_DATA_HOME", userDataDir)
+}
+
+func userDataDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%LocalAppData% is not defined")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support"), nil
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+func dataPath(name string) (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	migrateFromCache(name, path)
+	return path, nil
+}
+
+func migrateFromCache(name, path string) {
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	cache, err := cacheDir()
+	if err != nil {
+		return
+	}
+	legacy := filepath.Join(cache, name)
+	if legacy == path {
+		return
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	os.Rename(legacy, path)
+}
+
+func writePaths(w io.Writer, cfgPath string) error {
+	if cfgPath == "" {
+		var err error
+		if cfgPath, err = configPath(); err != nil {
+			return err
+		}
+	}
+	cache, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := dataDir()
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"config", cfgPath},
+		{"cache", cache},
+		{"data", data},
+	}
+	for _, line := range formatTable(rows, nil) {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
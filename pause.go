@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func heldWhilePaused(msg tea.Msg) bool {
+	switch msg.(type) {
+	case telemetryTickMsg, telemetryDoneMsg, locationMsg, headerTickMsg, sunMoonTickMsg,
+		cloudsTickMsg, cloudsMsg, tleRefreshMsg, vehiclesRefreshMsg, crewRefreshMsg, spaceWeatherRefreshMsg:
+		return true
+	}
+	return false
+}
+
+func (m model) paused() bool {
+	return !m.pausedAt.IsZero()
+}
+
+func (m model) displayTime() time.Time {
+	if m.paused() {
+		return m.pausedAt
+	}
+	return clockNow()
+}
+
+func (m model) togglePause() (model, tea.Cmd) {
+	if !m.paused() {
+		m.pausedAt = clockNow()
+		if demo != nil {
+			demo.clock.freeze(true)
+		}
+		return m.stopMapAnimation(), nil
+	}
+
+	m.pausedAt = time.Time{}
+	if demo != nil {
+		demo.clock.freeze(false)
+	}
+	cmds := make([]tea.Cmd, 0, len(m.held)+1)
+	for _, msg := range m.held {
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	m.held = nil
+	next, cmd := m.syncMapState()
+	return next, tea.Batch(append(cmds, cmd)...)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+type photoOptions struct {
+	observer observerFlags
+	radiusKm float64
+	days     int
+	ground   bool
+	json     bool
+}
+
+type photoJSON struct {
+	Time         time.Time `json:"time"`
+	DistanceKm   float64   `json:"distance_km"`
+	Elevation    float64   `json:"elevation"`
+	SunElevation float64   `json:"sun_elevation"`
+	AltitudeKm   float64   `json:"altitude_km"`
+}
+
+func newPhotoCmd(opts *globalOptions) *cobra.Command {
+	photo := photoOptions{radiusKm: 100, days: 7}
+	cmd := &cobra.Command{
+		Use:   "photo",
+		Short: "Find times to photograph a place from the station, or the station from a place",
+		Long:  "Find when the ground track passes within --radius km of a place, by propagating the\ncurrent Celestrak TLE with SGP4. By default it lists approaches in daylight, when the\ncrew can photograph the place. --ground lists approaches where the station is sunlit\nagainst a dark sky, when people there can photograph the station nearly overhead.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPhoto(cmd, *opts, cmd.OutOrStdout(), photo)
+		},
+	}
+	addObserverFlags(cmd, &photo.observer)
+	cmd.Flags().Float64Var(&photo.radiusKm, "radius", photo.radiusKm, "how far the subpoint may pass from the place, in km")
+	cmd.Flags().IntVar(&photo.days, "days", photo.days, "length of the search window in days")
+	cmd.Flags().BoolVar(&photo.ground, "ground", false, "photograph the station from the place instead of the place from the station")
+	cmd.Flags().BoolVar(&photo.json, "json", false, "print JSON")
+
+	return cmd
+}
+
+func runPhoto(cmd *cobra.Command, opts globalOptions, w io.Writer, photo photoOptions) error {
+	if photo.radiusKm <= 0 {
+		return fmt.Errorf("--radius must be positive, got %g", photo.radiusKm)
+	}
+	if photo.days <= 0 {
+		return fmt.Errorf("--days must be positive, got %d", photo.days)
+	}
+
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	observer, err := resolveObserver(cmd, photo.observer, cfg)
+	if err != nil {
+		return err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+	client := newHTTPClient(cmd.Context(), stats)
+
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, client)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", warning)
+	}
+
+	set, err := loadTLE(client, track.ISSNoradID, tleRefreshFromConfig(cfg))
+	if set.Line1 == "" {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	sat, err := track.NewSatellite(set)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now()
+	to := from.AddDate(0, 0, photo.days)
+	if drift := epochDistance(sat.Epoch, to); drift > tleAccuracyDays*24*time.Hour {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: the TLE epoch is %.0f days from the search window; predicted times will drift\n", drift.Hours()/24)
+	}
+	mode := track.PhotoFromOrbit
+	if photo.ground {
+		mode = track.PhotoFromGround
+	}
+	found, err := track.FindPhotoOps(sat, observer, from, to, photo.radiusKm, mode)
+	if err != nil {
+		return err
+	}
+
+	if photo.json {
+		out := make([]photoJSON, 0, len(found))
+		for _, op := range found {
+			out = append(out, photoJSON{Time: op.Time.UTC(), DistanceKm: op.DistanceKm, Elevation: op.Elevation, SunElevation: op.SunElevation, AltitudeKm: op.AltitudeKm})
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	if len(found) == 0 {
+		lighting := "in daylight"
+		if photo.ground {
+			lighting = "against a dark sky"
+		}
+		fmt.Fprintf(w, "The station does not pass within %.0f km %s between %s and %s.\n", photo.radiusKm, lighting, times.format(from, layoutStamp), times.format(to, layoutStamp))
+		return nil
+	}
+
+	rows := [][]string{{"Date (" + times.zone(found[0].Time) + ")", "Time", "Distance", "Elev", "Sun", "Altitude"}}
+	for _, op := range found {
+		rows = append(rows, []string{
+			times.format(op.Time, layoutDay),
+			times.format(op.Time, layoutClock),
+			fmt.Sprintf("%.0f km", op.DistanceKm),
+			fmt.Sprintf("%.0f°", op.Elevation),
+			fmt.Sprintf("%.0f°", op.SunElevation),
+			fmt.Sprintf("%.0f km", op.AltitudeKm),
+		})
+	}
+	for _, line := range formatTable(rows, nil) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package geo
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// countriesTXT lists each ISO 3166-1 country code with the names Nominatim
+// gives it, one country per line as CODE|name|variant..., English first and
+// the local names last. A * after the code marks a territory rather than one
+// of the 193 UN members and two observer states.
+//
+//go:embed countries.txt
+var countriesTXT string
+
+var (
+	countriesOnce sync.Once
+	countryCodes  map[string]string
+	sovereign     map[string]bool
+)
+
+var foldedLetters = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ą': "a", 'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ē': "e", 'ę': "e", 'ě': "e", 'ə': "a", 'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ı': "i", 'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o",
+	'ö': "o", 'ø': "o", 'œ': "oe", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ț': "t", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ů': "u", 'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'ʻ': "", '’': "", '\'': "", 'ệ': "e",
+}
+
+func loadCountries() map[string]string {
+	countriesOnce.Do(func() {
+		countryCodes = make(map[string]string)
+		sovereign = make(map[string]bool)
+		for _, line := range strings.Split(countriesTXT, "\n") {
+			fields := strings.Split(strings.TrimSpace(line), "|")
+			if len(fields) < 2 {
+				continue
+			}
+			code, territory := strings.CutSuffix(fields[0], "*")
+			if !territory {
+				sovereign[code] = true
+			}
+			for _, name := range fields[1:] {
+				countryCodes[foldCountryName(name)] = code
+			}
+		}
+	})
+	return countryCodes
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of the country called name,
+// or "" when name is not a country. It ignores case, accents, punctuation and
+// a leading "the", and takes Nominatim's multilingual names such as
+// "Schweiz/Suisse/Svizzera/Svizra" apart.
+func CountryCode(name string) string {
+	countries := loadCountries()
+	if code, ok := countries[foldCountryName(name)]; ok {
+		return code
+	}
+	for _, part := range strings.Split(name, "/") {
+		if code, ok := countries[foldCountryName(part)]; ok {
+			return code
+		}
+	}
+	return ""
+}
+
+// Sovereign reports whether code is a sovereign state: a UN member or one of
+// the observer states Palestine and the Vatican.
+func Sovereign(code string) bool {
+	loadCountries()
+	return sovereign[strings.ToUpper(code)]
+}
+
+// SovereignCount is the number of sovereign states Sovereign knows, 195.
+func SovereignCount() int {
+	loadCountries()
+	return len(sovereign)
+}
+
+// Flag returns the flag emoji of an ISO 3166-1 alpha-2 code, or "" when code
+// is not two letters.
+func Flag(code string) string {
+	if len(code) != 2 {
+		return ""
+	}
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+		b.WriteRune(0x1f1e6 + r - 'A')
+	}
+	return b.String()
+}
+
+func foldCountryName(name string) string {
+	var words []string
+	var word strings.Builder
+	for _, r := range strings.ToLower(name) + " " {
+		if folded, ok := foldedLetters[r]; ok {
+			word.WriteString(folded)
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			word.WriteRune(r)
+		} else if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	kept := words[:0]
+	for i, w := range words {
+		switch {
+		case i == 0 && w == "the", w == "and":
+			continue
+		case w == "st":
+			w = "saint"
+		}
+		kept = append(kept, w)
+	}
+	return strings.Join(kept, " ")
+}
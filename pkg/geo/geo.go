@@ -0,0 +1,58 @@
+// Package geo provides the geodesy helpers and reverse geocoding used to
+// describe where the ISS is.
+package geo
+
+import "math"
+
+const (
+	// EarthRadiusKm is the mean Earth radius.
+	EarthRadiusKm = 6371.0
+
+	KmToMiles         = 0.621371
+	KmToNauticalMiles = 0.539957
+)
+
+// HaversineKm returns the great-circle distance between two points in degrees.
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadiusKm * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// InitialBearing returns the compass bearing in degrees, clockwise from
+// north, at which the great circle from the first point to the second starts.
+func InitialBearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// Destination returns the point reached by going km along the great circle
+// that leaves lat/lon at bearing degrees clockwise from north.
+func Destination(lat, lon, bearing, km float64) (float64, float64) {
+	phi1 := lat * math.Pi / 180
+	lambda1 := lon * math.Pi / 180
+	theta := bearing * math.Pi / 180
+	delta := km / EarthRadiusKm
+
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+	return phi2 * 180 / math.Pi, math.Mod(lambda2*180/math.Pi+540, 360) - 180
+}
+
+// InLonRange reports whether lon lies in [minLon, maxLon], treating
+// minLon > maxLon as a range that crosses the antimeridian.
+func InLonRange(lon, minLon, maxLon float64) bool {
+	if minLon <= maxLon {
+		return lon >= minLon && lon <= maxLon
+	}
+	return lon >= minLon || lon <= maxLon
+}
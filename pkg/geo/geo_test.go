@@ -0,0 +1,77 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversineKm(t *testing.T) {
+	quarter := math.Pi / 2 * EarthRadiusKm
+	for _, tc := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want, tolerance        float64
+	}{
+		{"same point", 51.5, -0.1, 51.5, -0.1, 0, 1e-9},
+		{"quarter of the equator", 0, 0, 0, 90, quarter, 1e-6},
+		{"equator to pole", 0, 0, 90, 0, quarter, 1e-6},
+		{"pole to pole", 90, 0, -90, 0, 2 * quarter, 1e-6},
+		{"across the antimeridian", 0, 179, 0, -179, 2 * math.Pi / 180 * EarthRadiusKm, 1e-6},
+		{"london to paris", 51.5074, -0.1278, 48.8566, 2.3522, 343.5, 0.5},
+	} {
+		if got := HaversineKm(tc.lat1, tc.lon1, tc.lat2, tc.lon2); math.Abs(got-tc.want) > tc.tolerance {
+			t.Errorf("%s: HaversineKm = %v, want %v", tc.name, got, tc.want)
+		}
+		if got, back := HaversineKm(tc.lat1, tc.lon1, tc.lat2, tc.lon2), HaversineKm(tc.lat2, tc.lon2, tc.lat1, tc.lon1); math.Abs(got-back) > 1e-9 {
+			t.Errorf("%s: not symmetric: %v and %v", tc.name, got, back)
+		}
+	}
+}
+
+func TestInitialBearing(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"north", 0, 0, 10, 0, 0},
+		{"east", 0, 0, 0, 10, 90},
+		{"south", 10, 0, 0, 0, 180},
+		{"west", 0, 0, 0, -10, 270},
+		{"east across the antimeridian", 0, 179, 0, -179, 90},
+		{"northeast", 0, 0, 1, 1, 44.99563645534486},
+	} {
+		if got := InitialBearing(tc.lat1, tc.lon1, tc.lat2, tc.lon2); math.Abs(got-tc.want) > 1e-6 {
+			t.Errorf("%s: InitialBearing = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDestinationRoundTrip(t *testing.T) {
+	lat1, lon1 := 51.5074, -0.1278
+	lat2, lon2 := 48.8566, 2.3522
+	bearing := InitialBearing(lat1, lon1, lat2, lon2)
+	km := HaversineKm(lat1, lon1, lat2, lon2)
+
+	lat, lon := Destination(lat1, lon1, bearing, km)
+	if math.Abs(lat-lat2) > 1e-9 || math.Abs(lon-lon2) > 1e-9 {
+		t.Errorf("Destination = %v, %v, want %v, %v", lat, lon, lat2, lon2)
+	}
+}
+
+func TestInLonRange(t *testing.T) {
+	for _, tc := range []struct {
+		lon, min, max float64
+		want          bool
+	}{
+		{10, 0, 20, true},
+		{-10, 0, 20, false},
+		{175, 170, -170, true},
+		{-175, 170, -170, true},
+		{0, 170, -170, false},
+	} {
+		if got := InLonRange(tc.lon, tc.min, tc.max); got != tc.want {
+			t.Errorf("InLonRange(%v, %v, %v) = %v, want %v", tc.lon, tc.min, tc.max, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Command seasgen derives seas.json from the Marine Regions IHO Sea Areas
+// dataset: each area's outer rings simplified with Douglas-Peucker, holes
+// dropped, the areas sorted smallest first and the translated names of the
+// current seas.json carried over.
+//
+// The dataset is © Flanders Marine Institute (VLIZ), IHO Sea Areas version 3,
+// https://www.marineregions.org/, licensed under CC BY 4.0. Keep that
+// attribution wherever the generated file is shipped.
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultSource = "https://geo.vliz.be/geoserver/MarineRegions/wfs?service=WFS&version=1.0.0&request=GetFeature&typeName=MarineRegions:iho&outputFormat=application/json"
+
+// renames folds the IHO subdivisions into the names shown when over water.
+var renames = map[string]string{
+	"Mediterranean Sea - Eastern Basin": "Mediterranean Sea",
+	"Mediterranean Sea - Western Basin": "Mediterranean Sea",
+}
+
+type seaArea struct {
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names,omitempty"`
+	Rings [][][2]float64    `json:"rings"`
+
+	area float64
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+func main() {
+	source := flag.String("source", defaultSource, "URL or path of the IHO Sea Areas GeoJSON")
+	previous := flag.String("names", "seas.json", "existing seas.json whose translated names are kept")
+	out := flag.String("o", "seas.json", "output file")
+	tolerance := flag.Float64("tolerance", 0.1, "simplification tolerance in degrees")
+	minArea := flag.Float64("min-area", 0.05, "drop rings smaller than this many square degrees")
+	flag.Parse()
+
+	if err := run(*source, *previous, *out, *tolerance, *minArea); err != nil {
+		fmt.Fprintln(os.Stderr, "seasgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(source, previous, out string, tolerance, minArea float64) error {
+	data, err := read(source)
+	if err != nil {
+		return err
+	}
+	var fc featureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	names := map[string]map[string]string{}
+	if old, err := os.ReadFile(previous); err == nil {
+		var seas []seaArea
+		if err := json.Unmarshal(old, &seas); err != nil {
+			return fmt.Errorf("parse %s: %w", previous, err)
+		}
+		for _, sea := range seas {
+			names[sea.Name] = sea.Names
+		}
+	}
+
+	seas, err := build(fc, names, tolerance, minArea)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(seas)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, append(encoded, '\n'), 0o644)
+}
+
+func read(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func build(fc featureCollection, names map[string]map[string]string, tolerance, minArea float64) ([]seaArea, error) {
+	byName := map[string]*seaArea{}
+	var order []string
+	for _, feature := range fc.Features {
+		name, _ := feature.Properties["NAME"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("feature without a NAME: %v", feature.Properties)
+		}
+		name = cmp.Or(renames[name], name)
+
+		var polygons [][][][2]float64
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var polygon [][][2]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			polygons = append(polygons, polygon)
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		default:
+			return nil, fmt.Errorf("%s: unsupported geometry %s", name, feature.Geometry.Type)
+		}
+
+		sea := byName[name]
+		if sea == nil {
+			sea = &seaArea{Name: name, Names: names[name]}
+			byName[name] = sea
+			order = append(order, name)
+		}
+		for _, polygon := range polygons {
+			if len(polygon) == 0 {
+				continue
+			}
+			ring := simplify(open(polygon[0]), tolerance)
+			area := math.Abs(ringArea(ring))
+			if len(ring) < 3 || area < minArea {
+				continue
+			}
+			sea.Rings = append(sea.Rings, round(ring))
+			sea.area += area
+		}
+	}
+
+	seas := make([]seaArea, 0, len(order))
+	for _, name := range order {
+		if sea := byName[name]; len(sea.Rings) > 0 {
+			seas = append(seas, *sea)
+		}
+	}
+	slices.SortStableFunc(seas, func(a, b seaArea) int { return cmp.Compare(a.area, b.area) })
+	return seas, nil
+}
+
+// open drops the closing point GeoJSON repeats at the end of a ring.
+func open(ring [][2]float64) [][2]float64 {
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		return ring[:n-1]
+	}
+	return ring
+}
+
+// simplify is Douglas-Peucker over a closed ring, split at its first point
+// and the point farthest from it.
+func simplify(ring [][2]float64, tolerance float64) [][2]float64 {
+	if len(ring) < 4 {
+		return ring
+	}
+	far := 0
+	for i, p := range ring {
+		if dist2(p, ring[0]) > dist2(ring[far], ring[0]) {
+			far = i
+		}
+	}
+	closed := append(slices.Clone(ring), ring[0])
+	first := douglasPeucker(closed[:far+1], tolerance)
+	second := douglasPeucker(closed[far:], tolerance)
+	return append(first, second[1:len(second)-1]...)
+}
+
+func douglasPeucker(line [][2]float64, tolerance float64) [][2]float64 {
+	if len(line) < 3 {
+		return slices.Clone(line)
+	}
+	worst, index := 0.0, 0
+	for i := 1; i < len(line)-1; i++ {
+		if d := segmentDistance(line[i], line[0], line[len(line)-1]); d > worst {
+			worst, index = d, i
+		}
+	}
+	if worst <= tolerance {
+		return [][2]float64{line[0], line[len(line)-1]}
+	}
+	left := douglasPeucker(line[:index+1], tolerance)
+	right := douglasPeucker(line[index:], tolerance)
+	return append(left[:len(left)-1], right...)
+}
+
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Sqrt(dist2(p, a))
+	}
+	t := math.Max(0, math.Min(1, ((p[0]-a[0])*dx+(p[1]-a[1])*dy)/(dx*dx+dy*dy)))
+	return math.Sqrt(dist2(p, [2]float64{a[0] + t*dx, a[1] + t*dy}))
+}
+
+func dist2(a, b [2]float64) float64 {
+	return (a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1])
+}
+
+func ringArea(ring [][2]float64) float64 {
+	sum := 0.0
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		sum += (ring[j][0] - ring[i][0]) * (ring[j][1] + ring[i][1])
+	}
+	return sum / 2
+}
+
+func round(ring [][2]float64) [][2]float64 {
+	out := make([][2]float64, len(ring))
+	for i, p := range ring {
+		out[i] = [2]float64{math.Round(p[0]*100) / 100, math.Round(p[1]*100) / 100}
+	}
+	return out
+}
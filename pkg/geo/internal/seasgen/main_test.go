@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	const source = `{"features": [
+		{"properties": {"NAME": "North Pacific Ocean"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[120, 0], [180, 0], [180, 60], [120, 60], [120, 0]]
+		]}},
+		{"properties": {"NAME": "Mediterranean Sea - Western Basin"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[0, 35], [5, 35.001], [10, 35], [10, 44], [0, 44], [0, 35]]],
+			[[[3, 39], [3.1, 39], [3.1, 39.1], [3, 39]]]
+		]}},
+		{"properties": {"NAME": "Mediterranean Sea - Eastern Basin"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[15, 31], [36, 31], [36, 40], [15, 40], [15, 31]]
+		]}}
+	]}`
+	var fc featureCollection
+	if err := json.Unmarshal([]byte(source), &fc); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]map[string]string{"Mediterranean Sea": {"de": "Mittelmeer"}}
+	seas, err := build(fc, names, 0.1, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seas) != 2 {
+		t.Fatalf("got %d areas, want 2", len(seas))
+	}
+	med, pacific := seas[0], seas[1]
+	if med.Name != "Mediterranean Sea" || pacific.Name != "North Pacific Ocean" {
+		t.Fatalf("order = %q, %q; want the smaller area first", med.Name, pacific.Name)
+	}
+	if med.Names["de"] != "Mittelmeer" {
+		t.Errorf("translations not carried over: %v", med.Names)
+	}
+	if len(med.Rings) != 2 {
+		t.Fatalf("got %d Mediterranean rings, want 2 (the tiny one dropped)", len(med.Rings))
+	}
+	if got := len(med.Rings[0]); got != 4 {
+		t.Errorf("western basin has %d points after simplifying, want 4: %v", got, med.Rings[0])
+	}
+	if ring := pacific.Rings[0]; ring[0] == ring[len(ring)-1] {
+		t.Errorf("closing point kept: %v", ring)
+	}
+}
+
+func TestBuildRejectsUnnamed(t *testing.T) {
+	fc := featureCollection{Features: make([]struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	}, 1)}
+	if _, err := build(fc, nil, 0.1, 0.05); err == nil {
+		t.Fatal("want an error for a feature without a NAME")
+	}
+}
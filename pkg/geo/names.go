@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Naming chooses which of a place's names Locate and Search return.
+type Naming int
+
+const (
+	// Localized is the name in the requested language, falling back to the
+	// English name, the international name and a transliteration of the
+	// local name, in that order.
+	Localized Naming = iota
+	// Local is the name used on the spot, as printed on the map there.
+	Local
+)
+
+var transliterations = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'ђ': "dj", 'е': "e", 'ё': "yo", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj",
+	'м': "m", 'н': "n", 'њ': "nj", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "c", 'у': "u",
+	'ў': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'џ': "dzh", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y",
+	'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i",
+	'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y", 'ϋ': "y",
+	'ΰ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// name picks a place's name from Nominatim's namedetails, or returns
+// fallback, the name Nominatim chose, when none applies.
+func (n Naming) name(details map[string]string, fallback string, languages []string) string {
+	local := strings.TrimSpace(details["name"])
+	if n == Local {
+		if local != "" {
+			return local
+		}
+		return fallback
+	}
+
+	keys := make([]string, 0, len(languages)+2)
+	for _, lang := range languages {
+		keys = append(keys, "name:"+lang)
+	}
+	keys = append(keys, "name:en", "int_name")
+	for _, key := range keys {
+		if value := strings.TrimSpace(details[key]); value != "" {
+			return value
+		}
+	}
+	if local != "" {
+		if latin, ok := transliterate(local); ok {
+			return latin
+		}
+		return local
+	}
+	return fallback
+}
+
+// acceptedLanguages turns an Accept-Language value such as "de-AT,de,en"
+// into the language tags it lists, most preferred first.
+func acceptedLanguages(acceptLanguage string) []string {
+	var languages []string
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+			languages = append(languages, tag)
+		}
+	}
+	return languages
+}
+
+// transliterate spells a Cyrillic or Greek name in Latin letters. It reports
+// false when the name has letters of another script, which it leaves alone.
+func transliterate(name string) (string, bool) {
+	var b strings.Builder
+	changed := false
+	for _, r := range name {
+		lower := unicode.ToLower(r)
+		latin, ok := transliterations[lower]
+		switch {
+		case ok:
+			if lower != r && latin != "" {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+			b.WriteString(latin)
+			changed = true
+		case unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r):
+			return "", false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), changed
+}
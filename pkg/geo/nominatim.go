@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	nominatimURL         = "https://nominatim.openstreetmap.org/reverse"
+	nominatimSearchURL   = "https://nominatim.openstreetmap.org/search"
+	nominatimCountryZoom = 3
+)
+
+// UserAgent is sent with every Nominatim request, as its usage policy requires.
+var UserAgent = "iss-tui/1.2 (+https://github.com/kivayan/iss)"
+
+type nominatimResponse struct {
+	Addresstype string            `json:"addresstype"`
+	NameDetails map[string]string `json:"namedetails"`
+	Address     struct {
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// Location is a reverse-geocoded spot. CountryCode is the upper-case ISO
+// 3166-1 alpha-2 code and is empty over water.
+type Location struct {
+	Name        string
+	CountryCode string
+}
+
+// Place is a search result. Name is its short name and DisplayName the full
+// one, with region and country, for telling apart places of the same name.
+type Place struct {
+	Name        string
+	DisplayName string
+	Lat         float64
+	Lon         float64
+}
+
+// LocationName returns the country at lat/lon, or the name of the ocean or sea
+// when it is over water. Countries come from Nominatim; seas are looked up in
+// embedded rough outlines of the named oceans and seas, so it names stretches
+// of open ocean too. acceptLanguage is passed through as the Accept-Language of the
+// request, and naming picks between the name in that language and the local
+// one.
+func LocationName(client *http.Client, lat, lon float64, acceptLanguage string, naming Naming) (string, error) {
+	loc, err := Locate(client, lat, lon, acceptLanguage, naming)
+	return loc.Name, err
+}
+
+// Locate is LocationName with the country code included.
+func Locate(client *http.Client, lat, lon float64, acceptLanguage string, naming Naming) (Location, error) {
+	languages := acceptedLanguages(acceptLanguage)
+	payload, err := reverseGeocode(client, lat, lon, acceptLanguage)
+	if err != nil {
+		return Location{}, err
+	}
+
+	if country := strings.TrimSpace(payload.Address.Country); country != "" {
+		if strings.EqualFold(payload.Addresstype, "country") {
+			country = naming.name(payload.NameDetails, country, languages)
+		}
+		return Location{Name: country, CountryCode: strings.ToUpper(strings.TrimSpace(payload.Address.CountryCode))}, nil
+	}
+
+	sea, _ := seaAt(lat, lon)
+	return Location{Name: sea.name(languages, naming)}, nil
+}
+
+func reverseGeocode(client *http.Client, lat, lon float64, acceptLanguage string) (nominatimResponse, error) {
+	q := url.Values{}
+	q.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+	q.Set("zoom", strconv.Itoa(nominatimCountryZoom))
+	q.Set("addressdetails", "1")
+	q.Set("namedetails", "1")
+
+	var payload nominatimResponse
+	if err := nominatimGet(client, nominatimURL, q, acceptLanguage, &payload); err != nil {
+		return nominatimResponse{}, err
+	}
+	return payload, nil
+}
+
+// Search looks up places matching query, best match first, and returns at
+// most limit of them, named as naming asks.
+func Search(client *http.Client, query string, limit int, acceptLanguage string, naming Naming) ([]Place, error) {
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("namedetails", "1")
+
+	var payload []struct {
+		Name        string            `json:"name"`
+		DisplayName string            `json:"display_name"`
+		Lat         string            `json:"lat"`
+		Lon         string            `json:"lon"`
+		NameDetails map[string]string `json:"namedetails"`
+	}
+	if err := nominatimGet(client, nominatimSearchURL, q, acceptLanguage, &payload); err != nil {
+		return nil, err
+	}
+
+	languages := acceptedLanguages(acceptLanguage)
+	places := make([]Place, 0, len(payload))
+	for _, result := range payload {
+		lat, latErr := strconv.ParseFloat(result.Lat, 64)
+		lon, lonErr := strconv.ParseFloat(result.Lon, 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			continue
+		}
+		name := strings.TrimSpace(result.Name)
+		if name == "" {
+			name = strings.TrimSpace(strings.Split(result.DisplayName, ",")[0])
+		}
+		name = naming.name(result.NameDetails, name, languages)
+		places = append(places, Place{Name: name, DisplayName: result.DisplayName, Lat: lat, Lon: lon})
+	}
+	return places, nil
+}
+
+func nominatimGet(client *http.Client, endpoint string, q url.Values, acceptLanguage string, payload any) error {
+	q.Set("format", "jsonv2")
+	if acceptLanguage != "" {
+		q.Set("accept-language", acceptLanguage)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nominatim status: %s", resp.Status)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(payload)
+}
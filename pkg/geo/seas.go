@@ -0,0 +1,77 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+// seasJSON outlines the oceans and seas with hand-drawn polygons of 4 to 25
+// points, loosely following the names and extents of the IHO Limits of Oceans
+// and Seas but not derived from its boundaries. Smaller areas come before the
+// ones that contain them and the first match wins, so each outline only has
+// to be accurate where it meets water of a later area; crossing land does
+// not matter. Longitudes run past 180 for areas across the antimeridian.
+// Running go generate replaces them with a simplified copy of the Marine
+// Regions IHO Sea Areas (CC BY 4.0, credit the Flanders Marine Institute).
+//
+//go:generate go run ./internal/seasgen
+//go:embed seas.json
+var seasJSON []byte
+
+type seaArea struct {
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names"`
+	Rings [][][2]float64    `json:"rings"`
+}
+
+var (
+	seasOnce sync.Once
+	seas     []seaArea
+)
+
+func loadSeas() []seaArea {
+	seasOnce.Do(func() {
+		if err := json.Unmarshal(seasJSON, &seas); err != nil {
+			panic("geo: parse seas.json: " + err.Error())
+		}
+	})
+	return seas
+}
+
+// seaAt returns the sea area at lat/lon. The oceans between them cover the
+// globe, so it only fails at the poles themselves.
+func seaAt(lat, lon float64) (seaArea, bool) {
+	for _, sea := range loadSeas() {
+		for _, ring := range sea.Rings {
+			if inRing(ring, lon, lat) || inRing(ring, lon+360, lat) {
+				return sea, true
+			}
+		}
+	}
+	return seaArea{}, false
+}
+
+func inRing(ring [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi, xj, yj := ring[i][0], ring[i][1], ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// name is the sea's name in the first of languages it has one for, or its
+// English name.
+func (s seaArea) name(languages []string, naming Naming) string {
+	if naming == Localized {
+		for _, lang := range languages {
+			if name, ok := s.Names[lang]; ok {
+				return name
+			}
+		}
+	}
+	return s.Name
+}
@@ -0,0 +1,22 @@
+package geo
+
+import "testing"
+
+func TestSeaAt(t *testing.T) {
+	for _, tc := range []struct {
+		lat, lon float64
+		want     string
+	}{
+		{-40, 160, "Tasman Sea"},
+		{40, 15, "Tyrrhenian Sea"},
+		{0, -25, "North Atlantic Ocean"},
+		{-30, -140, "South Pacific Ocean"},
+		{45.8, 36.5, "Sea of Azov"},
+		{-20, 80, "Indian Ocean"},
+	} {
+		sea, ok := seaAt(tc.lat, tc.lon)
+		if !ok || sea.Name != tc.want {
+			t.Errorf("seaAt(%v, %v) = %q, %v, want %q", tc.lat, tc.lon, sea.Name, ok, tc.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package geofence tracks when a moving point enters and leaves named regions.
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+const (
+	// DefaultMarginKm is how far past a box or circle edge the point must be
+	// before it counts as having left.
+	DefaultMarginKm = 25.0
+	// DefaultConfirm is how many consecutive samples must agree before a
+	// region changes state.
+	DefaultConfirm = 2
+)
+
+// Region is a named area made of any combination of countries (ISO 3166-1
+// alpha-2 codes), a bounding box and a circle. A point inside any of them is
+// inside the region.
+type Region struct {
+	Name      string
+	Countries []string
+	Box       *Box
+	Circle    *Circle
+}
+
+// Box is a latitude/longitude rectangle in degrees. MinLon greater than
+// MaxLon means the box crosses the antimeridian.
+type Box struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Circle is a great-circle radius around a centre point.
+type Circle struct {
+	Lat, Lon, RadiusKm float64
+}
+
+// Sample is one observed position. CountryCode is empty over water.
+type Sample struct {
+	Lat, Lon    float64
+	CountryCode string
+	Time        time.Time
+}
+
+// Kind says whether an event is an entry or an exit.
+type Kind int
+
+const (
+	Enter Kind = iota
+	Exit
+)
+
+func (k Kind) String() string {
+	if k == Enter {
+		return "enter"
+	}
+	return "exit"
+}
+
+// Event is a confirmed entry into or exit from a region.
+type Event struct {
+	Region string
+	Kind   Kind
+	Time   time.Time
+	Lat    float64
+	Lon    float64
+}
+
+type regionState struct {
+	known  bool
+	inside bool
+	streak int
+}
+
+// Fence holds the regions and their current state. The zero value is not
+// usable; create one with New.
+type Fence struct {
+	MarginKm float64
+	Confirm  int
+
+	regions []Region
+	states  []regionState
+}
+
+// Validate checks that the region has a name and at least one sensible area.
+func (r Region) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("region without a name")
+	}
+	if len(r.Countries) == 0 && r.Box == nil && r.Circle == nil {
+		return fmt.Errorf("region %q: needs countries, a box or a circle", r.Name)
+	}
+	for _, code := range r.Countries {
+		if len(code) != 2 {
+			return fmt.Errorf("region %q: country %q is not a two-letter code", r.Name, code)
+		}
+	}
+	if b := r.Box; b != nil {
+		if b.MinLat > b.MaxLat || math.Abs(b.MinLat) > 90 || math.Abs(b.MaxLat) > 90 || math.Abs(b.MinLon) > 180 || math.Abs(b.MaxLon) > 180 {
+			return fmt.Errorf("region %q: box is out of range", r.Name)
+		}
+	}
+	if c := r.Circle; c != nil {
+		if c.RadiusKm <= 0 || math.Abs(c.Lat) > 90 || math.Abs(c.Lon) > 180 {
+			return fmt.Errorf("region %q: circle is out of range", r.Name)
+		}
+	}
+	return nil
+}
+
+// New returns a fence over regions with the default margin and confirmation
+// count.
+func New(regions []Region) *Fence {
+	return &Fence{
+		MarginKm: DefaultMarginKm,
+		Confirm:  DefaultConfirm,
+		regions:  regions,
+		states:   make([]regionState, len(regions)),
+	}
+}
+
+// Regions returns the configured regions.
+func (f *Fence) Regions() []Region {
+	if f == nil {
+		return nil
+	}
+	return f.regions
+}
+
+// Inside reports whether the point is currently inside the named region.
+func (f *Fence) Inside(name string) bool {
+	if f == nil {
+		return false
+	}
+	for i, region := range f.regions {
+		if region.Name == name {
+			return f.states[i].inside
+		}
+	}
+	return false
+}
+
+// Update feeds a new sample and returns the events it confirms. The first
+// sample only produces Enter events, for regions the point starts inside.
+func (f *Fence) Update(s Sample) []Event {
+	if f == nil {
+		return nil
+	}
+
+	var events []Event
+	for i, region := range f.regions {
+		state := &f.states[i]
+		inside := region.contains(s, state.inside, f.MarginKm)
+
+		switch {
+		case !state.known:
+			state.known, state.inside = true, inside
+			if inside {
+				events = append(events, Event{Region: region.Name, Kind: Enter, Time: s.Time, Lat: s.Lat, Lon: s.Lon})
+			}
+		case inside == state.inside:
+			state.streak = 0
+		default:
+			state.streak++
+			if state.streak < max(f.Confirm, 1) {
+				continue
+			}
+			state.inside, state.streak = inside, 0
+			kind := Exit
+			if inside {
+				kind = Enter
+			}
+			events = append(events, Event{Region: region.Name, Kind: kind, Time: s.Time, Lat: s.Lat, Lon: s.Lon})
+		}
+	}
+	return events
+}
+
+func (r Region) contains(s Sample, wasInside bool, marginKm float64) bool {
+	for _, code := range r.Countries {
+		if s.CountryCode != "" && strings.EqualFold(code, s.CountryCode) {
+			return true
+		}
+	}
+
+	slack := 0.0
+	if wasInside {
+		slack = marginKm
+	}
+	if r.Box != nil && r.Box.distanceKm(s.Lat, s.Lon) <= slack {
+		return true
+	}
+	if c := r.Circle; c != nil && geo.HaversineKm(c.Lat, c.Lon, s.Lat, s.Lon)-c.RadiusKm <= slack {
+		return true
+	}
+	return false
+}
+
+func (b Box) distanceKm(lat, lon float64) float64 {
+	if lat >= b.MinLat && lat <= b.MaxLat && geo.InLonRange(lon, b.MinLon, b.MaxLon) {
+		return 0
+	}
+
+	nearestLat := math.Max(b.MinLat, math.Min(b.MaxLat, lat))
+	nearestLon := lon
+	if !geo.InLonRange(lon, b.MinLon, b.MaxLon) {
+		nearestLon = b.MinLon
+		if lonGap(lon, b.MaxLon) < lonGap(lon, b.MinLon) {
+			nearestLon = b.MaxLon
+		}
+	}
+	return geo.HaversineKm(lat, lon, nearestLat, nearestLon)
+}
+
+func lonGap(a, b float64) float64 {
+	return math.Abs(math.Mod(a-b+540, 360) - 180)
+}
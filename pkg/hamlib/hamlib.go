@@ -0,0 +1,105 @@
+// Package hamlib speaks the line-based network protocol of hamlib's rotctld
+// and rigctld daemons.
+package hamlib
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ioTimeout = 3 * time.Second
+
+// Conn is a connection to rotctld or rigctld. It dials on first use and
+// redials after a failed command, so a daemon restart is survived. It is
+// safe for concurrent use.
+type Conn struct {
+	addr string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Dial returns a connection to addr (host:port). No network traffic happens
+// until the first command.
+func Dial(addr string) *Conn {
+	return &Conn{addr: addr}
+}
+
+// Addr is the daemon address.
+func (c *Conn) Addr() string {
+	return c.addr
+}
+
+// SetPosition points the rotator. Azimuth is wrapped to [0, 360) and
+// elevation clamped to [0, 90].
+func (c *Conn) SetPosition(azimuth, elevation float64) error {
+	azimuth = math.Mod(azimuth, 360)
+	if azimuth < 0 {
+		azimuth += 360
+	}
+	elevation = math.Max(0, math.Min(90, elevation))
+	return c.command(fmt.Sprintf("P %.1f %.1f", azimuth, elevation))
+}
+
+// SetFrequency tunes the rig's current VFO, in hertz.
+func (c *Conn) SetFrequency(hz float64) error {
+	return c.command("F " + strconv.FormatInt(int64(math.Round(hz)), 10))
+}
+
+// Close drops the connection, if any.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reset()
+}
+
+func (c *Conn) reset() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.reader = nil, nil
+	return err
+}
+
+func (c *Conn) command(line string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := net.DialTimeout("tcp", c.addr, ioTimeout)
+		if err != nil {
+			return err
+		}
+		c.conn, c.reader = conn, bufio.NewReader(conn)
+	}
+
+	c.conn.SetDeadline(time.Now().Add(ioTimeout))
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		c.reset()
+		return err
+	}
+
+	reply, err := c.reader.ReadString('\n')
+	if err != nil {
+		c.reset()
+		return err
+	}
+
+	reply = strings.TrimSpace(reply)
+	code, ok := strings.CutPrefix(reply, "RPRT ")
+	if !ok {
+		return fmt.Errorf("%s: unexpected reply %q", c.addr, reply)
+	}
+	if code != "0" {
+		return fmt.Errorf("%s: %q failed with RPRT %s", c.addr, line, code)
+	}
+	return nil
+}
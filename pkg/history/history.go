@@ -0,0 +1,231 @@
+// Package history stores telemetry samples across runs in an embedded bbolt
+// database.
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const openTimeout = 2 * time.Second
+
+var (
+	metaBucket    = []byte("meta")
+	samplesBucket = []byte("samples")
+	versionKey    = []byte("schema_version")
+)
+
+// ErrLocked is returned when another process holds the database open.
+var ErrLocked = errors.New("history database is in use by another process")
+
+// Sample is one recorded position. Location and CountryCode are what the
+// position was reverse-geocoded to, if anything.
+type Sample struct {
+	Time        time.Time `json:"time"`
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	AltitudeKm  float64   `json:"altitude_km,omitempty"`
+	VelocityKmh float64   `json:"velocity_kmh,omitempty"`
+	HasAltitude bool      `json:"has_altitude,omitempty"`
+	Provider    string    `json:"provider,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	CountryCode string    `json:"country_code,omitempty"`
+}
+
+// Store is an open history database.
+type Store struct {
+	db *bolt.DB
+}
+
+type migration func(tx *bolt.Tx) error
+
+// migrations bring the schema from version i to i+1. Append only.
+var migrations = []migration{
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(samplesBucket)
+		return err
+	},
+}
+
+// Open opens or creates the database at path and applies pending migrations.
+// A read-only store shares the file with other readers but not with a writer.
+func Open(path string, readOnly bool) (*Store, error) {
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: openTimeout, ReadOnly: readOnly})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+
+	store := &Store{db: db}
+	if readOnly {
+		if err := store.checkVersion(); err != nil {
+			db.Close()
+			return nil, err
+		}
+		return store, nil
+	}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Close releases the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func schemaVersion(tx *bolt.Tx) int {
+	meta := tx.Bucket(metaBucket)
+	if meta == nil {
+		return 0
+	}
+	raw := meta.Get(versionKey)
+	if len(raw) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(raw))
+}
+
+func (s *Store) checkVersion() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		if version := schemaVersion(tx); version != len(migrations) {
+			return fmt.Errorf("history schema is version %d, this build expects %d", version, len(migrations))
+		}
+		return nil
+	})
+}
+
+func (s *Store) migrate() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		version := schemaVersion(tx)
+		if version > len(migrations) {
+			return fmt.Errorf("history schema version %d is newer than this build supports", version)
+		}
+
+		for ; version < len(migrations); version++ {
+			if err := migrations[version](tx); err != nil {
+				return fmt.Errorf("history migration %d: %w", version+1, err)
+			}
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		raw := make([]byte, 8)
+		binary.BigEndian.PutUint64(raw, uint64(version))
+		return meta.Put(versionKey, raw)
+	})
+}
+
+func sampleKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+// Append records samples. Samples with the same timestamp replace each other.
+func (s *Store) Append(samples ...Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(samplesBucket)
+		for _, sample := range samples {
+			value, err := json.Marshal(sample)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(sampleKey(sample.Time), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Range calls fn for every sample from since up to, but not including, until,
+// oldest first. A zero until means no upper bound. Returning an error from fn
+// stops the scan.
+func (s *Store) Range(since, until time.Time, fn func(Sample) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(samplesBucket).Cursor()
+		var end []byte
+		if !until.IsZero() {
+			end = sampleKey(until)
+		}
+
+		start := sampleKey(time.Unix(0, 0))
+		if since.After(time.Unix(0, 0)) {
+			start = sampleKey(since)
+		}
+
+		for key, value := cursor.Seek(start); key != nil; key, value = cursor.Next() {
+			if end != nil && bytes.Compare(key, end) >= 0 {
+				break
+			}
+			var sample Sample
+			if err := json.Unmarshal(value, &sample); err != nil {
+				return fmt.Errorf("history sample: %w", err)
+			}
+			if err := fn(sample); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Since returns every sample recorded at or after since, oldest first.
+func (s *Store) Since(since time.Time) ([]Sample, error) {
+	var samples []Sample
+	err := s.Range(since, time.Time{}, func(sample Sample) error {
+		samples = append(samples, sample)
+		return nil
+	})
+	return samples, err
+}
+
+// Prune deletes every sample recorded before before and returns how many were
+// removed.
+func (s *Store) Prune(before time.Time) (int, error) {
+	var removed int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(samplesBucket)
+		end := sampleKey(before)
+		var keys [][]byte
+		cursor := bucket.Cursor()
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key, end) < 0; key, _ = cursor.Next() {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		removed = len(keys)
+		return nil
+	})
+	return removed, err
+}
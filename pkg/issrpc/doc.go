@@ -0,0 +1,5 @@
+// Package issrpc is the gRPC Tracker service that iss serves on grpc_addr,
+// generated from iss.proto with protoc-gen-go and protoc-gen-go-grpc.
+package issrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative iss.proto
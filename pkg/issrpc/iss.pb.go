@@ -0,0 +1,761 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: iss.proto
+
+package issrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetPositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionRequest) Reset() {
+	*x = GetPositionRequest{}
+	mi := &file_iss_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionRequest) ProtoMessage() {}
+
+func (x *GetPositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionRequest) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{0}
+}
+
+type StreamPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPositionsRequest) Reset() {
+	*x = StreamPositionsRequest{}
+	mi := &file_iss_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPositionsRequest) ProtoMessage() {}
+
+func (x *StreamPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPositionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{1}
+}
+
+type Position struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Lat         float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon         float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	AltitudeKm  *float64               `protobuf:"fixed64,3,opt,name=altitude_km,json=altitudeKm,proto3,oneof" json:"altitude_km,omitempty"`
+	VelocityKmh *float64               `protobuf:"fixed64,4,opt,name=velocity_kmh,json=velocityKmh,proto3,oneof" json:"velocity_kmh,omitempty"`
+	// over is the place below the station, as the TUI names it.
+	Over          string                 `protobuf:"bytes,5,opt,name=over,proto3" json:"over,omitempty"`
+	Provider      string                 `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_iss_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Position) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Position) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Position) GetAltitudeKm() float64 {
+	if x != nil && x.AltitudeKm != nil {
+		return *x.AltitudeKm
+	}
+	return 0
+}
+
+func (x *Position) GetVelocityKmh() float64 {
+	if x != nil && x.VelocityKmh != nil {
+		return *x.VelocityKmh
+	}
+	return 0
+}
+
+func (x *Position) GetOver() string {
+	if x != nil {
+		return x.Over
+	}
+	return ""
+}
+
+func (x *Position) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Position) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type PredictPassesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// lat and lon default to the observer in the config; set both or neither.
+	Lat  *float64 `protobuf:"fixed64,1,opt,name=lat,proto3,oneof" json:"lat,omitempty"`
+	Lon  *float64 `protobuf:"fixed64,2,opt,name=lon,proto3,oneof" json:"lon,omitempty"`
+	AltM float64  `protobuf:"fixed64,3,opt,name=alt_m,json=altM,proto3" json:"alt_m,omitempty"`
+	// days defaults to 3 and may be at most 30.
+	Days float64 `protobuf:"fixed64,4,opt,name=days,proto3" json:"days,omitempty"`
+	// min_elevation defaults to 10 degrees.
+	MinElevation *float64 `protobuf:"fixed64,5,opt,name=min_elevation,json=minElevation,proto3,oneof" json:"min_elevation,omitempty"`
+	VisibleOnly  bool     `protobuf:"varint,6,opt,name=visible_only,json=visibleOnly,proto3" json:"visible_only,omitempty"`
+	// count limits the number of passes; 0 returns all of them.
+	Count         int32 `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictPassesRequest) Reset() {
+	*x = PredictPassesRequest{}
+	mi := &file_iss_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictPassesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictPassesRequest) ProtoMessage() {}
+
+func (x *PredictPassesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictPassesRequest.ProtoReflect.Descriptor instead.
+func (*PredictPassesRequest) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PredictPassesRequest) GetLat() float64 {
+	if x != nil && x.Lat != nil {
+		return *x.Lat
+	}
+	return 0
+}
+
+func (x *PredictPassesRequest) GetLon() float64 {
+	if x != nil && x.Lon != nil {
+		return *x.Lon
+	}
+	return 0
+}
+
+func (x *PredictPassesRequest) GetAltM() float64 {
+	if x != nil {
+		return x.AltM
+	}
+	return 0
+}
+
+func (x *PredictPassesRequest) GetDays() float64 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *PredictPassesRequest) GetMinElevation() float64 {
+	if x != nil && x.MinElevation != nil {
+		return *x.MinElevation
+	}
+	return 0
+}
+
+func (x *PredictPassesRequest) GetVisibleOnly() bool {
+	if x != nil {
+		return x.VisibleOnly
+	}
+	return false
+}
+
+func (x *PredictPassesRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Pass struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Max           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	StartAzimuth  float64                `protobuf:"fixed64,4,opt,name=start_azimuth,json=startAzimuth,proto3" json:"start_azimuth,omitempty"`
+	MaxAzimuth    float64                `protobuf:"fixed64,5,opt,name=max_azimuth,json=maxAzimuth,proto3" json:"max_azimuth,omitempty"`
+	EndAzimuth    float64                `protobuf:"fixed64,6,opt,name=end_azimuth,json=endAzimuth,proto3" json:"end_azimuth,omitempty"`
+	MaxElevation  float64                `protobuf:"fixed64,7,opt,name=max_elevation,json=maxElevation,proto3" json:"max_elevation,omitempty"`
+	SunElevation  float64                `protobuf:"fixed64,8,opt,name=sun_elevation,json=sunElevation,proto3" json:"sun_elevation,omitempty"`
+	Visible       bool                   `protobuf:"varint,9,opt,name=visible,proto3" json:"visible,omitempty"`
+	Magnitude     *float64               `protobuf:"fixed64,10,opt,name=magnitude,proto3,oneof" json:"magnitude,omitempty"`
+	Score         int32                  `protobuf:"varint,11,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pass) Reset() {
+	*x = Pass{}
+	mi := &file_iss_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pass) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pass) ProtoMessage() {}
+
+func (x *Pass) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pass.ProtoReflect.Descriptor instead.
+func (*Pass) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Pass) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Pass) GetMax() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+func (x *Pass) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Pass) GetStartAzimuth() float64 {
+	if x != nil {
+		return x.StartAzimuth
+	}
+	return 0
+}
+
+func (x *Pass) GetMaxAzimuth() float64 {
+	if x != nil {
+		return x.MaxAzimuth
+	}
+	return 0
+}
+
+func (x *Pass) GetEndAzimuth() float64 {
+	if x != nil {
+		return x.EndAzimuth
+	}
+	return 0
+}
+
+func (x *Pass) GetMaxElevation() float64 {
+	if x != nil {
+		return x.MaxElevation
+	}
+	return 0
+}
+
+func (x *Pass) GetSunElevation() float64 {
+	if x != nil {
+		return x.SunElevation
+	}
+	return 0
+}
+
+func (x *Pass) GetVisible() bool {
+	if x != nil {
+		return x.Visible
+	}
+	return false
+}
+
+func (x *Pass) GetMagnitude() float64 {
+	if x != nil && x.Magnitude != nil {
+		return *x.Magnitude
+	}
+	return 0
+}
+
+func (x *Pass) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type PredictPassesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passes        []*Pass                `protobuf:"bytes,1,rep,name=passes,proto3" json:"passes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictPassesResponse) Reset() {
+	*x = PredictPassesResponse{}
+	mi := &file_iss_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictPassesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictPassesResponse) ProtoMessage() {}
+
+func (x *PredictPassesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictPassesResponse.ProtoReflect.Descriptor instead.
+func (*PredictPassesResponse) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PredictPassesResponse) GetPasses() []*Pass {
+	if x != nil {
+		return x.Passes
+	}
+	return nil
+}
+
+type ListCrewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrewRequest) Reset() {
+	*x = ListCrewRequest{}
+	mi := &file_iss_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrewRequest) ProtoMessage() {}
+
+func (x *ListCrewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrewRequest.ProtoReflect.Descriptor instead.
+func (*ListCrewRequest) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{6}
+}
+
+type CrewMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Craft         string                 `protobuf:"bytes,2,opt,name=craft,proto3" json:"craft,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrewMember) Reset() {
+	*x = CrewMember{}
+	mi := &file_iss_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrewMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrewMember) ProtoMessage() {}
+
+func (x *CrewMember) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrewMember.ProtoReflect.Descriptor instead.
+func (*CrewMember) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CrewMember) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CrewMember) GetCraft() string {
+	if x != nil {
+		return x.Craft
+	}
+	return ""
+}
+
+type ListCrewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	People        []*CrewMember          `protobuf:"bytes,1,rep,name=people,proto3" json:"people,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrewResponse) Reset() {
+	*x = ListCrewResponse{}
+	mi := &file_iss_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrewResponse) ProtoMessage() {}
+
+func (x *ListCrewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iss_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrewResponse.ProtoReflect.Descriptor instead.
+func (*ListCrewResponse) Descriptor() ([]byte, []int) {
+	return file_iss_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListCrewResponse) GetPeople() []*CrewMember {
+	if x != nil {
+		return x.People
+	}
+	return nil
+}
+
+var File_iss_proto protoreflect.FileDescriptor
+
+var file_iss_proto_rawDesc = string([]byte{
+	0x0a, 0x09, 0x69, 0x73, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x69, 0x73, 0x73,
+	0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x14, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x18, 0x0a, 0x16, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x87, 0x02, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x6c, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0b, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x5f, 0x6b, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x6c,
+	0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x4b, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x76,
+	0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x6b, 0x6d, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x01, 0x52, 0x0b, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x4b, 0x6d, 0x68,
+	0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x0e, 0x0a,
+	0x0c, 0x5f, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x5f, 0x6b, 0x6d, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x6b, 0x6d, 0x68, 0x22, 0xf2,
+	0x01, 0x0a, 0x14, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x03, 0x6c, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x03, 0x6c, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x15,
+	0x0a, 0x03, 0x6c, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x03, 0x6c,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x05, 0x61, 0x6c, 0x74, 0x5f, 0x6d, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x61, 0x6c, 0x74, 0x4d, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x79, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x12, 0x28,
+	0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x45, 0x6c, 0x65, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x69, 0x73, 0x69,
+	0x62, 0x6c, 0x65, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x76, 0x69, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6c, 0x61, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6c, 0x6f,
+	0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0xa6, 0x03, 0x0a, 0x04, 0x50, 0x61, 0x73, 0x73, 0x12, 0x30, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c,
+	0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x2c, 0x0a, 0x03,
+	0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x5f, 0x61, 0x7a, 0x69, 0x6d, 0x75, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x7a, 0x69, 0x6d, 0x75, 0x74, 0x68, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x7a, 0x69, 0x6d, 0x75, 0x74, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x41, 0x7a, 0x69, 0x6d, 0x75, 0x74, 0x68,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x7a, 0x69, 0x6d, 0x75, 0x74, 0x68, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x41, 0x7a, 0x69, 0x6d, 0x75, 0x74,
+	0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x45, 0x6c, 0x65,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x6e, 0x5f, 0x65, 0x6c,
+	0x65, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x73,
+	0x75, 0x6e, 0x45, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x69, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x76, 0x69,
+	0x73, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x09, 0x6d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x09, 0x6d, 0x61, 0x67, 0x6e,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x42, 0x0c,
+	0x0a, 0x0a, 0x5f, 0x6d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x3d, 0x0a, 0x15,
+	0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x73, 0x73, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x73, 0x22, 0x11, 0x0a, 0x0f, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x72, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x36,
+	0x0a, 0x0a, 0x43, 0x72, 0x65, 0x77, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x72, 0x61, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x63, 0x72, 0x61, 0x66, 0x74, 0x22, 0x3e, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x72,
+	0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x70, 0x65,
+	0x6f, 0x70, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x69, 0x73, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x77, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x06,
+	0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x32, 0x9a, 0x02, 0x0a, 0x07, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x72, 0x12, 0x3b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1a, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
+	0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x45, 0x0a, 0x0f, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x1e, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x30, 0x01, 0x12, 0x4c, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63,
+	0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x72, 0x65, 0x77,
+	0x12, 0x17, 0x2e, 0x69, 0x73, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x72,
+	0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x69, 0x73, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x72, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x4b, 0x69, 0x76, 0x61, 0x79, 0x61, 0x6e, 0x2f, 0x69, 0x73, 0x73, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x69, 0x73, 0x73, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_iss_proto_rawDescOnce sync.Once
+	file_iss_proto_rawDescData []byte
+)
+
+func file_iss_proto_rawDescGZIP() []byte {
+	file_iss_proto_rawDescOnce.Do(func() {
+		file_iss_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_iss_proto_rawDesc), len(file_iss_proto_rawDesc)))
+	})
+	return file_iss_proto_rawDescData
+}
+
+var file_iss_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_iss_proto_goTypes = []any{
+	(*GetPositionRequest)(nil),     // 0: iss.v1.GetPositionRequest
+	(*StreamPositionsRequest)(nil), // 1: iss.v1.StreamPositionsRequest
+	(*Position)(nil),               // 2: iss.v1.Position
+	(*PredictPassesRequest)(nil),   // 3: iss.v1.PredictPassesRequest
+	(*Pass)(nil),                   // 4: iss.v1.Pass
+	(*PredictPassesResponse)(nil),  // 5: iss.v1.PredictPassesResponse
+	(*ListCrewRequest)(nil),        // 6: iss.v1.ListCrewRequest
+	(*CrewMember)(nil),             // 7: iss.v1.CrewMember
+	(*ListCrewResponse)(nil),       // 8: iss.v1.ListCrewResponse
+	(*timestamppb.Timestamp)(nil),  // 9: google.protobuf.Timestamp
+}
+var file_iss_proto_depIdxs = []int32{
+	9,  // 0: iss.v1.Position.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 1: iss.v1.Pass.start:type_name -> google.protobuf.Timestamp
+	9,  // 2: iss.v1.Pass.max:type_name -> google.protobuf.Timestamp
+	9,  // 3: iss.v1.Pass.end:type_name -> google.protobuf.Timestamp
+	4,  // 4: iss.v1.PredictPassesResponse.passes:type_name -> iss.v1.Pass
+	7,  // 5: iss.v1.ListCrewResponse.people:type_name -> iss.v1.CrewMember
+	0,  // 6: iss.v1.Tracker.GetPosition:input_type -> iss.v1.GetPositionRequest
+	1,  // 7: iss.v1.Tracker.StreamPositions:input_type -> iss.v1.StreamPositionsRequest
+	3,  // 8: iss.v1.Tracker.PredictPasses:input_type -> iss.v1.PredictPassesRequest
+	6,  // 9: iss.v1.Tracker.ListCrew:input_type -> iss.v1.ListCrewRequest
+	2,  // 10: iss.v1.Tracker.GetPosition:output_type -> iss.v1.Position
+	2,  // 11: iss.v1.Tracker.StreamPositions:output_type -> iss.v1.Position
+	5,  // 12: iss.v1.Tracker.PredictPasses:output_type -> iss.v1.PredictPassesResponse
+	8,  // 13: iss.v1.Tracker.ListCrew:output_type -> iss.v1.ListCrewResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_iss_proto_init() }
+func file_iss_proto_init() {
+	if File_iss_proto != nil {
+		return
+	}
+	file_iss_proto_msgTypes[2].OneofWrappers = []any{}
+	file_iss_proto_msgTypes[3].OneofWrappers = []any{}
+	file_iss_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iss_proto_rawDesc), len(file_iss_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_iss_proto_goTypes,
+		DependencyIndexes: file_iss_proto_depIdxs,
+		MessageInfos:      file_iss_proto_msgTypes,
+	}.Build()
+	File_iss_proto = out.File
+	file_iss_proto_goTypes = nil
+	file_iss_proto_depIdxs = nil
+}
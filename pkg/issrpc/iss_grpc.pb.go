@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: iss.proto
+
+package issrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Tracker_GetPosition_FullMethodName     = "/iss.v1.Tracker/GetPosition"
+	Tracker_StreamPositions_FullMethodName = "/iss.v1.Tracker/StreamPositions"
+	Tracker_PredictPasses_FullMethodName   = "/iss.v1.Tracker/PredictPasses"
+	Tracker_ListCrew_FullMethodName        = "/iss.v1.Tracker/ListCrew"
+)
+
+// TrackerClient is the client API for Tracker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Tracker serves the position, passes and crew that the TUI shows.
+type TrackerClient interface {
+	// GetPosition returns the latest position. It fails with UNAVAILABLE
+	// until the first one has been fetched.
+	GetPosition(ctx context.Context, in *GetPositionRequest, opts ...grpc.CallOption) (*Position, error)
+	// StreamPositions sends the latest position and then every new one.
+	StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Position], error)
+	// PredictPasses computes the passes over an observer from the current TLE.
+	PredictPasses(ctx context.Context, in *PredictPassesRequest, opts ...grpc.CallOption) (*PredictPassesResponse, error)
+	// ListCrew returns everyone in space.
+	ListCrew(ctx context.Context, in *ListCrewRequest, opts ...grpc.CallOption) (*ListCrewResponse, error)
+}
+
+type trackerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackerClient(cc grpc.ClientConnInterface) TrackerClient {
+	return &trackerClient{cc}
+}
+
+func (c *trackerClient) GetPosition(ctx context.Context, in *GetPositionRequest, opts ...grpc.CallOption) (*Position, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Position)
+	err := c.cc.Invoke(ctx, Tracker_GetPosition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerClient) StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Position], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Tracker_ServiceDesc.Streams[0], Tracker_StreamPositions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPositionsRequest, Position]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Tracker_StreamPositionsClient = grpc.ServerStreamingClient[Position]
+
+func (c *trackerClient) PredictPasses(ctx context.Context, in *PredictPassesRequest, opts ...grpc.CallOption) (*PredictPassesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PredictPassesResponse)
+	err := c.cc.Invoke(ctx, Tracker_PredictPasses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackerClient) ListCrew(ctx context.Context, in *ListCrewRequest, opts ...grpc.CallOption) (*ListCrewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCrewResponse)
+	err := c.cc.Invoke(ctx, Tracker_ListCrew_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackerServer is the server API for Tracker service.
+// All implementations must embed UnimplementedTrackerServer
+// for forward compatibility.
+//
+// Tracker serves the position, passes and crew that the TUI shows.
+type TrackerServer interface {
+	// GetPosition returns the latest position. It fails with UNAVAILABLE
+	// until the first one has been fetched.
+	GetPosition(context.Context, *GetPositionRequest) (*Position, error)
+	// StreamPositions sends the latest position and then every new one.
+	StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[Position]) error
+	// PredictPasses computes the passes over an observer from the current TLE.
+	PredictPasses(context.Context, *PredictPassesRequest) (*PredictPassesResponse, error)
+	// ListCrew returns everyone in space.
+	ListCrew(context.Context, *ListCrewRequest) (*ListCrewResponse, error)
+	mustEmbedUnimplementedTrackerServer()
+}
+
+// UnimplementedTrackerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrackerServer struct{}
+
+func (UnimplementedTrackerServer) GetPosition(context.Context, *GetPositionRequest) (*Position, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPosition not implemented")
+}
+func (UnimplementedTrackerServer) StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[Position]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPositions not implemented")
+}
+func (UnimplementedTrackerServer) PredictPasses(context.Context, *PredictPassesRequest) (*PredictPassesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PredictPasses not implemented")
+}
+func (UnimplementedTrackerServer) ListCrew(context.Context, *ListCrewRequest) (*ListCrewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCrew not implemented")
+}
+func (UnimplementedTrackerServer) mustEmbedUnimplementedTrackerServer() {}
+func (UnimplementedTrackerServer) testEmbeddedByValue()                 {}
+
+// UnsafeTrackerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackerServer will
+// result in compilation errors.
+type UnsafeTrackerServer interface {
+	mustEmbedUnimplementedTrackerServer()
+}
+
+func RegisterTrackerServer(s grpc.ServiceRegistrar, srv TrackerServer) {
+	// If the following call pancis, it indicates UnimplementedTrackerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Tracker_ServiceDesc, srv)
+}
+
+func _Tracker_GetPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServer).GetPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tracker_GetPosition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServer).GetPosition(ctx, req.(*GetPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tracker_StreamPositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPositionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrackerServer).StreamPositions(m, &grpc.GenericServerStream[StreamPositionsRequest, Position]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Tracker_StreamPositionsServer = grpc.ServerStreamingServer[Position]
+
+func _Tracker_PredictPasses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictPassesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServer).PredictPasses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tracker_PredictPasses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServer).PredictPasses(ctx, req.(*PredictPassesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tracker_ListCrew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCrewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackerServer).ListCrew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tracker_ListCrew_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackerServer).ListCrew(ctx, req.(*ListCrewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tracker_ServiceDesc is the grpc.ServiceDesc for Tracker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tracker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iss.v1.Tracker",
+	HandlerType: (*TrackerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPosition",
+			Handler:    _Tracker_GetPosition_Handler,
+		},
+		{
+			MethodName: "PredictPasses",
+			Handler:    _Tracker_PredictPasses_Handler,
+		},
+		{
+			MethodName: "ListCrew",
+			Handler:    _Tracker_ListCrew_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPositions",
+			Handler:       _Tracker_StreamPositions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "iss.proto",
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// Attachment is a file sent with an email.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Email sends mail through an SMTP server. Addr is host:port; port 465 uses
+// TLS from the start, any other port upgrades with STARTTLS when the server
+// offers it. Username and Password are optional.
+type Email struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Send mails a plain-text message with the attachments to every recipient.
+func (e Email) Send(subject, text string, attachments ...Attachment) error {
+	body, err := e.compose(subject, text, attachments, time.Now())
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return fmt.Errorf("from: %w", err)
+	}
+	recipients := make([]string, len(e.To))
+	for i, to := range e.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		recipients[i] = addr.Address
+	}
+
+	client, err := e.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if e.Username != "" {
+		host, _, _ := net.SplitHostPort(e.Addr)
+		if err := client.Auth(smtp.PlainAuth("", e.Username, e.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range recipients {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (e Email) dial() (*smtp.Client, error) {
+	host, port, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	config := &tls.Config{ServerName: host}
+
+	var conn net.Conn
+	if port == "465" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", e.Addr, config)
+	} else {
+		conn, err = dialer.Dial("tcp", e.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if port != "465" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(config); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+func (e Email) compose(subject, text string, attachments []Attachment, now time.Time) ([]byte, error) {
+	if len(e.To) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", e.From)
+	header("To", strings.Join(e.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(e.From))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/mixed; boundary="+form.Boundary())
+	buf.WriteString("\r\n")
+
+	part, err := form.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		part, err := form.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, host, ok := strings.Cut(addr.Address, "@"); ok {
+			domain = host
+		}
+	}
+	var id [12]byte
+	rand.Read(id[:])
+	return "<" + hex.EncodeToString(id[:]) + "@" + domain + ">"
+}
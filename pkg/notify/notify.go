@@ -0,0 +1,232 @@
+// Package notify posts short messages, optionally with a PNG picture, to chat
+// and push services: Discord and Slack incoming webhooks, the Telegram bot
+// API, ntfy topics and Pushover. Email sends mail with attachments over SMTP.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// UserAgent is sent with every request.
+var UserAgent = "iss-tui/1.2 (+https://github.com/kivayan/iss)"
+
+// TelegramAPIURL is the Telegram bot API base URL.
+var TelegramAPIURL = "https://api.telegram.org"
+
+// PushoverAPIURL is the Pushover message endpoint.
+var PushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// NtfyServer is the ntfy server used when Ntfy.Server is empty.
+var NtfyServer = "https://ntfy.sh"
+
+// Message is what a notifier posts. Image, when set, is a PNG file.
+type Message struct {
+	Title string
+	Text  string
+	Image []byte
+}
+
+// Notifier posts messages to one chat service.
+type Notifier interface {
+	Name() string
+	Send(client *http.Client, msg Message) error
+}
+
+// Discord posts to a Discord channel webhook, with the image attached.
+type Discord struct {
+	WebhookURL string
+}
+
+// Slack posts to a Slack incoming webhook. Incoming webhooks cannot upload
+// files, so the image is left out.
+type Slack struct {
+	WebhookURL string
+}
+
+// Telegram sends through a bot to one chat, as a photo with a caption when
+// there is an image.
+type Telegram struct {
+	BotToken string
+	ChatID   string
+}
+
+// Ntfy publishes to an ntfy topic, with the image as an attachment. Token is
+// sent as a bearer token for protected topics.
+type Ntfy struct {
+	Server string
+	Topic  string
+	Token  string
+}
+
+// Pushover pushes to a Pushover user or group key through an application token.
+type Pushover struct {
+	Token string
+	User  string
+}
+
+func (Discord) Name() string  { return "discord" }
+func (Slack) Name() string    { return "slack" }
+func (Telegram) Name() string { return "telegram" }
+func (Ntfy) Name() string     { return "ntfy" }
+func (Pushover) Name() string { return "pushover" }
+
+// Send posts msg to the webhook.
+func (d Discord) Send(client *http.Client, msg Message) error {
+	payload, err := json.Marshal(map[string]string{"content": "**" + msg.Title + "**\n" + msg.Text})
+	if err != nil {
+		return err
+	}
+	if msg.Image == nil {
+		return post(client, d.WebhookURL, "application/json", bytes.NewReader(payload))
+	}
+
+	body, contentType, err := multipartBody(map[string]string{"payload_json": string(payload)}, "files[0]", msg.Image)
+	if err != nil {
+		return err
+	}
+	return post(client, d.WebhookURL, contentType, body)
+}
+
+// Send posts msg to the webhook.
+func (s Slack) Send(client *http.Client, msg Message) error {
+	payload, err := json.Marshal(map[string]string{"text": "*" + msg.Title + "*\n" + msg.Text})
+	if err != nil {
+		return err
+	}
+	return post(client, s.WebhookURL, "application/json", bytes.NewReader(payload))
+}
+
+// Send posts msg to the chat.
+func (t Telegram) Send(client *http.Client, msg Message) error {
+	text := msg.Title + "\n" + msg.Text
+	base := strings.TrimRight(TelegramAPIURL, "/") + "/bot" + t.BotToken
+	if msg.Image == nil {
+		payload, err := json.Marshal(map[string]string{"chat_id": t.ChatID, "text": text})
+		if err != nil {
+			return err
+		}
+		return post(client, base+"/sendMessage", "application/json", bytes.NewReader(payload))
+	}
+
+	body, contentType, err := multipartBody(map[string]string{"chat_id": t.ChatID, "caption": text}, "photo", msg.Image)
+	if err != nil {
+		return err
+	}
+	return post(client, base+"/sendPhoto", contentType, body)
+}
+
+// Send publishes msg to the topic.
+func (n Ntfy) Send(client *http.Client, msg Message) error {
+	server := n.Server
+	if server == "" {
+		server = NtfyServer
+	}
+	endpoint := strings.TrimRight(server, "/") + "/" + url.PathEscape(n.Topic)
+
+	var req *http.Request
+	var err error
+	if msg.Image == nil {
+		req, err = http.NewRequest(http.MethodPost, endpoint, strings.NewReader(msg.Text))
+	} else {
+		req, err = http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(msg.Image))
+		if err == nil {
+			req.Header.Set("Filename", "map.png")
+			req.Header.Set("Message", mime.BEncoding.Encode("utf-8", msg.Text))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", mime.BEncoding.Encode("utf-8", msg.Title))
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return do(client, req)
+}
+
+// Send pushes msg to the user.
+func (p Pushover) Send(client *http.Client, msg Message) error {
+	fields := map[string]string{"token": p.Token, "user": p.User, "title": msg.Title, "message": msg.Text}
+	if msg.Image == nil {
+		form := url.Values{}
+		for name, value := range fields {
+			form.Set(name, value)
+		}
+		return post(client, PushoverAPIURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	}
+
+	body, contentType, err := multipartBody(fields, "attachment", msg.Image)
+	if err != nil {
+		return err
+	}
+	return post(client, PushoverAPIURL, contentType, body)
+}
+
+func multipartBody(fields map[string]string, fileField string, png []byte) (io.Reader, string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename="map.png"`, fileField))
+	header.Set("Content-Type", "image/png")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(png); err != nil {
+		return nil, "", err
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, form.FormDataContentType(), nil
+}
+
+func post(client *http.Client, endpoint, contentType string, body io.Reader) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return do(client, req)
+}
+
+func do(client *http.Client, req *http.Request) error {
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return redact(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// redact drops the request URL from transport errors, because webhook URLs
+// and bot tokens are secrets that would otherwise end up on screen and in logs.
+func redact(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
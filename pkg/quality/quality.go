@@ -0,0 +1,95 @@
+// Package quality cross-checks reported positions against a local SGP4
+// propagation to catch stale element sets and misbehaving APIs.
+package quality
+
+import (
+	"math"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/track"
+)
+
+// DefaultThresholdKm is the ground distance above which a reported position
+// counts as a discrepancy. One second of timestamp error is about 8 km.
+const DefaultThresholdKm = 100.0
+
+// Check compares one reported position with the propagated one at the same
+// instant. AltitudeOffsetKm is only meaningful when HasAltitude is set.
+type Check struct {
+	Provider         string
+	Time             time.Time
+	OffsetKm         float64
+	AltitudeOffsetKm float64
+	HasAltitude      bool
+}
+
+// Compare propagates sat to t, the time pos was reported for, and measures
+// how far apart the two ground points are.
+func Compare(sat *track.Satellite, pos track.Position, t time.Time) (Check, error) {
+	predicted, err := sat.PositionAt(t)
+	if err != nil {
+		return Check{}, err
+	}
+
+	check := Check{
+		Provider: pos.Provider,
+		Time:     t,
+		OffsetKm: geo.HaversineKm(pos.Lat, pos.Lon, predicted.Lat, predicted.Lon),
+	}
+	if pos.HasAltitude {
+		check.AltitudeOffsetKm = math.Abs(pos.AltitudeKm - predicted.AltitudeKm)
+		check.HasAltitude = true
+	}
+	return check, nil
+}
+
+// Kind says whether a provider went out of or came back within tolerance.
+type Kind int
+
+const (
+	Discrepancy Kind = iota
+	Recovered
+)
+
+// Event reports a provider changing state, with the check that caused it.
+type Event struct {
+	Kind  Kind
+	Check Check
+}
+
+// Monitor tracks per provider whether reported positions are within
+// ThresholdKm of the prediction. It reports transitions only, so a stale
+// element set produces one event rather than one per sample.
+type Monitor struct {
+	ThresholdKm float64
+	flagged     map[string]bool
+}
+
+// NewMonitor returns a monitor with the given threshold, or
+// DefaultThresholdKm if it is not positive.
+func NewMonitor(thresholdKm float64) *Monitor {
+	if thresholdKm <= 0 {
+		thresholdKm = DefaultThresholdKm
+	}
+	return &Monitor{ThresholdKm: thresholdKm, flagged: map[string]bool{}}
+}
+
+// Observe records a check and returns an event if the provider crossed the
+// threshold in either direction.
+func (m *Monitor) Observe(c Check) (Event, bool) {
+	bad := c.OffsetKm > m.ThresholdKm
+	if bad == m.flagged[c.Provider] {
+		return Event{}, false
+	}
+	m.flagged[c.Provider] = bad
+	if bad {
+		return Event{Kind: Discrepancy, Check: c}, true
+	}
+	return Event{Kind: Recovered, Check: c}, true
+}
+
+// Flagged reports whether provider is currently out of tolerance.
+func (m *Monitor) Flagged(provider string) bool {
+	return m.flagged[provider]
+}
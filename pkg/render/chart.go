@@ -0,0 +1,96 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Series is one line on a chart. Values share the x axis, one per column,
+// and NaN leaves a gap.
+type Series struct {
+	Values []float64
+	Glyph  byte
+}
+
+// Chart draws series as an ASCII line chart height rows tall, scaled to the
+// range of all values, with the top, middle and bottom of the range labelled
+// on the left using format (such as "%.1f km"). Steep steps are filled in so
+// the line stays connected. Later series are drawn over earlier ones, and
+// all of them over a '|' down each cursor column.
+func Chart(series []Series, height int, format string, cursors ...int) (string, error) {
+	if height < 3 {
+		return "", fmt.Errorf("chart height must be >= 3, got %d", height)
+	}
+
+	width := 0
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		width = max(width, len(s.Values))
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	if math.IsInf(low, 1) {
+		return "", errors.New("chart has no values")
+	}
+	if high == low {
+		low, high = low-1, high+1
+	}
+
+	grid := make([][]byte, height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(" ", width))
+		for _, x := range cursors {
+			if x >= 0 && x < width {
+				grid[y][x] = '|'
+			}
+		}
+	}
+	row := func(v float64) int {
+		return int(math.Round((high - v) / (high - low) * float64(height-1)))
+	}
+	for _, s := range series {
+		prev := -1
+		for x, v := range s.Values {
+			if math.IsNaN(v) {
+				prev = -1
+				continue
+			}
+			y := row(v)
+			from, to := y, y
+			if prev >= 0 {
+				from, to = min(prev+1, y), max(prev-1, y)
+			}
+			for r := from; r <= to; r++ {
+				grid[r][x] = s.Glyph
+			}
+			prev = y
+		}
+	}
+
+	labels := make([]string, height)
+	labels[0] = fmt.Sprintf(format, high)
+	mid := (high + low) / 2
+	if math.Abs(mid) < (high-low)*1e-6 {
+		mid = 0
+	}
+	labels[height/2] = fmt.Sprintf(format, mid)
+	labels[height-1] = fmt.Sprintf(format, low)
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(label))
+	}
+
+	var b strings.Builder
+	for y, line := range grid {
+		pad := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(labels[y]))
+		fmt.Fprintf(&b, "%s%s |%s\n", pad, labels[y], line)
+	}
+	b.WriteString(strings.Repeat(" ", labelWidth) + " +" + strings.Repeat("-", width))
+	return b.String(), nil
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+var (
+	imageOcean = color.RGBA{R: 0x0b, G: 0x1d, B: 0x33, A: 0xff}
+	imageLand  = color.RGBA{R: 0x3f, G: 0x8f, B: 0x5a, A: 0xff}
+)
+
+// Point is a dot drawn on an Image, Radius pixels across, with a dark outline
+// so it stands out over land and sea alike.
+type Point struct {
+	Lat    float64
+	Lon    float64
+	Radius int
+	Color  color.RGBA
+}
+
+// Image renders the land mask through view as an equirectangular picture
+// width pixels wide and half as tall, with points drawn on top in order. It
+// uses the same projection as the text map, so both show the same area.
+func Image(mask *mapascii.LandMask, width int, view Viewport, points ...Point) (*image.RGBA, error) {
+	if mask == nil || mask.Width < 2 || mask.Height < 2 || len(mask.Data) != mask.Width*mask.Height {
+		return nil, fmt.Errorf("invalid land mask")
+	}
+	if width < 2 {
+		return nil, fmt.Errorf("width must be at least 2, got %d", width)
+	}
+
+	height := width / 2
+	view = view.Clamped()
+	lonSpan, latSpan, lonStart, latStart := view.span()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		lat := latStart - latSpan*(float64(y)+0.5)/float64(height)
+		for x := 0; x < width; x++ {
+			lon := lonStart + lonSpan*(float64(x)+0.5)/float64(width)
+			img.SetRGBA(x, y, blend(imageOcean, imageLand, sampleLand(mask, lon, lat)))
+		}
+	}
+
+	for _, p := range points {
+		u, v, ok := view.project(p.Lon, p.Lat)
+		if !ok || math.IsNaN(u) || math.IsNaN(v) {
+			continue
+		}
+		cx, cy := int(math.Round(u*float64(width-1))), int(math.Round(v*float64(height-1)))
+		drawDot(img, cx, cy, p.Radius+1, color.RGBA{A: 0xff})
+		drawDot(img, cx, cy, p.Radius, p.Color)
+	}
+	return img, nil
+}
+
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	t = math.Min(math.Max(t, 0), 1)
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+func drawDot(img *image.RGBA, cx, cy, radius int, c color.RGBA) {
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= radius*radius && image.Pt(x, y).In(img.Rect) {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
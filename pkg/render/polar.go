@@ -0,0 +1,104 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PolarPoint is a sky position in degrees, azimuth clockwise from north.
+// Points below the horizon are not drawn.
+type PolarPoint struct {
+	Azimuth   float64
+	Elevation float64
+	Glyph     byte
+}
+
+// PolarStyle sets the SGR sequences for the grid and for the last point
+// plotted, which is usually the satellite itself. Empty colours disable
+// colouring.
+type PolarStyle struct {
+	GridColor   string
+	MarkerColor string
+}
+
+// Polar draws an azimuth/elevation plot with north up and east to the right.
+// The horizon is the outer ring, the zenith the centre, with rings at 30° and
+// 60°. radius is the horizon radius in rows; columns are doubled to keep the
+// plot round on terminal cells. Later points overwrite earlier ones.
+func Polar(points []PolarPoint, radius int, style PolarStyle) (string, error) {
+	if radius < 4 {
+		return "", fmt.Errorf("polar radius must be >= 4, got %d", radius)
+	}
+
+	width := 4*radius + 1
+	height := 2*radius + 1
+	grid := make([][]byte, height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(" ", width))
+	}
+
+	plot := func(azimuth, elevation float64) (int, int, bool) {
+		if elevation < 0 {
+			return 0, 0, false
+		}
+		r := (90 - math.Min(elevation, 90)) / 90 * float64(radius)
+		theta := azimuth * math.Pi / 180
+		x := int(math.Round(float64(2*radius) + 2*r*math.Sin(theta)))
+		y := int(math.Round(float64(radius) - r*math.Cos(theta)))
+		if x < 0 || x >= width || y < 0 || y >= height {
+			return 0, 0, false
+		}
+		return x, y, true
+	}
+
+	for _, ring := range []float64{0, 30, 60} {
+		steps := 8 * radius
+		for i := 0; i < steps; i++ {
+			if x, y, ok := plot(float64(i)*360/float64(steps), ring); ok {
+				grid[y][x] = '.'
+			}
+		}
+	}
+	for y := 1; y < height-1; y++ {
+		grid[y][2*radius] = ':'
+	}
+	for x := 2; x < width-2; x++ {
+		grid[radius][x] = '-'
+	}
+	grid[radius][2*radius] = '+'
+	grid[0][2*radius] = 'N'
+	grid[height-1][2*radius] = 'S'
+	grid[radius][0] = 'W'
+	grid[radius][width-1] = 'E'
+
+	markerAt := [2]int{-1, -1}
+	for _, p := range points {
+		if x, y, ok := plot(p.Azimuth, p.Elevation); ok {
+			grid[y][x] = p.Glyph
+			markerAt = [2]int{x, y}
+		}
+	}
+
+	lines := make([]string, height)
+	for y, row := range grid {
+		if style.GridColor == "" && style.MarkerColor == "" {
+			lines[y] = string(row)
+			continue
+		}
+
+		var b strings.Builder
+		for x, c := range row {
+			switch {
+			case x == markerAt[0] && y == markerAt[1] && style.MarkerColor != "":
+				b.WriteString(style.MarkerColor + string(c) + Reset)
+			case strings.IndexByte(".:-+", c) >= 0 && style.GridColor != "":
+				b.WriteString(style.GridColor + string(c) + Reset)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
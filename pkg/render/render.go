@@ -0,0 +1,487 @@
+// Package render rasterizes a land mask into an ASCII world map and draws the
+// ISS marker, point glyphs and frame on top of it.
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+// Reset is the SGR sequence that clears colours set by ColorSequence.
+const Reset = "\x1b[0m"
+
+var ansiColorCodes = map[string]string{
+	"black":          "30",
+	"red":            "31",
+	"green":          "32",
+	"yellow":         "33",
+	"blue":           "34",
+	"magenta":        "35",
+	"cyan":           "36",
+	"white":          "37",
+	"bright-black":   "90",
+	"bright-red":     "91",
+	"bright-green":   "92",
+	"bright-yellow":  "93",
+	"bright-blue":    "94",
+	"bright-magenta": "95",
+	"bright-cyan":    "96",
+	"bright-white":   "97",
+}
+
+// Key identifies a rasterized base map: the mask, the width in columns, the
+// supersampling factor, the character height-to-width ratio and the part of
+// the world shown.
+type Key struct {
+	Mask        *mapascii.LandMask
+	Size        int
+	Supersample int
+	CharAspect  float64
+	View        Viewport
+}
+
+// Viewport is the part of the world a map shows: Zoom times closer than the
+// whole world, centred on Lat/Lon. A Zoom of 1 or less shows the whole world.
+type Viewport struct {
+	Lat  float64
+	Lon  float64
+	Zoom float64
+}
+
+// World reports whether v shows the whole world.
+func (v Viewport) World() bool {
+	return v.Zoom <= 1 || math.IsNaN(v.Zoom) || math.IsInf(v.Zoom, 0)
+}
+
+// Clamped returns v with its centre moved so the view stays within the poles.
+func (v Viewport) Clamped() Viewport {
+	if v.World() {
+		return Viewport{}
+	}
+	limit := 90 - 90/v.Zoom
+	v.Lat = math.Min(math.Max(v.Lat, -limit), limit)
+	v.Lon = math.Mod(v.Lon+540, 360) - 180
+	return v
+}
+
+// Raster is a base map without a marker.
+type Raster struct {
+	key    Key
+	Width  int
+	Height int
+	lines  [][]byte
+}
+
+// Cache keeps the most recently rasterized base map.
+type Cache struct {
+	mu     sync.Mutex
+	raster *Raster
+}
+
+// Style controls the blank rows around the map, the frame and the SGR colour
+// sequences for land, frame and marker. Empty colours disable colouring.
+// Border and Marker swap the ASCII frame and marker for other glyphs.
+type Style struct {
+	MarginRows  int
+	Frame       bool
+	MapColor    string
+	FrameColor  string
+	MarkerColor string
+	Border      Border
+	Marker      MarkerGlyphs
+}
+
+// Border holds the pieces a frame is drawn with, each one cell wide. Empty
+// pieces fall back to the ASCII +, - and |.
+type Border struct {
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+}
+
+// Top returns the top edge of a frame around width cells.
+func (b Border) Top(width int) string {
+	return cmp.Or(b.TopLeft, "+") + strings.Repeat(cmp.Or(b.Horizontal, "-"), width) + cmp.Or(b.TopRight, "+")
+}
+
+// Bottom returns the bottom edge of a frame around width cells.
+func (b Border) Bottom(width int) string {
+	return cmp.Or(b.BottomLeft, "+") + strings.Repeat(cmp.Or(b.Horizontal, "-"), width) + cmp.Or(b.BottomRight, "+")
+}
+
+// Side returns the left and right edge of a frame.
+func (b Border) Side() string {
+	return cmp.Or(b.Vertical, "|")
+}
+
+// MarkerGlyphs replaces the centre and arms of the marker on output, each one
+// cell wide. Empty fields keep the marker's own ASCII characters.
+type MarkerGlyphs struct {
+	Center     string
+	Horizontal string
+	Vertical   string
+}
+
+func (g MarkerGlyphs) cell(part byte) string {
+	switch part {
+	case markerCenter:
+		return g.Center
+	case markerHorizontal:
+		return g.Horizontal
+	case markerVertical:
+		return g.Vertical
+	}
+	return ""
+}
+
+const (
+	markerHorizontal byte = iota + 1
+	markerVertical
+	markerCenter
+)
+
+// Glyph is a single character drawn at a geographic point, such as the
+// subsolar point. An empty Color draws it in the map colour. DX and DY move
+// it by whole cells, to place it next to the marker rather than under it.
+type Glyph struct {
+	Lat   float64
+	Lon   float64
+	DX    int
+	DY    int
+	Char  byte
+	Color string
+}
+
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Get returns the raster for key, rasterizing it only when the key changed.
+func (c *Cache) Get(key Key) (*Raster, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.raster != nil && c.raster.key == key {
+		return c.raster, nil
+	}
+
+	raster, err := Rasterize(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.raster = raster
+	return raster, nil
+}
+
+// Rasterize renders the base map for key, splitting rows across GOMAXPROCS workers.
+func Rasterize(key Key) (*Raster, error) {
+	mask := key.Mask
+	if mask == nil || mask.Width < 2 || mask.Height < 2 || len(mask.Data) != mask.Width*mask.Height {
+		return nil, fmt.Errorf("invalid land mask")
+	}
+	if key.Size <= 0 {
+		return nil, fmt.Errorf("size must be > 0, got %d", key.Size)
+	}
+	if key.Supersample <= 0 {
+		return nil, fmt.Errorf("supersample must be > 0, got %d", key.Supersample)
+	}
+	if key.CharAspect <= 0 || math.IsNaN(key.CharAspect) || math.IsInf(key.CharAspect, 0) {
+		return nil, fmt.Errorf("char aspect must be > 0, got %v", key.CharAspect)
+	}
+
+	width := key.Size
+	height := int(math.Round(float64(width) / (2.0 * key.CharAspect)))
+	if height <= 0 {
+		return nil, fmt.Errorf("size=%d with char aspect %v produces zero map height", key.Size, key.CharAspect)
+	}
+
+	lines := make([][]byte, height)
+	workers := min(runtime.GOMAXPROCS(0), height)
+	band := (height + workers - 1) / workers
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * band
+		end := min(start+band, height)
+		if start >= end {
+			break
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			errs[w] = rasterizeRows(mask, lines, start, end, width, height, key.Supersample, key.View.Clamped())
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Raster{key: key, Width: width, Height: height, lines: lines}, nil
+}
+
+func (v Viewport) span() (lonSpan, latSpan, lonStart, latStart float64) {
+	if v.World() {
+		return 360, 180, -180, 90
+	}
+	lonSpan, latSpan = 360/v.Zoom, 180/v.Zoom
+	return lonSpan, latSpan, v.Lon - lonSpan/2, v.Lat + latSpan/2
+}
+
+// project maps a point to fractions of the view's width and height, with
+// ok false when it lies outside a zoomed view.
+func (v Viewport) project(lon, lat float64) (float64, float64, bool) {
+	if !v.World() {
+		x := (math.Mod(lon-v.Lon+540, 360)-180)*v.Zoom/360 + 0.5
+		y := (v.Lat-lat)*v.Zoom/180 + 0.5
+		return x, y, x >= 0 && x <= 1 && y >= 0 && y <= 1
+	}
+
+	x := math.Mod((lon+180.0)/360.0, 1.0)
+	if x < 0 {
+		x += 1.0
+	}
+	return x, math.Min(math.Max((90.0-lat)/180.0, 0), 1), true
+}
+
+func rasterizeRows(mask *mapascii.LandMask, lines [][]byte, start, end, width, height, supersample int, view Viewport) error {
+	lonSpan, latSpan, lonStart, latStart := view.span()
+
+	subsamples := float64(supersample * supersample)
+	for row := start; row < end; row++ {
+		line := make([]byte, width)
+		for col := 0; col < width; col++ {
+			landSum := 0.0
+			for sy := 0; sy < supersample; sy++ {
+				for sx := 0; sx < supersample; sx++ {
+					x := float64(col) + (float64(sx)+0.5)/float64(supersample)
+					y := float64(row) + (float64(sy)+0.5)/float64(supersample)
+					lon := lonStart + lonSpan*(x/float64(width))
+					lat := latStart - latSpan*(y/float64(height))
+					landSum += sampleLand(mask, lon, lat)
+				}
+			}
+
+			ch, err := mapascii.CharForLandFraction(landSum / subsamples)
+			if err != nil {
+				return err
+			}
+			line[col] = ch
+		}
+		lines[row] = line
+	}
+
+	return nil
+}
+
+// Land returns how much of the mask cell at lat/lon is land, from 0 for
+// open water to 1.
+func Land(mask *mapascii.LandMask, lat, lon float64) float64 {
+	return sampleLand(mask, lon, lat)
+}
+
+func sampleLand(mask *mapascii.LandMask, lon, lat float64) float64 {
+	u := math.Mod((lon+180.0)/360.0, 1.0)
+	if u < 0 {
+		u += 1.0
+	}
+	v := math.Min(math.Max((90.0-lat)/180.0, 0), 1)
+
+	x := min(int(u*float64(mask.Width)), mask.Width-1)
+	y := min(int(v*float64(mask.Height)), mask.Height-1)
+
+	return mask.Data[y*mask.Width+x]
+}
+
+// Compose draws glyphs and then marker (if any) onto a copy of base and
+// returns the framed, coloured map as newline-separated rows.
+func Compose(base *Raster, marker *mapascii.Marker, style Style, glyphs ...Glyph) (string, error) {
+	lines := make([][]byte, base.Height)
+	for i, line := range base.lines {
+		lines[i] = append([]byte(nil), line...)
+	}
+
+	var cellColors []string
+	colorize := style.MapColor != "" || style.FrameColor != "" || style.MarkerColor != ""
+	for _, glyph := range glyphs {
+		x, y, ok := base.cellFor(glyph.Lon, glyph.Lat)
+		x, y = x+glyph.DX, y+glyph.DY
+		if base.key.View.World() {
+			x = (x%base.Width + base.Width) % base.Width
+		}
+		if !ok || x < 0 || x >= base.Width || y < 0 || y >= base.Height {
+			continue
+		}
+		lines[y][x] = markerRune(rune(glyph.Char), '*')
+		if glyph.Color != "" {
+			if cellColors == nil {
+				cellColors = make([]string, base.Width*base.Height)
+			}
+			cellColors[y*base.Width+x] = glyph.Color
+			colorize = true
+		}
+	}
+
+	var markerMask []byte
+	if marker != nil {
+		var err error
+		markerMask, err = applyMarker(base, lines, *marker)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	rows := make([]string, 0, base.Height+2+2*style.MarginRows)
+	for i := 0; i < style.MarginRows; i++ {
+		rows = append(rows, "")
+	}
+	if style.Frame {
+		rows = append(rows, frameBorder(style.Border.Top(base.Width), style.FrameColor))
+	}
+
+	for row, line := range lines {
+		var b strings.Builder
+		current := ""
+		setColor := func(next string) {
+			if !colorize || next == current {
+				return
+			}
+			if next == "" {
+				b.WriteString(Reset)
+			} else {
+				b.WriteString(next)
+			}
+			current = next
+		}
+
+		if style.Frame {
+			setColor(style.FrameColor)
+			b.WriteString(style.Border.Side())
+		}
+		for col, ch := range line {
+			cell := row*base.Width + col
+			switch {
+			case markerMask != nil && markerMask[cell] != 0 && style.MarkerColor != "":
+				setColor(style.MarkerColor)
+			case cellColors != nil && cellColors[cell] != "" && (markerMask == nil || markerMask[cell] == 0):
+				setColor(cellColors[cell])
+			default:
+				setColor(style.MapColor)
+			}
+			if markerMask != nil {
+				if glyph := style.Marker.cell(markerMask[cell]); glyph != "" {
+					b.WriteString(glyph)
+					continue
+				}
+			}
+			b.WriteByte(ch)
+		}
+		if style.Frame {
+			setColor(style.FrameColor)
+			b.WriteString(style.Border.Side())
+		}
+		setColor("")
+		rows = append(rows, b.String())
+	}
+
+	if style.Frame {
+		rows = append(rows, frameBorder(style.Border.Bottom(base.Width), style.FrameColor))
+	}
+	for i := 0; i < style.MarginRows; i++ {
+		rows = append(rows, "")
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+func frameBorder(border string, color string) string {
+	if color == "" {
+		return border
+	}
+	return color + border + Reset
+}
+
+func applyMarker(base *Raster, lines [][]byte, marker mapascii.Marker) ([]byte, error) {
+	if math.IsNaN(marker.Lon) || math.IsInf(marker.Lon, 0) || math.IsNaN(marker.Lat) || math.IsInf(marker.Lat, 0) {
+		return nil, fmt.Errorf("marker lon and lat must be finite")
+	}
+
+	center := markerRune(marker.Center, 'O')
+	horizontal := markerRune(marker.Horizontal, '-')
+	vertical := markerRune(marker.Vertical, '|')
+
+	width, height := base.Width, base.Height
+	xCenter, yCenter, ok := base.cellFor(marker.Lon, marker.Lat)
+	if !ok {
+		return nil, nil
+	}
+
+	xStart, xEnd := 0, width-1
+	if marker.ArmX >= 0 {
+		xStart = max(0, xCenter-marker.ArmX)
+		xEnd = min(width-1, xCenter+marker.ArmX)
+	}
+	yStart, yEnd := 0, height-1
+	if marker.ArmY >= 0 {
+		yStart = max(0, yCenter-marker.ArmY)
+		yEnd = min(height-1, yCenter+marker.ArmY)
+	}
+
+	markerMask := make([]byte, width*height)
+	for y := yStart; y <= yEnd; y++ {
+		lines[y][xCenter] = vertical
+		markerMask[y*width+xCenter] = markerVertical
+	}
+	for x := xStart; x <= xEnd; x++ {
+		lines[yCenter][x] = horizontal
+		markerMask[yCenter*width+x] = markerHorizontal
+	}
+	lines[yCenter][xCenter] = center
+	markerMask[yCenter*width+xCenter] = markerCenter
+
+	return markerMask, nil
+}
+
+func (r *Raster) cellFor(lon, lat float64) (int, int, bool) {
+	if math.IsNaN(lon) || math.IsInf(lon, 0) || math.IsNaN(lat) || math.IsInf(lat, 0) {
+		return 0, 0, false
+	}
+
+	u, v, ok := r.key.View.Clamped().project(lon, lat)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(math.Round(u * float64(r.Width-1))), int(math.Round(v * float64(r.Height-1))), true
+}
+
+func markerRune(value rune, fallback rune) byte {
+	if value == 0 || value > 127 {
+		return byte(fallback)
+	}
+	return byte(value)
+}
+
+// ColorSequence returns the SGR sequence for a colour name such as "green" or
+// "bright-blue", or "" for unknown names.
+func ColorSequence(name string) string {
+	code, ok := ansiColorCodes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return ""
+	}
+	return "\x1b[" + code + "m"
+}
@@ -0,0 +1,161 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+func testMask() *mapascii.LandMask {
+	const width, height = 72, 36
+	data := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x/6+y/6)%2 == 0 {
+				data[y*width+x] = 1
+			}
+		}
+	}
+	return &mapascii.LandMask{Width: width, Height: height, Data: data}
+}
+
+func testKey(size int) Key {
+	return Key{Mask: testMask(), Size: size, Supersample: 2, CharAspect: 2}
+}
+
+func sameLines(a, b *Raster) bool {
+	if len(a.lines) != len(b.lines) {
+		return false
+	}
+	for i := range a.lines {
+		if !bytes.Equal(a.lines[i], b.lines[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCacheHit(t *testing.T) {
+	c := NewCache()
+	key := testKey(40)
+
+	first, err := c.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("same key rasterized again")
+	}
+	if first.Width != 40 || first.Height != 10 {
+		t.Errorf("raster is %dx%d, want 40x10", first.Width, first.Height)
+	}
+}
+
+func TestCacheEvict(t *testing.T) {
+	c := NewCache()
+	small, large := testKey(40), testKey(60)
+	large.Mask = small.Mask
+
+	first, err := c.Get(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.Get(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first || other.Width != 60 {
+		t.Fatalf("changed key returned the cached raster")
+	}
+
+	again, err := c.Get(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == first {
+		t.Error("cache kept more than the most recent raster")
+	}
+	if !sameLines(again, first) {
+		t.Error("re-rasterized map differs from the first one")
+	}
+}
+
+func TestCacheKeepsOnError(t *testing.T) {
+	c := NewCache()
+	key := testKey(40)
+	first, err := c.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := key
+	bad.Size = 0
+	if _, err := c.Get(bad); err == nil {
+		t.Fatal("size 0 accepted")
+	}
+
+	again, err := c.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("failed rasterization evicted the cached raster")
+	}
+}
+
+func embeddedMask(tb testing.TB) *mapascii.LandMask {
+	tb.Helper()
+	mask, err := mapascii.LoadEmbeddedDefaultLandMask()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return mask
+}
+
+func TestRasterizeMatchesSerial(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(7))
+	mask := embeddedMask(t)
+
+	for _, key := range []Key{
+		{Mask: mask, Size: 80, Supersample: 3, CharAspect: 2},
+		{Mask: mask, Size: 200, Supersample: 2, CharAspect: 2},
+		{Mask: mask, Size: 241, Supersample: 1, CharAspect: 1.7},
+		{Mask: mask, Size: 120, Supersample: 2, CharAspect: 2, View: Viewport{Lat: 50, Lon: 170, Zoom: 4}},
+	} {
+		parallel, err := Rasterize(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		serial := make([][]byte, parallel.Height)
+		if err := rasterizeRows(key.Mask, serial, 0, parallel.Height, parallel.Width, parallel.Height, key.Supersample, key.View.Clamped()); err != nil {
+			t.Fatal(err)
+		}
+		for row := range serial {
+			if !bytes.Equal(parallel.lines[row], serial[row]) {
+				t.Fatalf("size %d view %+v: row %d differs:\n%s\n%s", key.Size, key.View, row, parallel.lines[row], serial[row])
+			}
+		}
+	}
+}
+
+func BenchmarkRasterize(b *testing.B) {
+	mask := embeddedMask(b)
+	for _, size := range []int{80, 200, 240} {
+		key := Key{Mask: mask, Size: size, Supersample: 3, CharAspect: 2}
+		b.Run(fmt.Sprintf("cols=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Rasterize(key); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
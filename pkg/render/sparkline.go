@@ -0,0 +1,33 @@
+package render
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one block character per value, scaled from zero up to top
+// or up to the largest value if that is bigger. NaN values, for gaps with no
+// data, are drawn as spaces.
+func Sparkline(values []float64, top float64) string {
+	for _, v := range values {
+		if !math.IsNaN(v) && v > top {
+			top = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteByte(' ')
+		case top <= 0 || v <= 0:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			level := int(math.Round(v / top * float64(len(sparkBlocks)-1)))
+			b.WriteRune(sparkBlocks[min(level, len(sparkBlocks)-1)])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,400 @@
+// Package rules parses and evaluates small boolean expressions such as
+// "elevation > 40 and night" against a set of named values. Expressions can
+// only compare and combine the values they are given, so user-written rules
+// cannot reach anything else.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrUnknown is returned by Eval when the expression uses a value that is not
+// known at the moment, such as the elevation without an observer.
+var ErrUnknown = errors.New("value not known")
+
+// Vars are the values an expression is evaluated against. Each is a float64,
+// a string or a bool; a missing name is unknown.
+type Vars map[string]any
+
+// Rule is a parsed expression.
+type Rule struct {
+	source string
+	root   node
+}
+
+// Parse compiles expr. Only the names listed in known may be used; the
+// grammar is comparisons (< <= > >= == !=) of numbers, quoted strings,
+// true/false and names, combined with and, or, not and parentheses.
+func Parse(expr string, known []string) (*Rule, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, known: map[string]bool{}}
+	for _, name := range known {
+		p.known[name] = true
+	}
+
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEnd {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos+1)
+	}
+	return &Rule{source: expr, root: root}, nil
+}
+
+// String returns the expression the rule was parsed from.
+func (r *Rule) String() string {
+	return r.source
+}
+
+// Eval reports whether the rule holds for vars. It returns ErrUnknown when a
+// value the outcome depends on is missing, and an error when values of
+// different types are compared.
+func (r *Rule) Eval(vars Vars) (bool, error) {
+	v, err := r.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q is not true or false", r.source)
+	}
+	return b, nil
+}
+
+type tokenKind int
+
+const (
+	tokEnd tokenKind = iota
+	tokNumber
+	tokString
+	tokName
+	tokOp
+	tokOpen
+	tokClose
+)
+
+var operatorAliases = map[string]string{"&&": "and", "||": "or", "!": "not"}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(' || r == ')':
+			kind := tokOpen
+			if r == ')' {
+				kind = tokClose
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), pos: start})
+			i++
+		case r == '"' || r == '\'':
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start+1)
+			}
+			tokens = append(tokens, token{kind: tokString, text: string(runes[start+1 : i]), pos: start})
+			i++
+		case unicode.IsDigit(r) || r == '.' || r == '-' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.'):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokName
+			if word == "and" || word == "or" || word == "not" {
+				kind = tokOp
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: start})
+		default:
+			rest := string(runes[i:min(i+2, len(runes))])
+			matched := ""
+			for _, candidate := range []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "!"} {
+				if strings.HasPrefix(rest, candidate) {
+					matched = candidate
+					break
+				}
+			}
+			if matched == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", r, start+1)
+			}
+			i += len(matched)
+			if alias, ok := operatorAliases[matched]; ok {
+				matched = alias
+			}
+			tokens = append(tokens, token{kind: tokOp, text: matched, pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokEnd, text: "end of rule", pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	known  map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEnd {
+		p.next++
+	}
+	return tok
+}
+
+func (p *parser) isOp(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokOp && tok.text == text
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("or") {
+		p.take()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("and") {
+		p.take()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.isOp("not") {
+		p.take()
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return negation{operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind != tokOp || tok.text == "and" || tok.text == "or" || tok.text == "not" {
+		return left, nil
+	}
+	p.take()
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return compare{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *parser) operand() (node, error) {
+	tok := p.take()
+	switch tok.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q at %d", tok.text, tok.pos+1)
+		}
+		return literal{value: f}, nil
+	case tokString:
+		return literal{value: tok.text}, nil
+	case tokName:
+		switch tok.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		}
+		if !p.known[tok.text] {
+			return nil, fmt.Errorf("unknown name %q at %d", tok.text, tok.pos+1)
+		}
+		return variable{name: tok.text}, nil
+	case tokOpen:
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.take(); closing.kind != tokClose {
+			return nil, fmt.Errorf("expected ) at %d", closing.pos+1)
+		}
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos+1)
+}
+
+type node interface {
+	eval(Vars) (any, error)
+}
+
+type literal struct {
+	value any
+}
+
+func (n literal) eval(Vars) (any, error) {
+	return n.value, nil
+}
+
+type variable struct {
+	name string
+}
+
+func (n variable) eval(vars Vars) (any, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", n.name, ErrUnknown)
+	}
+	if i, ok := v.(int); ok {
+		return float64(i), nil
+	}
+	return v, nil
+}
+
+type negation struct {
+	operand node
+}
+
+func (n negation) eval(vars Vars) (any, error) {
+	b, err := evalBool(n.operand, vars)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logical struct {
+	or          bool
+	left, right node
+}
+
+func (n logical) eval(vars Vars) (any, error) {
+	left, leftErr := evalBool(n.left, vars)
+	if leftErr == nil && left == n.or {
+		return left, nil
+	}
+	if leftErr != nil && !errors.Is(leftErr, ErrUnknown) {
+		return nil, leftErr
+	}
+
+	right, err := evalBool(n.right, vars)
+	if err != nil {
+		return nil, err
+	}
+	if right == n.or {
+		return right, nil
+	}
+	if leftErr != nil {
+		return nil, leftErr
+	}
+	return right, nil
+}
+
+type compare struct {
+	op          string
+	left, right node
+}
+
+func (n compare) eval(vars Vars) (any, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return ordered(n.op, l, r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return ordered(n.op, strings.ToLower(l), strings.ToLower(r)), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok && (n.op == "==" || n.op == "!=") {
+			return (l == r) == (n.op == "=="), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %v %s %v", left, n.op, right)
+}
+
+func ordered[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "==":
+		return l == r
+	}
+	return l != r
+}
+
+func evalBool(n node, vars Vars) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%v is not true or false", v)
+	}
+	return b, nil
+}
@@ -0,0 +1,201 @@
+// Package screen keeps a grid of the cells a terminal shows and works out the
+// escape sequences that redraw only the cells a new frame changes.
+package screen
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// mergeGap is the longest run of unchanged cells rewritten rather than
+// skipped, since moving the cursor over it costs about as many bytes.
+const mergeGap = 4
+
+// Cell is one terminal cell: the grapheme drawn in it and the SGR sequences in
+// effect since the last reset. The cells that a wide grapheme covers after its
+// first have an empty Text.
+type Cell struct {
+	Text  string
+	Style string
+}
+
+func (c Cell) covered() bool {
+	return c.Text == ""
+}
+
+// ParseLine splits a rendered line into cells. It reports false when the line
+// has control characters or escape sequences other than SGR, whose effect on
+// the cells it cannot know.
+func ParseLine(line string) ([]Cell, bool) {
+	cells := []Cell{}
+	style := ""
+	var state byte
+	for len(line) > 0 {
+		seq, width, n, next := ansi.DecodeSequence(line, state, nil)
+		state = next
+		line = line[n:]
+		switch {
+		case width > 0:
+			cells = append(cells, Cell{Text: seq, Style: style})
+			for i := 1; i < width; i++ {
+				cells = append(cells, Cell{Style: style})
+			}
+		case combining(seq) && len(cells) > 0:
+			last := len(cells) - 1
+			for cells[last].covered() {
+				last--
+			}
+			cells[last].Text += seq
+		case seq == "\x1b[m" || seq == "\x1b[0m":
+			style = ""
+		case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+			style += seq
+		default:
+			return nil, false
+		}
+	}
+	return cells, true
+}
+
+// combining reports whether seq is a mark that the decoder returned apart from
+// the grapheme it belongs to.
+func combining(seq string) bool {
+	r, _ := utf8.DecodeRuneInString(seq)
+	return unicode.IsMark(r)
+}
+
+// Grid is what the terminal shows, row by row, relative to the top of the
+// frame. It is double-buffered: a frame is worked out against the front rows
+// and only becomes the front once it has been drawn in full.
+type Grid struct {
+	front [][]Cell
+	back  [][]Cell
+}
+
+// Reset forgets the grid, so that the next frame is drawn as it is.
+func (g *Grid) Reset() {
+	g.front, g.back = nil, nil
+}
+
+// Frame returns the output that draws rows over the grid, starting with the
+// cursor in the top left cell and leaving it at the start of the last row. A
+// nil row is unchanged and skipped. Frame reports false when rows have to be
+// drawn as they are: when the row count changed or every row is new, which is
+// how a repaint after a resize or a cleared screen looks. The grid then takes
+// rows as its front, with skipped rows unknown.
+func (g *Grid) Frame(rows [][]Cell) (string, bool) {
+	painted := 0
+	for _, row := range rows {
+		if row != nil {
+			painted++
+		}
+	}
+	if len(rows) != len(g.front) || painted == len(rows) {
+		g.swap(rows, len(rows) == len(g.front))
+		return "", false
+	}
+
+	var b strings.Builder
+	c := cursor{}
+	for y, row := range rows {
+		switch {
+		case row == nil:
+		case g.front[y] == nil:
+			c.moveTo(&b, y, 0)
+			c.write(&b, row)
+			c.restyle(&b, "")
+			b.WriteString(ansi.EraseLineRight)
+		default:
+			c.diff(&b, y, g.front[y], row)
+		}
+	}
+	c.restyle(&b, "")
+	c.moveTo(&b, len(rows)-1, 0)
+	g.swap(rows, true)
+	return b.String(), true
+}
+
+func (g *Grid) swap(rows [][]Cell, keep bool) {
+	g.back = append(g.back[:0], rows...)
+	for y, row := range g.back {
+		if row == nil && keep {
+			g.back[y] = g.front[y]
+		}
+	}
+	g.front, g.back = g.back, g.front
+}
+
+type cursor struct {
+	row, col int
+	style    string
+	styled   bool
+}
+
+func (c *cursor) moveTo(b *strings.Builder, row, col int) {
+	if row > c.row {
+		b.WriteString(ansi.CursorDown(row - c.row))
+		c.row = row
+	}
+	if col != c.col {
+		if col == 0 {
+			b.WriteByte('\r')
+		} else {
+			b.WriteString(ansi.CursorHorizontalAbsolute(col + 1))
+		}
+		c.col = col
+	}
+}
+
+func (c *cursor) restyle(b *strings.Builder, style string) {
+	if c.styled && style == c.style {
+		return
+	}
+	b.WriteString(ansi.ResetStyle)
+	b.WriteString(style)
+	c.style, c.styled = style, true
+}
+
+func (c *cursor) write(b *strings.Builder, cells []Cell) {
+	for _, cell := range cells {
+		if cell.covered() {
+			continue
+		}
+		c.restyle(b, cell.Style)
+		b.WriteString(cell.Text)
+		c.col += ansi.StringWidth(cell.Text)
+	}
+}
+
+func (c *cursor) diff(b *strings.Builder, row int, old, cells []Cell) {
+	changed := func(x int) bool {
+		return x >= len(old) || old[x] != cells[x]
+	}
+	for x := 0; x < len(cells); x++ {
+		if !changed(x) {
+			continue
+		}
+		start, end := x, x+1
+		for y := end; y < len(cells) && y-end < mergeGap; y++ {
+			if changed(y) {
+				end = y + 1
+			}
+		}
+		for start > 0 && (cells[start].covered() || start < len(old) && old[start].covered()) {
+			start--
+		}
+		for end < len(cells) && (cells[end].covered() || end < len(old) && old[end].covered()) {
+			end++
+		}
+		c.moveTo(b, row, start)
+		c.write(b, cells[start:end])
+		x = end - 1
+	}
+	if len(old) > len(cells) {
+		c.moveTo(b, row, len(cells))
+		c.restyle(b, "")
+		b.WriteString(ansi.EraseLineRight)
+	}
+}
@@ -0,0 +1,334 @@
+package screen
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// term is a minimal terminal: enough of the cursor, erase and SGR handling to
+// replay what Frame writes. Overwriting either half of a wide grapheme blanks
+// both halves, as terminals do.
+type term struct {
+	rows     [][]Cell
+	row, col int
+	style    string
+}
+
+func (t *term) apply(tb testing.TB, out string) {
+	tb.Helper()
+	var state byte
+	for len(out) > 0 {
+		seq, width, n, next := ansi.DecodeSequence(out, state, nil)
+		state = next
+		out = out[n:]
+		switch {
+		case width > 0:
+			t.put(seq, width)
+		case combining(seq) && t.col > 0:
+			line := *t.line()
+			x := t.col - 1
+			for line[x].covered() {
+				x--
+			}
+			line[x].Text += seq
+		case seq == "\r":
+			t.col = 0
+		case seq == "\n":
+			t.row++
+		case strings.HasPrefix(seq, "\x1b["):
+			t.csi(tb, seq)
+		default:
+			tb.Fatalf("unexpected sequence %q", seq)
+		}
+	}
+}
+
+func (t *term) csi(tb testing.TB, seq string) {
+	final := seq[len(seq)-1]
+	param := seq[2 : len(seq)-1]
+	count := 1
+	if final != 'm' && param != "" {
+		var err error
+		if count, err = strconv.Atoi(param); err != nil {
+			tb.Fatalf("unexpected sequence %q", seq)
+		}
+	}
+	switch final {
+	case 'B':
+		t.row += count
+	case 'G':
+		t.col = count - 1
+	case 'K':
+		if line := t.line(); len(*line) > t.col {
+			t.blankWide(t.col)
+			*line = (*line)[:t.col]
+		}
+	case 'm':
+		if param == "" || param == "0" {
+			t.style = ""
+		} else {
+			t.style += seq
+		}
+	default:
+		tb.Fatalf("unexpected sequence %q", seq)
+	}
+}
+
+func (t *term) line() *[]Cell {
+	for len(t.rows) <= t.row {
+		t.rows = append(t.rows, nil)
+	}
+	return &t.rows[t.row]
+}
+
+func (t *term) blankWide(x int) {
+	line := *t.line()
+	if x >= len(line) {
+		return
+	}
+	start := x
+	for start > 0 && line[start].covered() {
+		start--
+	}
+	end := x + 1
+	for end < len(line) && line[end].covered() {
+		end++
+	}
+	if end-start > 1 {
+		for i := start; i < end; i++ {
+			line[i] = Cell{Text: " ", Style: line[start].Style}
+		}
+	}
+}
+
+func (t *term) put(text string, width int) {
+	line := t.line()
+	for len(*line) < t.col+width {
+		*line = append(*line, Cell{Text: " "})
+	}
+	for x := t.col; x < t.col+width; x++ {
+		t.blankWide(x)
+	}
+	(*line)[t.col] = Cell{Text: text, Style: t.style}
+	for x := 1; x < width; x++ {
+		(*line)[t.col+x] = Cell{Style: t.style}
+	}
+	t.col += width
+}
+
+// paint draws rows as they are, the way a repaint that Frame declined would.
+func (t *term) paint(rows [][]Cell) {
+	for len(t.rows) < len(rows) {
+		t.rows = append(t.rows, nil)
+	}
+	for y, row := range rows {
+		if row != nil {
+			t.rows[y] = append([]Cell(nil), row...)
+		}
+	}
+	t.rows = t.rows[:len(rows)]
+}
+
+func parse(tb testing.TB, lines ...string) [][]Cell {
+	tb.Helper()
+	rows := make([][]Cell, len(lines))
+	for i, line := range lines {
+		row, ok := ParseLine(line)
+		if !ok {
+			tb.Fatalf("ParseLine(%q) failed", line)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// changed returns next with the rows equal to those in prev set to nil, as
+// the renderer skips lines it already drew.
+func changed(prev, next [][]Cell) [][]Cell {
+	out := make([][]Cell, len(next))
+	for y, row := range next {
+		if y >= len(prev) || fmt.Sprint(prev[y]) != fmt.Sprint(row) {
+			out[y] = row
+		}
+	}
+	return out
+}
+
+func sameCells(a, b []Cell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// redraw draws next over prev with g and checks the terminal ends up showing
+// next with the cursor at the start of the last row.
+func redraw(t *testing.T, g *Grid, screen *term, prev, next [][]Cell) string {
+	t.Helper()
+	out, ok := g.Frame(changed(prev, next))
+	if !ok {
+		screen.paint(next)
+		return ""
+	}
+	screen.row, screen.col, screen.style = 0, 0, ""
+	screen.apply(t, out)
+	for y := range next {
+		if !sameCells(screen.rows[y], next[y]) {
+			t.Fatalf("after %q row %d is %v, want %v", out, y, screen.rows[y], next[y])
+		}
+	}
+	if screen.row != len(next)-1 || screen.col != 0 || screen.style != "" {
+		t.Fatalf("after %q cursor at %d,%d with style %q", out, screen.row, screen.col, screen.style)
+	}
+	return out
+}
+
+func TestParseLine(t *testing.T) {
+	red, bold := "\x1b[31m", "\x1b[1m"
+	for _, tc := range []struct {
+		name string
+		line string
+		want []Cell
+	}{
+		{"plain", "ab", []Cell{{Text: "a"}, {Text: "b"}}},
+		{"empty", "", []Cell{}},
+		{"wide rune", "a世b", []Cell{{Text: "a"}, {Text: "世"}, {}, {Text: "b"}}},
+		{"style change mid-row", "a" + red + "b" + bold + "c\x1b[0md", []Cell{{Text: "a"}, {Text: "b", Style: red}, {Text: "c", Style: red + bold}, {Text: "d"}}},
+		{"short reset", red + "a\x1b[mb", []Cell{{Text: "a", Style: red}, {Text: "b"}}},
+		{"styled wide rune", red + "世", []Cell{{Text: "世", Style: red}, {Style: red}}},
+		{"combining mark", "éx", []Cell{{Text: "é"}, {Text: "x"}}},
+	} {
+		got, ok := ParseLine(tc.line)
+		if !ok || !sameCells(got, tc.want) {
+			t.Errorf("%s: ParseLine(%q) = %v, %v, want %v", tc.name, tc.line, got, ok, tc.want)
+		}
+	}
+
+	for _, line := range []string{"a\x1b[2Cb", "a\tb", "a\rb", "\x1b[K", "a\x1b]8;;https://example.com\x07b"} {
+		if _, ok := ParseLine(line); ok {
+			t.Errorf("ParseLine(%q) accepted a sequence that moves or erases", line)
+		}
+	}
+}
+
+func TestFrameDeclinesRepaints(t *testing.T) {
+	var g Grid
+	first := parse(t, "one", "two")
+	if _, ok := g.Frame(first); ok {
+		t.Fatal("first frame was diffed")
+	}
+	if _, ok := g.Frame([][]Cell{nil, parse(t, "tw0")[0]}); !ok {
+		t.Fatal("frame with one changed row was not diffed")
+	}
+	if _, ok := g.Frame(parse(t, "one", "two", "three")); ok {
+		t.Fatal("frame with more rows was diffed")
+	}
+	if _, ok := g.Frame(parse(t, "1", "2", "3")); ok {
+		t.Fatal("frame with every row changed was diffed")
+	}
+
+	g.Reset()
+	if _, ok := g.Frame([][]Cell{nil, nil, parse(t, "x")[0]}); ok {
+		t.Fatal("frame after Reset was diffed")
+	}
+}
+
+func TestFrameMergeGap(t *testing.T) {
+	base := parse(t, "abcdefghijklmnop", "last")
+
+	var g Grid
+	g.Frame(base)
+	near := parse(t, "aBcdEfghijklmnop", "last")
+	if out, _ := g.Frame(changed(base, near)); out != "\x1b[2G"+ansi.ResetStyle+"BcdE"+ansi.CursorDown(1)+"\r" {
+		t.Errorf("changes %d cells apart: %q, want one run", 3, out)
+	}
+
+	g.Reset()
+	g.Frame(base)
+	far := parse(t, "aBcdefghiJklmnop", "last")
+	if out, _ := g.Frame(changed(base, far)); out != "\x1b[2G"+ansi.ResetStyle+"B\x1b[10GJ"+ansi.CursorDown(1)+"\r" {
+		t.Errorf("changes %d cells apart: %q, want two runs", 8, out)
+	}
+}
+
+func TestFrameShrinkingRow(t *testing.T) {
+	var g Grid
+	screen := &term{}
+	prev := parse(t, "hello world", "last")
+	g.Frame(prev)
+	screen.paint(prev)
+
+	next := parse(t, "hello", "last")
+	out := redraw(t, &g, screen, prev, next)
+	if !strings.Contains(out, ansi.EraseLineRight) {
+		t.Errorf("shrunk row not erased: %q", out)
+	}
+	if strings.Contains(out, "hello") {
+		t.Errorf("unchanged cells rewritten: %q", out)
+	}
+}
+
+func TestFrameWideRunes(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"ab世cd", "ab世xd"},
+		{"ab世cd", "abx世d"},
+		{"ab世cd", "abxycd"},
+		{"abxycd", "ab世cd"},
+		{"a世世b", "a世x世"},
+		{"世", "世界"},
+		{"世界", "世"},
+	} {
+		var g Grid
+		screen := &term{}
+		prev := parse(t, tc[0], "last")
+		g.Frame(prev)
+		screen.paint(prev)
+		redraw(t, &g, screen, prev, parse(t, tc[1], "last"))
+	}
+}
+
+func TestFrameStyles(t *testing.T) {
+	var g Grid
+	screen := &term{}
+	prev := parse(t, "plain \x1b[31mred\x1b[0m text", "last")
+	g.Frame(prev)
+	screen.paint(prev)
+
+	redraw(t, &g, screen, prev, parse(t, "plain \x1b[32mred\x1b[0m text", "last"))
+	redraw(t, &g, screen, parse(t, "plain \x1b[32mred\x1b[0m text", "last"), parse(t, "plain \x1b[32mred \x1b[1mbold\x1b[0m", "last"))
+}
+
+func TestFrameRandom(t *testing.T) {
+	pieces := []string{"a", "b", " ", "世", "\x1b[31m", "\x1b[1m", "\x1b[0m", "é", "e\u0301"}
+	rng := rand.New(rand.NewSource(1))
+	line := func() string {
+		var b strings.Builder
+		for n := rng.Intn(12); n > 0; n-- {
+			b.WriteString(pieces[rng.Intn(len(pieces))])
+		}
+		return b.String()
+	}
+
+	var g Grid
+	screen := &term{}
+	lines := []string{line(), line(), line(), line()}
+	prev := parse(t, lines...)
+	g.Frame(prev)
+	screen.paint(prev)
+	for i := 0; i < 2000; i++ {
+		lines[rng.Intn(len(lines))] = line()
+		next := parse(t, lines...)
+		redraw(t, &g, screen, prev, next)
+		prev = next
+	}
+}
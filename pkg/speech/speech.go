@@ -0,0 +1,115 @@
+// Package speech reads short phrases aloud through a text-to-speech program:
+// say on macOS, the System.Speech synthesizer through PowerShell on Windows,
+// and espeak-ng or espeak elsewhere.
+package speech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// speaking is held while a phrase is read, so that phrases from different
+// goroutines follow each other instead of talking over each other.
+var speaking sync.Mutex
+
+// Speaker reads phrases aloud.
+type Speaker interface {
+	Name() string
+	Speak(ctx context.Context, text string) error
+}
+
+// Command speaks by running Program with Args followed by the phrase.
+type Command struct {
+	Program string
+	Args    []string
+}
+
+// PowerShell speaks through the .NET System.Speech synthesizer. Voice, when
+// set, is the name of an installed voice.
+type PowerShell struct {
+	Voice string
+}
+
+// Name is the program's base name.
+func (c Command) Name() string {
+	return filepath.Base(c.Program)
+}
+
+// Name is "powershell".
+func (PowerShell) Name() string {
+	return "powershell"
+}
+
+// Speak runs the program and waits for it to finish.
+func (c Command) Speak(ctx context.Context, text string) error {
+	return run(ctx, c.Program, append(append([]string{}, c.Args...), text)...)
+}
+
+// Speak runs a PowerShell script that reads text and waits for it to finish.
+func (p PowerShell) Speak(ctx context.Context, text string) error {
+	script := "Add-Type -AssemblyName System.Speech; $s = New-Object System.Speech.Synthesis.SpeechSynthesizer; "
+	if p.Voice != "" {
+		script += "$s.SelectVoice(" + quote(p.Voice) + "); "
+	}
+	script += "$s.Speak(" + quote(text) + ")"
+	return run(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func run(ctx context.Context, program string, args ...string) error {
+	speaking.Lock()
+	defer speaking.Unlock()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", filepath.Base(program), err, msg)
+		}
+		return fmt.Errorf("%s: %w", filepath.Base(program), err)
+	}
+	return nil
+}
+
+// Detect returns a Speaker for the text-to-speech program available on this
+// system. voice, when set, is passed as the voice name; otherwise espeak is
+// given lang, a language such as "de", so that it reads with the right
+// pronunciation.
+func Detect(voice, lang string) (Speaker, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("say"); err == nil {
+			if voice != "" {
+				return Command{Program: path, Args: []string{"-v", voice}}, nil
+			}
+			return Command{Program: path}, nil
+		}
+	case "windows":
+		if _, err := exec.LookPath("powershell"); err == nil {
+			return PowerShell{Voice: voice}, nil
+		}
+	}
+	if voice == "" {
+		voice = lang
+	}
+	for _, name := range []string{"espeak-ng", "espeak"} {
+		if path, err := exec.LookPath(name); err == nil {
+			if voice != "" {
+				return Command{Program: path, Args: []string{"-v", voice}}, nil
+			}
+			return Command{Program: path}, nil
+		}
+	}
+	return nil, errors.New("no text-to-speech program found (install espeak-ng, or set a command)")
+}
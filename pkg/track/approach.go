@@ -0,0 +1,95 @@
+package track
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+// Approach is the moment the ground track comes closest to a place, with the
+// great-circle distance between the place and the subpoint at that moment.
+type Approach struct {
+	Time       time.Time
+	DistanceKm float64
+}
+
+// NextApproach finds when the ground track next passes closest to lat/lon
+// between from and to. It returns the first local minimum of the distance
+// that is within withinKm, or the closest point of the whole window when the
+// track never comes that near.
+func NextApproach(s *Satellite, lat, lon float64, from, to time.Time, withinKm float64) (Approach, error) {
+	pos, err := s.PositionAt(from)
+	if err != nil {
+		return Approach{}, err
+	}
+	best := Approach{Time: from, DistanceKm: geo.HaversineKm(lat, lon, pos.Lat, pos.Lon)}
+	err = scanApproaches(s, lat, lon, from, to, func(a Approach) bool {
+		near := a.DistanceKm <= withinKm
+		if near || a.DistanceKm < best.DistanceKm {
+			best = a
+		}
+		return !near
+	})
+	if err != nil {
+		return Approach{}, err
+	}
+	return best, nil
+}
+
+// scanApproaches calls visit with each local minimum of the distance between
+// lat/lon and the subpoint, in order, until visit returns false or the window
+// or the orbit ends.
+func scanApproaches(s *Satellite, lat, lon float64, from, to time.Time, visit func(Approach) bool) error {
+	distanceAt := func(t time.Time) (float64, error) {
+		pos, err := s.PositionAt(t)
+		if err != nil {
+			return 0, err
+		}
+		return geo.HaversineKm(lat, lon, pos.Lat, pos.Lon), nil
+	}
+
+	prev, err := distanceAt(from)
+	if err != nil {
+		return err
+	}
+	falling := false
+
+	for t := from.Add(passScanStep); !t.After(to); t = t.Add(passScanStep) {
+		d, err := distanceAt(t)
+		if errors.Is(err, ErrDecayed) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if falling && d > prev && !visit(refineApproach(distanceAt, t.Add(-2*passScanStep), t)) {
+			return nil
+		}
+		falling = d < prev
+		prev = d
+	}
+
+	return nil
+}
+
+func refineApproach(distanceAt func(time.Time) (float64, error), lo, hi time.Time) Approach {
+	for hi.Sub(lo) > time.Second {
+		third := hi.Sub(lo) / 3
+		a, errA := distanceAt(lo.Add(third))
+		b, errB := distanceAt(hi.Add(-third))
+		if errA != nil || errB != nil {
+			break
+		}
+		if a > b {
+			lo = lo.Add(third)
+		} else {
+			hi = hi.Add(-third)
+		}
+	}
+
+	at := lo.Add(hi.Sub(lo) / 2)
+	d, _ := distanceAt(at)
+	return Approach{Time: at, DistanceKm: d}
+}
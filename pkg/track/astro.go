@@ -0,0 +1,143 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+// deltaT is TT minus UT, close enough for the lunar series over the 2020s.
+const deltaT = 69 * time.Second
+
+type lunarTerm struct {
+	d, m, mp, f float64
+	coeff       float64
+	distance    float64
+}
+
+// The largest terms of the ELP-2000/82 lunar series as given by Meeus,
+// Astronomical Algorithms, tables 47.A and 47.B, which together are good to
+// about 10 arcseconds.
+var lunarLongitude = []lunarTerm{
+	{0, 0, 1, 0, 6288774, -20905355}, {2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968}, {0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888}, {0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158}, {2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733}, {2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620}, {1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755}, {2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0}, {0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782}, {0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636}, {2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824}, {1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675}, {2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445}, {4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403}, {0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0}, {2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322}, {2, -2, 0, 0, 2236, -9884},
+	{0, 1, 2, 0, -2120, 5751}, {0, 2, 0, 0, -2069, 0},
+	{2, -2, -1, 0, 2048, -4950}, {2, 0, 1, -2, -1773, 4130},
+	{2, 0, 0, 2, -1595, 0}, {4, -1, -1, 0, 1215, -3958},
+	{0, 0, 2, 2, -1110, 0}, {3, 0, -1, 0, -892, 3258},
+	{2, 1, 1, 0, -810, 2616}, {4, -1, -2, 0, 759, -1897},
+	{0, 2, -1, 0, -713, -2117}, {2, 2, -1, 0, -700, 2354},
+	{2, 1, -2, 0, 691, 0}, {2, -1, 0, -2, 596, 0},
+	{4, 0, 1, 0, 549, -1423}, {0, 0, 4, 0, 537, -1117},
+	{4, -1, 0, 0, 520, -1571}, {1, 0, -2, 0, -487, -1739},
+	{2, 1, 0, -2, -399, 0}, {0, 0, 2, -2, -381, -4421},
+	{1, 1, 1, 0, 351, 0}, {3, 0, -2, 0, -340, 0},
+	{4, 0, -3, 0, 330, 0}, {2, -1, 2, 0, 327, 0},
+	{0, 2, 1, 0, -323, 1165}, {1, 1, -1, 0, 299, 0},
+	{2, 0, 3, 0, 294, 0}, {2, 0, -1, -2, 0, 8752},
+}
+
+var lunarLatitude = []lunarTerm{
+	{0, 0, 0, 1, 5128122, 0}, {0, 0, 1, 1, 280602, 0},
+	{0, 0, 1, -1, 277693, 0}, {2, 0, 0, -1, 173237, 0},
+	{2, 0, -1, 1, 55413, 0}, {2, 0, -1, -1, 46271, 0},
+	{2, 0, 0, 1, 32573, 0}, {0, 0, 2, 1, 17198, 0},
+	{2, 0, 1, -1, 9266, 0}, {0, 0, 2, -1, 8822, 0},
+	{2, -1, 0, -1, 8216, 0}, {2, 0, -2, -1, 4324, 0},
+	{2, 0, 1, 1, 4200, 0}, {2, 1, 0, -1, -3359, 0},
+	{2, -1, -1, 1, 2463, 0}, {2, -1, 0, 1, 2211, 0},
+	{2, -1, -1, -1, 2065, 0}, {0, 1, -1, -1, -1870, 0},
+	{4, 0, -1, -1, 1828, 0}, {0, 1, 0, 1, -1794, 0},
+	{0, 0, 0, 3, -1749, 0}, {0, 1, -1, 1, -1565, 0},
+	{1, 0, 0, 1, -1491, 0}, {0, 1, 1, 1, -1475, 0},
+	{0, 1, 1, -1, -1410, 0}, {0, 1, 0, -1, -1344, 0},
+	{1, 0, 0, -1, -1335, 0}, {0, 0, 3, 1, 1107, 0},
+	{4, 0, 0, -1, 1021, 0}, {4, 0, -1, 1, 833, 0},
+	{0, 0, 1, -3, 777, 0}, {4, 0, -2, 1, 671, 0},
+	{2, 0, 0, -3, 607, 0}, {2, 0, 2, -1, 596, 0},
+	{2, -1, 1, -1, 491, 0}, {2, 0, -2, 1, -451, 0},
+	{0, 0, 3, -1, 439, 0}, {2, 0, 2, 1, 422, 0},
+	{2, 0, -3, -1, 421, 0}, {2, 1, -1, 1, -366, 0},
+	{2, 1, 0, 1, -351, 0}, {4, 0, 0, 1, 331, 0},
+	{2, -1, 1, 1, 315, 0}, {2, -2, 0, -1, 302, 0},
+	{0, 0, 1, 3, -283, 0}, {2, 1, 1, -1, -229, 0},
+	{1, 1, 0, -1, 223, 0}, {1, 1, 0, 1, 223, 0},
+	{0, 1, -2, -1, -220, 0}, {2, 1, -1, -1, -220, 0},
+	{1, 0, 1, 1, -185, 0}, {2, -1, -2, -1, 181, 0},
+	{0, 1, 2, 1, -177, 0}, {4, 0, -2, -1, 176, 0},
+	{4, -1, -1, -1, 166, 0}, {1, 0, 1, -1, -164, 0},
+	{4, 0, 1, -1, 132, 0}, {1, 0, -1, -1, -119, 0},
+	{4, -1, 0, -1, 115, 0}, {2, -2, 0, 1, 107, 0},
+}
+
+func moonTEME(t time.Time) [3]float64 {
+	tt := (julianDate(t.Add(deltaT)) - julianJ2000) / 36525.0
+	deg := math.Pi / 180
+	sin := func(x float64) float64 { return math.Sin(x * deg) }
+	cos := func(x float64) float64 { return math.Cos(x * deg) }
+
+	meanLong := 218.3164477 + 481267.88123421*tt - 0.0015786*tt*tt
+	elongation := 297.8501921 + 445267.1114034*tt - 0.0018819*tt*tt
+	sunAnomaly := 357.5291092 + 35999.0502909*tt - 0.0001536*tt*tt
+	moonAnomaly := 134.9633964 + 477198.8675055*tt + 0.0087414*tt*tt
+	node := 93.2720950 + 483202.0175233*tt - 0.0036539*tt*tt
+	a1, a2, a3 := 119.75+131.849*tt, 53.09+479264.290*tt, 313.45+481266.484*tt
+	eccentricity := 1 - 0.002516*tt - 0.0000074*tt*tt
+
+	sum := func(terms []lunarTerm, trig func(float64) float64) (float64, float64) {
+		var value, distance float64
+		for _, term := range terms {
+			scale := math.Pow(eccentricity, math.Abs(term.m))
+			arg := term.d*elongation + term.m*sunAnomaly + term.mp*moonAnomaly + term.f*node
+			value += scale * term.coeff * sin(arg)
+			distance += scale * term.distance * trig(arg)
+		}
+		return value, distance
+	}
+	sumL, sumR := sum(lunarLongitude, cos)
+	sumB, _ := sum(lunarLatitude, cos)
+	sumL += 3958*sin(a1) + 1962*sin(meanLong-node) + 318*sin(a2)
+	sumB += -2235*sin(meanLong) + 382*sin(a3) + 175*sin(a1-node) + 175*sin(a1+node) +
+		127*sin(meanLong-moonAnomaly) - 115*sin(meanLong+moonAnomaly)
+
+	longitude := meanLong + sumL/1e6
+	latitude := sumB / 1e6
+	distance := 385000.56 + sumR/1000
+	obliquity := 23.439291 - 0.0130042*tt
+
+	return [3]float64{
+		distance * cos(latitude) * cos(longitude),
+		distance * (cos(obliquity)*cos(latitude)*sin(longitude) - sin(obliquity)*sin(latitude)),
+		distance * (sin(obliquity)*cos(latitude)*sin(longitude) + cos(obliquity)*sin(latitude)),
+	}
+}
+
+func subPoint(teme [3]float64, t time.Time) (lat, lon float64) {
+	r, _ := temeToECEF(teme, [3]float64{}, t)
+	lat = math.Atan2(r[2], math.Hypot(r[0], r[1])) * 180 / math.Pi
+	lon = math.Atan2(r[1], r[0]) * 180 / math.Pi
+	return lat, lon
+}
+
+// SubsolarPoint is where the Sun is directly overhead at t, in degrees.
+func SubsolarPoint(t time.Time) (lat, lon float64) {
+	return subPoint(sunTEME(t), t)
+}
+
+// SublunarPoint is where the Moon is directly overhead at t, in degrees.
+func SublunarPoint(t time.Time) (lat, lon float64) {
+	return subPoint(moonTEME(t), t)
+}
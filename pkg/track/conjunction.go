@@ -0,0 +1,117 @@
+package track
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const conjunctionScanStep = 10 * time.Second
+
+// Conjunction is the closest approach of two satellites: when it happens, how
+// far apart they are and how fast they pass each other.
+type Conjunction struct {
+	Time             time.Time
+	DistanceKm       float64
+	RelativeSpeedKmS float64
+	PositionA        Position
+	PositionB        Position
+}
+
+// ClosestApproach finds when a and b are nearest each other between from and
+// to. The window is scanned every 10 seconds and each local minimum refined to
+// about a millisecond, so fast head-on passes are not stepped over.
+func ClosestApproach(a, b *Satellite, from, to time.Time) (Conjunction, error) {
+	distanceAt := func(t time.Time) (float64, error) {
+		ra, _, err := a.At(t)
+		if err != nil {
+			return 0, err
+		}
+		rb, _, err := b.At(t)
+		if err != nil {
+			return 0, err
+		}
+		return math.Sqrt(sq(ra[0]-rb[0]) + sq(ra[1]-rb[1]) + sq(ra[2]-rb[2])), nil
+	}
+
+	prev, err := distanceAt(from)
+	if err != nil {
+		return Conjunction{}, err
+	}
+	bestTime, bestDistance := from, prev
+	falling := false
+
+	for t := from.Add(conjunctionScanStep); !t.After(to); t = t.Add(conjunctionScanStep) {
+		d, err := distanceAt(t)
+		if errors.Is(err, ErrDecayed) {
+			break
+		}
+		if err != nil {
+			return Conjunction{}, err
+		}
+		if falling && d > prev {
+			at, low := refineConjunction(distanceAt, t.Add(-2*conjunctionScanStep), t)
+			if low < bestDistance {
+				bestTime, bestDistance = at, low
+			}
+		}
+		if d < bestDistance {
+			bestTime, bestDistance = t, d
+		}
+		falling = d < prev
+		prev = d
+	}
+
+	return conjunctionAt(a, b, bestTime, bestDistance)
+}
+
+func refineConjunction(distanceAt func(time.Time) (float64, error), lo, hi time.Time) (time.Time, float64) {
+	for hi.Sub(lo) > time.Millisecond {
+		third := hi.Sub(lo) / 3
+		da, errA := distanceAt(lo.Add(third))
+		db, errB := distanceAt(hi.Add(-third))
+		if errA != nil || errB != nil {
+			break
+		}
+		if da > db {
+			lo = lo.Add(third)
+		} else {
+			hi = hi.Add(-third)
+		}
+	}
+
+	at := lo.Add(hi.Sub(lo) / 2)
+	d, _ := distanceAt(at)
+	return at, d
+}
+
+func conjunctionAt(a, b *Satellite, t time.Time, distance float64) (Conjunction, error) {
+	_, va, err := a.At(t)
+	if err != nil {
+		return Conjunction{}, err
+	}
+	_, vb, err := b.At(t)
+	if err != nil {
+		return Conjunction{}, err
+	}
+	posA, err := a.PositionAt(t)
+	if err != nil {
+		return Conjunction{}, err
+	}
+	posB, err := b.PositionAt(t)
+	if err != nil {
+		return Conjunction{}, err
+	}
+
+	return Conjunction{
+		Time:             t,
+		DistanceKm:       distance,
+		RelativeSpeedKmS: math.Sqrt(sq(va[0]-vb[0]) + sq(va[1]-vb[1]) + sq(va[2]-vb[2])),
+		PositionA:        posA,
+		PositionB:        posB,
+	}, nil
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
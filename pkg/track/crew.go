@@ -0,0 +1,78 @@
+package track
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const openNotifyAstrosURL = "http://api.open-notify.org/astros.json"
+
+// CrewMember is a person currently in space and the craft they are on.
+type CrewMember struct {
+	Name  string `json:"name"`
+	Craft string `json:"craft"`
+}
+
+type astrosResponse struct {
+	Message string       `json:"message"`
+	People  []CrewMember `json:"people"`
+}
+
+// CraftCount is how many people are aboard one craft.
+type CraftCount struct {
+	Craft  string
+	People int
+}
+
+// CountByCraft totals people per craft, most crowded first.
+func CountByCraft(people []CrewMember) []CraftCount {
+	index := map[string]int{}
+	var counts []CraftCount
+	for _, person := range people {
+		i, ok := index[person.Craft]
+		if !ok {
+			i = len(counts)
+			index[person.Craft] = i
+			counts = append(counts, CraftCount{Craft: person.Craft})
+		}
+		counts[i].People++
+	}
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].People > counts[j].People })
+	return counts
+}
+
+// FetchCrew returns everyone currently in space according to open-notify.
+// Filter on Craft == "ISS" for the station's crew.
+func FetchCrew(client *http.Client) ([]CrewMember, error) {
+	req, err := http.NewRequest(http.MethodGet, openNotifyAstrosURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("astros api status: %s", resp.Status)
+	}
+
+	data, err := readBody("astros", resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var payload astrosResponse
+	if err := decodeJSON("astros", data, &payload); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(payload.Message, "success") {
+		return nil, &ResponseError{Source: "astros", Field: "message", Reason: fmt.Sprintf("%q, want \"success\"", payload.Message)}
+	}
+
+	return payload.People, nil
+}
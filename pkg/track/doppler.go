@@ -0,0 +1,15 @@
+package track
+
+const speedOfLightKmS = 299792.458
+
+// DopplerDownlink is the frequency heard on the ground for a signal sent at
+// hz, given the range rate from Look (negative while approaching).
+func DopplerDownlink(hz, rangeRateKmS float64) float64 {
+	return hz * (1 - rangeRateKmS/speedOfLightKmS)
+}
+
+// DopplerUplink is the frequency to transmit on so that the satellite
+// receives hz.
+func DopplerUplink(hz, rangeRateKmS float64) float64 {
+	return hz / (1 - rangeRateKmS/speedOfLightKmS)
+}
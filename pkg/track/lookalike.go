@@ -0,0 +1,72 @@
+package track
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Lookalike is another satellite that comes close to a reference satellite in
+// an observer's sky: its closest angular separation from the reference and
+// when that happens.
+type Lookalike struct {
+	Name          string
+	NoradID       int
+	Time          time.Time
+	SeparationDeg float64
+	Elevation     float64
+}
+
+// Separation is the angle in degrees between two directions in the sky.
+func Separation(a, b Look) float64 {
+	const deg = math.Pi / 180
+	cos := math.Sin(a.Elevation*deg)*math.Sin(b.Elevation*deg) +
+		math.Cos(a.Elevation*deg)*math.Cos(b.Elevation*deg)*math.Cos((a.Azimuth-b.Azimuth)*deg)
+	return math.Acos(math.Min(math.Max(cos, -1), 1)) / deg
+}
+
+// FindLookalikes samples the sky track of ref as seen from o between from and
+// to, every step, and returns each of others that comes within withinDeg of
+// it while both are above the horizon, ordered by time. Others that fail to
+// propagate, and ref itself, are skipped.
+func FindLookalikes(ref *Satellite, others []*Satellite, o Observer, from, to time.Time, step time.Duration, withinDeg float64) []Lookalike {
+	var times []time.Time
+	var track []Look
+	for t := from; !t.After(to); t = t.Add(step) {
+		look, err := o.Look(ref, t)
+		if err != nil {
+			break
+		}
+		times, track = append(times, t), append(track, look)
+	}
+
+	var found []Lookalike
+	for _, sat := range others {
+		if sat == ref || sat.NoradID != 0 && sat.NoradID == ref.NoradID {
+			continue
+		}
+
+		best := Lookalike{SeparationDeg: math.Inf(1)}
+		for i, t := range times {
+			if track[i].Elevation <= 0 {
+				continue
+			}
+			look, err := o.Look(sat, t)
+			if err != nil {
+				break
+			}
+			if look.Elevation <= 0 {
+				continue
+			}
+			if sep := Separation(track[i], look); sep < best.SeparationDeg {
+				best = Lookalike{Name: sat.Name, NoradID: sat.NoradID, Time: t, SeparationDeg: sep, Elevation: look.Elevation}
+			}
+		}
+		if best.SeparationDeg <= withinDeg {
+			found = append(found, best)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Time.Before(found[j].Time) })
+	return found
+}
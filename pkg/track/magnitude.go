@@ -0,0 +1,42 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+// ISSStandardMagnitude is the station's visual magnitude at 1000 km range
+// and a 90° phase angle.
+const ISSStandardMagnitude = -1.8
+
+// Magnitude estimates the satellite's apparent visual magnitude from o at t,
+// treating it as a diffusely reflecting sphere with the given standard
+// magnitude. ok is false when the satellite is below the horizon or in the
+// Earth's shadow.
+func (o Observer) Magnitude(s *Satellite, t time.Time, standard float64) (magnitude float64, ok bool, err error) {
+	r, _, err := s.At(t)
+	if err != nil {
+		return 0, false, err
+	}
+
+	re, _ := temeToECEF(r, [3]float64{}, t)
+	_, elevation, rangeKm, rho := o.topocentric(re)
+	if elevation <= 0 {
+		return 0, false, nil
+	}
+	if lit, err := s.Sunlit(t); err != nil || !lit {
+		return 0, false, err
+	}
+
+	sun, _ := temeToECEF(sunTEME(t), [3]float64{}, t)
+	toSun := [3]float64{sun[0] - re[0], sun[1] - re[1], sun[2] - re[2]}
+	sunKm := math.Sqrt(toSun[0]*toSun[0] + toSun[1]*toSun[1] + toSun[2]*toSun[2])
+	cosPhase := -(toSun[0]*rho[0] + toSun[1]*rho[1] + toSun[2]*rho[2]) / (sunKm * rangeKm)
+	phase := math.Acos(math.Max(-1, math.Min(1, cosPhase)))
+
+	lit := math.Sin(phase) + (math.Pi-phase)*math.Cos(phase)
+	if lit <= 1e-6 {
+		return 0, false, nil
+	}
+	return standard + 5*math.Log10(rangeKm/1000) - 2.5*math.Log10(lit), true, nil
+}
@@ -0,0 +1,163 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	wgs84RadiusKm     = 6378.137
+	wgs84Flattening   = 1 / 298.257223563
+	earthRotationRadS = 7.292115146706979e-5
+	julianUnixEpoch   = 2440587.5
+	julianJ2000       = 2451545.0
+)
+
+// Observer is a point on the ground in geodetic degrees and kilometres above
+// the WGS84 ellipsoid.
+type Observer struct {
+	Lat   float64
+	Lon   float64
+	AltKm float64
+}
+
+// Look is the direction and distance from an observer to the satellite.
+// Azimuth and Elevation are in degrees, azimuth clockwise from north.
+type Look struct {
+	Azimuth      float64
+	Elevation    float64
+	RangeKm      float64
+	RangeRateKmS float64
+}
+
+func julianDate(t time.Time) float64 {
+	return julianUnixEpoch + float64(t.UnixNano())/float64(24*time.Hour)
+}
+
+func gmst(t time.Time) float64 {
+	tut1 := (julianDate(t) - julianJ2000) / 36525.0
+	seconds := -6.2e-6*tut1*tut1*tut1 + 0.093104*tut1*tut1 +
+		(876600.0*3600.0+8640184.812866)*tut1 + 67310.54841
+	theta := math.Mod(seconds*math.Pi/180.0/240.0, 2*math.Pi)
+	if theta < 0 {
+		theta += 2 * math.Pi
+	}
+	return theta
+}
+
+func temeToECEF(r, v [3]float64, t time.Time) ([3]float64, [3]float64) {
+	theta := gmst(t)
+	c, s := math.Cos(theta), math.Sin(theta)
+
+	re := [3]float64{c*r[0] + s*r[1], -s*r[0] + c*r[1], r[2]}
+	ve := [3]float64{
+		c*v[0] + s*v[1] + earthRotationRadS*re[1],
+		-s*v[0] + c*v[1] - earthRotationRadS*re[0],
+		v[2],
+	}
+	return re, ve
+}
+
+func geodeticToECEF(lat, lon, altKm float64) [3]float64 {
+	phi := lat * math.Pi / 180
+	lambda := lon * math.Pi / 180
+	e2 := wgs84Flattening * (2 - wgs84Flattening)
+	n := wgs84RadiusKm / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+
+	return [3]float64{
+		(n + altKm) * math.Cos(phi) * math.Cos(lambda),
+		(n + altKm) * math.Cos(phi) * math.Sin(lambda),
+		(n*(1-e2) + altKm) * math.Sin(phi),
+	}
+}
+
+func ecefToGeodetic(r [3]float64) (lat, lon, altKm float64) {
+	e2 := wgs84Flattening * (2 - wgs84Flattening)
+	p := math.Hypot(r[0], r[1])
+	lon = math.Atan2(r[1], r[0])
+
+	phi := math.Atan2(r[2], p*(1-e2))
+	var n float64
+	for i := 0; i < 10; i++ {
+		n = wgs84RadiusKm / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+		next := math.Atan2(r[2]+e2*n*math.Sin(phi), p)
+		if math.Abs(next-phi) < 1e-12 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	n = wgs84RadiusKm / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+
+	if math.Abs(math.Cos(phi)) > 1e-9 {
+		altKm = p/math.Cos(phi) - n
+	} else {
+		altKm = math.Abs(r[2]) - n*(1-e2)
+	}
+
+	return phi * 180 / math.Pi, lon * 180 / math.Pi, altKm
+}
+
+// PositionAt propagates to t and returns the sub-satellite point, altitude
+// and inertial speed.
+func (s *Satellite) PositionAt(t time.Time) (Position, error) {
+	r, v, err := s.At(t)
+	if err != nil {
+		return Position{}, err
+	}
+
+	re, _ := temeToECEF(r, v, t)
+	lat, lon, alt := ecefToGeodetic(re)
+	speed := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+
+	return Position{
+		Lat:         lat,
+		Lon:         lon,
+		AltitudeKm:  alt,
+		VelocityKmh: speed * 3600,
+		HasAltitude: true,
+		Timestamp:   t,
+		Provider:    "sgp4",
+	}, nil
+}
+
+// Look returns where the satellite appears from o at time t.
+func (o Observer) Look(s *Satellite, t time.Time) (Look, error) {
+	r, v, err := s.At(t)
+	if err != nil {
+		return Look{}, err
+	}
+
+	re, ve := temeToECEF(r, v, t)
+	azimuth, elevation, rangeKm, rho := o.topocentric(re)
+
+	return Look{
+		Azimuth:      azimuth,
+		Elevation:    elevation,
+		RangeKm:      rangeKm,
+		RangeRateKmS: (rho[0]*ve[0] + rho[1]*ve[1] + rho[2]*ve[2]) / rangeKm,
+	}, nil
+}
+
+func (o Observer) topocentric(target [3]float64) (azimuth, elevation, rangeKm float64, rho [3]float64) {
+	site := geodeticToECEF(o.Lat, o.Lon, o.AltKm)
+	rho = [3]float64{target[0] - site[0], target[1] - site[1], target[2] - site[2]}
+	rangeKm = math.Sqrt(rho[0]*rho[0] + rho[1]*rho[1] + rho[2]*rho[2])
+
+	phi := o.Lat * math.Pi / 180
+	lambda := o.Lon * math.Pi / 180
+	sinPhi, cosPhi := math.Sin(phi), math.Cos(phi)
+	sinLambda, cosLambda := math.Sin(lambda), math.Cos(lambda)
+
+	south := sinPhi*cosLambda*rho[0] + sinPhi*sinLambda*rho[1] - cosPhi*rho[2]
+	east := -sinLambda*rho[0] + cosLambda*rho[1]
+	zenith := cosPhi*cosLambda*rho[0] + cosPhi*sinLambda*rho[1] + sinPhi*rho[2]
+
+	azimuth = math.Atan2(east, -south) * 180 / math.Pi
+	if azimuth < 0 {
+		azimuth += 360
+	}
+	elevation = math.Asin(zenith/rangeKm) * 180 / math.Pi
+
+	return azimuth, elevation, rangeKm, rho
+}
@@ -0,0 +1,52 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+// OrbitNumber is the revolution the satellite is on at t, counted from the
+// element set's revolution number at epoch. A new revolution starts at each
+// ascending node. The element set stores only five digits, so for long-lived
+// satellites such as the ISS the count has wrapped past 100000.
+func (s *Satellite) OrbitNumber(t time.Time) int {
+	days := t.Sub(s.Epoch).Hours() / 24
+	revs := s.epochPhase + s.revsPerDay*days + s.revsPerDay2*days*days
+	return s.revNumber + int(math.Floor(revs))
+}
+
+// Period is how long one revolution takes at epoch.
+func (s *Satellite) Period() time.Duration {
+	return time.Duration(float64(24*time.Hour) / s.revsPerDay)
+}
+
+// OrbitChange is how far a newer element set moves the orbit from where an
+// older one predicted it would be.
+type OrbitChange struct {
+	From, To time.Time
+	DeltaKm  float64
+}
+
+// CompareOrbits propagates both element sets over the revolution after the
+// newer one's epoch and returns the difference in mean altitude, positive
+// when the newer set puts the satellite higher. SGP4's drag term already
+// covers ordinary decay, so a change of more than a few hundred metres
+// points to a reboost or an avoidance manoeuvre between the two epochs.
+func CompareOrbits(older, newer *Satellite) (OrbitChange, error) {
+	const samples = 60
+	start, period := newer.Epoch, newer.Period()
+	var sum float64
+	for i := 0; i < samples; i++ {
+		t := start.Add(period * time.Duration(i) / samples)
+		before, err := older.PositionAt(t)
+		if err != nil {
+			return OrbitChange{}, err
+		}
+		after, err := newer.PositionAt(t)
+		if err != nil {
+			return OrbitChange{}, err
+		}
+		sum += after.AltitudeKm - before.AltitudeKm
+	}
+	return OrbitChange{From: older.Epoch, To: newer.Epoch, DeltaKm: sum / samples}, nil
+}
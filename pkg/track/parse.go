@@ -0,0 +1,203 @@
+package track
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+// MaxResponseBytes caps how much of an API response is read.
+const MaxResponseBytes = 1 << 20
+
+// maxClockSkew is how far a provider's timestamp may be from the local clock
+// before it is treated as malformed.
+const maxClockSkew = 24 * time.Hour
+
+// ResponseError reports an API response that could not be used: too large,
+// not JSON, the wrong shape, or holding a value out of range. Field is the
+// JSON field at fault, or empty when the whole body is.
+type ResponseError struct {
+	Source string
+	Field  string
+	Reason string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s response: %s", e.Source, e.Reason)
+	}
+	return fmt.Sprintf("%s response: %s: %s", e.Source, e.Field, e.Reason)
+}
+
+func readBody(source string, r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxResponseBytes {
+		return nil, &ResponseError{Source: source, Reason: fmt.Sprintf("larger than %d bytes", MaxResponseBytes)}
+	}
+	return data, nil
+}
+
+func decodeJSON(source string, data []byte, v any) error {
+	err := json.Unmarshal(data, v)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return nil
+	case len(bytes.TrimSpace(data)) == 0:
+		return &ResponseError{Source: source, Reason: "empty body"}
+	case errors.As(err, &typeErr):
+		return &ResponseError{Source: source, Field: typeErr.Field, Reason: fmt.Sprintf("got JSON %s, want %s", typeErr.Value, jsonKind(typeErr.Type))}
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &syntaxErr) && strings.Contains(syntaxErr.Error(), "unexpected end"):
+		return &ResponseError{Source: source, Reason: "truncated JSON"}
+	case syntaxErr != nil:
+		return &ResponseError{Source: source, Reason: fmt.Sprintf("not valid JSON at byte %d", syntaxErr.Offset)}
+	}
+	return &ResponseError{Source: source, Reason: err.Error()}
+}
+
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	}
+	return "number"
+}
+
+// flexNumber accepts a JSON number or a string holding one, as open-notify
+// has sent both.
+type flexNumber struct {
+	value float64
+	set   bool
+}
+
+func (n *flexNumber) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if text == "" || text == "null" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("%s is not a number", data)
+	}
+	n.value, n.set = v, true
+	return nil
+}
+
+func checkCoordinates(source string, lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return &ResponseError{Source: source, Field: "latitude", Reason: fmt.Sprintf("%g is outside -90..90", lat)}
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return &ResponseError{Source: source, Field: "longitude", Reason: fmt.Sprintf("%g is outside -180..180", lon)}
+	}
+	return nil
+}
+
+func responseTime(raw json.RawMessage, now time.Time) time.Time {
+	var unix flexNumber
+	if unix.UnmarshalJSON(raw) != nil || !unix.set || unix.value <= 0 {
+		return now
+	}
+	at := time.Unix(int64(unix.value), 0)
+	if skew := at.Sub(now); skew > maxClockSkew || skew < -maxClockSkew {
+		return now
+	}
+	return at
+}
+
+// ParseOpenNotify reads an api.open-notify.org iss-now response. A missing
+// or malformed timestamp is replaced with now.
+func ParseOpenNotify(data []byte, now time.Time) (Position, error) {
+	const source = "open-notify"
+	var payload struct {
+		Message     string          `json:"message"`
+		Timestamp   json.RawMessage `json:"timestamp"`
+		ISSPosition *struct {
+			Latitude  flexNumber `json:"latitude"`
+			Longitude flexNumber `json:"longitude"`
+		} `json:"iss_position"`
+	}
+	if err := decodeJSON(source, data, &payload); err != nil {
+		return Position{}, err
+	}
+
+	if !strings.EqualFold(payload.Message, "success") {
+		return Position{}, &ResponseError{Source: source, Field: "message", Reason: fmt.Sprintf("%q, want \"success\"", payload.Message)}
+	}
+	if payload.ISSPosition == nil {
+		return Position{}, &ResponseError{Source: source, Field: "iss_position", Reason: "missing"}
+	}
+	lat, lon := payload.ISSPosition.Latitude, payload.ISSPosition.Longitude
+	if !lat.set || !lon.set {
+		return Position{}, &ResponseError{Source: source, Field: "iss_position", Reason: "latitude or longitude missing"}
+	}
+	if err := checkCoordinates(source, lat.value, lon.value); err != nil {
+		return Position{}, err
+	}
+
+	return Position{Lat: lat.value, Lon: lon.value, Timestamp: responseTime(payload.Timestamp, now)}, nil
+}
+
+// ParseWhereTheISS reads an api.wheretheiss.at satellite response. A missing
+// or malformed timestamp is replaced with now.
+func ParseWhereTheISS(data []byte, now time.Time) (Position, error) {
+	const source = "wheretheiss"
+	var payload struct {
+		Latitude  *float64        `json:"latitude"`
+		Longitude *float64        `json:"longitude"`
+		Altitude  float64         `json:"altitude"`
+		Velocity  float64         `json:"velocity"`
+		Timestamp json.RawMessage `json:"timestamp"`
+		Units     string          `json:"units"`
+	}
+	if err := decodeJSON(source, data, &payload); err != nil {
+		return Position{}, err
+	}
+
+	if payload.Latitude == nil || payload.Longitude == nil {
+		return Position{}, &ResponseError{Source: source, Reason: "latitude or longitude missing"}
+	}
+	if err := checkCoordinates(source, *payload.Latitude, *payload.Longitude); err != nil {
+		return Position{}, err
+	}
+
+	altitude, velocity := payload.Altitude, payload.Velocity
+	if strings.EqualFold(payload.Units, "miles") {
+		altitude /= geo.KmToMiles
+		velocity /= geo.KmToMiles
+	}
+	if altitude <= 0 {
+		return Position{}, &ResponseError{Source: source, Field: "altitude", Reason: fmt.Sprintf("%g is not above the ground", payload.Altitude)}
+	}
+	if velocity <= 0 {
+		return Position{}, &ResponseError{Source: source, Field: "velocity", Reason: fmt.Sprintf("%g is not positive", payload.Velocity)}
+	}
+
+	return Position{
+		Lat:         *payload.Latitude,
+		Lon:         *payload.Longitude,
+		AltitudeKm:  altitude,
+		VelocityKmh: velocity,
+		HasAltitude: true,
+		Timestamp:   responseTime(payload.Timestamp, now),
+	}, nil
+}
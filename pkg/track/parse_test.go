@@ -0,0 +1,146 @@
+package track
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	openNotifySample  = `{"message": "success", "timestamp": 1772366400, "iss_position": {"latitude": "-51.6423", "longitude": "112.2474"}}`
+	whereTheISSSample = `{"name":"iss","id":25544,"latitude":50.11496269845,"longitude":118.07900427317,"altitude":408.05526028199,"velocity":27635.971970874,"visibility":"daylight","footprint":4446.1877699772,"timestamp":1772366400,"daynum":2461101,"solar_lat":-7.6,"solar_lon":180.3,"units":"kilometers"}`
+)
+
+var parseNow = time.Unix(1772366400, 0)
+
+func TestParseOpenNotify(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		body          string
+		lat, lon      float64
+		at            time.Time
+		field, reason string
+	}{
+		{name: "numbers sent as strings", body: openNotifySample, lat: -51.6423, lon: 112.2474, at: parseNow},
+		{name: "plain numbers", body: `{"message":"success","timestamp":1772366400,"iss_position":{"latitude":10.5,"longitude":-20.25}}`, lat: 10.5, lon: -20.25, at: parseNow},
+		{name: "timestamp as string", body: `{"message":"success","timestamp":"1772366340","iss_position":{"latitude":1,"longitude":2}}`, lat: 1, lon: 2, at: parseNow.Add(-time.Minute)},
+		{name: "missing timestamp", body: `{"message":"success","iss_position":{"latitude":1,"longitude":2}}`, lat: 1, lon: 2, at: parseNow},
+		{name: "malformed timestamp", body: `{"message":"success","timestamp":"soon","iss_position":{"latitude":1,"longitude":2}}`, lat: 1, lon: 2, at: parseNow},
+		{name: "timestamp days off", body: `{"message":"success","timestamp":1,"iss_position":{"latitude":1,"longitude":2}}`, lat: 1, lon: 2, at: parseNow},
+		{name: "latitude above 90", body: `{"message":"success","iss_position":{"latitude":"91.5","longitude":"0"}}`, field: "latitude", reason: "outside -90..90"},
+		{name: "longitude out of range", body: `{"message":"success","iss_position":{"latitude":0,"longitude":-180.5}}`, field: "longitude", reason: "outside -180..180"},
+		{name: "latitude not a number", body: `{"message":"success","iss_position":{"latitude":"north","longitude":0}}`, reason: "not a number"},
+		{name: "missing position", body: `{"message":"success"}`, field: "iss_position", reason: "missing"},
+		{name: "missing longitude", body: `{"message":"success","iss_position":{"latitude":1}}`, field: "iss_position", reason: "latitude or longitude missing"},
+		{name: "failure message", body: `{"message":"failure"}`, field: "message", reason: `want "success"`},
+		{name: "wrong shape", body: `{"message":"success","iss_position":[1,2]}`, field: "iss_position", reason: "got JSON array, want object"},
+		{name: "empty body", body: "  \n", reason: "empty body"},
+		{name: "truncated JSON", body: openNotifySample[:40], reason: "truncated JSON"},
+		{name: "not JSON", body: "<html>502</html>", reason: "not valid JSON at byte 1"},
+	} {
+		pos, err := ParseOpenNotify([]byte(tc.body), parseNow)
+		checkParse(t, tc.name, pos, err, tc.lat, tc.lon, tc.at, tc.field, tc.reason)
+	}
+}
+
+func TestParseWhereTheISS(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		body          string
+		lat, lon      float64
+		at            time.Time
+		field, reason string
+	}{
+		{name: "sample", body: whereTheISSSample, lat: 50.11496269845, lon: 118.07900427317, at: parseNow},
+		{name: "miles", body: `{"latitude":1,"longitude":2,"altitude":253.5,"velocity":17170,"timestamp":1772366400,"units":"miles"}`, lat: 1, lon: 2, at: parseNow},
+		{name: "missing timestamp", body: `{"latitude":1,"longitude":2,"altitude":400,"velocity":27600}`, lat: 1, lon: 2, at: parseNow},
+		{name: "malformed timestamp", body: `{"latitude":1,"longitude":2,"altitude":400,"velocity":27600,"timestamp":{}}`, lat: 1, lon: 2, at: parseNow},
+		{name: "latitude above 90", body: `{"latitude":90.01,"longitude":2,"altitude":400,"velocity":27600}`, field: "latitude", reason: "outside -90..90"},
+		{name: "longitude out of range", body: `{"latitude":1,"longitude":540,"altitude":400,"velocity":27600}`, field: "longitude", reason: "outside -180..180"},
+		{name: "number sent as string", body: `{"latitude":"1","longitude":2,"altitude":400,"velocity":27600}`, field: "latitude", reason: "got JSON string, want number"},
+		{name: "missing latitude", body: `{"longitude":2,"altitude":400,"velocity":27600}`, reason: "latitude or longitude missing"},
+		{name: "underground", body: `{"latitude":1,"longitude":2,"altitude":-3,"velocity":27600}`, field: "altitude", reason: "not above the ground"},
+		{name: "standing still", body: `{"latitude":1,"longitude":2,"altitude":400}`, field: "velocity", reason: "not positive"},
+		{name: "empty body", body: "", reason: "empty body"},
+		{name: "truncated JSON", body: whereTheISSSample[:60], reason: "truncated JSON"},
+	} {
+		pos, err := ParseWhereTheISS([]byte(tc.body), parseNow)
+		checkParse(t, tc.name, pos, err, tc.lat, tc.lon, tc.at, tc.field, tc.reason)
+		if err == nil && (!pos.HasAltitude || pos.AltitudeKm < 400 || pos.AltitudeKm > 420) {
+			t.Errorf("%s: altitude = %v km, %v", tc.name, pos.AltitudeKm, pos.HasAltitude)
+		}
+	}
+}
+
+func checkParse(t *testing.T, name string, pos Position, err error, lat, lon float64, at time.Time, field, reason string) {
+	t.Helper()
+	if reason == "" {
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if pos.Lat != lat || pos.Lon != lon || !pos.Timestamp.Equal(at) {
+			t.Errorf("%s: got %v, %v at %v, want %v, %v at %v", name, pos.Lat, pos.Lon, pos.Timestamp, lat, lon, at)
+		}
+		return
+	}
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		t.Errorf("%s: err = %v, want a *ResponseError", name, err)
+		return
+	}
+	if respErr.Field != field || !strings.Contains(respErr.Reason, reason) {
+		t.Errorf("%s: field %q reason %q, want field %q reason containing %q", name, respErr.Field, respErr.Reason, field, reason)
+	}
+}
+
+func TestReadBodyLimit(t *testing.T) {
+	data, err := readBody("test", bytes.NewReader(make([]byte, MaxResponseBytes)))
+	if err != nil || len(data) != MaxResponseBytes {
+		t.Fatalf("body of exactly MaxResponseBytes: %d bytes, %v", len(data), err)
+	}
+
+	_, err = readBody("test", bytes.NewReader(make([]byte, MaxResponseBytes+1)))
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.Field != "" || !strings.Contains(respErr.Reason, "larger than") {
+		t.Errorf("oversized body: err = %v", err)
+	}
+}
+
+func checkFuzzedPosition(t *testing.T, pos Position, err error) {
+	if err != nil {
+		var respErr *ResponseError
+		if !errors.As(err, &respErr) {
+			t.Fatalf("error %v is not a *ResponseError", err)
+		}
+		return
+	}
+	if !(pos.Lat >= -90 && pos.Lat <= 90 && pos.Lon >= -180 && pos.Lon <= 180) {
+		t.Fatalf("coordinates out of range: %v, %v", pos.Lat, pos.Lon)
+	}
+	if pos.Timestamp.IsZero() {
+		t.Fatal("zero timestamp")
+	}
+}
+
+func FuzzParseOpenNotify(f *testing.F) {
+	f.Add([]byte(openNotifySample))
+	f.Add([]byte(`{"message":"success","timestamp":1772366400,"iss_position":{"latitude":10.5,"longitude":-20.25}}`))
+	f.Add([]byte(`{"message":"success","iss_position":{"latitude":"1e3","longitude":"NaN"}}`))
+	f.Add([]byte(""))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pos, err := ParseOpenNotify(data, parseNow)
+		checkFuzzedPosition(t, pos, err)
+	})
+}
+
+func FuzzParseWhereTheISS(f *testing.F) {
+	f.Add([]byte(whereTheISSSample))
+	f.Add([]byte(`{"latitude":1,"longitude":2,"altitude":253.5,"velocity":17170,"units":"miles"}`))
+	f.Add([]byte(`{"latitude":-90,"longitude":180,"altitude":1e308,"velocity":1e-300}`))
+	f.Add([]byte("null"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pos, err := ParseWhereTheISS(data, parseNow)
+		checkFuzzedPosition(t, pos, err)
+	})
+}
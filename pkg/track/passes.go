@@ -0,0 +1,147 @@
+package track
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	passScanStep       = 30 * time.Second
+	visibilitySampling = 10 * time.Second
+)
+
+// Pass is one rise-to-set crossing of the observer's horizon. Visible is set
+// when, for at least part of the pass, the station is sunlit while the
+// observer's sky is dark; Magnitude is then its brightest estimated magnitude
+// over that part. SunElevation is the Sun's elevation at Max.
+type Pass struct {
+	Start        time.Time
+	Max          time.Time
+	End          time.Time
+	StartAzimuth float64
+	MaxAzimuth   float64
+	EndAzimuth   float64
+	MaxElevation float64
+	SunElevation float64
+	Visible      bool
+	Magnitude    float64
+}
+
+// Duration is the time the satellite spends above the horizon.
+func (p Pass) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// FindPasses returns every pass that starts between from and to and climbs to
+// at least minElevation degrees. A pass already in progress at from is
+// included with its start clipped to from.
+func FindPasses(s *Satellite, o Observer, from, to time.Time, minElevation float64) ([]Pass, error) {
+	lookAt := func(t time.Time) (Look, error) {
+		return o.Look(s, t)
+	}
+
+	first, err := lookAt(from)
+	if err != nil {
+		return nil, err
+	}
+
+	var passes []Pass
+	var current *Pass
+	if first.Elevation > 0 {
+		current = &Pass{Start: from, StartAzimuth: first.Azimuth}
+	}
+
+	for t := from.Add(passScanStep); ; t = t.Add(passScanStep) {
+		if current == nil && t.After(to) {
+			break
+		}
+
+		look, err := lookAt(t)
+		if errors.Is(err, ErrDecayed) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case current == nil && look.Elevation > 0:
+			start := refineCrossing(lookAt, t.Add(-passScanStep), t, true)
+			startLook, _ := lookAt(start)
+			current = &Pass{Start: start, StartAzimuth: startLook.Azimuth}
+		case current != nil && look.Elevation <= 0:
+			end := refineCrossing(lookAt, t.Add(-passScanStep), t, false)
+			endLook, _ := lookAt(end)
+			current.End, current.EndAzimuth = end, endLook.Azimuth
+			refinePeak(lookAt, current)
+			if current.MaxElevation >= minElevation {
+				current.SunElevation = o.SunElevation(current.Max)
+				current.Visible, current.Magnitude = passVisible(s, o, *current)
+				passes = append(passes, *current)
+			}
+			current = nil
+		}
+	}
+
+	return passes, nil
+}
+
+func refineCrossing(lookAt func(time.Time) (Look, error), below, above time.Time, rising bool) time.Time {
+	lo, hi := below, above
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		look, err := lookAt(mid)
+		if err != nil {
+			break
+		}
+		if (look.Elevation > 0) == rising {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	if rising {
+		return hi
+	}
+	return lo
+}
+
+func refinePeak(lookAt func(time.Time) (Look, error), pass *Pass) {
+	lo, hi := pass.Start, pass.End
+	for hi.Sub(lo) > time.Second {
+		third := hi.Sub(lo) / 3
+		a, errA := lookAt(lo.Add(third))
+		b, errB := lookAt(hi.Add(-third))
+		if errA != nil || errB != nil {
+			break
+		}
+		if a.Elevation < b.Elevation {
+			lo = lo.Add(third)
+		} else {
+			hi = hi.Add(-third)
+		}
+	}
+
+	peak := lo.Add(hi.Sub(lo) / 2)
+	look, _ := lookAt(peak)
+	pass.Max, pass.MaxAzimuth, pass.MaxElevation = peak, look.Azimuth, look.Elevation
+}
+
+func passVisible(s *Satellite, o Observer, pass Pass) (bool, float64) {
+	visible, brightest := false, 0.0
+	for t := pass.Start; !t.After(pass.End); t = t.Add(visibilitySampling) {
+		if !o.Dark(t) {
+			continue
+		}
+		magnitude, ok, err := o.Magnitude(s, t, ISSStandardMagnitude)
+		if err != nil || !ok {
+			continue
+		}
+		if !visible || magnitude < brightest {
+			brightest = magnitude
+		}
+		visible = true
+	}
+	return visible, brightest
+}
@@ -0,0 +1,72 @@
+package track
+
+import "time"
+
+// PhotoMode says who takes the photograph in FindPhotoOps.
+type PhotoMode int
+
+const (
+	// PhotoFromOrbit is the crew photographing the place, which needs the
+	// place to be in daylight.
+	PhotoFromOrbit PhotoMode = iota
+	// PhotoFromGround is someone at the place photographing the station,
+	// which needs the station sunlit against a dark sky.
+	PhotoFromGround
+)
+
+// PhotoOp is a close approach of the ground track to a place that suits a
+// photograph. Elevation is the station's elevation seen from the place and
+// SunElevation the Sun's, both at the time of the approach.
+type PhotoOp struct {
+	Approach
+	Elevation    float64
+	SunElevation float64
+	AltitudeKm   float64
+}
+
+// FindPhotoOps returns every approach of the ground track to o between from
+// and to that comes within withinKm of the zenith while the lighting suits
+// mode.
+func FindPhotoOps(s *Satellite, o Observer, from, to time.Time, withinKm float64, mode PhotoMode) ([]PhotoOp, error) {
+	var ops []PhotoOp
+	var failed error
+	err := scanApproaches(s, o.Lat, o.Lon, from, to, func(a Approach) bool {
+		if a.DistanceKm > withinKm {
+			return true
+		}
+		op, ok, err := photoOpAt(s, o, a, mode)
+		if err != nil {
+			failed = err
+			return false
+		}
+		if ok {
+			ops = append(ops, op)
+		}
+		return true
+	})
+	if err == nil {
+		err = failed
+	}
+	return ops, err
+}
+
+func photoOpAt(s *Satellite, o Observer, a Approach, mode PhotoMode) (PhotoOp, bool, error) {
+	look, err := o.Look(s, a.Time)
+	if err != nil {
+		return PhotoOp{}, false, err
+	}
+	pos, err := s.PositionAt(a.Time)
+	if err != nil {
+		return PhotoOp{}, false, err
+	}
+	op := PhotoOp{Approach: a, Elevation: look.Elevation, SunElevation: o.SunElevation(a.Time), AltitudeKm: pos.AltitudeKm}
+
+	if mode == PhotoFromOrbit {
+		return op, op.SunElevation > 0, nil
+	}
+	sunlit, err := s.Sunlit(a.Time)
+	if err != nil {
+		return PhotoOp{}, false, err
+	}
+	return op, sunlit && op.SunElevation < TwilightElevation, nil
+}
@@ -0,0 +1,427 @@
+package track
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	wgs72Mu          = 398600.8
+	wgs72RadiusKm    = 6378.135
+	wgs72J2          = 0.001082616
+	wgs72J3          = -0.00000253881
+	wgs72J4          = -0.00000165597
+	minutesPerDay    = 1440.0
+	deepSpacePeriodM = 225.0
+)
+
+var (
+	wgs72Xke    = 60.0 / math.Sqrt(wgs72RadiusKm*wgs72RadiusKm*wgs72RadiusKm/wgs72Mu)
+	wgs72J3oJ2  = wgs72J3 / wgs72J2
+	kmPerSecond = wgs72RadiusKm * wgs72Xke / 60.0
+)
+
+// ErrDecayed is returned when the propagated orbit has dropped below the
+// Earth's surface.
+var ErrDecayed = errors.New("satellite has decayed")
+
+// Satellite propagates a TLE with the near-earth SGP4 model. Deep-space
+// orbits (period of 225 minutes or more) are not supported. NoradID is 0 when
+// the catalog number is not a plain five-digit number.
+type Satellite struct {
+	Name    string
+	NoradID int
+	Epoch   time.Time
+
+	bstar, ecco, argpo, inclo, mo, nodeo, no float64
+
+	isimp                                          bool
+	aycof, con41, cc1, cc4, cc5, d2, d3, d4, delmo float64
+	eta, argpdot, omgcof, sinmao, t2cof, t3cof     float64
+	t4cof, t5cof, x1mth2, x7thm1, mdot, nodedot    float64
+	xlcof, xmcof, nodecf                           float64
+
+	revNumber               int
+	revsPerDay, revsPerDay2 float64
+	epochPhase              float64
+}
+
+// NewSatellite parses the element set and initializes the propagator.
+func NewSatellite(tle TLE) (*Satellite, error) {
+	l1, l2 := tle.Line1, tle.Line2
+	if err := validateTLELine(l1, '1'); err != nil {
+		return nil, err
+	}
+	if err := validateTLELine(l2, '2'); err != nil {
+		return nil, err
+	}
+
+	epoch, err := parseTLEEpoch(l1[18:32])
+	if err != nil {
+		return nil, err
+	}
+	bstar, err := parseImpliedDecimal(l1[53:61])
+	if err != nil {
+		return nil, fmt.Errorf("TLE bstar: %w", err)
+	}
+	ndot, err := strconv.ParseFloat(strings.TrimSpace(l1[33:43]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("TLE mean motion derivative: %w", err)
+	}
+
+	var incl, node, ecc, argp, mean, motion float64
+	for _, field := range []struct {
+		value  string
+		target *float64
+	}{
+		{l2[8:16], &incl},
+		{l2[17:25], &node},
+		{"0." + strings.TrimSpace(l2[26:33]), &ecc},
+		{l2[34:42], &argp},
+		{l2[43:51], &mean},
+		{l2[52:63], &motion},
+	} {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field.value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("TLE line 2: %w", err)
+		}
+		*field.target = v
+	}
+	var rev int
+	if field := strings.TrimSpace(l2[63:68]); field != "" {
+		if rev, err = strconv.Atoi(field); err != nil {
+			return nil, fmt.Errorf("TLE revolution number: %w", err)
+		}
+	}
+
+	catalog, _ := strconv.Atoi(strings.TrimSpace(l1[2:7]))
+
+	const deg = math.Pi / 180
+	sat := &Satellite{
+		Name:    tle.Name,
+		NoradID: catalog,
+		Epoch:   epoch,
+		bstar:   bstar,
+		ecco:    ecc,
+		argpo:   argp * deg,
+		inclo:   incl * deg,
+		mo:      mean * deg,
+		nodeo:   node * deg,
+		no:      motion * 2 * math.Pi / minutesPerDay,
+
+		revNumber:   rev,
+		revsPerDay:  motion,
+		revsPerDay2: ndot,
+		epochPhase:  math.Mod(argp+mean, 360) / 360,
+	}
+	if err := sat.init(); err != nil {
+		return nil, err
+	}
+
+	return sat, nil
+}
+
+func parseTLEEpoch(field string) (time.Time, error) {
+	field = strings.TrimSpace(field)
+	if len(field) < 5 {
+		return time.Time{}, fmt.Errorf("TLE epoch %q", field)
+	}
+
+	year, err := strconv.Atoi(field[:2])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("TLE epoch year: %w", err)
+	}
+	days, err := strconv.ParseFloat(field[2:], 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("TLE epoch day: %w", err)
+	}
+
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration((days - 1) * 24 * float64(time.Hour))), nil
+}
+
+func parseImpliedDecimal(field string) (float64, error) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return 0, nil
+	}
+
+	sign := 1.0
+	switch field[0] {
+	case '-':
+		sign = -1
+		field = field[1:]
+	case '+':
+		field = field[1:]
+	}
+
+	cut := strings.LastIndexAny(field, "+-")
+	if cut <= 0 {
+		mantissa, err := strconv.ParseFloat("0."+field, 64)
+		return sign * mantissa, err
+	}
+
+	mantissa, err := strconv.ParseFloat("0."+field[:cut], 64)
+	if err != nil {
+		return 0, err
+	}
+	exponent, err := strconv.Atoi(field[cut:])
+	if err != nil {
+		return 0, err
+	}
+
+	return sign * mantissa * math.Pow10(exponent), nil
+}
+
+func (s *Satellite) init() error {
+	const x2o3 = 2.0 / 3.0
+
+	ss := 78.0/wgs72RadiusKm + 1.0
+	qzms2t := math.Pow((120.0-78.0)/wgs72RadiusKm, 4)
+
+	eccsq := s.ecco * s.ecco
+	omeosq := 1.0 - eccsq
+	rteosq := math.Sqrt(omeosq)
+	cosio := math.Cos(s.inclo)
+	cosio2 := cosio * cosio
+
+	ak := math.Pow(wgs72Xke/s.no, x2o3)
+	d1 := 0.75 * wgs72J2 * (3.0*cosio2 - 1.0) / (rteosq * omeosq)
+	del := d1 / (ak * ak)
+	adel := ak * (1.0 - del*del - del*(1.0/3.0+134.0*del*del/81.0))
+	del = d1 / (adel * adel)
+	s.no = s.no / (1.0 + del)
+
+	if 2*math.Pi/s.no >= deepSpacePeriodM {
+		return fmt.Errorf("deep-space orbits are not supported (period %.0f min)", 2*math.Pi/s.no)
+	}
+
+	ao := math.Pow(wgs72Xke/s.no, x2o3)
+	sinio := math.Sin(s.inclo)
+	po := ao * omeosq
+	con42 := 1.0 - 5.0*cosio2
+	s.con41 = -con42 - cosio2 - cosio2
+	posq := po * po
+	rp := ao * (1.0 - s.ecco)
+
+	s.isimp = rp < 220.0/wgs72RadiusKm+1.0
+
+	sfour := ss
+	qzms24 := qzms2t
+	perige := (rp - 1.0) * wgs72RadiusKm
+	if perige < 156.0 {
+		sfour = perige - 78.0
+		if perige < 98.0 {
+			sfour = 20.0
+		}
+		qzms24 = math.Pow((120.0-sfour)/wgs72RadiusKm, 4)
+		sfour = sfour/wgs72RadiusKm + 1.0
+	}
+
+	pinvsq := 1.0 / posq
+	tsi := 1.0 / (ao - sfour)
+	s.eta = ao * s.ecco * tsi
+	etasq := s.eta * s.eta
+	eeta := s.ecco * s.eta
+	psisq := math.Abs(1.0 - etasq)
+	coef := qzms24 * math.Pow(tsi, 4)
+	coef1 := coef / math.Pow(psisq, 3.5)
+	cc2 := coef1 * s.no * (ao*(1.0+1.5*etasq+eeta*(4.0+etasq)) +
+		0.375*wgs72J2*tsi/psisq*s.con41*(8.0+3.0*etasq*(8.0+etasq)))
+	s.cc1 = s.bstar * cc2
+	cc3 := 0.0
+	if s.ecco > 1.0e-4 {
+		cc3 = -2.0 * coef * tsi * wgs72J3oJ2 * s.no * sinio / s.ecco
+	}
+	s.x1mth2 = 1.0 - cosio2
+	s.cc4 = 2.0 * s.no * coef1 * ao * omeosq *
+		(s.eta*(2.0+0.5*etasq) + s.ecco*(0.5+2.0*etasq) -
+			wgs72J2*tsi/(ao*psisq)*(-3.0*s.con41*(1.0-2.0*eeta+etasq*(1.5-0.5*eeta))+
+				0.75*s.x1mth2*(2.0*etasq-eeta*(1.0+etasq))*math.Cos(2.0*s.argpo)))
+	s.cc5 = 2.0 * coef1 * ao * omeosq * (1.0 + 2.75*(etasq+eeta) + eeta*etasq)
+
+	cosio4 := cosio2 * cosio2
+	temp1 := 1.5 * wgs72J2 * pinvsq * s.no
+	temp2 := 0.5 * temp1 * wgs72J2 * pinvsq
+	temp3 := -0.46875 * wgs72J4 * pinvsq * pinvsq * s.no
+	s.mdot = s.no + 0.5*temp1*rteosq*s.con41 + 0.0625*temp2*rteosq*(13.0-78.0*cosio2+137.0*cosio4)
+	s.argpdot = -0.5*temp1*con42 + 0.0625*temp2*(7.0-114.0*cosio2+395.0*cosio4) +
+		temp3*(3.0-36.0*cosio2+49.0*cosio4)
+	xhdot1 := -temp1 * cosio
+	s.nodedot = xhdot1 + (0.5*temp2*(4.0-19.0*cosio2)+2.0*temp3*(3.0-7.0*cosio2))*cosio
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	s.xmcof = 0.0
+	if s.ecco > 1.0e-4 {
+		s.xmcof = -x2o3 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	if math.Abs(cosio+1.0) > 1.5e-12 {
+		s.xlcof = -0.25 * wgs72J3oJ2 * sinio * (3.0 + 5.0*cosio) / (1.0 + cosio)
+	} else {
+		s.xlcof = -0.25 * wgs72J3oJ2 * sinio * (3.0 + 5.0*cosio) / 1.5e-12
+	}
+	s.aycof = -0.5 * wgs72J3oJ2 * sinio
+	s.delmo = math.Pow(1.0+s.eta*math.Cos(s.mo), 3)
+	s.sinmao = math.Sin(s.mo)
+	s.x7thm1 = 7.0*cosio2 - 1.0
+
+	if !s.isimp {
+		cc1sq := s.cc1 * s.cc1
+		s.d2 = 4.0 * ao * tsi * cc1sq
+		temp := s.d2 * tsi * s.cc1 / 3.0
+		s.d3 = (17.0*ao + sfour) * temp
+		s.d4 = 0.5 * temp * ao * tsi * (221.0*ao + 31.0*sfour) * s.cc1
+		s.t3cof = s.d2 + 2.0*cc1sq
+		s.t4cof = 0.25 * (3.0*s.d3 + s.cc1*(12.0*s.d2+10.0*cc1sq))
+		s.t5cof = 0.2 * (3.0*s.d4 + 12.0*s.cc1*s.d3 + 6.0*s.d2*s.d2 + 15.0*cc1sq*(2.0*s.d2+cc1sq))
+	}
+
+	return nil
+}
+
+// Propagate returns the TEME position (km) and velocity (km/s) tsince
+// minutes after the element set epoch.
+func (s *Satellite) Propagate(tsince float64) ([3]float64, [3]float64, error) {
+	const x2o3 = 2.0 / 3.0
+	twoPi := 2 * math.Pi
+
+	xmdf := s.mo + s.mdot*tsince
+	argpdf := s.argpo + s.argpdot*tsince
+	nodedf := s.nodeo + s.nodedot*tsince
+	argpm := argpdf
+	mm := xmdf
+	t2 := tsince * tsince
+	nodem := nodedf + s.nodecf*t2
+	tempa := 1.0 - s.cc1*tsince
+	tempe := s.bstar * s.cc4 * tsince
+	templ := s.t2cof * t2
+
+	if !s.isimp {
+		delomg := s.omgcof * tsince
+		delm := s.xmcof * (math.Pow(1.0+s.eta*math.Cos(xmdf), 3) - s.delmo)
+		temp := delomg + delm
+		mm = xmdf + temp
+		argpm = argpdf - temp
+		t3 := t2 * tsince
+		t4 := t3 * tsince
+		tempa = tempa - s.d2*t2 - s.d3*t3 - s.d4*t4
+		tempe = tempe + s.bstar*s.cc5*(math.Sin(mm)-s.sinmao)
+		templ = templ + s.t3cof*t3 + t4*(s.t4cof+tsince*s.t5cof)
+	}
+
+	nm := s.no
+	em := s.ecco
+	inclm := s.inclo
+	if nm <= 0 {
+		return [3]float64{}, [3]float64{}, fmt.Errorf("sgp4: mean motion %v is not positive", nm)
+	}
+
+	am := math.Pow(wgs72Xke/nm, x2o3) * tempa * tempa
+	nm = wgs72Xke / math.Pow(am, 1.5)
+	em -= tempe
+	if em >= 1.0 || em < -0.001 {
+		return [3]float64{}, [3]float64{}, fmt.Errorf("sgp4: eccentricity %v out of range", em)
+	}
+	if em < 1.0e-6 {
+		em = 1.0e-6
+	}
+
+	mm += s.no * templ
+	xlm := mm + argpm + nodem
+	nodem = math.Mod(nodem, twoPi)
+	argpm = math.Mod(argpm, twoPi)
+	xlm = math.Mod(xlm, twoPi)
+	mm = math.Mod(xlm-argpm-nodem, twoPi)
+
+	sinim := math.Sin(inclm)
+	cosim := math.Cos(inclm)
+
+	axnl := em * math.Cos(argpm)
+	temp := 1.0 / (am * (1.0 - em*em))
+	aynl := em*math.Sin(argpm) + temp*s.aycof
+	xl := mm + argpm + nodem + temp*s.xlcof*axnl
+
+	u := math.Mod(xl-nodem, twoPi)
+	eo1 := u
+	tem5 := 9999.9
+	var sineo1, coseo1 float64
+	for ktr := 1; math.Abs(tem5) >= 1.0e-12 && ktr <= 10; ktr++ {
+		sineo1 = math.Sin(eo1)
+		coseo1 = math.Cos(eo1)
+		tem5 = 1.0 - coseo1*axnl - sineo1*aynl
+		tem5 = (u - aynl*coseo1 + axnl*sineo1 - eo1) / tem5
+		if math.Abs(tem5) >= 0.95 {
+			tem5 = math.Copysign(0.95, tem5)
+		}
+		eo1 += tem5
+	}
+
+	ecose := axnl*coseo1 + aynl*sineo1
+	esine := axnl*sineo1 - aynl*coseo1
+	el2 := axnl*axnl + aynl*aynl
+	pl := am * (1.0 - el2)
+	if pl < 0 {
+		return [3]float64{}, [3]float64{}, fmt.Errorf("sgp4: semi-latus rectum %v is negative", pl)
+	}
+
+	rl := am * (1.0 - ecose)
+	rdotl := math.Sqrt(am) * esine / rl
+	rvdotl := math.Sqrt(pl) / rl
+	betal := math.Sqrt(1.0 - el2)
+	temp = esine / (1.0 + betal)
+	sinu := am / rl * (sineo1 - aynl - axnl*temp)
+	cosu := am / rl * (coseo1 - axnl + aynl*temp)
+	su := math.Atan2(sinu, cosu)
+	sin2u := (cosu + cosu) * sinu
+	cos2u := 1.0 - 2.0*sinu*sinu
+	temp = 1.0 / pl
+	temp1 := 0.5 * wgs72J2 * temp
+	temp2 := temp1 * temp
+
+	mrt := rl*(1.0-1.5*temp2*betal*s.con41) + 0.5*temp1*s.x1mth2*cos2u
+	su -= 0.25 * temp2 * s.x7thm1 * sin2u
+	xnode := nodem + 1.5*temp2*cosim*sin2u
+	xinc := inclm + 1.5*temp2*cosim*sinim*cos2u
+	mvt := rdotl - nm*temp1*s.x1mth2*sin2u/wgs72Xke
+	rvdot := rvdotl + nm*temp1*(s.x1mth2*cos2u+1.5*s.con41)/wgs72Xke
+
+	sinsu, cossu := math.Sin(su), math.Cos(su)
+	snod, cnod := math.Sin(xnode), math.Cos(xnode)
+	sini, cosi := math.Sin(xinc), math.Cos(xinc)
+	xmx := -snod * cosi
+	xmy := cnod * cosi
+	ux := xmx*sinsu + cnod*cossu
+	uy := xmy*sinsu + snod*cossu
+	uz := sini * sinsu
+	vx := xmx*cossu - cnod*sinsu
+	vy := xmy*cossu - snod*sinsu
+	vz := sini * cossu
+
+	r := [3]float64{mrt * ux * wgs72RadiusKm, mrt * uy * wgs72RadiusKm, mrt * uz * wgs72RadiusKm}
+	v := [3]float64{
+		(mvt*ux + rvdot*vx) * kmPerSecond,
+		(mvt*uy + rvdot*vy) * kmPerSecond,
+		(mvt*uz + rvdot*vz) * kmPerSecond,
+	}
+	if mrt < 1.0 {
+		return r, v, ErrDecayed
+	}
+
+	return r, v, nil
+}
+
+// At propagates to the wall-clock time t.
+func (s *Satellite) At(t time.Time) ([3]float64, [3]float64, error) {
+	return s.Propagate(t.Sub(s.Epoch).Minutes())
+}
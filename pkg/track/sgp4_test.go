@@ -0,0 +1,94 @@
+package track
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// Reference vectors from Vallado et al., "Revisiting Spacetrack Report #3"
+// (AIAA 2006-6753), tcppver.out.
+var valladoCases = []struct {
+	tle    TLE
+	tsince float64
+	r, v   [3]float64
+}{
+	{tle00005, 0, [3]float64{7022.46529266, -1400.08296755, 0.03995155}, [3]float64{1.893841015, 6.405893759, 4.534807250}},
+	{tle00005, 360, [3]float64{-7154.03120202, -3783.17682504, -3536.19412294}, [3]float64{4.741887409, -4.151817765, -2.093935425}},
+	{tle00005, 720, [3]float64{-7134.59340119, 6531.68641334, 3260.27186483}, [3]float64{-4.113793027, -2.911922039, -2.557327851}},
+	{tle00005, 1080, [3]float64{5568.53901181, 4492.06992591, 3863.87641983}, [3]float64{-4.209106476, 5.159719888, 2.744852980}},
+	{tle00005, 1440, [3]float64{-938.55923943, -6268.18748831, -4294.02924910}, [3]float64{7.536105209, -0.427127707, 0.989878080}},
+	{tle06251, 0, [3]float64{3988.31022699, 5498.96657235, 0.90055879}, [3]float64{-3.290032738, 2.357652820, 6.496623475}},
+}
+
+var (
+	tle00005 = TLE{
+		Line1: "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+		Line2: "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+	}
+	tle06251 = TLE{
+		Line1: "1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+		Line2: "2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+	}
+)
+
+func TestPropagateVallado(t *testing.T) {
+	for _, tc := range valladoCases {
+		sat, err := NewSatellite(tc.tle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, v, err := sat.Propagate(tc.tsince)
+		if err != nil {
+			t.Fatalf("%05d at %v min: %v", sat.NoradID, tc.tsince, err)
+		}
+		for i := range r {
+			if math.Abs(r[i]-tc.r[i]) > 1e-4 || math.Abs(v[i]-tc.v[i]) > 1e-7 {
+				t.Errorf("%05d at %v min: r=%v v=%v, want r=%v v=%v", sat.NoradID, tc.tsince, r, v, tc.r, tc.v)
+				break
+			}
+		}
+	}
+}
+
+func TestNewSatellite(t *testing.T) {
+	sat, err := NewSatellite(tle00005)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sat.NoradID != 5 {
+		t.Errorf("NoradID = %d, want 5", sat.NoradID)
+	}
+	if got := sat.Epoch.Format("2006-01-02 15:04"); got != "2000-06-27 18:50" {
+		t.Errorf("Epoch = %s, want 2000-06-27 18:50", got)
+	}
+
+	r0, v0, _ := sat.Propagate(0)
+	r, v, err := sat.At(sat.Epoch)
+	if err != nil || r != r0 || v != v0 {
+		t.Errorf("At(Epoch) = %v %v %v, want Propagate(0) = %v %v", r, v, err, r0, v0)
+	}
+}
+
+func TestNewSatelliteRejectsBadLines(t *testing.T) {
+	bad := tle00005
+	bad.Line1 = bad.Line1[:68] + "0"
+	if _, err := NewSatellite(bad); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("bad checksum: err = %v", err)
+	}
+
+	swapped := TLE{Line1: tle00005.Line2, Line2: tle00005.Line1}
+	if _, err := NewSatellite(swapped); err == nil {
+		t.Error("swapped lines accepted")
+	}
+}
+
+func TestNewSatelliteRejectsDeepSpace(t *testing.T) {
+	molniya := TLE{
+		Line1: "1 08195U 75081A   06176.33215444  .00000099  00000-0  11873-3 0   813",
+		Line2: "2 08195  64.1586 279.0717 6877146 264.7651  20.2257  2.00491383225656",
+	}
+	if _, err := NewSatellite(molniya); err == nil || !strings.Contains(err.Error(), "deep-space") {
+		t.Errorf("err = %v, want deep-space error", err)
+	}
+}
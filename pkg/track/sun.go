@@ -0,0 +1,60 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+const astronomicalUnitKm = 149597870.7
+
+// TwilightElevation is the Sun elevation, in degrees, below which the sky is
+// dark enough to see the station.
+const TwilightElevation = -6.0
+
+func sunTEME(t time.Time) [3]float64 {
+	tut1 := (julianDate(t) - julianJ2000) / 36525.0
+	deg := math.Pi / 180
+
+	meanLong := math.Mod(280.460+36000.771*tut1, 360)
+	meanAnomaly := math.Mod(357.5291092+35999.05034*tut1, 360) * deg
+	eclipticLong := (meanLong + 1.914666471*math.Sin(meanAnomaly) + 0.019994643*math.Sin(2*meanAnomaly)) * deg
+	obliquity := (23.439291 - 0.0130042*tut1) * deg
+	distance := (1.000140612 - 0.016708617*math.Cos(meanAnomaly) - 0.000139589*math.Cos(2*meanAnomaly)) * astronomicalUnitKm
+
+	return [3]float64{
+		distance * math.Cos(eclipticLong),
+		distance * math.Cos(obliquity) * math.Sin(eclipticLong),
+		distance * math.Sin(obliquity) * math.Sin(eclipticLong),
+	}
+}
+
+// SunElevation is the Sun's elevation above the observer's horizon in degrees.
+func (o Observer) SunElevation(t time.Time) float64 {
+	sun, _ := temeToECEF(sunTEME(t), [3]float64{}, t)
+	_, elevation, _, _ := o.topocentric(sun)
+	return elevation
+}
+
+// Dark reports whether the Sun is below TwilightElevation for the observer.
+func (o Observer) Dark(t time.Time) bool {
+	return o.SunElevation(t) < TwilightElevation
+}
+
+// Sunlit reports whether the satellite is outside the Earth's shadow at t,
+// using a cylindrical shadow model.
+func (s *Satellite) Sunlit(t time.Time) (bool, error) {
+	r, _, err := s.At(t)
+	if err != nil {
+		return false, err
+	}
+
+	sun := sunTEME(t)
+	sunNorm := math.Sqrt(sun[0]*sun[0] + sun[1]*sun[1] + sun[2]*sun[2])
+	along := (r[0]*sun[0] + r[1]*sun[1] + r[2]*sun[2]) / sunNorm
+	if along >= 0 {
+		return true, nil
+	}
+
+	radius2 := r[0]*r[0] + r[1]*r[1] + r[2]*r[2]
+	return radius2-along*along > wgs84RadiusKm*wgs84RadiusKm, nil
+}
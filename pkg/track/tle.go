@@ -0,0 +1,158 @@
+package track
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const celestrakURL = "https://celestrak.org/NORAD/elements/gp.php"
+
+// ISSNoradID is the station's NORAD catalog number.
+const ISSNoradID = 25544
+
+// TiangongNoradID is the catalog number of Tianhe, the core module of the
+// Chinese space station.
+const TiangongNoradID = 48274
+
+// TLE is a two-line element set with its optional name line.
+type TLE struct {
+	Name  string
+	Line1 string
+	Line2 string
+}
+
+// FetchTLE downloads the current element set for noradID from Celestrak.
+func FetchTLE(client *http.Client, noradID int) (TLE, error) {
+	q := url.Values{}
+	q.Set("CATNR", strconv.Itoa(noradID))
+	q.Set("FORMAT", "TLE")
+
+	req, err := http.NewRequest(http.MethodGet, celestrakURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return TLE{}, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return TLE{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TLE{}, fmt.Errorf("celestrak status: %s", resp.Status)
+	}
+
+	sets, err := ParseTLEs(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return TLE{}, err
+	}
+	if len(sets) == 0 {
+		return TLE{}, fmt.Errorf("celestrak returned no TLE for NORAD %d", noradID)
+	}
+
+	return sets[0], nil
+}
+
+// FetchTLEGroup downloads every element set in a Celestrak group such as
+// "visual" or "starlink".
+func FetchTLEGroup(client *http.Client, group string) ([]TLE, error) {
+	q := url.Values{}
+	q.Set("GROUP", group)
+	q.Set("FORMAT", "TLE")
+
+	req, err := http.NewRequest(http.MethodGet, celestrakURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("celestrak status: %s", resp.Status)
+	}
+
+	sets, err := ParseTLEs(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("celestrak returned no TLEs for group %q", group)
+	}
+	return sets, nil
+}
+
+// ParseTLEs reads two- or three-line element sets and verifies their checksums.
+func ParseTLEs(r io.Reader) ([]TLE, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var sets []TLE
+	for i := 0; i < len(lines); {
+		set := TLE{}
+		if !strings.HasPrefix(lines[i], "1 ") {
+			set.Name = strings.TrimSpace(lines[i])
+			i++
+		}
+		if i+1 >= len(lines) {
+			return nil, fmt.Errorf("truncated TLE near %q", lines[len(lines)-1])
+		}
+
+		set.Line1, set.Line2 = lines[i], lines[i+1]
+		if err := validateTLELine(set.Line1, '1'); err != nil {
+			return nil, err
+		}
+		if err := validateTLELine(set.Line2, '2'); err != nil {
+			return nil, err
+		}
+
+		sets = append(sets, set)
+		i += 2
+	}
+
+	return sets, nil
+}
+
+func validateTLELine(line string, number byte) error {
+	if len(line) < 69 || line[0] != number || line[1] != ' ' {
+		return fmt.Errorf("malformed TLE line %c: %q", number, line)
+	}
+
+	if got, want := tleChecksum(line[:68]), int(line[68]-'0'); got != want {
+		return fmt.Errorf("TLE line %c checksum mismatch: got %d, want %d", number, got, want)
+	}
+
+	return nil
+}
+
+func tleChecksum(line string) int {
+	sum := 0
+	for _, ch := range line {
+		switch {
+		case ch >= '0' && ch <= '9':
+			sum += int(ch - '0')
+		case ch == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
@@ -0,0 +1,142 @@
+// Package track fetches the live position of the International Space Station.
+package track
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	openNotifyURL  = "http://api.open-notify.org/iss-now.json"
+	whereTheISSURL = "https://api.wheretheiss.at/v1/satellites/25544"
+)
+
+// UserAgent is sent with every request made by this package.
+var UserAgent = "iss-tui/1.2 (+https://github.com/kivayan/iss)"
+
+// Position is a single observation of the station's sub-satellite point.
+type Position struct {
+	Lat         float64
+	Lon         float64
+	AltitudeKm  float64
+	VelocityKmh float64
+	HasAltitude bool
+	Timestamp   time.Time
+	Provider    string
+}
+
+// Provider is a source of live positions.
+type Provider interface {
+	Name() string
+	Fetch(client *http.Client) (Position, error)
+}
+
+// OpenNotify reads api.open-notify.org. It reports latitude and longitude only.
+type OpenNotify struct{}
+
+// WhereTheISS reads api.wheretheiss.at, which also reports altitude and velocity.
+type WhereTheISS struct{}
+
+var knownProviders = map[string]Provider{
+	"wheretheiss": WhereTheISS{},
+	"open-notify": OpenNotify{},
+}
+
+// DefaultProviderOrder is used by ResolveProviders when no names are given.
+var DefaultProviderOrder = []string{"wheretheiss", "open-notify"}
+
+// ResolveProviders maps provider names to providers, keeping their order.
+func ResolveProviders(names []string) ([]Provider, error) {
+	if len(names) == 0 {
+		names = DefaultProviderOrder
+	}
+
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		provider, ok := knownProviders[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider %q (want wheretheiss or open-notify)", name)
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// FetchPosition tries each provider in turn and returns the first position
+// that succeeds, or all of their errors joined.
+func FetchPosition(client *http.Client, providers []Provider) (Position, error) {
+	var errs []error
+	for _, provider := range providers {
+		pos, err := provider.Fetch(client)
+		if err == nil {
+			pos.Provider = provider.Name()
+			return pos, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+
+	if len(errs) == 0 {
+		return Position{}, errors.New("no position providers configured")
+	}
+	return Position{}, errors.Join(errs...)
+}
+
+func (OpenNotify) Name() string {
+	return "open-notify"
+}
+
+func (OpenNotify) Fetch(client *http.Client) (Position, error) {
+	req, err := http.NewRequest(http.MethodGet, openNotifyURL, nil)
+	if err != nil {
+		return Position{}, err
+	}
+
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return Position{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Position{}, fmt.Errorf("iss api status: %s", resp.Status)
+	}
+
+	data, err := readBody("open-notify", resp.Body)
+	if err != nil {
+		return Position{}, err
+	}
+	return ParseOpenNotify(data, time.Now())
+}
+
+func (WhereTheISS) Name() string {
+	return "wheretheiss"
+}
+
+func (WhereTheISS) Fetch(client *http.Client) (Position, error) {
+	req, err := http.NewRequest(http.MethodGet, whereTheISSURL, nil)
+	if err != nil {
+		return Position{}, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Position{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Position{}, fmt.Errorf("wheretheiss status: %s", resp.Status)
+	}
+
+	data, err := readBody("wheretheiss", resp.Body)
+	if err != nil {
+		return Position{}, err
+	}
+	return ParseWhereTheISS(data, time.Now())
+}
@@ -0,0 +1,275 @@
+package track
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	sunRadiusKm       = 696000.0
+	moonRadiusKm      = 1737.4
+	transitScanStep   = time.Second
+	transitPrecision  = time.Millisecond
+	transitCentreSpan = 10 * time.Second
+	transitStep       = 100 * time.Millisecond
+)
+
+// Body is the Sun or the Moon, as the disc the station crosses in a Transit.
+type Body int
+
+const (
+	Sun Body = iota
+	Moon
+)
+
+// String is "Sun" or "Moon".
+func (b Body) String() string {
+	if b == Moon {
+		return "Moon"
+	}
+	return "Sun"
+}
+
+func (b Body) teme(t time.Time) [3]float64 {
+	if b == Moon {
+		return moonTEME(t)
+	}
+	return sunTEME(t)
+}
+
+func (b Body) radiusKm() float64 {
+	if b == Moon {
+		return moonRadiusKm
+	}
+	return sunRadiusKm
+}
+
+// Transit is the station crossing, or passing close to, the disc of the Sun
+// or the Moon as seen from near an observer. The crossing is visible along a
+// centre line on the ground, WidthKm wide; CentreKm and CentreAzimuth give
+// the nearest point of that line from the observer. Time is when the station
+// is closest to the middle of the disc seen from the observer and Duration
+// how long the crossing lasts on the centre line. SeparationDeg is the
+// station's closest distance from the middle of the disc seen from the
+// observer, whose angular radius is RadiusDeg, so the observer sees the
+// transit when Visible is set.
+type Transit struct {
+	Body          Body
+	Time          time.Time
+	Duration      time.Duration
+	WidthKm       float64
+	CentreKm      float64
+	CentreAzimuth float64
+	SeparationDeg float64
+	RadiusDeg     float64
+	BodyElevation float64
+	BodyAzimuth   float64
+	RangeKm       float64
+	Visible       bool
+}
+
+// FindTransits returns the transits of the Sun and the Moon between from and
+// to whose centre line passes within withinKm of o while both the station
+// and the disc are above o's horizon, ordered by time.
+func FindTransits(s *Satellite, o Observer, from, to time.Time, withinKm float64) ([]Transit, error) {
+	passes, err := FindPasses(s, o, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var transits []Transit
+	for _, pass := range passes {
+		for _, body := range []Body{Sun, Moon} {
+			transit, ok, err := passTransit(s, o, body, pass)
+			if err != nil {
+				return nil, err
+			}
+			if ok && transit.CentreKm <= withinKm {
+				transits = append(transits, transit)
+			}
+		}
+	}
+	return transits, nil
+}
+
+func passTransit(s *Satellite, o Observer, body Body, pass Pass) (Transit, bool, error) {
+	separationAt := func(t time.Time) (float64, error) {
+		look, err := o.Look(s, t)
+		if err != nil {
+			return 0, err
+		}
+		return Separation(look, o.bodyLook(body, t)), nil
+	}
+
+	var best time.Time
+	bestSeparation := math.Inf(1)
+	for t := pass.Start; !t.After(pass.End); t = t.Add(transitScanStep) {
+		if o.bodyLook(body, t).Elevation <= 0 {
+			continue
+		}
+		separation, err := separationAt(t)
+		if err != nil {
+			return Transit{}, false, err
+		}
+		if separation < bestSeparation {
+			best, bestSeparation = t, separation
+		}
+	}
+	if math.IsInf(bestSeparation, 1) {
+		return Transit{}, false, nil
+	}
+
+	lo, hi := best.Add(-transitScanStep), best.Add(transitScanStep)
+	for hi.Sub(lo) > transitPrecision {
+		third := hi.Sub(lo) / 3
+		a, errA := separationAt(lo.Add(third))
+		b, errB := separationAt(hi.Add(-third))
+		if errA != nil || errB != nil {
+			break
+		}
+		if a > b {
+			lo = lo.Add(third)
+		} else {
+			hi = hi.Add(-third)
+		}
+	}
+	at := lo.Add(hi.Sub(lo) / 2)
+	return transitAt(s, o, body, at)
+}
+
+func (o Observer) bodyLook(body Body, t time.Time) Look {
+	r, _ := temeToECEF(body.teme(t), [3]float64{}, t)
+	azimuth, elevation, rangeKm, _ := o.topocentric(r)
+	return Look{Azimuth: azimuth, Elevation: elevation, RangeKm: rangeKm}
+}
+
+// centreLine is the ground point from which the station sits on the middle
+// of the body's disc at t: where the line from the body through the station
+// meets a sphere as high as the observer.
+func (o Observer) centreLine(s *Satellite, body Body, t time.Time) ([3]float64, bool, error) {
+	r, v, err := s.At(t)
+	if err != nil {
+		return [3]float64{}, false, err
+	}
+	station, _ := temeToECEF(r, v, t)
+	site := geodeticToECEF(o.Lat, o.Lon, o.AltKm)
+	target, _ := temeToECEF(body.teme(t), [3]float64{}, t)
+	u := unit(sub(target, site))
+
+	along := dot(station, u)
+	disc := along*along - dot(station, station) + dot(site, site)
+	if disc < 0 {
+		return [3]float64{}, false, nil
+	}
+	back := along - math.Sqrt(disc)
+	if back <= 0 {
+		return [3]float64{}, false, nil
+	}
+	return sub(station, scale(u, back)), true, nil
+}
+
+// transitAt describes the transit around at, when the station is closest to
+// the middle of the disc seen from o.
+func transitAt(s *Satellite, o Observer, body Body, at time.Time) (Transit, bool, error) {
+	look, err := o.Look(s, at)
+	if err != nil {
+		return Transit{}, false, err
+	}
+	bodyLook := o.bodyLook(body, at)
+	transit := Transit{
+		Body:          body,
+		Time:          at,
+		SeparationDeg: Separation(look, bodyLook),
+		RadiusDeg:     math.Asin(body.radiusKm()/bodyLook.RangeKm) * 180 / math.Pi,
+		BodyElevation: bodyLook.Elevation,
+		BodyAzimuth:   bodyLook.Azimuth,
+		RangeKm:       look.RangeKm,
+	}
+	transit.Visible = transit.SeparationDeg <= transit.RadiusDeg
+
+	site := geodeticToECEF(o.Lat, o.Lon, o.AltKm)
+	distanceAt := func(t time.Time) float64 {
+		point, ok, err := o.centreLine(s, body, t)
+		if err != nil || !ok {
+			return math.Inf(1)
+		}
+		return norm(sub(point, site))
+	}
+	lo, hi := at.Add(-transitCentreSpan), at.Add(transitCentreSpan)
+	for hi.Sub(lo) > transitPrecision {
+		third := hi.Sub(lo) / 3
+		if distanceAt(lo.Add(third)) > distanceAt(hi.Add(-third)) {
+			lo = lo.Add(third)
+		} else {
+			hi = hi.Add(-third)
+		}
+	}
+	nearest := lo.Add(hi.Sub(lo) / 2)
+	point, ok, err := o.centreLine(s, body, nearest)
+	if err != nil || !ok {
+		return Transit{}, false, err
+	}
+	ahead, ok, err := o.centreLine(s, body, nearest.Add(transitStep))
+	if err != nil || !ok {
+		return Transit{}, false, err
+	}
+	transit.CentreKm = norm(sub(point, site))
+	transit.CentreAzimuth, _, _, _ = o.topocentric(point)
+
+	// Seen from the ground near the centre line, the station is inside the
+	// disc over an ellipse whose short radius is the disc's radius at the
+	// station's distance and which is stretched along the body's azimuth as
+	// the body gets lower. The ellipse slides along the line, so the path is
+	// as wide as the ellipse across the line, and the transit lasts as long
+	// as the ellipse takes to pass one point of it.
+	r, _, err := s.At(nearest)
+	if err != nil {
+		return Transit{}, false, err
+	}
+	station, _ := temeToECEF(r, [3]float64{}, nearest)
+	minor := norm(sub(station, point)) * transit.RadiusDeg * math.Pi / 180
+	up := unit(point)
+	target, _ := temeToECEF(body.teme(nearest), [3]float64{}, nearest)
+	u := unit(sub(target, point))
+	flat := sub(u, scale(up, dot(u, up)))
+	heading := sub(ahead, point)
+	heading = unit(sub(heading, scale(up, dot(heading, up))))
+	across := cross(up, heading)
+
+	sinElevation := dot(u, up)
+	cot2 := (1 - sinElevation*sinElevation) / (sinElevation * sinElevation)
+	acrossFlat, alongFlat := 0.0, dot(heading, flat)
+	if n := norm(flat); n > 0 {
+		acrossFlat = dot(across, flat) / n
+	}
+	transit.WidthKm = 2 * minor * math.Sqrt(1+cot2*acrossFlat*acrossFlat)
+	if speed := norm(sub(ahead, point)) / transitStep.Seconds(); speed > 0 {
+		chord := 2 * minor / math.Sqrt(1-alongFlat*alongFlat)
+		transit.Duration = time.Duration(chord / speed * float64(time.Second))
+	}
+	return transit, true, nil
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func sub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func scale(a [3]float64, k float64) [3]float64 {
+	return [3]float64{a[0] * k, a[1] * k, a[2] * k}
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+func unit(a [3]float64) [3]float64 {
+	return scale(a, 1/norm(a))
+}
@@ -0,0 +1,79 @@
+package track
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const issStationURL = "https://ll.thespacedevs.com/2.2.0/spacestation/4/"
+
+// DockedVehicle is a spacecraft attached to the station.
+type DockedVehicle struct {
+	Name   string    `json:"name"`
+	Type   string    `json:"type,omitempty"`
+	Port   string    `json:"port,omitempty"`
+	Docked time.Time `json:"docked"`
+}
+
+type stationResponse struct {
+	DockingLocation []struct {
+		Name   string `json:"name"`
+		Docked *struct {
+			Docking       time.Time `json:"docking"`
+			FlightVehicle *struct {
+				Spacecraft struct {
+					Name   string `json:"name"`
+					Config struct {
+						Name string `json:"name"`
+					} `json:"spacecraft_config"`
+				} `json:"spacecraft"`
+			} `json:"flight_vehicle"`
+		} `json:"docked"`
+	} `json:"docking_location"`
+}
+
+// FetchDockedVehicles lists the vehicles docked or berthed to the ISS
+// according to The Space Devs' Launch Library. Its free tier allows a few
+// requests an hour, so callers should cache the result.
+func FetchDockedVehicles(client *http.Client) ([]DockedVehicle, error) {
+	req, err := http.NewRequest(http.MethodGet, issStationURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("launch library status: %s", resp.Status)
+	}
+
+	data, err := readBody("launch library", resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var payload stationResponse
+	if err := decodeJSON("launch library", data, &payload); err != nil {
+		return nil, err
+	}
+
+	vehicles := []DockedVehicle{}
+	for _, port := range payload.DockingLocation {
+		if port.Docked == nil || port.Docked.FlightVehicle == nil {
+			continue
+		}
+		craft := port.Docked.FlightVehicle.Spacecraft
+		vehicles = append(vehicles, DockedVehicle{
+			Name:   craft.Name,
+			Type:   craft.Config.Name,
+			Port:   port.Name,
+			Docked: port.Docked.Docking,
+		})
+	}
+	return vehicles, nil
+}
@@ -0,0 +1,187 @@
+// Package weather reads cloud cover and time zones from the Open-Meteo
+// forecast API and space-weather indices from NOAA's Space Weather
+// Prediction Center. Neither needs an API key.
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	openMeteoURL    = "https://api.open-meteo.com/v1/forecast"
+	timeLayout      = "2006-01-02T15:04"
+	maxForecastDays = 16
+)
+
+// UserAgent is sent with every request.
+var UserAgent = "iss-tui/1.2 (+https://github.com/kivayan/iss)"
+
+// Point is a location in degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Forecast is hourly total cloud cover, in percent, at one location.
+type Forecast struct {
+	Times []time.Time
+	Cover []float64
+}
+
+type hourlyResponse struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+	Hourly struct {
+		Time       []string   `json:"time"`
+		CloudCover []*float64 `json:"cloud_cover"`
+	} `json:"hourly"`
+}
+
+type timezoneResponse struct {
+	Error    bool   `json:"error"`
+	Reason   string `json:"reason"`
+	Timezone string `json:"timezone"`
+}
+
+type currentResponse struct {
+	Current struct {
+		CloudCover float64 `json:"cloud_cover"`
+	} `json:"current"`
+}
+
+// CloudForecast returns the hourly cloud cover at p for the next days days,
+// up to Open-Meteo's horizon of 16.
+func CloudForecast(client *http.Client, p Point, days int) (Forecast, error) {
+	q := url.Values{}
+	q.Set("latitude", formatCoord(p.Lat))
+	q.Set("longitude", formatCoord(p.Lon))
+	q.Set("hourly", "cloud_cover")
+	q.Set("forecast_days", strconv.Itoa(max(1, min(days, maxForecastDays))))
+	q.Set("timezone", "GMT")
+
+	var payload hourlyResponse
+	if err := get(client, q, &payload); err != nil {
+		return Forecast{}, err
+	}
+	if payload.Error {
+		return Forecast{}, fmt.Errorf("open-meteo: %s", payload.Reason)
+	}
+
+	forecast := Forecast{}
+	for i, raw := range payload.Hourly.Time {
+		if i >= len(payload.Hourly.CloudCover) || payload.Hourly.CloudCover[i] == nil {
+			continue
+		}
+		t, err := time.ParseInLocation(timeLayout, raw, time.UTC)
+		if err != nil {
+			return Forecast{}, fmt.Errorf("open-meteo time %q: %w", raw, err)
+		}
+		forecast.Times = append(forecast.Times, t)
+		forecast.Cover = append(forecast.Cover, *payload.Hourly.CloudCover[i])
+	}
+	return forecast, nil
+}
+
+// At returns the cloud cover for the hour nearest t. ok is false when t is
+// more than an hour outside the forecast.
+func (f Forecast) At(t time.Time) (cover float64, ok bool) {
+	best := time.Duration(-1)
+	for i, at := range f.Times {
+		d := t.Sub(at)
+		if d < 0 {
+			d = -d
+		}
+		if best < 0 || d < best {
+			best, cover = d, f.Cover[i]
+		}
+	}
+	return cover, best >= 0 && best <= time.Hour
+}
+
+// CurrentCloudCover returns the current cloud cover, in percent, at each of
+// points in the same order.
+func CurrentCloudCover(client *http.Client, points []Point) ([]float64, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	lats := make([]string, len(points))
+	lons := make([]string, len(points))
+	for i, p := range points {
+		lats[i], lons[i] = formatCoord(p.Lat), formatCoord(p.Lon)
+	}
+	q := url.Values{}
+	q.Set("latitude", strings.Join(lats, ","))
+	q.Set("longitude", strings.Join(lons, ","))
+	q.Set("current", "cloud_cover")
+
+	var payloads []currentResponse
+	if len(points) == 1 {
+		payloads = make([]currentResponse, 1)
+		if err := get(client, q, &payloads[0]); err != nil {
+			return nil, err
+		}
+	} else if err := get(client, q, &payloads); err != nil {
+		return nil, err
+	}
+	if len(payloads) != len(points) {
+		return nil, fmt.Errorf("open-meteo returned %d locations, want %d", len(payloads), len(points))
+	}
+
+	cover := make([]float64, len(points))
+	for i, payload := range payloads {
+		cover[i] = payload.Current.CloudCover
+	}
+	return cover, nil
+}
+
+// Timezone returns the IANA time zone name at p, such as "Europe/Berlin".
+func Timezone(client *http.Client, p Point) (string, error) {
+	q := url.Values{}
+	q.Set("latitude", formatCoord(p.Lat))
+	q.Set("longitude", formatCoord(p.Lon))
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", "1")
+
+	var payload timezoneResponse
+	if err := get(client, q, &payload); err != nil {
+		return "", err
+	}
+	if payload.Error {
+		return "", fmt.Errorf("open-meteo: %s", payload.Reason)
+	}
+	if payload.Timezone == "" {
+		return "", fmt.Errorf("open-meteo returned no time zone")
+	}
+	return payload.Timezone, nil
+}
+
+func get(client *http.Client, q url.Values, out any) error {
+	req, err := http.NewRequest(http.MethodGet, openMeteoURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("open-meteo status: %s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(out)
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
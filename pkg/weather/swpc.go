@@ -0,0 +1,94 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const swpcTimeLayout = "2006-01-02T15:04:05"
+
+// SWPC endpoints for the planetary K index and the 10.7 cm solar flux.
+var (
+	KpURL   = "https://services.swpc.noaa.gov/json/planetary_k_index_1m.json"
+	F107URL = "https://services.swpc.noaa.gov/json/f107_cm_flux.json"
+)
+
+// SpaceWeather is the latest geomagnetic and solar activity. Kp runs from
+// 0 (quiet) to 9 (extreme storm); 5 and above is a geomagnetic storm with
+// aurora at lower latitudes than usual. F10.7 is the solar radio flux in
+// solar flux units, roughly 65 at solar minimum and 200 or more at maximum.
+// A higher flux heats the upper atmosphere and speeds up orbital decay.
+type SpaceWeather struct {
+	Kp       float64   `json:"kp"`
+	KpTime   time.Time `json:"kp_time"`
+	F107     float64   `json:"f107"`
+	F107Time time.Time `json:"f107_time"`
+}
+
+type kpSample struct {
+	TimeTag     string  `json:"time_tag"`
+	EstimatedKp float64 `json:"estimated_kp"`
+}
+
+type fluxSample struct {
+	TimeTag   string  `json:"time_tag"`
+	Frequency int     `json:"frequency"`
+	Flux      float64 `json:"flux"`
+}
+
+// FetchSpaceWeather returns the most recent Kp estimate and F10.7 flux.
+func FetchSpaceWeather(client *http.Client) (SpaceWeather, error) {
+	var sw SpaceWeather
+
+	var kp []kpSample
+	if err := getSWPC(client, KpURL, &kp); err != nil {
+		return sw, fmt.Errorf("kp: %w", err)
+	}
+	for _, s := range kp {
+		if t, err := time.Parse(swpcTimeLayout, s.TimeTag); err == nil && t.After(sw.KpTime) {
+			sw.Kp, sw.KpTime = s.EstimatedKp, t
+		}
+	}
+	if sw.KpTime.IsZero() {
+		return sw, fmt.Errorf("kp: no samples")
+	}
+
+	var flux []fluxSample
+	if err := getSWPC(client, F107URL, &flux); err != nil {
+		return sw, fmt.Errorf("f10.7: %w", err)
+	}
+	for _, s := range flux {
+		if s.Frequency != 0 && s.Frequency != 2800 {
+			continue
+		}
+		if t, err := time.Parse(swpcTimeLayout, s.TimeTag); err == nil && t.After(sw.F107Time) {
+			sw.F107, sw.F107Time = s.Flux, t
+		}
+	}
+	if sw.F107Time.IsZero() {
+		return sw, fmt.Errorf("f10.7: no samples")
+	}
+	return sw, nil
+}
+
+func getSWPC(client *http.Client, url string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("swpc status: %s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(out)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+)
+
+const (
+	profileMinElevation = 10
+	profileLookahead    = 72 * time.Hour
+)
+
+type observerProfile struct {
+	name     string
+	observer track.Observer
+}
+
+type profilePass struct {
+	profile observerProfile
+	pass    *track.Pass
+}
+
+type profilePassJSON struct {
+	Profile string    `json:"profile"`
+	Lat     float64   `json:"lat"`
+	Lon     float64   `json:"lon"`
+	Pass    *passJSON `json:"pass"`
+}
+
+func observerProfiles(observer *track.Observer, bookmarks []bookmarkConfig) []observerProfile {
+	profiles := make([]observerProfile, 0, len(bookmarks)+1)
+	listed := false
+	for _, b := range bookmarks {
+		profiles = append(profiles, observerProfile{name: b.Name, observer: b.observer()})
+		listed = listed || observer != nil && b.Lat == observer.Lat && b.Lon == observer.Lon
+	}
+	if observer != nil && !listed {
+		profiles = append([]observerProfile{{name: "observer", observer: *observer}}, profiles...)
+	}
+	return profiles
+}
+
+func nextProfilePasses(sat *track.Satellite, profiles []observerProfile, from, to time.Time, minElevation float64, visibleOnly bool) ([]profilePass, error) {
+	out := make([]profilePass, len(profiles))
+	for i, profile := range profiles {
+		out[i].profile = profile
+		found, err := track.FindPasses(sat, profile.observer, from, to, minElevation)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", profile.name, err)
+		}
+		if visibleOnly {
+			found = visiblePasses(found)
+		}
+		if len(found) > 0 {
+			out[i].pass = &found[0]
+		}
+	}
+	return out, nil
+}
+
+func writeProfilePasses(w io.Writer, passes []profilePass, weights passScoreWeights, times timeDisplay, asJSON bool) error {
+	if asJSON {
+		out := make([]profilePassJSON, len(passes))
+		for i, p := range passes {
+			out[i] = profilePassJSON{Profile: p.profile.name, Lat: p.profile.observer.Lat, Lon: p.profile.observer.Lon}
+			if p.pass != nil {
+				pass := newPassJSON(*p.pass, nil, weights)
+				out[i].Pass = &pass
+			}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	for _, line := range profilePassTable(passes, weights, times, "Profile", "none") {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+func profilePassTable(passes []profilePass, weights passScoreWeights, times timeDisplay, profileHeader, none string) []string {
+	date := "Date (" + times.zone(time.Now()) + ")"
+	rows := [][]string{{profileHeader, date, "Rise", "Az", "Max", "Elev", "Duration", "Visible", "Mag", "Score"}}
+	for _, p := range passes {
+		if p.pass == nil {
+			rows = append(rows, []string{p.profile.name, none})
+			continue
+		}
+		pass := *p.pass
+		rows = append(rows, []string{
+			p.profile.name,
+			times.format(pass.Start, layoutDay),
+			times.format(pass.Start, layoutShort),
+			compassPoint(pass.StartAzimuth),
+			times.format(pass.Max, layoutShort),
+			fmt.Sprintf("%.0f°", pass.MaxElevation),
+			pass.Duration().Round(time.Second).String(),
+			passSky(pass),
+			formatMagnitude(passMagnitude(pass)),
+			fmt.Sprint(passScore(pass, nil, weights)),
+		})
+	}
+	return formatTable(rows, nil)
+}
+
+func (m model) refreshProfilePasses(now time.Time) model {
+	if m.sat == nil {
+		return m
+	}
+	stale := m.profilePasses == nil
+	for _, p := range m.profilePasses {
+		stale = stale || p.pass != nil && !p.pass.End.After(now)
+	}
+	if !stale {
+		return m
+	}
+
+	passes, err := nextProfilePasses(m.sat, observerProfiles(m.observer, m.bookmarks), now, now.Add(profileLookahead), profileMinElevation, false)
+	if err != nil {
+		m.lastErr = err.Error()
+		return m
+	}
+	m.profilePasses = passes
+	return m
+}
+
+func (m model) profilesView() string {
+	lines := []string{m.lang.text("profiles_view"), ""}
+
+	switch profiles := observerProfiles(m.observer, m.bookmarks); {
+	case len(profiles) == 0:
+		lines = append(lines, m.lang.text("no_profiles"))
+	case m.sat == nil:
+		lines = append(lines, m.lang.text("loading_tle"))
+	default:
+		lines = append(lines, profilePassTable(m.profilePasses, m.passWeights, m.times, m.lang.text("profile"), m.lang.text("no_pass"))...)
+		lines = append(lines, "", fmt.Sprintf(m.lang.text("profiles_help"), profileMinElevation, int(profileLookahead.Hours())))
+	}
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type textPrompt struct {
+	active bool
+	label  string
+	detail string
+	input  string
+}
+
+func newTextPrompt(label string) textPrompt {
+	return textPrompt{active: true, label: label}
+}
+
+func (p *textPrompt) key(msg tea.KeyMsg) (submitted bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		p.active = false
+	case tea.KeyEnter:
+		p.active = false
+		return true
+	case tea.KeyBackspace:
+		if runes := []rune(p.input); len(runes) > 0 {
+			p.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		p.input += " "
+	case tea.KeyRunes:
+		p.input += string(msg.Runes)
+	}
+	return false
+}
+
+func (m model) promptLine() string {
+	if !m.prompt.active {
+		return ""
+	}
+	label := m.lang.text(m.prompt.label)
+	if m.prompt.detail != "" {
+		label += " (" + m.prompt.detail + ")"
+	}
+	return label + ": " + m.prompt.input + "_  " + m.lang.text("prompt_help")
+}
@@ -0,0 +1,527 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+const (
+	latencySampleCap  = 512
+	statsFlushEvery   = 20
+	healthBucketWidth = time.Minute
+	healthBuckets     = 30
+)
+
+var providerHosts = map[string]string{
+	"api.open-notify.org":         "open-notify",
+	"api.wheretheiss.at":          "wheretheiss",
+	"nominatim.openstreetmap.org": "nominatim",
+	"celestrak.org":               "celestrak",
+	"services.swpc.noaa.gov":      "swpc",
+}
+
+type providerRecord struct {
+	Requests  int             `json:"requests"`
+	Successes int             `json:"successes"`
+	Errors    map[string]int  `json:"errors,omitempty"`
+	Latencies []time.Duration `json:"latencies_ns,omitempty"`
+	Latency   time.Duration   `json:"latency_total_ns,omitempty"`
+}
+
+type providerStats struct {
+	mu       sync.Mutex
+	path     string
+	session  map[string]*providerRecord
+	history  map[string]*providerRecord
+	unsaved  map[string]*providerRecord
+	pending  int
+	timeline map[string][]healthBucket
+	breakers *breakerSet
+}
+
+type healthBucket struct {
+	start     time.Time
+	requests  int
+	successes int
+	latency   time.Duration
+}
+
+type providerTimeline struct {
+	name    string
+	latency []float64
+	success []float64
+	worst   time.Duration
+}
+
+type providerSummary struct {
+	name      string
+	requests  int
+	successes int
+	p50       time.Duration
+	p95       time.Duration
+	latency   time.Duration
+	errors    map[string]int
+}
+
+type instrumentedTransport struct {
+	ctx      context.Context
+	base     http.RoundTripper
+	stats    *providerStats
+	hostOnly bool
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func newProviderStats() *providerStats {
+	stats := &providerStats{
+		session:  map[string]*providerRecord{},
+		history:  map[string]*providerRecord{},
+		unsaved:  map[string]*providerRecord{},
+		timeline: map[string][]healthBucket{},
+		breakers: newBreakerSet(),
+	}
+
+	path, err := dataPath("provider-stats.json")
+	if err != nil {
+		return stats
+	}
+	stats.path = path
+
+	withFileLock(stats.path, func() error {
+		history, err := readProviderRecords(stats.path)
+		if err == nil {
+			stats.history = history
+		}
+		return nil
+	})
+
+	return stats
+}
+
+func newInstrumentedTransport(ctx context.Context, stats *providerStats, hostOnly bool) http.RoundTripper {
+	return &instrumentedTransport{ctx: ctx, base: http.DefaultTransport, stats: stats, hostOnly: hostOnly}
+}
+
+func (t *instrumentedTransport) logURL(u *url.URL) string {
+	if t.hostOnly {
+		return u.Scheme + "://" + u.Host
+	}
+	return u.Redacted()
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := providerForHost(req.URL.Hostname())
+	if err := t.stats.breakers.allow(service, time.Now()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		slog.Debug("http request skipped", "method", req.Method, "url", t.logURL(req.URL), "err", err)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(t.ctx, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	took := time.Since(start)
+	if err != nil {
+		release()
+	} else {
+		resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: release}
+	}
+	outcome := classifyOutcome(resp, err)
+	t.stats.record(service, took, outcome)
+	t.stats.breakers.report(service, outcome, time.Now())
+	switch {
+	case err != nil:
+		slog.Warn("http request failed", "method", req.Method, "url", t.logURL(req.URL), "took", took, "outcome", outcome, "err", err)
+	case outcome != "":
+		slog.Warn("http request failed", "method", req.Method, "url", t.logURL(req.URL), "took", took, "status", resp.StatusCode, "outcome", outcome)
+	default:
+		slog.Debug("http request", "method", req.Method, "url", t.logURL(req.URL), "took", took, "status", resp.StatusCode, "bytes", resp.ContentLength)
+	}
+	return resp, err
+}
+
+func providerForHost(host string) string {
+	if name, ok := providerHosts[strings.ToLower(host)]; ok {
+		return name
+	}
+	return host
+}
+
+func classifyOutcome(resp *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		var dnsErr *net.DNSError
+		var opErr *net.OpError
+		var tlsErr *tls.CertificateVerificationError
+		switch {
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			return "timeout"
+		case errors.As(err, &dnsErr):
+			return "dns"
+		case errors.As(err, &tlsErr):
+			return "tls"
+		case errors.As(err, &opErr):
+			return "connection"
+		default:
+			return "other"
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case resp.StatusCode >= 500:
+		return "http_5xx"
+	case resp.StatusCode >= 400:
+		return "http_4xx"
+	default:
+		return ""
+	}
+}
+
+func (s *providerStats) record(provider string, latency time.Duration, failure string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	for _, set := range []map[string]*providerRecord{s.session, s.unsaved} {
+		rec := set[provider]
+		if rec == nil {
+			rec = &providerRecord{}
+			set[provider] = rec
+		}
+		rec.add(latency, failure)
+	}
+	s.timeline[provider] = addHealthSample(s.timeline[provider], time.Now(), latency, failure == "")
+	s.pending++
+	flush := s.pending >= statsFlushEvery
+	s.mu.Unlock()
+
+	if flush {
+		s.save()
+	}
+}
+
+func (r *providerRecord) add(latency time.Duration, failure string) {
+	r.Requests++
+	if failure == "" {
+		r.Successes++
+	} else {
+		if r.Errors == nil {
+			r.Errors = map[string]int{}
+		}
+		r.Errors[failure]++
+	}
+
+	r.Latency += latency
+	r.Latencies = append(r.Latencies, latency)
+	if len(r.Latencies) > latencySampleCap {
+		r.Latencies = r.Latencies[len(r.Latencies)-latencySampleCap:]
+	}
+}
+
+func addHealthSample(buckets []healthBucket, at time.Time, latency time.Duration, ok bool) []healthBucket {
+	start := at.Truncate(healthBucketWidth)
+	if n := len(buckets); n == 0 || !buckets[n-1].start.Equal(start) {
+		buckets = append(buckets, healthBucket{start: start})
+		if extra := len(buckets) - healthBuckets; extra > 0 {
+			buckets = append([]healthBucket(nil), buckets[extra:]...)
+		}
+	}
+	b := &buckets[len(buckets)-1]
+	b.requests++
+	b.latency += latency
+	if ok {
+		b.successes++
+	}
+	return buckets
+}
+
+func (s *providerStats) timelines(now time.Time) []providerTimeline {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first := now.Truncate(healthBucketWidth).Add(-(healthBuckets - 1) * healthBucketWidth)
+	timelines := make([]providerTimeline, 0, len(s.timeline))
+	for name, buckets := range s.timeline {
+		t := providerTimeline{name: name, latency: make([]float64, healthBuckets), success: make([]float64, healthBuckets)}
+		for i := range t.latency {
+			t.latency[i], t.success[i] = math.NaN(), math.NaN()
+		}
+		for _, b := range buckets {
+			i := int(b.start.Sub(first) / healthBucketWidth)
+			if i < 0 || i >= healthBuckets || b.requests == 0 {
+				continue
+			}
+			mean := b.latency / time.Duration(b.requests)
+			t.latency[i] = mean.Seconds()
+			t.success[i] = 100 * float64(b.successes) / float64(b.requests)
+			t.worst = max(t.worst, mean)
+		}
+		timelines = append(timelines, t)
+	}
+	sort.Slice(timelines, func(i, j int) bool {
+		return timelines[i].name < timelines[j].name
+	})
+	return timelines
+}
+
+func providerTimelineTable(title string, empty string, timelines []providerTimeline) []string {
+	lines := []string{title}
+	if len(timelines) == 0 {
+		return append(lines, "  "+empty)
+	}
+
+	lines = append(lines, fmt.Sprintf("  %-14s %-*s %8s  %-*s", "provider", healthBuckets, "latency", "worst", healthBuckets, "ok"))
+	for _, t := range timelines {
+		lines = append(lines, fmt.Sprintf("  %-14s %s %8s  %s",
+			t.name, render.Sparkline(t.latency, 0), formatLatency(t.worst), render.Sparkline(t.success, 100)))
+	}
+	return lines
+}
+
+func (r *providerRecord) merge(other *providerRecord) {
+	r.Requests += other.Requests
+	r.Successes += other.Successes
+	for kind, n := range other.Errors {
+		if r.Errors == nil {
+			r.Errors = map[string]int{}
+		}
+		r.Errors[kind] += n
+	}
+
+	r.Latency += other.Latency
+	r.Latencies = append(r.Latencies, other.Latencies...)
+	if len(r.Latencies) > latencySampleCap {
+		r.Latencies = r.Latencies[len(r.Latencies)-latencySampleCap:]
+	}
+}
+
+func (s *providerStats) save() error {
+	if s == nil || s.path == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == 0 {
+		return nil
+	}
+
+	return withFileLock(s.path, func() error {
+		onDisk, err := readProviderRecords(s.path)
+		if err != nil {
+			onDisk = map[string]*providerRecord{}
+		}
+
+		for name, delta := range s.unsaved {
+			rec := onDisk[name]
+			if rec == nil {
+				rec = &providerRecord{}
+				onDisk[name] = rec
+			}
+			rec.merge(delta)
+		}
+
+		data, err := json.Marshal(onDisk)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(s.path, data, 0o644); err != nil {
+			return err
+		}
+
+		s.history = onDisk
+		s.unsaved = map[string]*providerRecord{}
+		s.pending = 0
+		return nil
+	})
+}
+
+func readProviderRecords(path string) (map[string]*providerRecord, error) {
+	data, err := readFileIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := map[string]*providerRecord{}
+	if len(data) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse provider stats: %w", err)
+	}
+
+	return records, nil
+}
+
+func (s *providerStats) summaries(includeHistory bool) []providerSummary {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	combined := map[string]*providerRecord{}
+	add := func(set map[string]*providerRecord) {
+		for name, rec := range set {
+			acc := combined[name]
+			if acc == nil {
+				acc = &providerRecord{}
+				combined[name] = acc
+			}
+			acc.merge(rec)
+		}
+	}
+	if includeHistory {
+		add(s.history)
+		add(s.unsaved)
+	} else {
+		add(s.session)
+	}
+
+	summaries := make([]providerSummary, 0, len(combined))
+	for name, rec := range combined {
+		summaries = append(summaries, providerSummary{
+			name:      name,
+			requests:  rec.Requests,
+			successes: rec.Successes,
+			p50:       latencyPercentile(rec.Latencies, 0.50),
+			p95:       latencyPercentile(rec.Latencies, 0.95),
+			latency:   rec.Latency,
+			errors:    rec.Errors,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].name < summaries[j].name
+	})
+
+	return summaries
+}
+
+func latencyPercentile(samples []time.Duration, q float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(q*float64(len(sorted)-1) + 0.5)
+	return sorted[idx]
+}
+
+func (p providerSummary) successRate() float64 {
+	if p.requests == 0 {
+		return 0
+	}
+	return 100 * float64(p.successes) / float64(p.requests)
+}
+
+func (p providerSummary) errorBreakdown() string {
+	if len(p.errors) == 0 {
+		return "-"
+	}
+
+	kinds := make([]string, 0, len(p.errors))
+	for kind := range p.errors {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s:%d", kind, p.errors[kind]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func providerStatsTable(title string, empty string, summaries []providerSummary) []string {
+	lines := []string{title}
+	if len(summaries) == 0 {
+		return append(lines, "  "+empty)
+	}
+
+	lines = append(lines, fmt.Sprintf("  %-14s %6s %7s %8s %8s  %s", "provider", "reqs", "ok", "p50", "p95", "errors"))
+	for _, p := range summaries {
+		lines = append(lines, fmt.Sprintf("  %-14s %6d %6.1f%% %8s %8s  %s",
+			p.name, p.requests, p.successRate(), formatLatency(p.p50), formatLatency(p.p95), p.errorBreakdown()))
+	}
+
+	return lines
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+func (s *providerStats) writeMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	summaries := s.summaries(false)
+	fmt.Fprintln(w, "# HELP iss_provider_requests_total Upstream requests this session by result.")
+	fmt.Fprintln(w, "# TYPE iss_provider_requests_total counter")
+	for _, p := range summaries {
+		fmt.Fprintf(w, "iss_provider_requests_total{provider=%q,result=\"success\"} %d\n", p.name, p.successes)
+		fmt.Fprintf(w, "iss_provider_requests_total{provider=%q,result=\"error\"} %d\n", p.name, p.requests-p.successes)
+	}
+
+	fmt.Fprintln(w, "# HELP iss_provider_errors_total Upstream failures this session by kind.")
+	fmt.Fprintln(w, "# TYPE iss_provider_errors_total counter")
+	for _, p := range summaries {
+		for kind, n := range p.errors {
+			fmt.Fprintf(w, "iss_provider_errors_total{provider=%q,kind=%q} %d\n", p.name, kind, n)
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP iss_provider_latency_seconds Upstream request latency, quantiles over recent requests.")
+	fmt.Fprintln(w, "# TYPE iss_provider_latency_seconds summary")
+	for _, p := range summaries {
+		fmt.Fprintf(w, "iss_provider_latency_seconds{provider=%q,quantile=\"0.5\"} %g\n", p.name, p.p50.Seconds())
+		fmt.Fprintf(w, "iss_provider_latency_seconds{provider=%q,quantile=\"0.95\"} %g\n", p.name, p.p95.Seconds())
+		fmt.Fprintf(w, "iss_provider_latency_seconds_sum{provider=%q} %g\n", p.name, p.latency.Seconds())
+		fmt.Fprintf(w, "iss_provider_latency_seconds_count{provider=%q} %d\n", p.name, p.requests)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetricsSummary(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	stats := newProviderStats()
+	stats.record("wheretheiss", 200*time.Millisecond, "")
+	stats.record("wheretheiss", 300*time.Millisecond, "timeout")
+
+	rec := httptest.NewRecorder()
+	stats.writeMetrics(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE iss_provider_latency_seconds summary\n",
+		`iss_provider_latency_seconds{provider="wheretheiss",quantile="0.5"} `,
+		`iss_provider_latency_seconds_sum{provider="wheretheiss"} 0.5` + "\n",
+		`iss_provider_latency_seconds_count{provider="wheretheiss"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	recordInterval        = 500 * time.Millisecond
+	defaultRecordDuration = time.Minute
+)
+
+type recordTickMsg struct{}
+
+type gifRecorder struct {
+	mu         sync.Mutex
+	path       string
+	until      time.Time
+	palette    color.Palette
+	canvas     *image.Paletted
+	anim       gif.GIF
+	lastScreen string
+	lastAt     time.Time
+	finished   bool
+}
+
+func newGIFRecorder(path string, duration time.Duration, now time.Time) *gifRecorder {
+	if duration <= 0 {
+		duration = defaultRecordDuration
+	}
+
+	palette := color.Palette{screenshotBackground, screenshotForeground}
+	for _, code := range []int{30, 31, 32, 33, 34, 35, 36, 37, 90, 91, 92, 93, 94, 95, 96, 97} {
+		palette = append(palette, ansiPalette[code])
+	}
+
+	return &gifRecorder{path: path, until: now.Add(duration), palette: palette}
+}
+
+func recordTick(delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return recordTickMsg{}
+	})
+}
+
+func (r *gifRecorder) due(now time.Time) bool {
+	return !now.Before(r.until)
+}
+
+func (r *gifRecorder) capture(screen string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished || screen == r.lastScreen {
+		return
+	}
+	r.lastScreen = screen
+
+	frame := r.paletted(rasterizeScreen(parseScreen(screen)))
+	if r.canvas == nil {
+		r.canvas = frame
+		r.anim.Config = image.Config{ColorModel: r.palette, Width: frame.Bounds().Dx(), Height: frame.Bounds().Dy()}
+		r.appendFrame(frame, now)
+		return
+	}
+
+	changed := changedBounds(r.canvas, frame)
+	if changed.Empty() {
+		return
+	}
+
+	patch := image.NewPaletted(changed, r.palette)
+	for y := changed.Min.Y; y < changed.Max.Y; y++ {
+		copy(patch.Pix[patch.PixOffset(changed.Min.X, y):patch.PixOffset(changed.Max.X, y)],
+			frame.Pix[frame.PixOffset(changed.Min.X, y):frame.PixOffset(changed.Max.X, y)])
+		copy(r.canvas.Pix[r.canvas.PixOffset(changed.Min.X, y):r.canvas.PixOffset(changed.Max.X, y)],
+			patch.Pix[patch.PixOffset(changed.Min.X, y):patch.PixOffset(changed.Max.X, y)])
+	}
+	r.appendFrame(patch, now)
+}
+
+func (r *gifRecorder) appendFrame(frame *image.Paletted, now time.Time) {
+	if n := len(r.anim.Delay); n > 0 {
+		r.anim.Delay[n-1] = gifDelay(now.Sub(r.lastAt))
+	}
+	r.anim.Image = append(r.anim.Image, frame)
+	r.anim.Delay = append(r.anim.Delay, gifDelay(recordInterval))
+	r.anim.Disposal = append(r.anim.Disposal, gif.DisposalNone)
+	r.lastAt = now
+}
+
+func (r *gifRecorder) paletted(src *image.RGBA) *image.Paletted {
+	bounds := src.Bounds()
+	if r.canvas != nil {
+		bounds = r.canvas.Bounds()
+	}
+
+	dst := image.NewPaletted(bounds, r.palette)
+	lookup := make(map[color.RGBA]uint8, len(r.palette))
+	for i, c := range r.palette {
+		lookup[c.(color.RGBA)] = uint8(i)
+	}
+
+	clip := bounds.Intersect(src.Bounds())
+	for y := clip.Min.Y; y < clip.Max.Y; y++ {
+		for x := clip.Min.X; x < clip.Max.X; x++ {
+			c := src.RGBAAt(x, y)
+			index, ok := lookup[c]
+			if !ok {
+				index = uint8(r.palette.Index(c))
+				lookup[c] = index
+			}
+			dst.SetColorIndex(x, y, index)
+		}
+	}
+
+	return dst
+}
+
+func changedBounds(a, b *image.Paletted) image.Rectangle {
+	changed := image.Rectangle{}
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if a.ColorIndexAt(x, y) != b.ColorIndexAt(x, y) {
+				changed = changed.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return changed
+}
+
+func gifDelay(d time.Duration) int {
+	delay := int(d / (10 * time.Millisecond))
+	if delay < 2 {
+		delay = 2
+	}
+	return delay
+}
+
+func (r *gifRecorder) finish(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished {
+		return nil
+	}
+	r.finished = true
+
+	if len(r.anim.Image) == 0 {
+		return fmt.Errorf("recording: no frames captured")
+	}
+	r.anim.Delay[len(r.anim.Delay)-1] = gifDelay(now.Sub(r.lastAt))
+
+	file, err := os.Create(r.path)
+	if err != nil {
+		return fmt.Errorf("recording: %w", err)
+	}
+	if err := gif.EncodeAll(file, &r.anim); err != nil {
+		file.Close()
+		return fmt.Errorf("recording: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("recording: %w", err)
+	}
+
+	r.anim = gif.GIF{}
+	r.canvas = nil
+	return nil
+}
+
+func finishRecordingCmd(r *gifRecorder, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if err := r.finish(now); err != nil {
+			return fileSavedMsg{err: err}
+		}
+		return fileSavedMsg{path: r.path}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/Kivayan/iss/pkg/screen"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type redrawWriter struct {
+	*castRecorder
+
+	mu   sync.Mutex
+	grid screen.Grid
+}
+
+func newRedrawWriter(cast *castRecorder) *redrawWriter {
+	return &redrawWriter{castRecorder: cast}
+}
+
+func (r *redrawWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out, ok := r.redraw(string(p))
+	if !ok {
+		return r.castRecorder.Write(p)
+	}
+	if _, err := r.castRecorder.Write([]byte(out)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (r *redrawWriter) redraw(frame string) (string, bool) {
+	prefix, body, suffix, ok := splitFrame(frame)
+	if !ok {
+		r.grid.Reset()
+		return "", false
+	}
+
+	lines := strings.Split(body, "\n")
+	rows := make([][]screen.Cell, len(lines))
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		if i < len(lines)-1 {
+			if line, ok = strings.CutSuffix(line, "\r"); !ok {
+				r.grid.Reset()
+				return "", false
+			}
+		}
+		if rows[i], ok = screen.ParseLine(strings.TrimSuffix(line, ansi.EraseLineRight)); !ok {
+			r.grid.Reset()
+			return "", false
+		}
+	}
+
+	out, ok := r.grid.Frame(rows)
+	if !ok || len(prefix)+len(out)+len(suffix) >= len(frame) {
+		return "", false
+	}
+	return prefix + out + suffix, true
+}
+
+func splitFrame(frame string) (prefix, body, suffix string, ok bool) {
+	switch {
+	case strings.HasPrefix(frame, ansi.CursorHomePosition):
+		prefix = ansi.CursorHomePosition
+	case strings.HasPrefix(frame, "\x1b["):
+		end := strings.IndexByte(frame, 'A')
+		if end < 0 || !digitsOnly(frame[2:end]) {
+			return "", "", "", false
+		}
+		prefix = frame[:end+1]
+	default:
+		return "", "", "", false
+	}
+
+	cut := strings.LastIndex(frame, "\x1b[")
+	if cut < len(prefix) {
+		return "", "", "", false
+	}
+	suffix = frame[cut:]
+	switch {
+	case strings.HasSuffix(suffix, ";H") && digitsOnly(suffix[2:len(suffix)-2]):
+	case strings.HasSuffix(suffix, "D") && digitsOnly(suffix[2:len(suffix)-1]):
+	default:
+		return "", "", "", false
+	}
+	body = frame[len(prefix):cut]
+	if strings.HasSuffix(body, ansi.EraseScreenBelow) {
+		return "", "", "", false
+	}
+	return prefix, body, suffix, true
+}
+
+func digitsOnly(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
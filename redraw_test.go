@@ -0,0 +1,169 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSplitFrame(t *testing.T) {
+	for _, tc := range []struct {
+		frame                string
+		prefix, body, suffix string
+		ok                   bool
+	}{
+		{"\x1b[Ha\r\nb\x1b[2;H", "\x1b[H", "a\r\nb", "\x1b[2;H", true},
+		{"\x1b[3Aa\x1b[K\r\n\nb\x1b[80D", "\x1b[3A", "a\x1b[K\r\n\nb", "\x1b[80D", true},
+		{"\x1b[Ha\r\nb\x1b[D", "\x1b[H", "a\r\nb", "\x1b[D", true},
+		{"a\r\nb\x1b[80D", "", "", "", false},
+		{"\x1b[?25l", "", "", "", false},
+		{"\x1b[Ha\r\nb", "", "", "", false},
+		{"\x1b[Ha\r\nb\x1b[?25h", "", "", "", false},
+		{"\x1b[Ha\x1b[J\x1b[1;H", "", "", "", false},
+		{"\x1b[1;2Ha\x1b[1;H", "", "", "", false},
+	} {
+		prefix, body, suffix, ok := splitFrame(tc.frame)
+		if ok != tc.ok || prefix != tc.prefix || body != tc.body || suffix != tc.suffix {
+			t.Errorf("splitFrame(%q) = %q, %q, %q, %v, want %q, %q, %q, %v", tc.frame, prefix, body, suffix, ok, tc.prefix, tc.body, tc.suffix, tc.ok)
+		}
+	}
+}
+
+func newTestRedrawWriter(t *testing.T) (*redrawWriter, func() string) {
+	t.Helper()
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { out.Close() })
+	return newRedrawWriter(newCastRecorder(out)), func() string {
+		data, err := os.ReadFile(out.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
+
+func TestRedrawWriterPassesThrough(t *testing.T) {
+	w, written := newTestRedrawWriter(t)
+	writes := []string{
+		"\x1b[?1049h\x1b[?25l",
+		"\x1b[H\rtop\x1b[K\r\nbottom\x1b[K\x1b[2;H",
+		"\x1b[H\ntop\x1b[K\x1b[9;9Hbottom\x1b[2;H",
+		"\x1b[Htab\tbed\x1b[K\r\n\x1b[2;H",
+		"\x1b[Hsome text without a suffix",
+		"plain text",
+	}
+	for _, p := range writes {
+		if n, err := w.Write([]byte(p)); err != nil || n != len(p) {
+			t.Fatalf("Write(%q) = %d, %v", p, n, err)
+		}
+	}
+	if got, want := written(), strings.Join(writes, ""); got != want {
+		t.Errorf("frames rewritten:\n got %q\nwant %q", got, want)
+	}
+}
+
+type layoutModel struct {
+	lines []string
+}
+
+type layoutLinesMsg []string
+
+func (m layoutModel) Init() tea.Cmd { return nil }
+
+func (m layoutModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if lines, ok := msg.(layoutLinesMsg); ok {
+		m.lines = lines
+	}
+	return m, nil
+}
+
+func (m layoutModel) View() string { return strings.Join(m.lines, "\n") }
+
+type writeLog struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (l *writeLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writes = append(l.writes, string(p))
+	return len(p), nil
+}
+
+func (l *writeLog) frames() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var frames []string
+	for _, w := range l.writes {
+		if strings.Contains(w, "\n") {
+			frames = append(frames, w)
+		}
+	}
+	return frames
+}
+
+// TestBubbleteaFrameLayout runs a real program to check that the renderer
+// still writes frames in the layout splitFrame expects. When it fails after a
+// bubbletea upgrade, the cell redraw has silently fallen back to full frames.
+func TestBubbleteaFrameLayout(t *testing.T) {
+	for _, alt := range []bool{true, false} {
+		log := &writeLog{}
+		opts := []tea.ProgramOption{tea.WithInput(nil), tea.WithOutput(log), tea.WithoutSignalHandler()}
+		if alt {
+			opts = append(opts, tea.WithAltScreen())
+		}
+		p := tea.NewProgram(layoutModel{}, opts...)
+		done := make(chan error, 1)
+		go func() {
+			_, err := p.Run()
+			done <- err
+		}()
+
+		frames := [][]string{
+			{"header", "the first row of the frame", "the second row of the frame", "footer"},
+			{"header", "the first row of the frame", "the second row 0f the frame", "footer"},
+			{"header", "the first row of the frame ¿世?", "the second row 0f the frame", "footer"},
+			{"header", "the first row", "the second row 0f the frame", "footer"},
+		}
+		p.Send(tea.WindowSizeMsg{Width: 40, Height: 10})
+		for _, lines := range frames {
+			p.Send(layoutLinesMsg(lines))
+			time.Sleep(60 * time.Millisecond)
+		}
+		p.Quit()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+
+		written := log.frames()
+		if len(written) < len(frames) {
+			t.Fatalf("alt=%v: %d frames written, want %d: %q", alt, len(written), len(frames), log.writes)
+		}
+		w, _ := newTestRedrawWriter(t)
+		w.redraw(written[0])
+		diffed := 0
+		for i, frame := range written[1:] {
+			if _, _, _, ok := splitFrame(frame); !ok {
+				t.Fatalf("alt=%v: frame %d has an unexpected layout: %q", alt, i+1, frame)
+			}
+			if _, ok := w.redraw(frame); ok {
+				diffed++
+			}
+		}
+		if diffed == 0 {
+			t.Errorf("alt=%v: no frame went through the cell redraw: %q", alt, written)
+		}
+		if alt && !strings.HasPrefix(written[1], ansi.CursorHomePosition) {
+			t.Errorf("alt screen frame does not start at home: %q", written[1])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const telemetryMinGap = 3 * time.Second
+
+type telemetryDoneMsg struct {
+	msg tea.Msg
+}
+
+type fetchCoordinator struct {
+	inFlight bool
+	last     time.Time
+}
+
+func refreshIntervalFromConfig(cfg config) (time.Duration, error) {
+	if cfg.RefreshSeconds == 0 {
+		return telemetryInterval, nil
+	}
+	interval := time.Duration(cfg.RefreshSeconds * float64(time.Second))
+	if interval < telemetryMinGap {
+		return telemetryInterval, fmt.Errorf("refresh_seconds must be at least %v", telemetryMinGap.Seconds())
+	}
+	return interval, nil
+}
+
+func (c fetchCoordinator) cooldown(now time.Time) time.Duration {
+	if demo != nil {
+		return 0
+	}
+	return max(c.last.Add(telemetryMinGap).Sub(now), 0)
+}
+
+func (c fetchCoordinator) ready(now time.Time) bool {
+	return !c.inFlight && c.cooldown(now) == 0
+}
+
+func (m model) requestTelemetry(now time.Time) (model, tea.Cmd) {
+	m.fetches = fetchCoordinator{inFlight: true, last: now}
+	fetch := fetchPositionCmd(m.client, m.providers, m.geocodes, m.lang)
+	return m, func() tea.Msg {
+		return telemetryDoneMsg{msg: fetch()}
+	}
+}
+
+func (m model) manualRefresh(now time.Time) (model, tea.Cmd) {
+	switch {
+	case m.fetches.inFlight:
+		m.notice = m.lang.text("refreshing")
+		return m, nil
+	case !m.fetches.ready(now):
+		wait := (m.fetches.cooldown(now) + time.Second - 1).Truncate(time.Second)
+		m.notice = fmt.Sprintf(m.lang.text("refresh_wait"), wait)
+		return m, nil
+	}
+	m.notice = m.lang.text("refreshing")
+	return m.requestTelemetry(now)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geofence"
+	"github.com/Kivayan/iss/pkg/render"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	notifyTimeout         = 10 * time.Second
+	highlightRegionName   = "highlight"
+	defaultHighlightColor = "bright-yellow"
+	highlightFlashPeriod  = 500 * time.Millisecond
+)
+
+type highlightState struct {
+	fence  *geofence.Fence
+	color  string
+	flash  bool
+	active bool
+}
+
+func regionsFromConfig(cfg config) ([]geofence.Region, error) {
+	regions := make([]geofence.Region, 0, len(cfg.Regions))
+	for _, rc := range cfg.Regions {
+		region := geofence.Region{Name: rc.Name}
+		for _, code := range rc.Countries {
+			region.Countries = append(region.Countries, strings.ToUpper(strings.TrimSpace(code)))
+		}
+		if rc.Box != nil {
+			if len(rc.Box) != 4 {
+				return nil, fmt.Errorf("region %q: box needs [min_lat, min_lon, max_lat, max_lon]", rc.Name)
+			}
+			region.Box = &geofence.Box{MinLat: rc.Box[0], MinLon: rc.Box[1], MaxLat: rc.Box[2], MaxLon: rc.Box[3]}
+		}
+		if rc.Circle != nil {
+			region.Circle = &geofence.Circle{Lat: rc.Circle.Lat, Lon: rc.Circle.Lon, RadiusKm: rc.Circle.RadiusKm}
+		}
+		if err := region.Validate(); err != nil {
+			return nil, err
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+func highlightFromConfig(cfg config) (highlightState, error) {
+	hc := cfg.Highlight
+	if hc == nil {
+		return highlightState{}, nil
+	}
+
+	region := geofence.Region{Name: highlightRegionName}
+	if code := strings.ToUpper(strings.TrimSpace(hc.Country)); code != "" {
+		region.Countries = []string{code}
+	}
+	if hc.RadiusKm > 0 {
+		if cfg.Observer == nil {
+			return highlightState{}, fmt.Errorf("highlight: radius_km needs an observer location")
+		}
+		region.Circle = &geofence.Circle{Lat: cfg.Observer.Lat, Lon: cfg.Observer.Lon, RadiusKm: hc.RadiusKm}
+	}
+	if err := region.Validate(); err != nil {
+		return highlightState{}, fmt.Errorf("highlight: set country or radius_km")
+	}
+
+	color := defaultHighlightColor
+	if hc.Color != "" {
+		color = hc.Color
+	}
+	if render.ColorSequence(color) == "" {
+		return highlightState{}, fmt.Errorf("highlight: unknown color %q", hc.Color)
+	}
+
+	return highlightState{fence: geofence.New([]geofence.Region{region}), color: color, flash: hc.Flash}, nil
+}
+
+func (h highlightState) update(sample geofence.Sample) highlightState {
+	if h.fence == nil {
+		return h
+	}
+	h.fence.Update(sample)
+	h.active = h.fence.Inside(highlightRegionName)
+	return h
+}
+
+func (h highlightState) apply(lines []string, glyphs glyphProfile, now time.Time, p palette) string {
+	if !h.active || h.flash && (now.UnixNano()/int64(highlightFlashPeriod))%2 == 1 {
+		return boxWith(lines, glyphs.border)
+	}
+
+	sequence := p.color(h.color)
+	if sequence == "" {
+		return boxWith(lines, glyphs.strong)
+	}
+	rows := strings.Split(boxWith(lines, glyphs.border), "\n")
+	for i, row := range rows {
+		rows[i] = sequence + "\x1b[1m" + row + render.Reset
+	}
+	return strings.Join(rows, "\n")
+}
+
+func regionEventText(lang language, event geofence.Event) string {
+	verb := lang.text("entered")
+	if event.Kind == geofence.Exit {
+		verb = lang.text("left")
+	}
+	return verb + " " + event.Region
+}
+
+func notifyCmd(command []string, title, message string) tea.Cmd {
+	if len(command) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		args := append(append([]string{}, command[1:]...), title, message)
+		if err := exec.CommandContext(ctx, command[0], args...).Run(); err != nil {
+			return errMsg{err: fmt.Errorf("notify: %w", err)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+type theme struct {
+	name        string
+	mapColor    string
+	markerColor string
+}
+
+var themes = []theme{
+	{name: "classic", mapColor: "green", markerColor: "blue"},
+	{name: "amber", mapColor: "yellow", markerColor: "bright-red"},
+	{name: "ocean", mapColor: "cyan", markerColor: "bright-white"},
+	{name: "mono", mapColor: "white", markerColor: "bright-white"},
+}
+
+func parseTheme(name string) (theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return themes[0], nil
+	}
+	for _, t := range themes {
+		if t.name == name {
+			return t, nil
+		}
+	}
+	return theme{}, fmt.Errorf("unknown theme %q", name)
+}
+
+func (t theme) next(step int) theme {
+	for i, candidate := range themes {
+		if candidate.name == t.name {
+			return themes[(i+step+len(themes))%len(themes)]
+		}
+	}
+	return themes[0]
+}
+
+type palette struct {
+	enabled bool
+	theme   theme
+}
+
+func newPalette(mode string, terminal bool) (palette, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "always":
+		return palette{enabled: true, theme: themes[0]}, nil
+	case "never":
+		return palette{theme: themes[0]}, nil
+	case "", "auto":
+		return palette{enabled: terminal && os.Getenv("NO_COLOR") == "", theme: themes[0]}, nil
+	default:
+		return palette{}, fmt.Errorf("unknown color mode %q (want always, never or auto)", mode)
+	}
+}
+
+var virtualConsole bool
+
+func capableTerminal() bool {
+	term := strings.TrimSpace(os.Getenv("TERM"))
+	if term == "dumb" || term == "" && !virtualConsole {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (p palette) color(name string) string {
+	if !p.enabled {
+		return ""
+	}
+	return render.ColorSequence(name)
+}
+
+func (p palette) mapStyle() render.Style {
+	return render.Style{
+		MarginRows:  mapMarginRows,
+		Frame:       true,
+		MapColor:    p.color(p.theme.mapColor),
+		MarkerColor: p.color(p.theme.markerColor),
+	}
+}
+
+func (p palette) polarStyle() render.PolarStyle {
+	return render.PolarStyle{GridColor: p.color(p.theme.mapColor), MarkerColor: p.color(p.theme.markerColor)}
+}
+
+func (m model) withColors(enabled bool) model {
+	m.palette.enabled = enabled
+	m.style = m.palette.mapStyle()
+	return m
+}
+
+func (m model) withTheme(t theme) model {
+	m.palette.theme = t
+	m.style = m.palette.mapStyle()
+	return m
+}
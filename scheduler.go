@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/schedule"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultHistoryPrune = "30 3 * * *"
+
+type scheduleConfig struct {
+	TLERefresh   string `json:"tle_refresh"`
+	LogRotate    string `json:"log_rotate"`
+	HistoryPrune string `json:"history_prune"`
+}
+
+type schedules struct {
+	tleRefresh   *schedule.Schedule
+	logRotate    *schedule.Schedule
+	historyPrune *schedule.Schedule
+	retention    time.Duration
+}
+
+type scheduledJob struct {
+	name string
+	when *schedule.Schedule
+	run  func(m model) tea.Cmd
+}
+
+type jobMsg struct {
+	index int
+	at    time.Time
+}
+
+func schedulesFromConfig(cfg config) (schedules, error) {
+	var s schedules
+	if cfg.HistoryRetainDays < 0 {
+		return s, errors.New("history_retention_days must not be negative")
+	}
+	s.retention = time.Duration(cfg.HistoryRetainDays * float64(24*time.Hour))
+
+	var sc scheduleConfig
+	if cfg.Schedule != nil {
+		sc = *cfg.Schedule
+	}
+	if sc.HistoryPrune != "" && s.retention == 0 {
+		return s, errors.New("schedule: history_prune needs history_retention_days")
+	}
+	if sc.HistoryPrune == "" && s.retention > 0 {
+		sc.HistoryPrune = defaultHistoryPrune
+	}
+
+	for _, entry := range []struct {
+		name string
+		expr string
+		dst  **schedule.Schedule
+	}{
+		{"tle_refresh", sc.TLERefresh, &s.tleRefresh},
+		{"log_rotate", sc.LogRotate, &s.logRotate},
+		{"history_prune", sc.HistoryPrune, &s.historyPrune},
+	} {
+		if entry.expr == "" {
+			continue
+		}
+		parsed, err := schedule.Parse(entry.expr)
+		if err != nil {
+			return schedules{}, fmt.Errorf("schedule: %s: %w", entry.name, err)
+		}
+		*entry.dst = parsed
+	}
+	return s, nil
+}
+
+func (m model) scheduledJobs(s schedules) []scheduledJob {
+	var jobs []scheduledJob
+	if s.tleRefresh != nil {
+		jobs = append(jobs, scheduledJob{name: "tle_refresh", when: s.tleRefresh, run: func(model) tea.Cmd {
+			return func() tea.Msg { return tleRefreshMsg{} }
+		}})
+	}
+	if m.digest != nil && m.observer != nil {
+		jobs = append(jobs, scheduledJob{name: "digest", when: m.digest.schedule, run: func(m model) tea.Cmd {
+			return m.digest.sendCmd(m.client, *m.observer, m.times, m.passWeights, m.tleRefresh)
+		}})
+	}
+	if s.logRotate != nil {
+		jobs = append(jobs, scheduledJob{name: "log_rotate", when: s.logRotate, run: func(model) tea.Cmd {
+			return rotateLogCmd()
+		}})
+	}
+	if s.historyPrune != nil && m.history != nil {
+		retention := s.retention
+		jobs = append(jobs, scheduledJob{name: "history_prune", when: s.historyPrune, run: func(m model) tea.Cmd {
+			return pruneHistoryCmd(m.history, retention)
+		}})
+	}
+	return jobs
+}
+
+func (m model) jobTick(index int, after time.Time) tea.Cmd {
+	next := m.jobs[index].when.Next(after)
+	if next.IsZero() {
+		return nil
+	}
+	return tea.Tick(time.Until(next), func(time.Time) tea.Msg {
+		return jobMsg{index: index, at: next}
+	})
+}
+
+func (m model) startJobs() []tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.jobs))
+	for i := range m.jobs {
+		cmds[i] = m.jobTick(i, time.Now())
+	}
+	return cmds
+}
+
+func (m model) runJob(msg jobMsg) tea.Cmd {
+	job := m.jobs[msg.index]
+	slog.Debug("scheduled job", "job", job.name)
+	return tea.Batch(job.run(m), m.jobTick(msg.index, msg.at))
+}
+
+func (m model) runJobs(ctx context.Context) {
+	defer crashes.recover()
+	if len(m.jobs) == 0 {
+		return
+	}
+	due := make([]time.Time, len(m.jobs))
+	for i, job := range m.jobs {
+		due[i] = job.when.Next(time.Now())
+	}
+	for {
+		next := -1
+		for i, at := range due {
+			if !at.IsZero() && (next < 0 || at.Before(due[next])) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(due[next]))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		job := m.jobs[next]
+		slog.Debug("scheduled job", "job", job.name)
+		if cmd := job.run(m); cmd != nil {
+			if msg, ok := cmd().(errMsg); ok {
+				slog.Warn("scheduled job failed", "job", job.name, "err", msg.err)
+			}
+		}
+		due[next] = job.when.Next(due[next])
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultInlineRows = 30
+	minInlineRows     = 12
+	inlineChromeRows  = 14
+)
+
+func inlineRowsFromConfig(cfg config) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Screen)) {
+	case "", "full":
+		return 0, nil
+	case "inline":
+	default:
+		return 0, fmt.Errorf("unknown screen %q (want full or inline)", cfg.Screen)
+	}
+	return inlineRows(cfg.InlineHeight)
+}
+
+func inlineRows(height int) (int, error) {
+	if height == 0 {
+		return defaultInlineRows, nil
+	}
+	if height < minInlineRows {
+		return 0, fmt.Errorf("inline height must be at least %d rows", minInlineRows)
+	}
+	return height, nil
+}
+
+func (m model) programOptions() []tea.ProgramOption {
+	if m.inlineRows > 0 {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithAltScreen()}
+}
+
+func (m model) mapWidthLimit() int {
+	if m.inlineRows == 0 {
+		return m.detail.maxWidth
+	}
+	rows := max(m.inlineRows-inlineChromeRows, 4)
+	return max(min(m.detail.maxWidth, int(float64(rows)*2*mapCharAspect)), minMapWidth)
+}
+
+func (m model) fitInline(screen string) string {
+	if m.inlineRows == 0 {
+		return screen
+	}
+	lines := strings.Split(strings.TrimSuffix(screen, "\n"), "\n")
+	if len(lines) > m.inlineRows {
+		lines = lines[:m.inlineRows]
+	}
+	for len(lines) < m.inlineRows {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
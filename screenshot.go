@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	defaultScreenshotFormat = "png"
+	screenshotCellWidth     = 7
+	screenshotCellHeight    = 13
+	screenshotPadding       = 8
+)
+
+var screenshotFormats = map[string]string{
+	"txt":  ".txt",
+	"ansi": ".ans",
+	"svg":  ".svg",
+	"png":  ".png",
+}
+
+var (
+	screenshotBackground = color.RGBA{0x12, 0x12, 0x12, 0xff}
+	screenshotForeground = color.RGBA{0xd0, 0xd0, 0xd0, 0xff}
+)
+
+var ansiPalette = map[int]color.RGBA{
+	30: {0x00, 0x00, 0x00, 0xff},
+	31: {0xcd, 0x31, 0x31, 0xff},
+	32: {0x0d, 0xbc, 0x79, 0xff},
+	33: {0xe5, 0xe5, 0x10, 0xff},
+	34: {0x24, 0x72, 0xc8, 0xff},
+	35: {0xbc, 0x3f, 0xbc, 0xff},
+	36: {0x11, 0xa8, 0xcd, 0xff},
+	37: {0xe5, 0xe5, 0xe5, 0xff},
+	90: {0x66, 0x66, 0x66, 0xff},
+	91: {0xf1, 0x4c, 0x4c, 0xff},
+	92: {0x23, 0xd1, 0x8b, 0xff},
+	93: {0xf5, 0xf5, 0x43, 0xff},
+	94: {0x3b, 0x8e, 0xea, 0xff},
+	95: {0xd6, 0x70, 0xd6, 0xff},
+	96: {0x29, 0xb8, 0xdb, 0xff},
+	97: {0xff, 0xff, 0xff, 0xff},
+}
+
+type fileSavedMsg struct {
+	path string
+	err  error
+}
+
+type screenCell struct {
+	ch rune
+	fg color.RGBA
+}
+
+func normalizeScreenshotFormat(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return defaultScreenshotFormat, nil
+	}
+	if _, ok := screenshotFormats[value]; !ok {
+		return "", fmt.Errorf("unknown screenshot format %q (want txt, ansi, svg or png)", value)
+	}
+	return value, nil
+}
+
+func saveScreenshotCmd(screen string, format string, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		name := "iss-" + now.Format("20060102-150405") + screenshotFormats[format]
+		path, err := filepath.Abs(name)
+		if err != nil {
+			path = name
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fileSavedMsg{err: fmt.Errorf("screenshot: %w", err)}
+		}
+
+		w := bufio.NewWriter(file)
+		err = writeScreenshot(w, format, screen)
+		if err == nil {
+			err = w.Flush()
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return fileSavedMsg{err: fmt.Errorf("screenshot: %w", err)}
+		}
+
+		return fileSavedMsg{path: path}
+	}
+}
+
+func writeScreenshot(w io.Writer, format string, screen string) error {
+	switch format {
+	case "txt":
+		_, err := io.WriteString(w, ansi.Strip(screen))
+		return err
+	case "ansi":
+		_, err := io.WriteString(w, screen+"\x1b[0m")
+		return err
+	case "svg":
+		return writeScreenSVG(w, parseScreen(screen))
+	case "png":
+		return png.Encode(w, rasterizeScreen(parseScreen(screen)))
+	default:
+		return fmt.Errorf("unknown screenshot format %q", format)
+	}
+}
+
+func parseScreen(screen string) [][]screenCell {
+	screen = strings.TrimRight(screen, "\n")
+	lines := strings.Split(screen, "\n")
+	grid := make([][]screenCell, 0, len(lines))
+
+	fg := screenshotForeground
+	for _, line := range lines {
+		var row []screenCell
+		for i := 0; i < len(line); {
+			if line[i] == 0x1b && i+1 < len(line) && line[i+1] == '[' {
+				end := i + 2
+				for end < len(line) && (line[end] < 0x40 || line[end] > 0x7e) {
+					end++
+				}
+				if end < len(line) && line[end] == 'm' {
+					fg = applySGR(fg, line[i+2:end])
+				}
+				i = end + 1
+				continue
+			}
+
+			ch, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+			if ch == '\r' {
+				continue
+			}
+			row = append(row, screenCell{ch: ch, fg: fg})
+		}
+		grid = append(grid, row)
+	}
+
+	return grid
+}
+
+func applySGR(fg color.RGBA, params string) color.RGBA {
+	if params == "" {
+		return screenshotForeground
+	}
+
+	for _, param := range strings.Split(params, ";") {
+		code, err := strconv.Atoi(param)
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0 || code == 39:
+			fg = screenshotForeground
+		default:
+			if rgba, ok := ansiPalette[code]; ok {
+				fg = rgba
+			}
+		}
+	}
+
+	return fg
+}
+
+func screenGridSize(grid [][]screenCell) (int, int) {
+	cols := 0
+	for _, row := range grid {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	return cols, len(grid)
+}
+
+func rasterizeScreen(grid [][]screenCell) *image.RGBA {
+	cols, rows := screenGridSize(grid)
+	bounds := image.Rect(0, 0, cols*screenshotCellWidth+2*screenshotPadding, rows*screenshotCellHeight+2*screenshotPadding)
+	img := image.NewRGBA(bounds)
+	draw.Draw(img, bounds, image.NewUniform(screenshotBackground), image.Point{}, draw.Src)
+
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: img, Face: face}
+	for y, row := range grid {
+		baseline := screenshotPadding + y*screenshotCellHeight + face.Ascent
+		for x, cell := range row {
+			if cell.ch == ' ' {
+				continue
+			}
+			drawer.Src = image.NewUniform(cell.fg)
+			drawer.Dot = fixed.P(screenshotPadding+x*screenshotCellWidth, baseline)
+			drawer.DrawString(asciiGlyphs.Replace(string(cell.ch)))
+		}
+	}
+
+	return img
+}
+
+func writeScreenSVG(w io.Writer, grid [][]screenCell) error {
+	cols, rows := screenGridSize(grid)
+	width := cols*screenshotCellWidth + 2*screenshotPadding
+	height := rows*screenshotCellHeight + 2*screenshotPadding
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(screenshotBackground))
+	fmt.Fprintf(&b, `<g font-family="monospace" font-size="%dpx" xml:space="preserve">`+"\n", screenshotCellHeight-1)
+
+	for y, row := range grid {
+		if len(strings.TrimSpace(cellText(row))) == 0 {
+			continue
+		}
+		baseline := screenshotPadding + y*screenshotCellHeight + basicfont.Face7x13.Ascent
+		fmt.Fprintf(&b, `<text y="%d">`, baseline)
+		for start := 0; start < len(row); {
+			end := start + 1
+			for end < len(row) && row[end].fg == row[start].fg {
+				end++
+			}
+			run := cellText(row[start:end])
+			if strings.TrimSpace(run) != "" {
+				fmt.Fprintf(&b, `<tspan x="%d" textLength="%d" fill="%s">%s</tspan>`,
+					screenshotPadding+start*screenshotCellWidth, (end-start)*screenshotCellWidth,
+					hexColor(row[start].fg), html.EscapeString(run))
+			}
+			start = end
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</g>\n</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func cellText(cells []screenCell) string {
+	var b strings.Builder
+	for _, cell := range cells {
+		b.WriteRune(cell.ch)
+	}
+	return b.String()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
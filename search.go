@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/Kivayan/iss/pkg/render"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	placeSearchLimit = 5
+	searchZoom       = 4
+)
+
+type placeSearchMsg struct {
+	purpose string
+	query   string
+	places  []geo.Place
+	err     error
+}
+
+func searchPlaceCmd(client *http.Client, purpose, query string, lang language) tea.Cmd {
+	return func() tea.Msg {
+		places, err := geo.Search(client, query, placeSearchLimit, lang.acceptLanguage(), lang.names)
+		return placeSearchMsg{purpose: purpose, query: query, places: places, err: err}
+	}
+}
+
+func (m model) submitPrompt() (model, tea.Cmd) {
+	purpose, query := m.prompt.label, strings.TrimSpace(m.prompt.input)
+	if purpose == "journal_prompt" {
+		return m.submitJournal()
+	}
+	if query == "" {
+		return m.choosePlace(purpose, nil)
+	}
+	return m, searchPlaceCmd(m.client, purpose, query, m.lang)
+}
+
+func (m model) applyPlaceSearch(msg placeSearchMsg) (model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.lastErr = msg.err.Error()
+	case len(msg.places) == 0:
+		m.lastErr = fmt.Sprintf(m.lang.text("place_not_found"), msg.query)
+	case len(msg.places) == 1:
+		return m.choosePlace(msg.purpose, &msg.places[0])
+	default:
+		options := make([]string, len(msg.places))
+		for i, place := range msg.places {
+			options[i] = place.DisplayName
+		}
+		m.dialog = newSelectDialog(m.lang.text("choose_place"), options, func(m model, index int) (model, tea.Cmd) {
+			return m.choosePlace(msg.purpose, &msg.places[index])
+		})
+	}
+	return m, nil
+}
+
+func (m model) choosePlace(purpose string, place *geo.Place) (model, tea.Cmd) {
+	switch purpose {
+	case "eta_prompt":
+		if place == nil {
+			m.eta = nil
+			return m, nil
+		}
+		return m.pinETA(*place), nil
+	case "search_prompt":
+		return m.jumpTo(place)
+	}
+	return m, nil
+}
+
+func (m model) jumpTo(place *geo.Place) (model, tea.Cmd) {
+	m.searchPin = place
+	m.mapView = render.Viewport{}
+	if place != nil {
+		m.mapView = render.Viewport{Lat: place.Lat, Lon: place.Lon, Zoom: searchZoom}.Clamped()
+	}
+	return m.syncMapState()
+}
+
+func (m model) searchPinGlyph() (render.Glyph, bool) {
+	if m.searchPin == nil {
+		return render.Glyph{}, false
+	}
+	return render.Glyph{Lat: m.searchPin.Lat, Lon: m.searchPin.Lon, Char: '@', Color: m.palette.color("bright-magenta")}, true
+}
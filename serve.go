@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	mapascii "github.com/Kivayan/map-ascii"
+)
+
+//go:embed data/dashboard.html
+var dashboardHTML []byte
+
+const (
+	defaultMapPNGWidth   = 800
+	minMapPNGWidth       = 64
+	maxMapPNGWidth       = 4096
+	serveTermWidth       = 84
+	serveShutdownTimeout = 5 * time.Second
+	keyframeInterval     = 30 * time.Second
+)
+
+type frameHub struct {
+	mu      sync.Mutex
+	latest  string
+	clients map[chan string]struct{}
+}
+
+func newFrameHub() *frameHub {
+	return &frameHub{clients: map[chan string]struct{}{}}
+}
+
+func (h *frameHub) publish(frame string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.latest = frame
+	for ch := range h.clients {
+		select {
+		case ch <- frame:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- frame
+		}
+	}
+}
+
+func (h *frameHub) subscribe() (<-chan string, func()) {
+	ch := make(chan string, 1)
+
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	if h.latest != "" {
+		ch <- h.latest
+	}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}
+}
+
+func (h *frameHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	frames, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	var previous []string
+	var lastKeyframe time.Time
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame := <-frames:
+			lines := strings.Split(frame, "\n")
+
+			var payload string
+			if previous == nil || time.Since(lastKeyframe) >= keyframeInterval {
+				payload = encodeKeyframe(lines)
+				lastKeyframe = time.Now()
+			} else {
+				payload = encodeFrameDiff(previous, lines)
+			}
+			previous = lines
+
+			if payload == "" {
+				continue
+			}
+			if _, err := fmt.Fprint(w, payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func dashboardOr(terminal http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || !strings.Contains(r.Header.Get("Accept"), "text/html") {
+			terminal.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(dashboardHTML)
+	})
+}
+
+func mapPNGHandler(mask *mapascii.LandMask, api *apiServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		width := defaultMapPNGWidth
+		if value := r.URL.Query().Get("width"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < minMapPNGWidth || n > maxMapPNGWidth {
+				http.Error(w, fmt.Sprintf("width must be between %d and %d", minMapPNGWidth, maxMapPNGWidth), http.StatusBadRequest)
+				return
+			}
+			width = n
+		}
+
+		var points []render.Point
+		if api.observer != nil {
+			points = append(points, render.Point{Lat: api.observer.Lat, Lon: api.observer.Lon, Radius: max(width/300, 2), Color: color.RGBA{R: 0x88, G: 0xc0, B: 0xd0, A: 0xff}})
+		}
+		if pos, ok := api.position(); ok {
+			points = append(points, render.Point{Lat: pos.Lat, Lon: pos.Lon, Radius: max(width/150, 3), Color: color.RGBA{R: 0xeb, G: 0xcb, B: 0x8b, A: 0xff}})
+		}
+
+		img, err := render.Image(mask, width, render.Viewport{}, points...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-cache")
+		png.Encode(w, img)
+	}
+}
+
+func encodeKeyframe(lines []string) string {
+	var b strings.Builder
+	b.WriteString("\x1b[?25l\x1b[H\x1b[2J")
+	for i, line := range lines {
+		writeLineAt(&b, i, line)
+	}
+
+	return b.String()
+}
+
+func encodeFrameDiff(previous, current []string) string {
+	var b strings.Builder
+	for i, line := range current {
+		if i < len(previous) && previous[i] == line {
+			continue
+		}
+		writeLineAt(&b, i, line)
+	}
+
+	if len(current) < len(previous) {
+		b.WriteString("\x1b[" + strconv.Itoa(len(current)+1) + ";1H\x1b[J")
+	}
+
+	return b.String()
+}
+
+func writeLineAt(b *strings.Builder, row int, line string) {
+	b.WriteString("\x1b[")
+	b.WriteString(strconv.Itoa(row + 1))
+	b.WriteString(";1H")
+	b.WriteString(line)
+	b.WriteString("\x1b[K")
+}
+
+func runServer(addr string, m model, api bool) error {
+	if m.mapMask == nil {
+		return errors.New(m.lastErr)
+	}
+
+	hub := newFrameHub()
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	m.width = serveTermWidth
+	go serveTelemetryLoop(ctx, m, hub)
+	go m.runJobs(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", dashboardOr(hub))
+	mux.HandleFunc("/events", m.api.serveEvents)
+	mux.HandleFunc("/map.png", mapPNGHandler(m.mapMask, m.api))
+	mux.HandleFunc("/metrics", m.stats.writeMetrics)
+	mux.HandleFunc("/passes", m.api.predictPasses)
+	if api {
+		mux.Handle(apiPrefix, m.api.handler())
+	}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Fprintf(os.Stderr, "streaming ISS map on %s (view with: curl -N http://%s/, or open it in a browser)\n", addr, addr)
+	grpcErr := make(chan error, 1)
+	if m.api.grpcAddr != "" {
+		fmt.Fprintf(os.Stderr, "serving the gRPC API on %s\n", m.api.grpcAddr)
+		go func() {
+			if err := m.api.serveGRPC(ctx, m.api.grpcAddr); err != nil {
+				grpcErr <- fmt.Errorf("grpc: %w", err)
+				cancel()
+			}
+		}()
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer done()
+		server.Shutdown(shutdownCtx)
+	}()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	select {
+	case err := <-grpcErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+func serveTelemetryLoop(ctx context.Context, m model, hub *frameHub) {
+	defer crashes.recover()
+	ticker := time.NewTicker(telemetryInterval)
+	defer ticker.Stop()
+
+	var cancelAnim context.CancelFunc
+	defer func() {
+		if cancelAnim != nil {
+			cancelAnim()
+		}
+	}()
+
+	restart := func() {
+		if cancelAnim != nil {
+			cancelAnim()
+		}
+
+		lines := m.telemetryLines()
+		if !m.hasCoords {
+			rendered, err := renderMap(m.rasters, m.rasterKey(), m.style, 0, 0, false, m.mapGlyphs(time.Now()))
+			if err == nil {
+				hub.publish(renderScreen(rendered, telemetryBox(lines), m.width))
+			}
+			return
+		}
+
+		animCtx, cancel := context.WithCancel(ctx)
+		cancelAnim = cancel
+		go func() {
+			defer crashes.recover()
+			animateMap(animCtx, m.rasters, m.rasterKey(), issMarker(m.lat, m.lon), m.mapGlyphs(time.Now()), m.style, func(frame string) error {
+				hub.publish(renderScreen(frame, telemetryBox(lines), m.width))
+				return nil
+			})
+		}()
+	}
+
+	restart()
+	for {
+		switch msg := fetchTelemetryCmd(m.client, m.providers, m.geocodes, m.lang, m.location())().(type) {
+		case telemetryMsg:
+			m = m.applyTelemetry(msg)
+			m.memUsage = processMemoryUsage()
+			m.api.observe(newAPIPosition(msg.pos, m.issOver))
+			restart()
+		case errMsg:
+			m.lastErr = msg.err.Error()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type settingField struct {
+	label  string
+	help   string
+	text   bool
+	value  func(m model) string
+	edit   func(m model) string
+	step   func(m model, step int) (model, tea.Cmd)
+	parse  func(m model, input string) (model, tea.Cmd, error)
+	remove func(m model) (model, tea.Cmd)
+}
+
+type settingsForm struct {
+	list    list.Model
+	input   textinput.Model
+	editing bool
+	err     string
+}
+
+type settingItem string
+
+func (i settingItem) FilterValue() string { return string(i) }
+
+type settingDelegate struct{}
+
+func (settingDelegate) Height() int                         { return 1 }
+func (settingDelegate) Spacing() int                        { return 0 }
+func (settingDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+func (settingDelegate) Render(w io.Writer, l list.Model, index int, item list.Item) {
+	marker := "  "
+	if index == l.Index() {
+		marker = "> "
+	}
+	fmt.Fprint(w, marker+string(item.(settingItem)))
+}
+
+func newSettingsForm() settingsForm {
+	items := make([]list.Item, len(settingFields))
+	for i := range items {
+		items[i] = settingItem("")
+	}
+	fields := list.New(items, settingDelegate{}, 0, len(settingFields))
+	fields.SetShowTitle(false)
+	fields.SetShowStatusBar(false)
+	fields.SetShowPagination(false)
+	fields.SetShowHelp(false)
+	fields.SetFilteringEnabled(false)
+	fields.DisableQuitKeybindings()
+	fields.InfiniteScrolling = true
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return settingsForm{list: fields, input: input}
+}
+
+var settingFields = []settingField{
+	{
+		label: "setting_refresh",
+		value: func(m model) string { return formatSettingNumber(m.interval.Seconds()) },
+		parse: func(m model, input string) (model, tea.Cmd, error) {
+			seconds, err := parseSettingNumber(input, telemetryMinGap.Seconds(), 3600)
+			if err != nil {
+				return m, nil, err
+			}
+			m.interval = time.Duration(seconds * float64(time.Second))
+			return m, persistConfigCmd(m.configPath, "refresh_seconds", seconds), nil
+		},
+	},
+	{
+		label: "setting_theme",
+		value: func(m model) string { return m.palette.theme.name },
+		step: func(m model, step int) (model, tea.Cmd) {
+			m = m.withTheme(m.palette.theme.next(step))
+			next, cmd := m.syncMapState()
+			return next, tea.Batch(cmd, persistConfigCmd(m.configPath, "theme", next.palette.theme.name))
+		},
+	},
+	{
+		label: "setting_units",
+		value: func(m model) string { return m.units.String() },
+		step: func(m model, step int) (model, tea.Cmd) {
+			count := len(unitSystemNames)
+			m.units = unitSystem((int(m.units) + step + count) % count)
+			return m, persistConfigCmd(m.configPath, "units", m.units.String())
+		},
+	},
+	{
+		label: "setting_location",
+		value: func(m model) string { return m.locDisplay.String() },
+		step: func(m model, step int) (model, tea.Cmd) {
+			count := len(locationDisplayNames)
+			m.locDisplay = locationDisplay((int(m.locDisplay) + step + count) % count)
+			return m, persistConfigCmd(m.configPath, "location_display", m.locDisplay.String())
+		},
+	},
+	{
+		label: "setting_flag",
+		value: func(m model) string { return m.flagStyle.String() },
+		step: func(m model, step int) (model, tea.Cmd) {
+			count := len(countryFlagNames)
+			m.flagStyle = countryFlag((int(m.flagStyle) + step + count) % count)
+			return m, persistConfigCmd(m.configPath, "country_flag", m.flagStyle.String())
+		},
+	},
+	{
+		label: "setting_charset",
+		value: func(m model) string { return m.charset.String() },
+		step: func(m model, step int) (model, tea.Cmd) {
+			count := len(charsetNames)
+			m.charset = charset((int(m.charset) + step + count) % count)
+			return m, persistConfigCmd(m.configPath, "charset", m.charset.String())
+		},
+	},
+	{
+		label: "setting_lat",
+		value: func(m model) string { return observerSetting(m, func(o track.Observer) float64 { return o.Lat }) },
+		parse: func(m model, input string) (model, tea.Cmd, error) {
+			lat, err := parseSettingNumber(input, -90, 90)
+			if err != nil {
+				return m, nil, err
+			}
+			next, cmd := m.setObserver(func(o *track.Observer) { o.Lat = lat })
+			return next, cmd, nil
+		},
+	},
+	{
+		label: "setting_lon",
+		value: func(m model) string { return observerSetting(m, func(o track.Observer) float64 { return o.Lon }) },
+		parse: func(m model, input string) (model, tea.Cmd, error) {
+			lon, err := parseSettingNumber(input, -180, 180)
+			if err != nil {
+				return m, nil, err
+			}
+			next, cmd := m.setObserver(func(o *track.Observer) { o.Lon = lon })
+			return next, cmd, nil
+		},
+	},
+	{
+		label: "setting_alt",
+		value: func(m model) string {
+			return observerSetting(m, func(o track.Observer) float64 { return o.AltKm * 1000 })
+		},
+		parse: func(m model, input string) (model, tea.Cmd, error) {
+			alt, err := parseSettingNumber(input, -500, 9000)
+			if err != nil {
+				return m, nil, err
+			}
+			next, cmd := m.setObserver(func(o *track.Observer) { o.AltKm = alt / 1000 })
+			return next, cmd, nil
+		},
+	},
+	{
+		label:  "setting_bookmark",
+		help:   "bookmark_help",
+		text:   true,
+		value:  func(m model) string { return m.bookmarkSetting() },
+		edit:   func(m model) string { return bookmarkName(m) },
+		step:   func(m model, step int) (model, tea.Cmd) { return m.stepBookmark(step) },
+		parse:  func(m model, input string) (model, tea.Cmd, error) { return m.renameBookmark(input) },
+		remove: func(m model) (model, tea.Cmd) { return m.deleteBookmark() },
+	},
+	{
+		label: "setting_bookmark_add",
+		help:  "bookmark_add_help",
+		text:  true,
+		value: func(m model) string { return "-" },
+		edit:  func(m model) string { return "" },
+		parse: func(m model, input string) (model, tea.Cmd, error) { return m.saveBookmark(input) },
+	},
+	{
+		label: "setting_sun_moon",
+		value: func(m model) string { return m.onOff(m.sunMoon) },
+		step: func(m model, step int) (model, tea.Cmd) {
+			m.sunMoon = !m.sunMoon
+			next, cmd := m.syncMapState()
+			return next, tea.Batch(cmd, persistConfigCmd(m.configPath, "sun_moon", m.sunMoon))
+		},
+	},
+	{
+		label: "setting_cloud_layer",
+		value: func(m model) string { return m.onOff(m.cloudLayerOn) },
+		step: func(m model, step int) (model, tea.Cmd) {
+			m.cloudLayerOn = !m.cloudLayerOn
+			cmds := []tea.Cmd{persistConfigCmd(m.configPath, "cloud_layer", m.cloudLayerOn)}
+			if !m.cloudLayerOn {
+				m.cloudLayer = nil
+			} else if !m.cloudsPolling {
+				m.cloudsPolling = true
+				cmds = append(cmds, m.fetchCloudsCmd())
+			}
+			next, cmd := m.syncMapState()
+			return next, tea.Batch(append(cmds, cmd)...)
+		},
+	},
+}
+
+func formatSettingNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func parseSettingNumber(input string, lo, hi float64) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", input)
+	}
+	if v < lo || v > hi {
+		return 0, fmt.Errorf("must be between %s and %s", formatSettingNumber(lo), formatSettingNumber(hi))
+	}
+	return v, nil
+}
+
+func bookmarkName(m model) string {
+	if len(m.bookmarks) == 0 {
+		return ""
+	}
+	return m.bookmarks[m.bookmarkCursor].Name
+}
+
+func observerSetting(m model, field func(track.Observer) float64) string {
+	if m.observer == nil {
+		return "-"
+	}
+	return formatSettingNumber(field(*m.observer))
+}
+
+func (m model) onOff(on bool) string {
+	if on {
+		return m.lang.text("on")
+	}
+	return m.lang.text("off")
+}
+
+func (m model) setObserver(update func(*track.Observer)) (model, tea.Cmd) {
+	observer := track.Observer{}
+	if m.observer != nil {
+		observer = *m.observer
+	}
+	update(&observer)
+	m.observer = &observer
+	m.skyPass = nil
+	m.profilePasses = nil
+	m = m.refreshSkyPass(time.Now())
+
+	saved := observerConfig{Lat: observer.Lat, Lon: observer.Lon, AltM: observer.AltKm * 1000}
+	return m, persistConfigCmd(m.configPath, "observer", saved)
+}
+
+func (m model) editObserver() model {
+	input := ""
+	if m.observer != nil {
+		input = strings.Join([]string{formatSettingNumber(m.observer.Lat), formatSettingNumber(m.observer.Lon), formatSettingNumber(m.observer.AltKm * 1000)}, ", ")
+	}
+	m.dialog = newInputDialog(m.lang.text("observer_dialog"), input, func(m model, input string) (model, tea.Cmd, error) {
+		observer, err := parseObserverInput(input)
+		if err != nil {
+			return m, nil, err
+		}
+		next, cmd := m.setObserver(func(o *track.Observer) { *o = observer })
+		next, syncCmd := next.syncMapState()
+		return next, tea.Batch(cmd, syncCmd), nil
+	})
+	return m
+}
+
+func parseObserverInput(input string) (track.Observer, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+	if len(fields) < 2 || len(fields) > 3 {
+		return track.Observer{}, fmt.Errorf("want latitude, longitude and optionally altitude in metres, got %q", input)
+	}
+	lat, err := parseSettingNumber(fields[0], -90, 90)
+	if err != nil {
+		return track.Observer{}, fmt.Errorf("latitude %w", err)
+	}
+	lon, err := parseSettingNumber(fields[1], -180, 180)
+	if err != nil {
+		return track.Observer{}, fmt.Errorf("longitude %w", err)
+	}
+	observer := track.Observer{Lat: lat, Lon: lon}
+	if len(fields) == 3 {
+		alt, err := parseSettingNumber(fields[2], -500, 9000)
+		if err != nil {
+			return track.Observer{}, fmt.Errorf("altitude %w", err)
+		}
+		observer.AltKm = alt / 1000
+	}
+	return observer, nil
+}
+
+func (m model) settingsKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	form := &m.settings
+	field := settingFields[form.list.Index()]
+
+	if form.editing {
+		switch msg.Type {
+		case tea.KeyEsc:
+			form.editing, form.err = false, ""
+			form.input.Blur()
+			return m, nil, true
+		case tea.KeyEnter:
+			next, cmd, err := field.parse(m, form.input.Value())
+			if err != nil {
+				form.err = err.Error()
+				return m, nil, true
+			}
+			next.settings.editing, next.settings.err = false, ""
+			next.settings.input.Blur()
+			return next, cmd, true
+		case tea.KeySpace:
+			if !field.text {
+				return m, nil, true
+			}
+		case tea.KeyRunes:
+			if !field.text {
+				msg.Runes = []rune(strings.Map(func(r rune) rune {
+					if strings.ContainsRune("0123456789.-", r) {
+						return r
+					}
+					return -1
+				}, string(msg.Runes)))
+			}
+		case tea.KeyCtrlC:
+			return m, nil, false
+		}
+		var cmd tea.Cmd
+		form.input, cmd = form.input.Update(msg)
+		return m, cmd, true
+	}
+
+	switch msg.String() {
+	case "left", "h", "right", "l", "enter":
+		form.err = ""
+		if field.step != nil && (field.parse == nil || msg.String() != "enter") {
+			step := 1
+			if s := msg.String(); s == "left" || s == "h" {
+				step = -1
+			}
+			next, cmd := field.step(m, step)
+			return next, cmd, true
+		}
+		if msg.String() == "enter" && field.parse != nil {
+			form.editing = true
+			input := field.value(m)
+			if field.edit != nil {
+				input = field.edit(m)
+			}
+			if input == "-" {
+				input = ""
+			}
+			form.input.SetValue(input)
+			form.input.CursorEnd()
+			return m, form.input.Focus(), true
+		}
+	case "x", "delete":
+		if field.remove != nil {
+			form.err = ""
+			next, cmd := field.remove(m)
+			return next, cmd, true
+		}
+	case "esc":
+		m.view = viewMap
+	default:
+		keys := form.list.KeyMap
+		if !key.Matches(msg, keys.CursorUp, keys.CursorDown) {
+			return m, nil, false
+		}
+		var cmd tea.Cmd
+		form.list, cmd = form.list.Update(msg)
+		return m, cmd, true
+	}
+	return m, nil, true
+}
+
+func (m model) settingsView() string {
+	form := m.settings
+	fields := make([][2]string, len(settingFields))
+	for i, field := range settingFields {
+		value := field.value(m)
+		if i == form.list.Index() && form.editing {
+			value = "[" + form.input.View() + "]"
+		}
+		fields[i] = [2]string{m.lang.text(field.label), value}
+	}
+	items := make([]list.Item, len(fields))
+	for i, line := range alignFields(fields) {
+		items[i] = settingItem(line)
+	}
+	form.list.SetItems(items)
+
+	lines := []string{m.lang.text("settings"), ""}
+	lines = append(lines, strings.Split(form.list.View(), "\n")...)
+	lines = append(lines, "", m.lang.text("settings_help"))
+	if help := settingFields[form.list.Index()].help; help != "" {
+		lines = append(lines, m.lang.text(help))
+	}
+	if m.settings.err != "" {
+		lines = append(lines, "", m.settings.err)
+	}
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSettingsEditRefresh(t *testing.T) {
+	m := testModel(t)
+	m.view = viewSettings
+	m.settings = newSettingsForm()
+	key := func(m model, msg tea.KeyMsg) model {
+		t.Helper()
+		next, _, handled := m.settingsKey(msg)
+		if !handled {
+			t.Fatalf("%v not handled", msg)
+		}
+		return next
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m = key(m, runes("j"))
+	if got := m.settings.list.Index(); got != 1 {
+		t.Fatalf("cursor at %d after j, want 1", got)
+	}
+	m = key(m, runes("k"))
+	m = key(m, runes("k"))
+	if got := m.settings.list.Index(); got != len(settingFields)-1 {
+		t.Fatalf("cursor at %d after wrapping up, want %d", got, len(settingFields)-1)
+	}
+	m = key(m, runes("j"))
+
+	m = key(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.settings.editing {
+		t.Fatal("enter did not start editing the refresh interval")
+	}
+	for range m.settings.input.Value() {
+		m = key(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m = key(m, runes("4x5"))
+	if got := m.settings.input.Value(); got != "45" {
+		t.Fatalf("input = %q, want letters dropped from a number", got)
+	}
+	if view := m.settingsView(); !strings.Contains(view, "> "+m.lang.text("setting_refresh")) {
+		t.Errorf("view does not mark the refresh row:\n%s", view)
+	}
+
+	m = key(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.settings.editing || m.settings.err != "" {
+		t.Fatalf("editing = %v, err = %q after a valid value", m.settings.editing, m.settings.err)
+	}
+	if m.interval != 45*time.Second {
+		t.Errorf("interval = %v, want 45s", m.interval)
+	}
+}
+
+func TestSettingsRejectsOutOfRange(t *testing.T) {
+	m := testModel(t)
+	m.view = viewSettings
+	m.settings = newSettingsForm()
+	interval := m.interval
+
+	next, _, _ := m.settingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	next.settings.input.SetValue("99999")
+	next, _, _ = next.settingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !next.settings.editing || next.settings.err == "" {
+		t.Fatalf("editing = %v, err = %q, want the form to stay open with an error", next.settings.editing, next.settings.err)
+	}
+	if next.interval != interval {
+		t.Errorf("interval changed to %v", next.interval)
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	mapascii "github.com/Kivayan/map-ascii"
+	"github.com/spf13/cobra"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	shareLookback     = 24 * time.Hour
+	shareLookahead    = 7 * 24 * time.Hour
+	shareMinElevation = 10
+	shareCityKm       = 50
+	shareCardWidth    = 800
+	shareMapZoom      = 6
+	shareTrackStep    = 10 * time.Second
+	sharePadding      = 16
+)
+
+var (
+	shareBackground = color.RGBA{R: 0x12, G: 0x12, B: 0x12, A: 0xff}
+	shareHeadline   = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	shareDetail     = color.RGBA{R: 0xa0, G: 0xa0, B: 0xa0, A: 0xff}
+	shareObserver   = color.RGBA{R: 0x88, G: 0xc0, B: 0xd0, A: 0xff}
+	shareStation    = color.RGBA{R: 0xeb, G: 0xcb, B: 0x8b, A: 0xff}
+)
+
+var shareGlyphs = map[rune][]image.Point{
+	'°': {{2, 1}, {3, 1}, {4, 1}, {1, 2}, {5, 2}, {1, 3}, {5, 3}, {2, 4}, {3, 4}, {4, 4}},
+	'·': {{3, 6}, {4, 6}, {3, 7}, {4, 7}},
+}
+
+type shareOptions struct {
+	observer observerFlags
+	place    string
+	next     bool
+	output   string
+}
+
+type shareCard struct {
+	headline string
+	detail   string
+}
+
+func newShareCmd(opts *globalOptions) *cobra.Command {
+	var share shareOptions
+	cmd := &cobra.Command{
+		Use:   "share",
+		Short: "Write a short post, and optionally a PNG card, about a pass",
+		Long:  "Write a line about the last pass over the observer in the past day, ready to paste into\na post, with its time, peak elevation and brightness. --next describes the next visible\npass instead. -o also renders a card with a map of the ground track under the text.\nThe place is the nearest large city within 50 km unless --place names it.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShare(cmd, *opts, cmd.OutOrStdout(), share)
+		},
+	}
+	addObserverFlags(cmd, &share.observer)
+	cmd.Flags().StringVar(&share.place, "place", "", "name of the place to mention instead of the nearest city")
+	cmd.Flags().BoolVar(&share.next, "next", false, "describe the next visible pass instead of the last one")
+	cmd.Flags().StringVarP(&share.output, "output", "o", "", "also render a PNG card to this file")
+
+	return cmd
+}
+
+func runShare(cmd *cobra.Command, opts globalOptions, w io.Writer, share shareOptions) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	observer, err := resolveObserver(cmd, share.observer, cfg)
+	if err != nil {
+		return err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+	client := newHTTPClient(cmd.Context(), stats)
+
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, client)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", warning)
+	}
+
+	set, err := loadTLE(client, track.ISSNoradID, tleRefreshFromConfig(cfg))
+	if set.Line1 == "" {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	sat, err := track.NewSatellite(set)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	pass, err := sharePass(sat, observer, now, share.next)
+	if err != nil {
+		return err
+	}
+	if drift := epochDistance(sat.Epoch, pass.Max); drift > tleAccuracyDays*24*time.Hour {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: the TLE epoch is %.0f days from the pass; its time may be off\n", drift.Hours()/24)
+	}
+
+	place := share.place
+	if place == "" {
+		place, err = sharePlace(observer)
+		if err != nil {
+			return err
+		}
+	}
+	card := newShareCard(pass, place, times, share.next)
+	fmt.Fprintln(w, card.headline)
+	fmt.Fprintln(w, card.detail)
+
+	if share.output == "" {
+		return nil
+	}
+	mask, err := mapascii.LoadEmbeddedDefaultLandMask()
+	if err != nil {
+		return fmt.Errorf("load land mask: %w", err)
+	}
+	img, err := renderShareCard(mask, sat, observer, pass, card)
+	if err != nil {
+		return err
+	}
+	return writeSharePNG(share.output, img)
+}
+
+func sharePass(sat *track.Satellite, observer track.Observer, now time.Time, next bool) (track.Pass, error) {
+	if next {
+		passes, err := track.FindPasses(sat, observer, now, now.Add(shareLookahead), shareMinElevation)
+		if err != nil {
+			return track.Pass{}, err
+		}
+		if passes = visiblePasses(passes); len(passes) == 0 {
+			return track.Pass{}, fmt.Errorf("no visible pass above %d° in the next %.0f days", shareMinElevation, shareLookahead.Hours()/24)
+		}
+		return passes[0], nil
+	}
+
+	passes, err := track.FindPasses(sat, observer, now.Add(-shareLookback), now, shareMinElevation)
+	if err != nil {
+		return track.Pass{}, err
+	}
+	if len(passes) == 0 {
+		return track.Pass{}, fmt.Errorf("no pass above %d° in the last %.0f hours (use --next for an upcoming one)", shareMinElevation, shareLookback.Hours())
+	}
+	for i := len(passes) - 1; i >= 0; i-- {
+		if passes[i].Visible {
+			return passes[i], nil
+		}
+	}
+	return passes[len(passes)-1], nil
+}
+
+func sharePlace(observer track.Observer) (string, error) {
+	cities, err := loadCities()
+	if err != nil {
+		return "", err
+	}
+	if c, km, ok := nearestCity(cities, observer.Lat, observer.Lon); ok && km <= shareCityKm {
+		return c.Name, nil
+	}
+	return fmt.Sprintf("%.2f, %.2f", observer.Lat, observer.Lon), nil
+}
+
+func newShareCard(pass track.Pass, place string, times timeDisplay, next bool) shareCard {
+	at := times.format(pass.Max, layoutShort)
+	headline := fmt.Sprintf("I just saw the ISS pass over %s at %s, max elevation %.0f°", place, at, pass.MaxElevation)
+	if next {
+		headline = fmt.Sprintf("The ISS passes over %s on %s at %s, max elevation %.0f°", place, times.format(pass.Max, layoutDay), at, pass.MaxElevation)
+	}
+
+	details := []string{
+		times.format(pass.Max, layoutDay),
+		fmt.Sprintf("%s to %s", compassPoint(pass.StartAzimuth), compassPoint(pass.EndAzimuth)),
+		fmt.Sprintf("%.0f min", pass.Duration().Minutes()),
+	}
+	if magnitude := passMagnitude(pass); magnitude != nil {
+		details = append(details, fmt.Sprintf("magnitude %.1f", *magnitude))
+	}
+	return shareCard{headline: headline, detail: strings.Join(details, " · ")}
+}
+
+func renderShareCard(mask *mapascii.LandMask, sat *track.Satellite, observer track.Observer, pass track.Pass, card shareCard) (*image.RGBA, error) {
+	var points []render.Point
+	for t := pass.Start; !t.After(pass.End); t = t.Add(shareTrackStep) {
+		pos, err := sat.PositionAt(t)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, render.Point{Lat: pos.Lat, Lon: pos.Lon, Radius: 1, Color: shareStation})
+	}
+	if pos, err := sat.PositionAt(pass.Max); err == nil {
+		points = append(points, render.Point{Lat: pos.Lat, Lon: pos.Lon, Radius: 5, Color: shareStation})
+	}
+	points = append(points, render.Point{Lat: observer.Lat, Lon: observer.Lon, Radius: 4, Color: shareObserver})
+
+	view := render.Viewport{Lat: observer.Lat, Lon: observer.Lon, Zoom: shareMapZoom}
+	world, err := render.Image(mask, shareCardWidth, view, points...)
+	if err != nil {
+		return nil, err
+	}
+
+	face := basicfont.Face7x13
+	headline := wrapWords(card.headline, (shareCardWidth-2*sharePadding)/(2*face.Advance))
+	text := sharePadding + len(headline)*2*face.Height + face.Height/2 + face.Height + sharePadding
+	mapHeight := world.Bounds().Dy()
+	img := image.NewRGBA(image.Rect(0, 0, shareCardWidth, mapHeight+text))
+	draw.Draw(img, img.Bounds(), image.NewUniform(shareBackground), image.Point{}, draw.Src)
+	draw.Draw(img, world.Bounds(), world, image.Point{}, draw.Src)
+
+	y := mapHeight + sharePadding
+	for _, line := range headline {
+		drawShareText(img, sharePadding, y, line, 2, shareHeadline)
+		y += 2 * face.Height
+	}
+	drawShareText(img, sharePadding, y+face.Height/2, card.detail, 1, shareDetail)
+	return img, nil
+}
+
+func wrapWords(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func drawShareText(img *image.RGBA, x, y int, text string, zoom int, c color.RGBA) {
+	face := basicfont.Face7x13
+	glyphs := image.NewAlpha(image.Rect(0, 0, len([]rune(text))*face.Advance, face.Height))
+	drawer := &font.Drawer{Dst: glyphs, Src: image.Opaque, Face: face}
+	for i, r := range []rune(text) {
+		if marks, ok := shareGlyphs[r]; ok {
+			for _, mark := range marks {
+				glyphs.SetAlpha(i*face.Advance+mark.X, mark.Y, color.Alpha{A: 0xff})
+			}
+			continue
+		}
+		drawer.Dot = fixed.P(i*face.Advance, face.Ascent)
+		drawer.DrawString(string(r))
+	}
+
+	bounds := glyphs.Bounds()
+	for gy := 0; gy < bounds.Dy(); gy++ {
+		for gx := 0; gx < bounds.Dx(); gx++ {
+			if glyphs.AlphaAt(gx, gy).A == 0 {
+				continue
+			}
+			for dy := 0; dy < zoom; dy++ {
+				for dx := 0; dx < zoom; dx++ {
+					if p := image.Pt(x+gx*zoom+dx, y+gy*zoom+dy); p.In(img.Rect) {
+						img.SetRGBA(p.X, p.Y, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+func writeSharePNG(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("share card: %w", err)
+	}
+	if err := writeFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("share card: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	skyPlotRadius    = 10
+	skyTrackStep     = 20 * time.Second
+	skyPassLookahead = 48 * time.Hour
+	skyPassLookback  = 30 * time.Minute
+)
+
+type satelliteMsg struct {
+	sat      *track.Satellite
+	previous *track.Satellite
+	err      error
+}
+
+func observerFromConfig(cfg config) *track.Observer {
+	if cfg.Observer == nil {
+		return nil
+	}
+	return &track.Observer{Lat: cfg.Observer.Lat, Lon: cfg.Observer.Lon, AltKm: cfg.Observer.AltM / 1000}
+}
+
+func fetchSatelliteCmd(client *http.Client, refresh time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		cached := cachedTLE(track.ISSNoradID)
+		set, fetchErr := loadTLE(client, track.ISSNoradID, refresh)
+		if set.Line1 == "" {
+			return satelliteMsg{err: fmt.Errorf("tle: %w", fetchErr)}
+		}
+		sat, err := track.NewSatellite(set)
+		if err != nil {
+			return satelliteMsg{err: fmt.Errorf("tle: %w", err)}
+		}
+		msg := satelliteMsg{sat: sat, err: fetchErr}
+		if cached.Line1 != "" && cached.Line1 != set.Line1 {
+			msg.previous, _ = track.NewSatellite(cached)
+		}
+		return msg
+	}
+}
+
+func nextPass(sat *track.Satellite, observer track.Observer, now time.Time) *track.Pass {
+	passes, err := track.FindPasses(sat, observer, now.Add(-skyPassLookback), now.Add(skyPassLookahead), 0)
+	if err != nil {
+		return nil
+	}
+	for _, pass := range passes {
+		if pass.End.After(now) {
+			return &pass
+		}
+	}
+	return nil
+}
+
+func (m model) refreshSkyPass(now time.Time) model {
+	if m.sat == nil || m.observer == nil {
+		return m
+	}
+	if m.skyPass == nil || !m.skyPass.End.After(now) {
+		m.skyPass = nextPass(m.sat, *m.observer, now)
+	}
+	return m
+}
+
+func skyPlot(sat *track.Satellite, observer track.Observer, pass *track.Pass, now time.Time, style render.PolarStyle) (string, track.Look, error) {
+	var points []render.PolarPoint
+	if pass != nil {
+		for t := pass.Start; !t.After(pass.End); t = t.Add(skyTrackStep) {
+			look, err := observer.Look(sat, t)
+			if err != nil {
+				break
+			}
+			glyph := byte('*')
+			if t.Before(now) {
+				glyph = 'o'
+			}
+			points = append(points, render.PolarPoint{Azimuth: look.Azimuth, Elevation: look.Elevation, Glyph: glyph})
+		}
+	}
+
+	current, err := observer.Look(sat, now)
+	if err != nil {
+		return "", track.Look{}, err
+	}
+	points = append(points, render.PolarPoint{Azimuth: current.Azimuth, Elevation: current.Elevation, Glyph: 'X'})
+
+	plot, err := render.Polar(points, skyPlotRadius, style)
+	return plot, current, err
+}
+
+func (m model) skyView() string {
+	lines := []string{m.lang.text("sky_view"), ""}
+
+	switch {
+	case m.observer == nil:
+		lines = append(lines, m.lang.text("no_observer"))
+	case m.sat == nil:
+		lines = append(lines, m.lang.text("loading_tle"))
+	default:
+		now := clockNow()
+		plot, look, err := skyPlot(m.sat, *m.observer, m.skyPass, now, m.palette.polarStyle())
+		if err != nil {
+			lines = append(lines, err.Error())
+			break
+		}
+
+		fields := [][2]string{
+			{m.lang.text("azimuth"), fmt.Sprintf("%.1f° %s", look.Azimuth, compassPoint(look.Azimuth))},
+			{m.lang.text("elevation"), fmt.Sprintf("%.1f°", look.Elevation)},
+			{m.lang.text("range"), m.units.formatDistance(look.RangeKm)},
+		}
+		if look.Elevation > 0 {
+			fields = append(fields, m.magnitudeField(now))
+		}
+		if pass := m.skyPass; pass != nil {
+			label := m.lang.text("next_pass")
+			if !pass.Start.After(now) {
+				label = m.lang.text("this_pass")
+			}
+			fields = append(fields, [2]string{label, fmt.Sprintf("%s %s → %s %s, max %.0f°",
+				m.times.format(pass.Start, layoutShort), compassPoint(pass.StartAzimuth),
+				m.times.format(pass.End, layoutShort), compassPoint(pass.EndAzimuth), pass.MaxElevation)})
+			if field, ok := m.cloudField(pass); ok {
+				fields = append(fields, field)
+			}
+		}
+
+		fields = append(fields, dopplerFields(m.lang, m.radios, look)...)
+
+		lines = append(lines, strings.Split(plot, "\n")...)
+		lines = append(lines, "")
+		lines = append(lines, alignFields(fields)...)
+		if lookalikes := m.lookalikeLines(); lookalikes != nil {
+			lines = append(lines, "")
+			lines = append(lines, lookalikes...)
+		}
+		if stale := m.tleStaleLine(now); stale != "" {
+			lines = append(lines, "", stale)
+		}
+	}
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
+
+func dopplerFields(lang language, radios []radioConfig, look track.Look) [][2]string {
+	fields := make([][2]string, 0, 2*len(radios))
+	for _, radio := range radios {
+		name := radio.Name
+		if name == "" {
+			name = lang.text("radio")
+		}
+		if radio.DownlinkMHz > 0 {
+			fields = append(fields, [2]string{name + " ↓", formatDoppler(radio.DownlinkMHz, track.DopplerDownlink(radio.DownlinkMHz*1e6, look.RangeRateKmS))})
+		}
+		if radio.UplinkMHz > 0 {
+			fields = append(fields, [2]string{name + " ↑", formatDoppler(radio.UplinkMHz, track.DopplerUplink(radio.UplinkMHz*1e6, look.RangeRateKmS))})
+		}
+	}
+	return fields
+}
+
+func formatDoppler(nominalMHz, correctedHz float64) string {
+	return fmt.Sprintf("%.4f MHz (%+.1f kHz)", correctedHz/1e6, (correctedHz-nominalMHz*1e6)/1e3)
+}
+
+func (m model) currentLook(now time.Time) (track.Look, bool) {
+	if m.sat == nil || m.observer == nil {
+		return track.Look{}, false
+	}
+	look, err := m.observer.Look(m.sat, now)
+	return look, err == nil
+}
+
+func (m model) magnitudeField(now time.Time) [2]string {
+	value := m.lang.text("eclipsed")
+	if magnitude, ok, err := m.observer.Magnitude(m.sat, now, track.ISSStandardMagnitude); err == nil && ok {
+		value = fmt.Sprintf("%.1f", magnitude)
+	}
+	return [2]string{m.lang.text("magnitude"), value}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/weather"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	spaceWeatherRefresh = 6 * time.Hour
+	spaceWeatherRetry   = 30 * time.Minute
+)
+
+type spaceWeatherMsg struct {
+	indices   weather.SpaceWeather
+	fetchedAt time.Time
+	err       error
+}
+
+type spaceWeatherRefreshMsg struct{}
+
+type spaceWeatherCache struct {
+	Fetched time.Time            `json:"fetched"`
+	Indices weather.SpaceWeather `json:"indices"`
+}
+
+func loadSpaceWeather(client *http.Client, now time.Time) spaceWeatherMsg {
+	dir, pathErr := cacheDir()
+	path := filepath.Join(dir, "space-weather.json")
+
+	var cached spaceWeatherCache
+	if pathErr == nil {
+		withFileLock(path, func() error {
+			data, err := readFileIfExists(path)
+			if err == nil && len(data) > 0 {
+				json.Unmarshal(data, &cached)
+			}
+			return nil
+		})
+	}
+	if !cached.Fetched.IsZero() && now.Sub(cached.Fetched) < spaceWeatherRefresh {
+		return spaceWeatherMsg{indices: cached.Indices, fetchedAt: cached.Fetched}
+	}
+
+	indices, err := weather.FetchSpaceWeather(client)
+	if err != nil {
+		err = fmt.Errorf("space weather: %w", err)
+		if !cached.Fetched.IsZero() {
+			return spaceWeatherMsg{indices: cached.Indices, fetchedAt: cached.Fetched, err: err}
+		}
+		return spaceWeatherMsg{err: err}
+	}
+
+	if pathErr == nil {
+		if data, err := json.Marshal(spaceWeatherCache{Fetched: now, Indices: indices}); err == nil {
+			withFileLock(path, func() error {
+				return writeFileAtomic(path, data, 0o644)
+			})
+		}
+	}
+	return spaceWeatherMsg{indices: indices, fetchedAt: now}
+}
+
+func fetchSpaceWeatherCmd(client *http.Client) tea.Cmd {
+	return func() tea.Msg {
+		return loadSpaceWeather(client, time.Now())
+	}
+}
+
+func spaceWeatherRefreshTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return spaceWeatherRefreshMsg{}
+	})
+}
+
+func (m model) applySpaceWeather(msg spaceWeatherMsg) (model, tea.Cmd) {
+	next := spaceWeatherRefresh
+	if msg.err != nil {
+		m.lastErr = msg.err.Error()
+		next = spaceWeatherRetry
+	}
+	if !msg.fetchedAt.IsZero() {
+		indices := msg.indices
+		m.spaceWx = &indices
+		if msg.err == nil {
+			next = max(msg.fetchedAt.Add(spaceWeatherRefresh).Sub(time.Now()), time.Minute)
+		}
+	}
+	return m, spaceWeatherRefreshTick(next)
+}
+
+func kpLevel(kp float64) string {
+	switch {
+	case kp >= 5:
+		return fmt.Sprintf("storm_g%d", min(int(kp)-4, 5))
+	case kp >= 4:
+		return "kp_active"
+	default:
+		return "kp_quiet"
+	}
+}
+
+func fluxLevel(flux float64) string {
+	switch {
+	case flux >= 150:
+		return "flux_high"
+	case flux >= 100:
+		return "flux_moderate"
+	default:
+		return "flux_low"
+	}
+}
+
+func (m model) spaceWeatherLines() []string {
+	if m.spaceWx == nil {
+		return nil
+	}
+	sw := m.spaceWx
+	fields := [][2]string{
+		{"Kp", fmt.Sprintf("%.1f · %s", sw.Kp, m.lang.text(kpLevel(sw.Kp)))},
+		{"F10.7", fmt.Sprintf("%.0f sfu · %s", sw.F107, m.lang.text(fluxLevel(sw.F107)))},
+	}
+	return append([]string{"", m.lang.text("space_weather")}, alignFields(fields)...)
+}
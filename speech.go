@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/speech"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	speechTimeout     = 30 * time.Second
+	defaultSpeechLead = 2 * time.Minute
+	maxSpeechMinutes  = 60
+	announceWindow    = 45 * time.Second
+)
+
+type speechConfig struct {
+	Command     []string `json:"command"`
+	Voice       string   `json:"voice"`
+	PassMinutes *float64 `json:"pass_minutes"`
+}
+
+type speechSettings struct {
+	speaker speech.Speaker
+	lead    time.Duration
+}
+
+type speechStage int
+
+const (
+	stageNone speechStage = iota
+	stageSoon
+	stageRise
+	stagePeak
+)
+
+type announcer struct {
+	lead  time.Duration
+	pass  time.Time
+	stage speechStage
+}
+
+var spokenDirections = []string{"speak_n", "speak_ne", "speak_e", "speak_se", "speak_s", "speak_sw", "speak_w", "speak_nw"}
+
+func speechFromConfig(cfg config, lang language) (speechSettings, error) {
+	sc := cfg.Speech
+	if sc == nil {
+		return speechSettings{}, nil
+	}
+	settings := speechSettings{lead: defaultSpeechLead}
+	if sc.PassMinutes != nil {
+		if *sc.PassMinutes < 0 || *sc.PassMinutes > maxSpeechMinutes {
+			return speechSettings{}, fmt.Errorf("speech: pass_minutes must be between 0 and %d", maxSpeechMinutes)
+		}
+		settings.lead = time.Duration(*sc.PassMinutes * float64(time.Minute))
+	}
+	if len(sc.Command) > 0 {
+		if sc.Command[0] == "" {
+			return speechSettings{}, errors.New("speech: command needs a program")
+		}
+		settings.speaker = speech.Command{Program: sc.Command[0], Args: sc.Command[1:]}
+		return settings, nil
+	}
+	speaker, err := speech.Detect(sc.Voice, lang.base)
+	if err != nil {
+		return speechSettings{}, fmt.Errorf("speech: %w", err)
+	}
+	settings.speaker = speaker
+	return settings, nil
+}
+
+func speechSubscriber(speaker speech.Speaker) subscriber {
+	return func(e busEvent) tea.Cmd {
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), speechTimeout)
+			defer cancel()
+			if err := speaker.Speak(ctx, e.text); err != nil {
+				return errMsg{err: fmt.Errorf("speech: %w", err)}
+			}
+			return nil
+		}
+	}
+}
+
+func spokenDirection(lang language, azimuth float64) string {
+	index := int(math.Round(azimuth/45)) % len(spokenDirections)
+	return lang.text(spokenDirections[index])
+}
+
+func (m model) checkAnnouncements(now time.Time, pos track.Position) (model, []busEvent) {
+	pass := m.skyPass
+	if m.speaker == nil || pass == nil || !pass.Visible || !pass.End.After(now) {
+		return m, nil
+	}
+	if !pass.Start.Equal(m.announcer.pass) {
+		m.announcer.pass, m.announcer.stage = pass.Start, stageNone
+	}
+
+	stage, text := stageNone, ""
+	switch {
+	case !now.Before(pass.Max.Add(announceWindow)):
+	case !now.Before(pass.Max):
+		stage = stagePeak
+		text = fmt.Sprintf(m.lang.text("speak_peak"), pass.MaxElevation, spokenDirection(m.lang, pass.MaxAzimuth))
+	case !now.Before(pass.Start.Add(announceWindow)):
+	case !now.Before(pass.Start):
+		stage = stageRise
+		text = fmt.Sprintf(m.lang.text("speak_rise"), spokenDirection(m.lang, pass.StartAzimuth), pass.MaxElevation)
+	case m.announcer.lead > 0 && pass.Start.Sub(now) <= m.announcer.lead:
+		stage = stageSoon
+		minutes := max(int(pass.Start.Sub(now).Round(time.Minute).Minutes()), 1)
+		text = fmt.Sprintf(m.lang.text("speak_soon"), spokenDirection(m.lang, pass.StartAzimuth), minutes)
+		if minutes == 1 {
+			text = fmt.Sprintf(m.lang.text("speak_soon_one"), spokenDirection(m.lang, pass.StartAzimuth))
+		}
+	}
+	if stage <= m.announcer.stage {
+		return m, nil
+	}
+	m.announcer.stage = stage
+	return m, []busEvent{{topic: topicAnnounce, at: now, text: text, pos: pos, pass: pass}}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const startupFrameInterval = 120 * time.Millisecond
+
+type startupStep int
+
+const (
+	stepConfig startupStep = iota
+	stepMap
+	stepTLE
+	stepPosition
+	startupSteps
+)
+
+var startupLabels = [startupSteps]string{"startup_config", "startup_map", "startup_tle", "startup_position"}
+
+type stepStatus int
+
+const (
+	stepPending stepStatus = iota
+	stepReady
+	stepFailed
+)
+
+type readyMsg struct {
+	step startupStep
+	err  error
+}
+
+type startupTickMsg struct{}
+
+type startupState struct {
+	done    bool
+	frame   int
+	status  [startupSteps]stepStatus
+	errs    [startupSteps]string
+	pending []readyMsg
+}
+
+func newStartup(configErr, mapErr error) startupState {
+	return startupState{pending: []readyMsg{{step: stepConfig, err: configErr}, {step: stepMap, err: mapErr}}}
+}
+
+func startupTick() tea.Cmd {
+	return tea.Tick(startupFrameInterval, func(time.Time) tea.Msg {
+		return startupTickMsg{}
+	})
+}
+
+func (s startupState) begin() []tea.Cmd {
+	if s.done {
+		return nil
+	}
+	cmds := []tea.Cmd{startupTick()}
+	for _, msg := range s.pending {
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	return cmds
+}
+
+func (s startupState) mark(msg readyMsg) startupState {
+	s.status[msg.step], s.errs[msg.step] = stepReady, ""
+	if msg.err != nil {
+		s.status[msg.step], s.errs[msg.step] = stepFailed, msg.err.Error()
+	}
+	s.done = s.status[stepPosition] == stepReady && s.status[stepConfig] != stepPending && s.status[stepMap] != stepPending
+	return s
+}
+
+func (s startupState) observe(msg tea.Msg) startupState {
+	switch msg := msg.(type) {
+	case telemetryDoneMsg:
+		if failed, ok := msg.msg.(errMsg); ok {
+			return s.mark(readyMsg{step: stepPosition, err: failed.err})
+		}
+	case telemetryMsg:
+		return s.mark(readyMsg{step: stepPosition})
+	case daemonLostMsg:
+		return s.mark(readyMsg{step: stepPosition, err: msg.err})
+	case satelliteMsg:
+		if msg.sat != nil {
+			return s.mark(readyMsg{step: stepTLE})
+		}
+		return s.mark(readyMsg{step: stepTLE, err: msg.err})
+	}
+	return s
+}
+
+func (m model) startupView() string {
+	const spinner = `-\|/`
+	lines := []string{m.lang.text("startup_title"), ""}
+	width := max(m.width-16, 20)
+	for step, label := range startupLabels {
+		mark := "[ " + string(spinner[m.startup.frame%len(spinner)]) + "]"
+		text := m.lang.text(label)
+		switch m.startup.status[step] {
+		case stepReady:
+			mark = "[ok]"
+		case stepFailed:
+			mark = "[!!]"
+			if startupStep(step) == stepPosition {
+				text += " " + m.lang.text("startup_retrying")
+			}
+			text += ": " + m.startup.errs[step]
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", mark, ansi.Truncate(text, width, "…")))
+	}
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+)
+
+const (
+	stateFileName = "state.json"
+	stateMaxAge   = time.Hour
+	trailSpacing  = 30 * time.Second
+	trailMax      = 90
+)
+
+type trailPoint struct {
+	Lat float64   `json:"lat"`
+	Lon float64   `json:"lon"`
+	At  time.Time `json:"at"`
+}
+
+type stateSnapshot struct {
+	SavedAt     time.Time       `json:"saved_at"`
+	Position    *statePosition  `json:"position,omitempty"`
+	Location    string          `json:"location,omitempty"`
+	CountryCode string          `json:"country_code,omitempty"`
+	Lang        string          `json:"lang,omitempty"`
+	Trail       []trailPoint    `json:"trail,omitempty"`
+	View        string          `json:"view"`
+	Viewport    render.Viewport `json:"viewport"`
+	UTC         bool            `json:"utc"`
+	CountrySort string          `json:"country_sort"`
+	Tour        bool            `json:"tour"`
+}
+
+type statePosition struct {
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	AltitudeKm  float64   `json:"altitude_km,omitempty"`
+	VelocityKmh float64   `json:"velocity_kmh,omitempty"`
+	HasAltitude bool      `json:"has_altitude"`
+	At          time.Time `json:"at"`
+}
+
+var restorableViews = map[viewMode]string{
+	viewMap:         "map",
+	viewDiagnostics: "diagnostics",
+	viewCountries:   "countries",
+	viewLog:         "log",
+	viewAltitude:    "altitude",
+	viewLatitude:    "latitude",
+	viewNadir:       "nadir",
+	viewCollection:  "collection",
+}
+
+func (m model) pushTrail(lat, lon float64, at time.Time) []trailPoint {
+	if n := len(m.trail); n > 0 && at.Sub(m.trail[n-1].At) < trailSpacing {
+		return m.trail
+	}
+	trail := append(m.trail, trailPoint{Lat: lat, Lon: lon, At: at})
+	if extra := len(trail) - trailMax; extra > 0 {
+		trail = append([]trailPoint(nil), trail[extra:]...)
+	}
+	return trail
+}
+
+func (m model) trailGlyphs() []render.Glyph {
+	glyphs := make([]render.Glyph, len(m.trail))
+	for i, p := range m.trail {
+		glyphs[i] = render.Glyph{Lat: p.Lat, Lon: p.Lon, Char: '.', Color: m.palette.color("bright-white")}
+	}
+	return glyphs
+}
+
+func (m model) snapshot(now time.Time) stateSnapshot {
+	s := stateSnapshot{
+		SavedAt:     now,
+		Trail:       m.trail,
+		View:        restorableViews[viewMap],
+		Viewport:    m.mapView,
+		UTC:         m.times.utc,
+		CountrySort: "time",
+		Tour:        m.tour.enabled,
+	}
+	if name, ok := restorableViews[m.view]; ok {
+		s.View = name
+	}
+	if m.countrySort == sortByName {
+		s.CountrySort = "name"
+	}
+	if m.hasCoords {
+		s.Position = &statePosition{
+			Lat:         m.lat,
+			Lon:         m.lon,
+			AltitudeKm:  m.altitudeKm,
+			VelocityKmh: m.velocityKmh,
+			HasAltitude: m.hasAltitude,
+			At:          m.motion.at,
+		}
+		if m.issOver != m.lang.text("resolving") {
+			s.Location, s.CountryCode, s.Lang = m.issOver, m.countryCode, m.lang.geocodeKey()
+		}
+	}
+	return s
+}
+
+func (m model) restore(s stateSnapshot, now time.Time) model {
+	for view, name := range restorableViews {
+		if name == s.View {
+			m.view = view
+		}
+	}
+	m.mapView = s.Viewport.Clamped()
+	m.times.utc = m.times.utc || s.UTC
+	if s.CountrySort == "name" {
+		m.countrySort = sortByName
+	}
+	m.tour.enabled = m.tour.enabled || s.Tour
+
+	pos := s.Position
+	if pos == nil || now.Sub(pos.At) > stateMaxAge || now.Sub(s.SavedAt) > stateMaxAge {
+		return m
+	}
+	m.lat, m.lon, m.hasCoords = pos.Lat, pos.Lon, true
+	m.startup.done = true
+	m.altitudeKm, m.velocityKmh, m.hasAltitude = pos.AltitudeKm, pos.VelocityKmh, pos.HasAltitude
+	m.motion = m.motion.observe(pos.Lat, pos.Lon, pos.At)
+	if s.Location != "" && s.Lang == m.lang.geocodeKey() {
+		m.issOver, m.countryCode = s.Location, s.CountryCode
+	}
+	for _, p := range s.Trail {
+		if now.Sub(p.At) <= stateMaxAge {
+			m.trail = append(m.trail, p)
+		}
+	}
+	return m
+}
+
+func loadState() (stateSnapshot, bool) {
+	path, err := dataPath(stateFileName)
+	if err != nil {
+		return stateSnapshot{}, false
+	}
+	var data []byte
+	err = withFileLock(path, func() error {
+		data, err = readFileIfExists(path)
+		return err
+	})
+	if err != nil || data == nil {
+		return stateSnapshot{}, false
+	}
+	var s stateSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		slog.Warn("ignoring saved state", "path", path, "err", err)
+		return stateSnapshot{}, false
+	}
+	return s, true
+}
+
+func saveState(s stateSnapshot) error {
+	path, err := dataPath(stateFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return withFileLock(path, func() error {
+		return writeFileAtomic(path, data, 0o644)
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	crewRefresh = 6 * time.Hour
+	crewRetry   = 30 * time.Minute
+)
+
+type stationMsg struct {
+	sat *track.Satellite
+	err error
+}
+
+type crewMsg struct {
+	people []track.CrewMember
+	err    error
+}
+
+type crewRefreshMsg struct{}
+
+func fetchStationCmd(client *http.Client, noradID int, refresh time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		set, fetchErr := loadTLE(client, noradID, refresh)
+		if set.Line1 == "" {
+			return stationMsg{err: fmt.Errorf("tiangong tle: %w", fetchErr)}
+		}
+		sat, err := track.NewSatellite(set)
+		if err != nil {
+			return stationMsg{err: fmt.Errorf("tiangong tle: %w", err)}
+		}
+		return stationMsg{sat: sat, err: fetchErr}
+	}
+}
+
+func fetchCrewCmd(client *http.Client) tea.Cmd {
+	return func() tea.Msg {
+		people, err := track.FetchCrew(client)
+		if err != nil {
+			err = fmt.Errorf("crew: %w", err)
+		}
+		return crewMsg{people: people, err: err}
+	}
+}
+
+func crewRefreshTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return crewRefreshMsg{}
+	})
+}
+
+func (m model) applyCrew(msg crewMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		m.lastErr = msg.err.Error()
+		return m, crewRefreshTick(crewRetry)
+	}
+	m.crew = track.CountByCraft(msg.people)
+	return m, crewRefreshTick(crewRefresh)
+}
+
+func (m model) crewField() ([2]string, bool) {
+	if m.crew == nil {
+		return [2]string{}, false
+	}
+	total := 0
+	parts := make([]string, len(m.crew))
+	for i, count := range m.crew {
+		total += count.People
+		parts[i] = fmt.Sprintf("%s %d", count.Craft, count.People)
+	}
+	return [2]string{m.lang.text("people_in_space"), fmt.Sprintf("%d · %s", total, strings.Join(parts, ", "))}, true
+}
+
+func (m model) tiangongPosition(now time.Time) (track.Position, bool) {
+	if m.tiangong == nil {
+		return track.Position{}, false
+	}
+	pos, err := m.tiangong.PositionAt(now)
+	return pos, err == nil
+}
+
+func (m model) tiangongFields(now time.Time) [][2]string {
+	pos, ok := m.tiangongPosition(now)
+	if !ok {
+		return nil
+	}
+	return [][2]string{
+		{m.lang.text("tiangong"), formatLatitude(pos.Lat) + ", " + formatLongitude(pos.Lon)},
+		{m.lang.text("tiangong_alt"), m.units.formatDistance(pos.AltitudeKm) + " · " + m.units.formatSpeed(pos.VelocityKmh)},
+	}
+}
+
+func (m model) tiangongGlyph(now time.Time) (render.Glyph, bool) {
+	pos, ok := m.tiangongPosition(now)
+	if !ok {
+		return render.Glyph{}, false
+	}
+	return render.Glyph{Lat: pos.Lat, Lon: pos.Lon, Char: 'T', Color: m.palette.color("bright-red")}, true
+}
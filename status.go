@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/geo"
+)
+
+const (
+	statusCacheFile     = "status.json"
+	defaultStatusMaxAge = 10 * time.Second
+	defaultWaybarFormat = "ISS {{.Country}}"
+)
+
+type statusOptions struct {
+	format string
+	waybar bool
+	maxAge time.Duration
+}
+
+type statusSnapshot struct {
+	Lang        string    `json:"lang"`
+	Country     string    `json:"country"`
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	AltitudeKm  float64   `json:"altitude_km,omitempty"`
+	VelocityKmh float64   `json:"velocity_kmh,omitempty"`
+	HasAltitude bool      `json:"has_altitude"`
+	Provider    string    `json:"provider"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+type statusFields struct {
+	Country     string
+	Lat         float64
+	Lon         float64
+	Latitude    string
+	Longitude   string
+	Altitude    string
+	Velocity    string
+	AltitudeKm  float64
+	VelocityKmh float64
+	Provider    string
+	Time        time.Time
+}
+
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+func runStatus(ctx context.Context, opts globalOptions, w io.Writer, status statusOptions) error {
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	lang := resolveLanguage(opts.lang, cfg.Lang)
+	if lang.names, err = placeNamesFromConfig(cfg); err != nil {
+		return err
+	}
+	units, err := parseUnits(cfg.Units)
+	if err != nil {
+		return err
+	}
+
+	format := status.format
+	if format == "" && status.waybar {
+		format = defaultWaybarFormat
+	}
+	var tmpl *template.Template
+	if format != "" {
+		tmpl, err = template.New("status").Parse(format)
+		if err != nil {
+			return fmt.Errorf("status format: %w", err)
+		}
+	}
+
+	snapshot, warning, err := currentStatus(ctx, cfg, lang, status.maxAge, time.Now())
+	if err != nil {
+		if status.waybar {
+			return writeWaybar(w, waybarOutput{Text: "ISS ?", Tooltip: err.Error(), Class: "error"})
+		}
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+
+	fields := snapshot.fields(units)
+	switch {
+	case status.waybar:
+		var text strings.Builder
+		if err := tmpl.Execute(&text, fields); err != nil {
+			return fmt.Errorf("status format: %w", err)
+		}
+		return writeWaybar(w, waybarOutput{
+			Text:    text.String(),
+			Tooltip: strings.Join(snapshot.lines(lang, units), "\n"),
+			Class:   "iss",
+		})
+	case tmpl != nil:
+		if err := tmpl.Execute(w, fields); err != nil {
+			return fmt.Errorf("status format: %w", err)
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	default:
+		for _, line := range snapshot.lines(lang, units) {
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+}
+
+func currentStatus(ctx context.Context, cfg config, lang language, maxAge time.Duration, now time.Time) (snapshot statusSnapshot, warning error, err error) {
+	path := ""
+	if dir, err := cacheDir(); err == nil && demo == nil {
+		path = filepath.Join(dir, statusCacheFile)
+	}
+
+	if path != "" && maxAge > 0 {
+		if cached, ok := readStatusCache(path); ok && cached.Lang == lang.geocodeKey() && now.Sub(cached.FetchedAt) < maxAge {
+			return cached, nil, nil
+		}
+	}
+
+	providers, err := positionProviders(cfg)
+	if err != nil {
+		return statusSnapshot{}, nil, err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+
+	switch msg := fetchTelemetryCmd(newHTTPClient(ctx, stats), providers, newGeocodeCache(0), lang, geo.Location{Name: "?"})().(type) {
+	case errMsg:
+		return statusSnapshot{}, nil, msg.err
+	case telemetryMsg:
+		snapshot = statusSnapshot{
+			Lang:        lang.geocodeKey(),
+			Country:     msg.country,
+			Lat:         msg.pos.Lat,
+			Lon:         msg.pos.Lon,
+			AltitudeKm:  msg.pos.AltitudeKm,
+			VelocityKmh: msg.pos.VelocityKmh,
+			HasAltitude: msg.pos.HasAltitude,
+			Provider:    msg.pos.Provider,
+			FetchedAt:   now,
+		}
+		if path != "" && msg.err == nil {
+			if data, err := json.Marshal(snapshot); err == nil {
+				writeFileAtomic(path, data, 0o644)
+			}
+		}
+		return snapshot, msg.err, nil
+	}
+
+	return statusSnapshot{}, nil, fmt.Errorf("unexpected telemetry result")
+}
+
+func readStatusCache(path string) (statusSnapshot, bool) {
+	data, err := readFileIfExists(path)
+	if err != nil || len(data) == 0 {
+		return statusSnapshot{}, false
+	}
+
+	var snapshot statusSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return statusSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+func (s statusSnapshot) fields(units unitSystem) statusFields {
+	fields := statusFields{
+		Country:   s.Country,
+		Lat:       s.Lat,
+		Lon:       s.Lon,
+		Latitude:  formatLatitude(s.Lat),
+		Longitude: formatLongitude(s.Lon),
+		Provider:  s.Provider,
+		Time:      s.FetchedAt,
+	}
+	if s.HasAltitude {
+		fields.AltitudeKm = s.AltitudeKm
+		fields.VelocityKmh = s.VelocityKmh
+		fields.Altitude = units.formatDistance(s.AltitudeKm)
+		fields.Velocity = units.formatSpeed(s.VelocityKmh)
+	}
+	return fields
+}
+
+func (s statusSnapshot) lines(lang language, units unitSystem) []string {
+	fields := [][2]string{
+		{lang.text("latitude"), formatLatitude(s.Lat)},
+		{lang.text("longitude"), formatLongitude(s.Lon)},
+	}
+	if s.HasAltitude {
+		fields = append(fields,
+			[2]string{lang.text("altitude"), units.formatDistance(s.AltitudeKm)},
+			[2]string{lang.text("velocity"), units.formatSpeed(s.VelocityKmh)},
+		)
+	}
+
+	return append([]string{lang.text("iss_over") + ": " + s.Country}, alignFields(fields)...)
+}
+
+func writeWaybar(w io.Writer, out waybarOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
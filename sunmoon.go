@@ -0,0 +1,44 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Kivayan/iss/pkg/render"
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const sunMoonInterval = time.Minute
+
+type sunMoonTickMsg struct{}
+
+func sunMoonTick() tea.Cmd {
+	return tea.Tick(sunMoonInterval, func(time.Time) tea.Msg {
+		return sunMoonTickMsg{}
+	})
+}
+
+func (m model) mapGlyphs(now time.Time) []render.Glyph {
+	glyphs := append([]render.Glyph(nil), m.cloudLayer...)
+	glyphs = append(glyphs, m.trailGlyphs()...)
+	glyphs = append(glyphs, m.bookmarkGlyphs()...)
+	if arrow, ok := m.headingGlyph(); ok {
+		glyphs = append(glyphs, arrow)
+	}
+	if pin, ok := m.searchPinGlyph(); ok {
+		glyphs = append(glyphs, pin)
+	}
+	if station, ok := m.tiangongGlyph(now); ok {
+		glyphs = append(glyphs, station)
+	}
+	if !m.sunMoon {
+		return glyphs
+	}
+
+	sunLat, sunLon := track.SubsolarPoint(now)
+	moonLat, moonLon := track.SublunarPoint(now)
+	return append(glyphs,
+		render.Glyph{Lat: moonLat, Lon: moonLon, Char: 'M', Color: m.palette.color("bright-white")},
+		render.Glyph{Lat: sunLat, Lon: sunLon, Char: 'S', Color: m.palette.color("bright-yellow")},
+	)
+}
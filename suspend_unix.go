@@ -0,0 +1,43 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const suspendSupported = true
+
+func forwardSuspend(ctx context.Context, p *tea.Program) {
+	stop := make(chan os.Signal, 1)
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	go func() {
+		defer signal.Stop(cont)
+		for {
+			signal.Notify(stop, syscall.SIGTSTP)
+			select {
+			case <-ctx.Done():
+				signal.Stop(stop)
+				return
+			case <-stop:
+			}
+			signal.Stop(stop)
+			select {
+			case <-cont:
+			default:
+			}
+			p.Send(suspendMsg{})
+			select {
+			case <-ctx.Done():
+				return
+			case <-cont:
+			}
+		}
+	}()
+}
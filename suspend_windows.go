@@ -0,0 +1,11 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const suspendSupported = false
+
+func forwardSuspend(ctx context.Context, p *tea.Program) {}
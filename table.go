@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func formatTable(rows [][]string, rightAligned map[int]bool) []string {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], ansi.StringWidth(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-ansi.StringWidth(cell))
+			if rightAligned[i] {
+				cells[i] = padding + cell
+			} else {
+				cells[i] = cell + padding
+			}
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	return lines
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/weather"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	layoutClock = "15:04:05"
+	layoutTenth = "15:04:05.0"
+	layoutShort = "15:04"
+	layoutDate  = "2006-01-02"
+	layoutDay   = "Mon 2006-01-02"
+	layoutStamp = "2006-01-02 15:04"
+	layoutFull  = "2006-01-02 15:04:05"
+)
+
+type timeDisplay struct {
+	loc *time.Location
+	utc bool
+}
+
+type timezoneMsg struct {
+	loc *time.Location
+	err error
+}
+
+func (d timeDisplay) location() *time.Location {
+	if d.utc {
+		return time.UTC
+	}
+	if d.loc != nil {
+		return d.loc
+	}
+	return time.Local
+}
+
+func (d timeDisplay) in(t time.Time) time.Time {
+	return t.In(d.location())
+}
+
+func (d timeDisplay) format(t time.Time, layout string) string {
+	return d.in(t).Format(layout)
+}
+
+func (d timeDisplay) zone(t time.Time) string {
+	name, _ := d.in(t).Zone()
+	return name
+}
+
+func (d timeDisplay) toggleUTC() timeDisplay {
+	d.utc = !d.utc
+	return d
+}
+
+func timezoneCachePath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timezones.json"), nil
+}
+
+func timezoneCacheKey(lat, lon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", lat, lon)
+}
+
+func cachedObserverTimezone(lat, lon float64) string {
+	path, err := timezoneCachePath()
+	if err != nil {
+		return ""
+	}
+	var names map[string]string
+	withFileLock(path, func() error {
+		data, err := readFileIfExists(path)
+		if err == nil && len(data) > 0 {
+			json.Unmarshal(data, &names)
+		}
+		return nil
+	})
+	return names[timezoneCacheKey(lat, lon)]
+}
+
+func lookupObserverTimezone(client *http.Client, lat, lon float64) (*time.Location, error) {
+	if name := cachedObserverTimezone(lat, lon); name != "" {
+		return time.LoadLocation(name)
+	}
+
+	name, err := weather.Timezone(client, weather.Point{Lat: lat, Lon: lon})
+	if err != nil {
+		return nil, fmt.Errorf("time zone lookup: %w", err)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if path, err := timezoneCachePath(); err == nil {
+		withFileLock(path, func() error {
+			names := map[string]string{}
+			if data, err := readFileIfExists(path); err == nil && len(data) > 0 {
+				json.Unmarshal(data, &names)
+			}
+			names[timezoneCacheKey(lat, lon)] = name
+			data, err := json.Marshal(names)
+			if err != nil {
+				return err
+			}
+			return writeFileAtomic(path, data, 0o644)
+		})
+	}
+	return loc, nil
+}
+
+func timeDisplayFromConfig(cfg config, utc bool) (timeDisplay, bool, error) {
+	display := timeDisplay{utc: utc}
+	switch name := strings.TrimSpace(cfg.Timezone); strings.ToLower(name) {
+	case "", "local":
+		return display, false, nil
+	case "utc":
+		display.utc = true
+		return display, false, nil
+	case "auto":
+		if cfg.Observer == nil {
+			return display, false, nil
+		}
+		if cached := cachedObserverTimezone(cfg.Observer.Lat, cfg.Observer.Lon); cached != "" {
+			loc, err := time.LoadLocation(cached)
+			display.loc = loc
+			return display, false, err
+		}
+		return display, true, nil
+	default:
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return display, false, fmt.Errorf("timezone %q: %w", name, err)
+		}
+		display.loc = loc
+		return display, false, nil
+	}
+}
+
+func resolveTimeDisplay(cfg config, utc bool, client *http.Client) (timeDisplay, error, error) {
+	display, lookup, err := timeDisplayFromConfig(cfg, utc)
+	if err != nil || !lookup {
+		return display, nil, err
+	}
+	loc, warning := lookupObserverTimezone(client, cfg.Observer.Lat, cfg.Observer.Lon)
+	display.loc = loc
+	return display, warning, nil
+}
+
+func lookupTimezoneCmd(client *http.Client, lat, lon float64) tea.Cmd {
+	return func() tea.Msg {
+		loc, err := lookupObserverTimezone(client, lat, lon)
+		return timezoneMsg{loc: loc, err: err}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultTLERefresh = 6 * time.Hour
+	defaultTLEMaxAge  = 3 * 24 * time.Hour
+	tleRetryInterval  = 15 * time.Minute
+	orbitChangeMinKm  = 0.3
+)
+
+type tleRefreshMsg struct{}
+
+func tleRefreshFromConfig(cfg config) time.Duration {
+	if cfg.TLERefreshHours > 0 {
+		return time.Duration(cfg.TLERefreshHours * float64(time.Hour))
+	}
+	return defaultTLERefresh
+}
+
+func tleMaxAgeFromConfig(cfg config) time.Duration {
+	if cfg.TLEMaxAgeDays > 0 {
+		return time.Duration(cfg.TLEMaxAgeDays * float64(24*time.Hour))
+	}
+	return defaultTLEMaxAge
+}
+
+func tleCachePath(noradID int) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("tle-%d.txt", noradID)), nil
+}
+
+func loadTLE(client *http.Client, noradID int, refresh time.Duration) (track.TLE, error) {
+	if demo != nil {
+		if noradID != track.ISSNoradID {
+			return track.TLE{}, fmt.Errorf("NORAD %d: %w", noradID, errOffline)
+		}
+		return demo.tle, nil
+	}
+	path, pathErr := tleCachePath(noradID)
+
+	var cached track.TLE
+	var fetchedAt time.Time
+	if pathErr == nil {
+		withFileLock(path, func() error {
+			cached, fetchedAt = readCachedTLE(path)
+			return nil
+		})
+	}
+	if cached.Line1 != "" && time.Since(fetchedAt) < refresh {
+		return cached, nil
+	}
+
+	set, err := track.FetchTLE(client, noradID)
+	if err != nil {
+		if cached.Line1 != "" {
+			return cached, fmt.Errorf("tle refresh failed, using copy fetched %s ago: %w", time.Since(fetchedAt).Round(time.Minute), err)
+		}
+		return track.TLE{}, err
+	}
+
+	if pathErr == nil {
+		writeCachedTLEs(path, []track.TLE{set})
+	}
+	return set, nil
+}
+
+func cachedTLE(noradID int) track.TLE {
+	path, err := tleCachePath(noradID)
+	if demo != nil || err != nil {
+		return track.TLE{}
+	}
+	var set track.TLE
+	withFileLock(path, func() error {
+		set, _ = readCachedTLE(path)
+		return nil
+	})
+	return set
+}
+
+func readCachedTLE(path string) (track.TLE, time.Time) {
+	sets, fetchedAt := readCachedTLEs(path)
+	if len(sets) == 0 {
+		return track.TLE{}, time.Time{}
+	}
+	return sets[0], fetchedAt
+}
+
+func readCachedTLEs(path string) ([]track.TLE, time.Time) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	data, err := readFileIfExists(path)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	sets, err := track.ParseTLEs(bytes.NewReader(data))
+	if err != nil || len(sets) == 0 {
+		return nil, time.Time{}
+	}
+	return sets, info.ModTime()
+}
+
+func writeCachedTLEs(path string, sets []track.TLE) error {
+	var b bytes.Buffer
+	for _, set := range sets {
+		if set.Name != "" {
+			fmt.Fprintln(&b, set.Name)
+		}
+		fmt.Fprintln(&b, set.Line1)
+		fmt.Fprintln(&b, set.Line2)
+	}
+	return withFileLock(path, func() error {
+		return writeFileAtomic(path, b.Bytes(), 0o644)
+	})
+}
+
+func loadTLEGroup(client *http.Client, group string, refresh time.Duration) ([]track.TLE, error) {
+	if demo != nil {
+		return nil, fmt.Errorf("group %s: %w", group, errOffline)
+	}
+	dir, pathErr := cacheDir()
+	path := filepath.Join(dir, "tle-group-"+group+".txt")
+
+	var cached []track.TLE
+	var fetchedAt time.Time
+	if pathErr == nil {
+		withFileLock(path, func() error {
+			cached, fetchedAt = readCachedTLEs(path)
+			return nil
+		})
+	}
+	if len(cached) > 0 && time.Since(fetchedAt) < refresh {
+		return cached, nil
+	}
+
+	sets, err := track.FetchTLEGroup(client, group)
+	if err != nil {
+		if len(cached) > 0 {
+			return cached, fmt.Errorf("group %s refresh failed, using copy fetched %s ago: %w", group, time.Since(fetchedAt).Round(time.Minute), err)
+		}
+		return nil, err
+	}
+	if pathErr == nil {
+		writeCachedTLEs(path, sets)
+	}
+	return sets, nil
+}
+
+func (m model) orbitChangeEvent(previous, sat *track.Satellite) (busEvent, bool) {
+	if !sat.Epoch.After(previous.Epoch) {
+		return busEvent{}, false
+	}
+	change, err := track.CompareOrbits(previous, sat)
+	if err != nil || math.Abs(change.DeltaKm) < orbitChangeMinKm {
+		return busEvent{}, false
+	}
+	format := m.lang.text("orbit_raised")
+	if change.DeltaKm < 0 {
+		format = m.lang.text("orbit_lowered")
+	}
+	text := fmt.Sprintf(format, m.units.formatDistance(math.Abs(change.DeltaKm)), m.times.format(change.To, layoutDay))
+	return busEvent{topic: topicOrbit, at: clockNow(), text: text, pos: track.Position{Lat: m.lat, Lon: m.lon}}, true
+}
+
+func tleRefreshTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return tleRefreshMsg{}
+	})
+}
+
+func (m model) tleStaleLine(now time.Time) string {
+	if m.sat == nil {
+		return ""
+	}
+	age := now.Sub(m.sat.Epoch)
+	if age <= m.tleMaxAge {
+		return ""
+	}
+	return fmt.Sprintf("%s (%.1f d)", m.lang.text("tle_stale"), age.Hours()/24)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	maxToasts     = 3
+	toastDuration = 6 * time.Second
+	toastTopRow   = 1
+)
+
+type toast struct {
+	text  string
+	until time.Time
+}
+
+type toastExpiredMsg struct{}
+
+func (m model) pushToast(text string) (model, tea.Cmd) {
+	now := time.Now()
+	toasts := append(liveToasts(m.toasts, now), toast{text: text, until: now.Add(toastDuration)})
+	if len(toasts) > maxToasts {
+		toasts = toasts[len(toasts)-maxToasts:]
+	}
+	m.toasts = toasts
+	return m, tea.Tick(toastDuration, func(time.Time) tea.Msg { return toastExpiredMsg{} })
+}
+
+func liveToasts(toasts []toast, now time.Time) []toast {
+	var live []toast
+	for _, t := range toasts {
+		if now.Before(t.until) {
+			live = append(live, t)
+		}
+	}
+	return live
+}
+
+func (m model) overlayToasts(screen string, now time.Time) string {
+	toasts := liveToasts(m.toasts, now)
+	if len(toasts) == 0 {
+		return screen
+	}
+
+	texts := make([]string, len(toasts))
+	for i, t := range toasts {
+		texts[i] = m.icon("toast") + t.text
+	}
+	box := strings.Split(m.box(texts), "\n")
+	boxWidth := ansi.StringWidth(box[0])
+	col := max(m.width-boxWidth-1, 0)
+
+	lines := strings.Split(screen, "\n")
+	for len(lines) < toastTopRow+len(box) {
+		lines = append(lines, "")
+	}
+	for i, row := range box {
+		lines[toastTopRow+i] = overlayLine(lines[toastTopRow+i], row, col)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func overlayLine(line, overlay string, col int) string {
+	left := ansi.Truncate(line, col, "")
+	if pad := col - ansi.StringWidth(left); pad > 0 {
+		left += strings.Repeat(" ", pad)
+	}
+	right := ansi.TruncateLeft(line, col+ansi.StringWidth(overlay), "")
+	if strings.Contains(line, "\x1b[") {
+		return left + "\x1b[0m" + overlay + right
+	}
+	return left + overlay + right
+}
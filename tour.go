@@ -0,0 +1,98 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Kivayan/iss/pkg/geo"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const tourTickerSize = 3
+
+//go:embed data/annotations.json
+var annotationsJSON []byte
+
+type annotation struct {
+	Text     string    `json:"text"`
+	Lat      float64   `json:"lat"`
+	Lon      float64   `json:"lon"`
+	RadiusKm float64   `json:"radius_km"`
+	Box      []float64 `json:"box"`
+}
+
+type tourState struct {
+	enabled bool
+	active  map[int]bool
+	ticker  []string
+}
+
+func loadAnnotations() ([]annotation, error) {
+	var annotations []annotation
+	if err := json.Unmarshal(annotationsJSON, &annotations); err != nil {
+		return nil, fmt.Errorf("parse annotations: %w", err)
+	}
+
+	for i, a := range annotations {
+		if len(a.Box) != 0 && len(a.Box) != 4 {
+			return nil, fmt.Errorf("annotation %d (%s): box must be [min_lat, min_lon, max_lat, max_lon]", i, a.Text)
+		}
+		if len(a.Box) == 0 && a.RadiusKm <= 0 {
+			return nil, fmt.Errorf("annotation %d (%s): needs a box or a positive radius_km", i, a.Text)
+		}
+	}
+
+	return annotations, nil
+}
+
+func (a annotation) contains(lat, lon float64) bool {
+	if len(a.Box) == 4 {
+		return lat >= a.Box[0] && lat <= a.Box[2] && geo.InLonRange(lon, a.Box[1], a.Box[3])
+	}
+	return geo.HaversineKm(lat, lon, a.Lat, a.Lon) <= a.RadiusKm
+}
+
+func (t tourState) advance(annotations []annotation, lat, lon float64) tourState {
+	active := make(map[int]bool, len(t.active))
+	for i, a := range annotations {
+		if !a.contains(lat, lon) {
+			continue
+		}
+
+		active[i] = true
+		if !t.active[i] {
+			t = t.push(a.Text)
+		}
+	}
+
+	t.active = active
+	return t
+}
+
+func (t tourState) push(text string) tourState {
+	ticker := make([]string, 0, tourTickerSize)
+	ticker = append(ticker, text)
+	for _, previous := range t.ticker {
+		if len(ticker) == tourTickerSize {
+			break
+		}
+		ticker = append(ticker, previous)
+	}
+
+	t.ticker = ticker
+	return t
+}
+
+func (t tourState) line(label string, width int) string {
+	if !t.enabled || len(t.ticker) == 0 {
+		return ""
+	}
+
+	line := label + ": " + strings.Join(t.ticker, "  ·  ")
+	if width > 4 {
+		line = ansi.Truncate(line, width-4, "…")
+	}
+	return line
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+type transitOptions struct {
+	observer observerFlags
+	radiusKm float64
+	days     int
+	json     bool
+}
+
+type transitJSON struct {
+	Body          string    `json:"body"`
+	Time          time.Time `json:"time"`
+	DurationSec   float64   `json:"duration_s"`
+	WidthKm       float64   `json:"width_km"`
+	CentreKm      float64   `json:"centre_km"`
+	CentreAzimuth float64   `json:"centre_azimuth"`
+	SeparationDeg float64   `json:"separation_deg"`
+	RadiusDeg     float64   `json:"radius_deg"`
+	BodyElevation float64   `json:"body_elevation"`
+	BodyAzimuth   float64   `json:"body_azimuth"`
+	RangeKm       float64   `json:"range_km"`
+	Visible       bool      `json:"visible"`
+}
+
+func newTransitCmd(opts *globalOptions) *cobra.Command {
+	transit := transitOptions{radiusKm: 25, days: 14}
+	cmd := &cobra.Command{
+		Use:   "transit",
+		Short: "Predict the station crossing the Sun or the Moon",
+		Long:  "Predict when the station crosses the disc of the Sun or the Moon as seen from near an\nobserver, by propagating the current Celestrak TLE with SGP4. Each transit is seen along\na narrow path on the ground; it is listed when the centre of that path comes within\n--radius km, with its width, how long the crossing lasts and where the path is.\nThe elements are good to a few km, so check again a day or two before.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransit(cmd, *opts, cmd.OutOrStdout(), transit)
+		},
+	}
+	addObserverFlags(cmd, &transit.observer)
+	cmd.Flags().Float64Var(&transit.radiusKm, "radius", transit.radiusKm, "list transits whose centre line passes within this many km")
+	cmd.Flags().IntVar(&transit.days, "days", transit.days, "length of the search window in days")
+	cmd.Flags().BoolVar(&transit.json, "json", false, "print JSON")
+
+	return cmd
+}
+
+func runTransit(cmd *cobra.Command, opts globalOptions, w io.Writer, transit transitOptions) error {
+	if transit.radiusKm < 0 {
+		return fmt.Errorf("--radius must not be negative, got %g", transit.radiusKm)
+	}
+	if transit.days <= 0 {
+		return fmt.Errorf("--days must be positive, got %d", transit.days)
+	}
+
+	cfg, err := loadConfig(opts.configPath)
+	if err != nil {
+		return err
+	}
+	observer, err := resolveObserver(cmd, transit.observer, cfg)
+	if err != nil {
+		return err
+	}
+
+	stats := newProviderStats()
+	defer stats.save()
+	client := newHTTPClient(cmd.Context(), stats)
+
+	times, warning, err := resolveTimeDisplay(cfg, opts.utc, client)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", warning)
+	}
+
+	set, err := loadTLE(client, track.ISSNoradID, tleRefreshFromConfig(cfg))
+	if set.Line1 == "" {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+	}
+	sat, err := track.NewSatellite(set)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now()
+	to := from.AddDate(0, 0, transit.days)
+	if drift := epochDistance(sat.Epoch, to); drift > tleAccuracyDays*24*time.Hour {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: the TLE epoch is %.0f days from the search window; predicted times will drift\n", drift.Hours()/24)
+	}
+	found, err := track.FindTransits(sat, observer, from, to, transit.radiusKm)
+	if err != nil {
+		return err
+	}
+
+	if transit.json {
+		out := make([]transitJSON, 0, len(found))
+		for _, t := range found {
+			out = append(out, transitJSON{
+				Body:          t.Body.String(),
+				Time:          t.Time.UTC(),
+				DurationSec:   t.Duration.Seconds(),
+				WidthKm:       t.WidthKm,
+				CentreKm:      t.CentreKm,
+				CentreAzimuth: t.CentreAzimuth,
+				SeparationDeg: t.SeparationDeg,
+				RadiusDeg:     t.RadiusDeg,
+				BodyElevation: t.BodyElevation,
+				BodyAzimuth:   t.BodyAzimuth,
+				RangeKm:       t.RangeKm,
+				Visible:       t.Visible,
+			})
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	if len(found) == 0 {
+		fmt.Fprintf(w, "No transits with a centre line within %.0f km between %s and %s.\n", transit.radiusKm, times.format(from, layoutStamp), times.format(to, layoutStamp))
+		return nil
+	}
+
+	rows := [][]string{{"Date (" + times.zone(found[0].Time) + ")", "Time", "Body", "Elev", "Az", "Duration", "Width", "Centre line", "Here"}}
+	for _, t := range found {
+		centre := "here"
+		if t.CentreKm >= 0.05 {
+			centre = fmt.Sprintf("%.1f km %s", t.CentreKm, compassPoint(t.CentreAzimuth))
+		}
+		here := "no"
+		if t.Visible {
+			here = "yes"
+		}
+		rows = append(rows, []string{
+			times.format(t.Time, layoutDay),
+			times.format(t.Time, layoutTenth),
+			t.Body.String(),
+			fmt.Sprintf("%.0f°", t.BodyElevation),
+			compassPoint(t.BodyAzimuth),
+			fmt.Sprintf("%.2f s", t.Duration.Seconds()),
+			fmt.Sprintf("%.1f km", t.WidthKm),
+			centre,
+			here,
+		})
+	}
+	for _, line := range formatTable(rows, nil) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"github.com/Kivayan/iss/pkg/geo"
+	"strings"
+)
+
+type unitSystem int
+
+const (
+	unitsMetric unitSystem = iota
+	unitsImperial
+	unitsNautical
+)
+
+var unitSystemNames = []string{"metric", "imperial", "nautical"}
+
+func parseUnits(value string) (unitSystem, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return unitsMetric, nil
+	}
+
+	for i, name := range unitSystemNames {
+		if name == value {
+			return unitSystem(i), nil
+		}
+	}
+
+	return unitsMetric, fmt.Errorf("unknown units %q (want metric, imperial or nautical)", value)
+}
+
+func (u unitSystem) String() string {
+	return unitSystemNames[u]
+}
+
+func (u unitSystem) next() unitSystem {
+	return (u + 1) % unitSystem(len(unitSystemNames))
+}
+
+func (u unitSystem) distanceUnit() (float64, string) {
+	switch u {
+	case unitsImperial:
+		return geo.KmToMiles, "mi"
+	case unitsNautical:
+		return geo.KmToNauticalMiles, "nmi"
+	default:
+		return 1, "km"
+	}
+}
+
+func (u unitSystem) formatDistance(km float64) string {
+	factor, unit := u.distanceUnit()
+	return fmt.Sprintf("%.1f %s", km*factor, unit)
+}
+
+func (u unitSystem) formatSpeed(kmh float64) string {
+	switch u {
+	case unitsImperial:
+		return fmt.Sprintf("%.0f mph", kmh*geo.KmToMiles)
+	case unitsNautical:
+		return fmt.Sprintf("%.0f kn", kmh*geo.KmToNauticalMiles)
+	default:
+		return fmt.Sprintf("%.0f km/h", kmh)
+	}
+}
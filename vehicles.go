@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Kivayan/iss/pkg/track"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	vehiclesRefresh = 24 * time.Hour
+	vehiclesRetry   = 30 * time.Minute
+)
+
+type vehiclesMsg struct {
+	vehicles  []track.DockedVehicle
+	fetchedAt time.Time
+	err       error
+}
+
+type vehiclesRefreshMsg struct{}
+
+type vehiclesCache struct {
+	Fetched  time.Time             `json:"fetched"`
+	Vehicles []track.DockedVehicle `json:"vehicles"`
+}
+
+func vehiclesCachePath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vehicles.json"), nil
+}
+
+func loadDockedVehicles(client *http.Client, now time.Time) vehiclesMsg {
+	path, pathErr := vehiclesCachePath()
+
+	var cached vehiclesCache
+	if pathErr == nil {
+		withFileLock(path, func() error {
+			data, err := readFileIfExists(path)
+			if err == nil && len(data) > 0 {
+				json.Unmarshal(data, &cached)
+			}
+			return nil
+		})
+	}
+	if !cached.Fetched.IsZero() && now.Sub(cached.Fetched) < vehiclesRefresh {
+		return vehiclesMsg{vehicles: cached.Vehicles, fetchedAt: cached.Fetched}
+	}
+
+	vehicles, err := track.FetchDockedVehicles(client)
+	if err != nil {
+		err = fmt.Errorf("docked vehicles: %w", err)
+		if !cached.Fetched.IsZero() {
+			return vehiclesMsg{vehicles: cached.Vehicles, fetchedAt: cached.Fetched, err: err}
+		}
+		return vehiclesMsg{err: err}
+	}
+
+	if pathErr == nil {
+		if data, err := json.Marshal(vehiclesCache{Fetched: now, Vehicles: vehicles}); err == nil {
+			withFileLock(path, func() error {
+				return writeFileAtomic(path, data, 0o644)
+			})
+		}
+	}
+	return vehiclesMsg{vehicles: vehicles, fetchedAt: now}
+}
+
+func fetchVehiclesCmd(client *http.Client) tea.Cmd {
+	return func() tea.Msg {
+		return loadDockedVehicles(client, time.Now())
+	}
+}
+
+func vehiclesRefreshTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return vehiclesRefreshMsg{}
+	})
+}
+
+func (m model) vehiclesView() string {
+	lines := []string{m.lang.text("vehicles"), ""}
+
+	switch {
+	case m.vehiclesAt.IsZero():
+		lines = append(lines, m.lang.text("resolving"))
+	case len(m.vehicles) == 0:
+		lines = append(lines, m.lang.text("no_vehicles"))
+	default:
+		vehicles := append([]track.DockedVehicle(nil), m.vehicles...)
+		sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].Docked.Before(vehicles[j].Docked) })
+
+		table := [][]string{{m.lang.text("vehicle"), m.lang.text("port"), m.lang.text("docked")}}
+		for _, v := range vehicles {
+			name := v.Name
+			if v.Type != "" && v.Type != v.Name {
+				name += " (" + v.Type + ")"
+			}
+			docked := ""
+			if !v.Docked.IsZero() {
+				docked = m.times.format(v.Docked, layoutDate)
+			}
+			table = append(table, []string{name, v.Port, docked})
+		}
+		lines = append(lines, formatTable(table, nil)...)
+	}
+
+	if !m.vehiclesAt.IsZero() {
+		lines = append(lines, "", m.lang.text("updated")+": "+m.times.format(m.vehiclesAt, layoutStamp))
+	}
+	if m.lastErr != "" {
+		lines = append(lines, "", m.lang.text("last_error")+": "+m.lastErr)
+	}
+
+	return "\n" + centerBlock(m.box(lines), m.width) + "\n"
+}
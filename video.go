@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultVideoURL = "https://www.youtube.com/@NASA/live"
+
+type videoMsg struct {
+	started bool
+	opened  bool
+	stopped bool
+	err     error
+}
+
+type videoPlayer struct {
+	url     string
+	command []string
+
+	mu  sync.Mutex
+	cmd *exec.Cmd
+}
+
+func newVideoPlayer(cfg config) *videoPlayer {
+	p := &videoPlayer{url: defaultVideoURL}
+	if cfg.Video != nil {
+		if cfg.Video.URL != "" {
+			p.url = cfg.Video.URL
+		}
+		p.command = cfg.Video.Player
+	}
+	return p
+}
+
+func (p *videoPlayer) running() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cmd != nil
+}
+
+func (p *videoPlayer) start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd != nil {
+		return errors.New("player already running")
+	}
+
+	args := make([]string, 0, len(p.command)+1)
+	substituted := false
+	for _, arg := range p.command {
+		if strings.Contains(arg, "{url}") {
+			arg = strings.ReplaceAll(arg, "{url}", p.url)
+			substituted = true
+		}
+		args = append(args, arg)
+	}
+	if !substituted {
+		args = append(args, p.url)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	p.cmd = cmd
+
+	go func() {
+		cmd.Wait()
+		p.mu.Lock()
+		if p.cmd == cmd {
+			p.cmd = nil
+		}
+		p.mu.Unlock()
+	}()
+	return nil
+}
+
+func (p *videoPlayer) stop() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		return nil
+	}
+	err := p.cmd.Process.Kill()
+	p.cmd = nil
+	return err
+}
+
+func toggleVideoCmd(p *videoPlayer) tea.Cmd {
+	return func() tea.Msg {
+		if p.running() {
+			if err := p.stop(); err != nil {
+				return videoMsg{err: fmt.Errorf("video: %w", err)}
+			}
+			return videoMsg{stopped: true}
+		}
+
+		if len(p.command) == 0 {
+			if err := openURL(p.url); err != nil {
+				return videoMsg{err: fmt.Errorf("video: %w", err)}
+			}
+			return videoMsg{opened: true}
+		}
+
+		if err := p.start(); err != nil {
+			return videoMsg{err: fmt.Errorf("video: %w", err)}
+		}
+		return videoMsg{started: true}
+	}
+}
+
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
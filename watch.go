@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type watchOptions struct {
+	interval time.Duration
+	showMap  bool
+	width    int
+}
+
+func runWatch(ctx context.Context, opts globalOptions, w io.Writer, watch watchOptions) error {
+	m := newModel(ctx, opts)
+	if watch.showMap && m.mapMask == nil {
+		return fmt.Errorf("%s", m.lastErr)
+	}
+	defer m.stats.save()
+
+	m.width = watch.width
+	if watch.interval <= 0 {
+		watch.interval = telemetryInterval
+	}
+
+	cursor := capableTerminal()
+	previousLines := 0
+	ticker := time.NewTicker(watch.interval)
+	defer ticker.Stop()
+
+	for {
+		msg := fetchTelemetryCmd(m.client, m.providers, m.geocodes, m.lang, m.location())()
+		if ctx.Err() != nil {
+			if cursor && !watch.showMap {
+				fmt.Fprintln(w)
+			}
+			return nil
+		}
+		switch msg := msg.(type) {
+		case telemetryMsg:
+			m = m.applyTelemetry(msg)
+		case errMsg:
+			m.lastErr = msg.err.Error()
+		}
+
+		var err error
+		if watch.showMap {
+			previousLines, err = m.writeWatchFrame(w, cursor, previousLines)
+		} else {
+			err = m.writeWatchLine(w, cursor, time.Now())
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if cursor && !watch.showMap {
+				fmt.Fprintln(w)
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m model) watchLine(now time.Time) string {
+	if !m.hasCoords {
+		line := m.times.format(now, layoutClock) + "  " + m.lang.text("coords") + ": " + m.lang.text("resolving")
+		if m.lastErr != "" {
+			line += "  (" + strings.ReplaceAll(m.lastErr, "\n", "; ") + ")"
+		}
+		return line
+	}
+
+	parts := []string{
+		m.times.format(now, layoutClock),
+		m.lang.text("iss_over") + ": " + m.issOver,
+		formatLatitude(m.lat) + " " + formatLongitude(m.lon),
+	}
+	if m.hasAltitude {
+		parts = append(parts, m.units.formatDistance(m.altitudeKm), m.units.formatSpeed(m.velocityKmh))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
+func (m model) writeWatchLine(w io.Writer, cursor bool, now time.Time) error {
+	line := m.watchLine(now)
+	if cursor {
+		_, err := fmt.Fprint(w, "\r"+line+"\x1b[K")
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+func (m model) writeWatchFrame(w io.Writer, cursor bool, previousLines int) (int, error) {
+	rendered, err := renderMap(m.rasters, m.rasterKey(), m.style, m.lat, m.lon, m.hasCoords, m.mapGlyphs(time.Now()))
+	if err != nil {
+		return previousLines, err
+	}
+
+	lines := m.telemetryLines()
+	if m.lastErr != "" {
+		lines = append(lines, m.lang.text("last_error")+": "+m.lastErr)
+	}
+	frame := strings.TrimRight(renderScreen(rendered, telemetryBox(lines), m.width), "\n")
+	rows := strings.Split(frame, "\n")
+
+	var b strings.Builder
+	if cursor {
+		if previousLines > 0 {
+			fmt.Fprintf(&b, "\x1b[%dA", previousLines)
+		}
+		for _, row := range rows {
+			b.WriteString("\r" + row + "\x1b[K\n")
+		}
+		b.WriteString("\x1b[J")
+	} else {
+		b.WriteString(frame + "\n\n")
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return len(rows), err
+}